@@ -21,6 +21,10 @@ func NewAdminClient(opts ...ClientOption) (*AdminClient, error) {
 		opt(config)
 	}
 
+	if err := validateTablePrefix(config.TablePrefix); err != nil {
+		return nil, err
+	}
+
 	// Admin operations typically use information_schema
 	if config.Database == "" {
 		config.Database = "information_schema"
@@ -37,6 +41,7 @@ func NewAdminClient(opts ...ClientOption) (*AdminClient, error) {
 			config.Password,
 			config.Database,
 			config.Tenant,
+			remoteConnectionOptions(config)...,
 		)
 	} else if config.Path != "" {
 		// Embedded mode
@@ -75,6 +80,12 @@ func (a *AdminClient) IsConnected() bool {
 	return a.conn.IsConnected()
 }
 
+// Ping verifies the server is reachable, retrying once on another pooled connection
+// if the one used has died.
+func (a *AdminClient) Ping(ctx context.Context) error {
+	return a.conn.Ping(ctx)
+}
+
 // CreateDatabase creates a new database.
 func (a *AdminClient) CreateDatabase(ctx context.Context, name string, tenant ...string) (*Database, error) {
 	tenantName := a.config.Tenant
@@ -82,7 +93,11 @@ func (a *AdminClient) CreateDatabase(ctx context.Context, name string, tenant ..
 		tenantName = tenant[0]
 	}
 
-	createSQL := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", name)
+	if err := validateDatabaseName(name); err != nil {
+		return nil, err
+	}
+
+	createSQL := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", name)
 	if _, err := a.conn.Execute(ctx, createSQL); err != nil {
 		return nil, fmt.Errorf("failed to create database: %w", err)
 	}
@@ -122,7 +137,11 @@ func (a *AdminClient) GetDatabase(ctx context.Context, name string, tenant ...st
 
 // DeleteDatabase deletes a database.
 func (a *AdminClient) DeleteDatabase(ctx context.Context, name string, tenant ...string) error {
-	dropSQL := fmt.Sprintf("DROP DATABASE IF EXISTS %s", name)
+	if err := validateDatabaseName(name); err != nil {
+		return err
+	}
+
+	dropSQL := fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", name)
 	if _, err := a.conn.Execute(ctx, dropSQL); err != nil {
 		return fmt.Errorf("failed to delete database: %w", err)
 	}
@@ -221,6 +240,10 @@ func WithCollation(collation string) DatabaseOption {
 
 // CreateDatabaseWithOptions creates a database with custom options.
 func (a *AdminClient) CreateDatabaseWithOptions(ctx context.Context, name string, opts ...DatabaseOption) (*Database, error) {
+	if err := validateDatabaseName(name); err != nil {
+		return nil, err
+	}
+
 	config := &DatabaseConfig{
 		Charset:   "utf8mb4",
 		Collation: "utf8mb4_general_ci",
@@ -231,7 +254,7 @@ func (a *AdminClient) CreateDatabaseWithOptions(ctx context.Context, name string
 	}
 
 	var createParts []string
-	createParts = append(createParts, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", name))
+	createParts = append(createParts, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", name))
 
 	if config.Charset != "" {
 		createParts = append(createParts, fmt.Sprintf("CHARACTER SET %s", config.Charset))
@@ -252,3 +275,15 @@ func (a *AdminClient) CreateDatabaseWithOptions(ctx context.Context, name string
 		Collation: config.Collation,
 	}, nil
 }
+
+// validateDatabaseName checks that a database name can be quoted with backticks
+// in DDL: it must be non-empty and must not contain a backtick.
+func validateDatabaseName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: database name must not be empty", ErrInvalidParameter)
+	}
+	if strings.Contains(name, "`") {
+		return fmt.Errorf("%w: database name %q must not contain a backtick", ErrInvalidParameter, name)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CollectionLocation describes a collection found by AdminClient.ListAllCollections.
+type CollectionLocation struct {
+	Database  string `json:"database"`
+	Name      string `json:"name"`
+	Dimension int    `json:"dimension"`
+	RowCount  int64  `json:"row_count"`
+}
+
+// ListAllCollections lists the collections in every database the admin user can see,
+// skipping the system schemas excluded by ListDatabases, e.g. for a dashboard of a
+// shared cluster. Collections are recognized by their table prefix (see
+// WithTablePrefix and WithSchemaVersion). RowCount is an exact COUNT(*), so listing
+// is proportional to the size of the collections.
+func (a *AdminClient) ListAllCollections(ctx context.Context) ([]CollectionLocation, error) {
+	databases, err := a.ListDatabases(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []CollectionLocation
+	for _, database := range databases {
+		found, err := a.listDatabaseCollections(ctx, database.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collections in database %s: %w", database.Name, err)
+		}
+		locations = append(locations, found...)
+	}
+
+	return locations, nil
+}
+
+// listDatabaseCollections lists the collections in one database.
+func (a *AdminClient) listDatabaseCollections(ctx context.Context, database string) ([]CollectionLocation, error) {
+	query := fmt.Sprintf(`
+		SELECT t.TABLE_NAME, t.TABLE_COMMENT, c.COLUMN_TYPE
+		FROM INFORMATION_SCHEMA.TABLES t
+		LEFT JOIN INFORMATION_SCHEMA.COLUMNS c
+			ON c.TABLE_SCHEMA = t.TABLE_SCHEMA AND c.TABLE_NAME = t.TABLE_NAME AND c.COLUMN_NAME = '%s'
+		WHERE t.TABLE_SCHEMA = ?
+		ORDER BY t.TABLE_NAME
+	`, FieldEmbedding)
+
+	rows, err := a.conn.Query(ctx, query, database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locations []CollectionLocation
+	var tables []string
+	for rows.Next() {
+		var tableName, comment string
+		var columnType sql.NullString
+		if err := rows.Scan(&tableName, &comment, &columnType); err != nil {
+			return nil, err
+		}
+		name, ok := collectionNameFromTable(a.config, tableName)
+		if !ok {
+			continue
+		}
+
+		// Prefer the persisted dimension over the column definition
+		location := CollectionLocation{Database: database, Name: name}
+		if meta, ok, err := parseCollectionMeta(comment); err == nil && ok {
+			location.Dimension = meta.Dimension
+		} else if dimension, ok := parseVectorColumnDimension(columnType.String); ok {
+			location.Dimension = dimension
+		}
+		locations = append(locations, location)
+		tables = append(tables, tableName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, tableName := range tables {
+		countSQL := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`", database, tableName)
+		if err := a.conn.QueryRow(ctx, countSQL).Scan(&locations[i].RowCount); err != nil {
+			return nil, fmt.Errorf("failed to count collection %s: %w", locations[i].Name, err)
+		}
+	}
+
+	return locations, nil
+}
+
+// parseVectorColumnDimension returns the dimension of a vector column type as
+// reported by INFORMATION_SCHEMA.COLUMNS, e.g. 384 for "vector(384)".
+func parseVectorColumnDimension(columnType string) (int, bool) {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	if !strings.HasPrefix(columnType, "vector(") || !strings.HasSuffix(columnType, ")") {
+		return 0, false
+	}
+	dimension, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(columnType, "vector("), ")"))
+	if err != nil || dimension <= 0 {
+		return 0, false
+	}
+	return dimension, true
+}
+
+// DropDatabaseCascade drops a database after dropping its collections one by one,
+// each after its secondary (e.g. vector and full-text) indexes, so no collection
+// is left half-removed if dropping the database itself fails. It returns the number
+// of collections removed, also when it stops at an error. Like DeleteDatabase, it is
+// not an error if the database does not exist.
+func (a *AdminClient) DropDatabaseCascade(ctx context.Context, name string) (int, error) {
+	if err := validateDatabaseName(name); err != nil {
+		return 0, err
+	}
+
+	exists, err := a.HasDatabase(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check database %s: %w", name, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	tables, err := a.listCollectionTables(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list collections in database %s: %w", name, err)
+	}
+
+	removed := 0
+	for _, tableName := range tables {
+		indexes, err := a.listSecondaryIndexes(ctx, name, tableName)
+		if err != nil {
+			return removed, fmt.Errorf("failed to list indexes of %s: %w", tableName, err)
+		}
+		for _, stmt := range dropCollectionSQL(name, tableName, indexes) {
+			if _, err := a.conn.Execute(ctx, stmt); err != nil {
+				return removed, fmt.Errorf("failed to drop collection table %s: %w", tableName, err)
+			}
+		}
+		removed++
+	}
+
+	if _, err := a.conn.Execute(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", name)); err != nil {
+		return removed, fmt.Errorf("failed to delete database: %w", err)
+	}
+	return removed, nil
+}
+
+// listCollectionTables returns the collection table names in a database.
+func (a *AdminClient) listCollectionTables(ctx context.Context, database string) ([]string, error) {
+	query := `
+		SELECT TABLE_NAME
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME
+	`
+
+	rows, err := a.conn.Query(ctx, query, database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		if _, ok := collectionNameFromTable(a.config, tableName); ok {
+			tables = append(tables, tableName)
+		}
+	}
+
+	return tables, rows.Err()
+}
+
+// listSecondaryIndexes returns the names of a table's indexes other than the primary key.
+func (a *AdminClient) listSecondaryIndexes(ctx context.Context, database, tableName string) ([]string, error) {
+	query := `
+		SELECT DISTINCT INDEX_NAME
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME <> 'PRIMARY'
+		ORDER BY INDEX_NAME
+	`
+
+	rows, err := a.conn.Query(ctx, query, database, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, rows.Err()
+}
+
+// dropCollectionSQL returns the statements dropping a collection table in database:
+// its secondary indexes first, then the table.
+func dropCollectionSQL(database, tableName string, indexes []string) []string {
+	stmts := make([]string, 0, len(indexes)+1)
+	for _, index := range indexes {
+		stmts = append(stmts, fmt.Sprintf("DROP INDEX `%s` ON `%s`.`%s`", index, database, tableName))
+	}
+	return append(stmts, fmt.Sprintf("DROP TABLE IF EXISTS `%s`.`%s`", database, tableName))
+}
@@ -8,6 +8,7 @@ Supports configuring connection parameters via environment variables
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -276,3 +277,138 @@ var testCounter int
 func init() {
 	testCounter = int(^uint(0) >> 63) // Random starting point
 }
+
+// TestParseVectorColumnDimension tests reading the dimension off a vector column type
+func TestParseVectorColumnDimension(t *testing.T) {
+	dimension, ok := parseVectorColumnDimension("vector(384)")
+	assert.True(t, ok)
+	assert.Equal(t, 384, dimension)
+
+	dimension, ok = parseVectorColumnDimension(" VECTOR(3) ")
+	assert.True(t, ok)
+	assert.Equal(t, 3, dimension)
+
+	for _, columnType := range []string{"", "json", "vector", "vector()", "vector(0)", "vector(x)"} {
+		_, ok := parseVectorColumnDimension(columnType)
+		assert.False(t, ok, columnType)
+	}
+}
+
+// TestValidateDatabaseName tests rejecting names that cannot be quoted with backticks
+func TestValidateDatabaseName(t *testing.T) {
+	for _, name := range []string{"test", "tenant-db", "db$1"} {
+		assert.NoError(t, validateDatabaseName(name), name)
+	}
+
+	admin := &AdminClient{config: &ClientConfig{}}
+	ctx := context.Background()
+	for _, name := range []string{"", "te`st", "x` CHARACTER SET latin1; DROP DATABASE `prod"} {
+		assert.True(t, errors.Is(validateDatabaseName(name), ErrInvalidParameter), name)
+
+		_, err := admin.CreateDatabase(ctx, name)
+		assert.True(t, errors.Is(err, ErrInvalidParameter), name)
+		_, err = admin.CreateDatabaseWithOptions(ctx, name)
+		assert.True(t, errors.Is(err, ErrInvalidParameter), name)
+		assert.True(t, errors.Is(admin.DeleteDatabase(ctx, name), ErrInvalidParameter), name)
+		_, err = admin.DropDatabaseCascade(ctx, name)
+		assert.True(t, errors.Is(err, ErrInvalidParameter), name)
+	}
+}
+
+// TestServerAdminListAllCollections tests listing collections across databases
+func TestServerAdminListAllCollections(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := fmt.Sprintf("test_admin_list_%d", os.Getpid())
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"first", "second"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}))
+	require.NoError(t, err)
+
+	admin, err := NewAdminClient(
+		WithHost(getAdminServerHost()),
+		WithPort(getAdminServerPort()),
+		WithTenant("sys"),
+		WithUser(getAdminServerUser()),
+		WithPassword(getAdminServerPassword()),
+	)
+	require.NoError(t, err)
+	defer admin.Close()
+
+	locations, err := admin.ListAllCollections(ctx)
+	require.NoError(t, err)
+
+	var found *CollectionLocation
+	for i := range locations {
+		assert.NotContains(t, []string{"information_schema", "mysql", "performance_schema", "sys"}, locations[i].Database)
+		if locations[i].Name == collectionName {
+			found = &locations[i]
+		}
+	}
+	require.NotNil(t, found, "collection %s not listed", collectionName)
+	assert.Equal(t, getServerDatabase(), found.Database)
+	assert.Equal(t, 3, found.Dimension)
+	assert.Equal(t, int64(2), found.RowCount)
+}
+
+// TestDropCollectionSQL tests that indexes are dropped before their table
+func TestDropCollectionSQL(t *testing.T) {
+	assert.Equal(t, []string{
+		"DROP INDEX `vidx_docs` ON `tenant_db`.`c$v1$docs`",
+		"DROP INDEX `idx_fts` ON `tenant_db`.`c$v1$docs`",
+		"DROP TABLE IF EXISTS `tenant_db`.`c$v1$docs`",
+	}, dropCollectionSQL("tenant_db", "c$v1$docs", []string{"vidx_docs", "idx_fts"}))
+
+	assert.Equal(t, []string{"DROP TABLE IF EXISTS `tenant_db`.`c$v1$docs`"}, dropCollectionSQL("tenant_db", "c$v1$docs", nil))
+}
+
+// TestServerAdminDropDatabaseCascade tests dropping a database with collections
+func TestServerAdminDropDatabaseCascade(t *testing.T) {
+	admin, err := NewAdminClient(
+		WithHost(getAdminServerHost()),
+		WithPort(getAdminServerPort()),
+		WithTenant("sys"),
+		WithUser(getAdminServerUser()),
+		WithPassword(getAdminServerPassword()),
+	)
+	require.NoError(t, err)
+	defer admin.Close()
+
+	ctx := context.Background()
+	dbName := fmt.Sprintf("test_cascade_%d", os.Getpid())
+	_, err = admin.CreateDatabase(ctx, dbName)
+	require.NoError(t, err)
+
+	client, err := NewClient(
+		WithHost(getAdminServerHost()),
+		WithPort(getAdminServerPort()),
+		WithTenant("sys"),
+		WithDatabase(dbName),
+		WithUser(getAdminServerUser()),
+		WithPassword(getAdminServerPassword()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+	for _, name := range []string{"first", "second"} {
+		_, err := client.CreateCollection(ctx, name, WithConfiguration(&HNSWConfiguration{Dimension: 3, Distance: DistanceL2}))
+		require.NoError(t, err)
+	}
+
+	removed, err := admin.DropDatabaseCascade(ctx, dbName)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	exists, err := admin.HasDatabase(ctx, dbName)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Dropping a missing database is not an error
+	removed, err = admin.DropDatabaseCascade(ctx, dbName)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
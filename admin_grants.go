@@ -0,0 +1,150 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Permission is a table privilege granted on a collection with
+// AdminClient.GrantCollectionAccess.
+type Permission string
+
+// Collection permissions, mapped to the SQL privileges of the same name.
+const (
+	PermissionSelect Permission = "SELECT"
+	PermissionInsert Permission = "INSERT"
+	PermissionUpdate Permission = "UPDATE"
+	PermissionDelete Permission = "DELETE"
+)
+
+// GrantCollectionAccess grants user the given permissions on a collection's table,
+// e.g. []Permission{PermissionSelect} for a read-only user. The collection is looked
+// up in the database the admin client was created for (see WithDatabase). user is
+// an account name, optionally with a host as "name@host"; without one any host
+// ("%") is assumed. The user must already exist.
+func (a *AdminClient) GrantCollectionAccess(ctx context.Context, user, collectionName string, perms []Permission) error {
+	stmt, err := a.collectionAccessSQL(ctx, "GRANT", user, collectionName, perms)
+	if err != nil {
+		return err
+	}
+	if _, err := a.conn.Execute(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to grant access to collection %s: %w", collectionName, err)
+	}
+	return nil
+}
+
+// RevokeCollectionAccess revokes permissions granted with GrantCollectionAccess.
+// Revoking a permission the user does not hold fails with the server's error.
+func (a *AdminClient) RevokeCollectionAccess(ctx context.Context, user, collectionName string, perms []Permission) error {
+	stmt, err := a.collectionAccessSQL(ctx, "REVOKE", user, collectionName, perms)
+	if err != nil {
+		return err
+	}
+	if _, err := a.conn.Execute(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to revoke access to collection %s: %w", collectionName, err)
+	}
+	return nil
+}
+
+// collectionAccessSQL validates the arguments of a grant or revoke, resolves the
+// collection's table and returns the statement.
+func (a *AdminClient) collectionAccessSQL(ctx context.Context, verb, user, collectionName string, perms []Permission) (string, error) {
+	account, err := formatAccount(user)
+	if err != nil {
+		return "", err
+	}
+	if err := validatePermissions(perms); err != nil {
+		return "", err
+	}
+	database := a.config.Database
+	if database == "" || strings.EqualFold(database, "information_schema") {
+		return "", fmt.Errorf("%w: create the admin client with WithDatabase to manage collection access", ErrInvalidParameter)
+	}
+
+	tableName, err := a.findCollectionTable(ctx, database, collectionName)
+	if err != nil {
+		return "", err
+	}
+	return collectionAccessSQL(verb, database, tableName, account, perms), nil
+}
+
+// findCollectionTable returns the table of a collection in database. If the
+// collection exists in several schema versions, the configured one wins, then the
+// newest, as for clients.
+func (a *AdminClient) findCollectionTable(ctx context.Context, database, collectionName string) (string, error) {
+	tables, err := a.listCollectionTables(ctx, database)
+	if err != nil {
+		return "", fmt.Errorf("failed to list collections in database %s: %w", database, err)
+	}
+
+	byVersion := make(map[int]string)
+	var versions []int
+	for _, tableName := range tables {
+		if name, ok := collectionNameFromTable(a.config, tableName); !ok || name != collectionName {
+			continue
+		}
+		version, _, _ := parseVersionedTableName(tableName) // 0 with a custom table prefix
+		byVersion[version] = tableName
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrCollectionNotFound, collectionName)
+	}
+
+	preferred := DefaultSchemaVersion
+	if a.config.SchemaVersion != 0 {
+		preferred = a.config.SchemaVersion
+	}
+	return byVersion[pickSchemaVersion(versions, preferred)], nil
+}
+
+// collectionAccessSQL returns a GRANT or REVOKE of perms on a table for an account
+// formatted by formatAccount.
+func collectionAccessSQL(verb, database, tableName, account string, perms []Permission) string {
+	privileges := make([]string, len(perms))
+	for i, perm := range perms {
+		privileges[i] = string(perm)
+	}
+	direction := "TO"
+	if verb == "REVOKE" {
+		direction = "FROM"
+	}
+	return fmt.Sprintf("%s %s ON `%s`.`%s` %s %s", verb, strings.Join(privileges, ", "), database, tableName, direction, account)
+}
+
+// validatePermissions checks that perms is a non-empty list of distinct known permissions.
+func validatePermissions(perms []Permission) error {
+	if len(perms) == 0 {
+		return fmt.Errorf("%w: no permissions given", ErrInvalidParameter)
+	}
+	seen := make(map[Permission]bool, len(perms))
+	for _, perm := range perms {
+		switch perm {
+		case PermissionSelect, PermissionInsert, PermissionUpdate, PermissionDelete:
+		default:
+			return fmt.Errorf("%w: unknown permission %q", ErrInvalidParameter, perm)
+		}
+		if seen[perm] {
+			return fmt.Errorf("%w: duplicate permission %q", ErrInvalidParameter, perm)
+		}
+		seen[perm] = true
+	}
+	return nil
+}
+
+// formatAccount quotes a "name" or "name@host" user as 'name'@'host', with host "%"
+// if none is given. Names and hosts must not contain quotes or backslashes.
+func formatAccount(user string) (string, error) {
+	name, host := user, "%"
+	if at := strings.LastIndex(user, "@"); at >= 0 {
+		name, host = user[:at], user[at+1:]
+	}
+	if name == "" || host == "" {
+		return "", fmt.Errorf("%w: invalid user %q", ErrInvalidParameter, user)
+	}
+	if strings.ContainsAny(user, "'\"`\\") {
+		return "", fmt.Errorf("%w: user %q must not contain quotes or backslashes", ErrInvalidParameter, user)
+	}
+	return fmt.Sprintf("'%s'@'%s'", name, host), nil
+}
@@ -0,0 +1,91 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionAccessSQL tests the generated GRANT and REVOKE statements
+func TestCollectionAccessSQL(t *testing.T) {
+	perms := []Permission{PermissionSelect, PermissionInsert}
+	assert.Equal(t, "GRANT SELECT, INSERT ON `tenant_db`.`c$v1$docs` TO 'reader'@'%'",
+		collectionAccessSQL("GRANT", "tenant_db", "c$v1$docs", "'reader'@'%'", perms))
+	assert.Equal(t, "REVOKE SELECT, INSERT ON `tenant_db`.`c$v1$docs` FROM 'reader'@'%'",
+		collectionAccessSQL("REVOKE", "tenant_db", "c$v1$docs", "'reader'@'%'", perms))
+}
+
+// TestFormatAccount tests quoting users with and without a host
+func TestFormatAccount(t *testing.T) {
+	account, err := formatAccount("reader")
+	require.NoError(t, err)
+	assert.Equal(t, "'reader'@'%'", account)
+
+	account, err = formatAccount("reader@10.0.0.%")
+	require.NoError(t, err)
+	assert.Equal(t, "'reader'@'10.0.0.%'", account)
+
+	for _, user := range []string{"", "@host", "reader@", "read'er", "reader@h`ost", `read\er`} {
+		_, err := formatAccount(user)
+		assert.True(t, errors.Is(err, ErrInvalidParameter), user)
+	}
+}
+
+// TestValidatePermissions tests rejecting empty, unknown and duplicate permissions
+func TestValidatePermissions(t *testing.T) {
+	assert.NoError(t, validatePermissions([]Permission{PermissionSelect, PermissionInsert, PermissionUpdate, PermissionDelete}))
+
+	for _, perms := range [][]Permission{
+		nil,
+		{"DROP"},
+		{"select"},
+		{PermissionSelect, PermissionSelect},
+	} {
+		err := validatePermissions(perms)
+		assert.True(t, errors.Is(err, ErrInvalidParameter), "%v", perms)
+	}
+}
+
+// TestServerAdminCollectionAccess tests granting and revoking access to a collection
+func TestServerAdminCollectionAccess(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := fmt.Sprintf("test_admin_grant_%d", os.Getpid())
+	createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	admin, err := NewAdminClient(
+		WithHost(getAdminServerHost()),
+		WithPort(getAdminServerPort()),
+		WithTenant("sys"),
+		WithDatabase(getServerDatabase()),
+		WithUser(getAdminServerUser()),
+		WithPassword(getAdminServerPassword()),
+	)
+	require.NoError(t, err)
+	defer admin.Close()
+
+	user := fmt.Sprintf("test_reader_%d", os.Getpid())
+	_, err = admin.conn.Execute(ctx, fmt.Sprintf("CREATE USER IF NOT EXISTS '%s'@'%%' IDENTIFIED BY 'secret'", user))
+	require.NoError(t, err)
+	defer func() {
+		_, _ = admin.conn.Execute(ctx, fmt.Sprintf("DROP USER IF EXISTS '%s'@'%%'", user))
+	}()
+
+	err = admin.GrantCollectionAccess(ctx, user, collectionName, []Permission{PermissionSelect})
+	require.NoError(t, err)
+	err = admin.RevokeCollectionAccess(ctx, user, collectionName, []Permission{PermissionSelect})
+	require.NoError(t, err)
+
+	err = admin.GrantCollectionAccess(ctx, user, "no_such_collection", []Permission{PermissionSelect})
+	assert.True(t, errors.Is(err, ErrCollectionNotFound))
+}
@@ -0,0 +1,37 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// autoExactOptions returns the options to run a query with: opts itself, or a copy
+// with Exact set if opts has an AutoExactThreshold (see WithAutoExactThreshold) and
+// the collection holds fewer rows than it.
+func (c *Client) autoExactOptions(ctx context.Context, collectionName string, opts *QueryOptions) (*QueryOptions, error) {
+	if opts.AutoExactThreshold <= 0 || opts.Exact {
+		return opts, nil
+	}
+
+	var count int
+	if err := c.conn.QueryRow(ctx, autoExactCountSQL(c.GetTableName(collectionName)), opts.AutoExactThreshold).Scan(&count); err != nil {
+		if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+			return nil, notFound
+		}
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	if count >= opts.AutoExactThreshold {
+		return opts, nil
+	}
+
+	c.logger().Debugf("collection %s has %d rows, below %d; searching exactly", collectionName, count, opts.AutoExactThreshold)
+	exact := *opts
+	exact.Exact = true
+	return &exact, nil
+}
+
+// autoExactCountSQL returns a statement counting the rows of a table up to a bound
+// passed as its argument, so that large collections are not counted in full.
+func autoExactCountSQL(tableName string) string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s LIMIT ?) t", tableName)
+}
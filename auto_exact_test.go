@@ -0,0 +1,55 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAutoExactCountSQL tests the bounded row count of the auto exact check
+func TestAutoExactCountSQL(t *testing.T) {
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT 1 FROM c$v1$docs LIMIT ?) t", autoExactCountSQL("c$v1$docs"))
+
+	// Disabled or already exact: no count is needed
+	client := &Client{config: &ClientConfig{}}
+	opts := &QueryOptions{}
+	resolved, err := client.autoExactOptions(context.Background(), "docs", opts)
+	require.NoError(t, err)
+	assert.Same(t, opts, resolved)
+
+	opts = &QueryOptions{AutoExactThreshold: 100, Exact: true}
+	resolved, err = client.autoExactOptions(context.Background(), "docs", opts)
+	require.NoError(t, err)
+	assert.Same(t, opts, resolved)
+}
+
+// TestCollectionQueryAutoExact tests that small collections are searched exactly
+func TestCollectionQueryAutoExact(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_auto_exact_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {9, 9, 9}}),
+	)
+	require.NoError(t, err)
+
+	queryEmbeddings := WithQueryEmbeddings([][]float32{{1, 2, 3}})
+	results, err := collection.Query(ctx, nil, 3, queryEmbeddings, WithQueryStats(true), WithAutoExactThreshold(100))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id1", "id2", "id3"}, results.IDs[0])
+	assert.False(t, results.Stats[0].IndexUsed)
+
+	// At or above the threshold the options are left alone
+	_, err = collection.Query(ctx, nil, 3, queryEmbeddings, WithAutoExactThreshold(3))
+	require.NoError(t, err)
+}
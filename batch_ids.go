@@ -0,0 +1,69 @@
+package goseekdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateID is returned when an ID appears more than once in one Add batch.
+var ErrDuplicateID = errors.New("duplicate id")
+
+// validateUniqueIDs checks that no ID repeats within a batch, so a repeated ID is
+// reported before the multi-row INSERT fails on the primary key or, depending on the
+// server, keeps only one of the rows.
+func validateUniqueIDs(ids []string) error {
+	seen := make(map[string]int, len(ids))
+	for i, id := range ids {
+		if first, ok := seen[id]; ok {
+			return fmt.Errorf("%w: %q at index %d and %d", ErrDuplicateID, id, first, i)
+		}
+		seen[id] = i
+	}
+	return nil
+}
+
+// lastWinsBatch drops all but the last record of each repeated ID from an Upsert
+// batch, so that the last record for an ID is the one stored. The documents,
+// embeddings, metadatas and named embeddings of the dropped records are removed
+// from documents and opts alike; slices not parallel to ids are left to validation.
+// The caller's slices are not modified.
+func lastWinsBatch(ids, documents []string, opts *AddOptions) ([]string, []string) {
+	last := make(map[string]int, len(ids))
+	for i, id := range ids {
+		last[id] = i
+	}
+	if len(last) == len(ids) {
+		return ids, documents
+	}
+
+	keep := make([]int, 0, len(last))
+	for i, id := range ids {
+		if last[id] == i {
+			keep = append(keep, i)
+		}
+	}
+
+	opts.Embeddings = keepParallel(opts.Embeddings, keep, len(ids))
+	opts.Metadatas = keepParallel(opts.Metadatas, keep, len(ids))
+	if opts.NamedEmbeddings != nil {
+		named := make(map[string][][]float32, len(opts.NamedEmbeddings))
+		for name, embeddings := range opts.NamedEmbeddings {
+			named[name] = keepParallel(embeddings, keep, len(ids))
+		}
+		opts.NamedEmbeddings = named
+	}
+	return keepParallel(ids, keep, len(ids)), keepParallel(documents, keep, len(ids))
+}
+
+// keepParallel returns the elements of values at the indexes in keep, if values is
+// parallel to a batch of n records, and values unchanged otherwise.
+func keepParallel[T any](values []T, keep []int, n int) []T {
+	if len(values) != n {
+		return values
+	}
+	kept := make([]T, len(keep))
+	for i, j := range keep {
+		kept[i] = values[j]
+	}
+	return kept
+}
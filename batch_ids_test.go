@@ -0,0 +1,59 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastWinsBatch(t *testing.T) {
+	ids := []string{"a", "b", "a", "c", "b"}
+	documents := []string{"a1", "b1", "a2", "c1", "b2"}
+	opts := &AddOptions{
+		Embeddings:      [][]float32{{1}, {2}, {3}, {4}, {5}},
+		Metadatas:       []Metadata{{"v": 1}, {"v": 2}, {"v": 3}, {"v": 4}, {"v": 5}},
+		NamedEmbeddings: map[string][][]float32{"title": {{10}, {20}, {30}, {40}, {50}}},
+	}
+
+	keptIDs, keptDocuments := lastWinsBatch(ids, documents, opts)
+	assert.Equal(t, []string{"a", "c", "b"}, keptIDs)
+	assert.Equal(t, []string{"a2", "c1", "b2"}, keptDocuments)
+	assert.Equal(t, [][]float32{{3}, {4}, {5}}, opts.Embeddings)
+	assert.Equal(t, []Metadata{{"v": 3}, {"v": 4}, {"v": 5}}, opts.Metadatas)
+	assert.Equal(t, map[string][][]float32{"title": {{30}, {40}, {50}}}, opts.NamedEmbeddings)
+
+	// The caller's slices are untouched
+	assert.Equal(t, []string{"a", "b", "a", "c", "b"}, ids)
+
+	// Unique batches and slices not parallel to ids are returned as is
+	opts = &AddOptions{Metadatas: []Metadata{{"v": 1}}}
+	keptIDs, keptDocuments = lastWinsBatch([]string{"a", "a"}, nil, opts)
+	assert.Equal(t, []string{"a"}, keptIDs)
+	assert.Nil(t, keptDocuments)
+	assert.Equal(t, []Metadata{{"v": 1}}, opts.Metadatas)
+}
+
+// TestCollectionDuplicateIDs tests rejecting duplicates in Add and resolving them in Upsert
+func TestCollectionDuplicateIDs(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "duplicates", dimension: 3, distance: DistanceL2}
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id1"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}))
+	assert.ErrorIs(t, err, ErrDuplicateID)
+	assert.Contains(t, err.Error(), `"id1" at index 0 and 2`)
+	results, err := collection.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results.IDs)
+
+	result, err := collection.UpsertN(ctx, []string{"id1", "id2", "id1"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Inserted)
+	results, err = collection.Get(ctx, []string{"id1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"doc 3"}, results.Documents)
+}
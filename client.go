@@ -0,0 +1,815 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+)
+
+// Client is the entry point for collection management and raw SQL on a seekdb
+// database, in embedded or remote mode.
+type Client struct {
+	conn          connection.Connection
+	config        *ClientConfig
+	filterBuilder *FilterBuilder
+
+	// schemaVersions remembers the schema version of collections by name (see
+	// resolveSchemaVersion).
+	schemaVersions sync.Map
+	// indexedMetadataKeys remembers the filter builders for the indexed metadata keys
+	// of collections by name (see collectionFilterBuilder).
+	indexedMetadataKeys sync.Map
+}
+
+// NewClient creates a new client. Set WithHost for remote mode or WithPath for
+// embedded mode. With AutoConnect (the default), the connection is established on
+// first use; otherwise call Connect.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	config := DefaultClientConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := validateTablePrefix(config.TablePrefix); err != nil {
+		return nil, err
+	}
+	if config.SchemaVersion != 0 {
+		if err := validateSchemaVersion(config.SchemaVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	var conn connection.Connection
+
+	if config.Host != "" {
+		// Remote mode
+		conn = connection.NewRemoteConnection(
+			config.Host,
+			config.Port,
+			config.User,
+			config.Password,
+			config.Database,
+			config.Tenant,
+			remoteConnectionOptions(config)...,
+		)
+	} else if config.Path != "" {
+		// Embedded mode
+		conn = connection.NewEmbeddedConnection(config.Path, config.Database)
+	} else {
+		return nil, fmt.Errorf("%w: must specify either host or path", ErrInvalidParameter)
+	}
+
+	return &Client{
+		conn:          clientConnection(config, conn),
+		config:        config,
+		filterBuilder: NewFilterBuilder(),
+	}, nil
+}
+
+// Connect establishes a connection to the database and detects the distance
+// function names the server provides. A failed detection is logged as a warning and
+// the default names are used.
+func (c *Client) Connect(ctx context.Context) error {
+	if err := c.conn.Connect(ctx); err != nil {
+		return err
+	}
+	if err := c.detectDistanceFuncs(ctx); err != nil {
+		c.logger().Warnf("%v; using default distance function names", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// IsConnected returns true if connected.
+func (c *Client) IsConnected() bool {
+	return c.conn.IsConnected()
+}
+
+// Mode returns the connection mode, "embedded" or "remote".
+func (c *Client) Mode() string {
+	return c.conn.Mode()
+}
+
+// Query runs a raw SQL query and returns its rows.
+func (c *Client) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.conn.Query(ctx, query, args...)
+}
+
+// Execute runs a raw SQL statement.
+func (c *Client) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.conn.Execute(ctx, query, args...)
+}
+
+// QueryRow runs a raw SQL query that returns at most one row.
+func (c *Client) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if err := c.ensureConnected(ctx); err != nil {
+		return errRow(ctx, err)
+	}
+	return c.conn.QueryRow(ctx, query, args...)
+}
+
+// CreateCollection creates a new collection. Without WithConfiguration, the
+// dimension is that of the embedding function and the distance is
+// DefaultDistanceMetric. With WithGetOrCreate, an existing collection of the same
+// name is returned instead.
+func (c *Client) CreateCollection(ctx context.Context, name string, opts ...CreateCollectionOption) (*Collection, error) {
+	options := &CreateCollectionOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if err := validateCollectionName(name); err != nil {
+		return nil, err
+	}
+	if err := validateHNSWConfiguration(options.Configuration); err != nil {
+		return nil, err
+	}
+	if err := validateVectorsOnlyOptions(options); err != nil {
+		return nil, err
+	}
+	if err := validateMetadataColumnOptions(options); err != nil {
+		return nil, err
+	}
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
+	if options.GetOrCreate {
+		exists, err := c.HasCollection(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			existing, err := c.GetCollection(ctx, name, opts...)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkExistingCollectionConfig(existing, options); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	embFunc, err := c.collectionEmbeddingFunc(options)
+	if err != nil && options.Configuration == nil {
+		return nil, err
+	}
+
+	dimension := DefaultVectorDimension
+	distance := DefaultDistanceMetric
+	if embFunc != nil {
+		dimension = embFunc.Dimension()
+	}
+	if options.Configuration != nil {
+		if options.Configuration.Dimension > 0 {
+			dimension = options.Configuration.Dimension
+		}
+		if options.Configuration.Distance != "" {
+			distance = options.Configuration.Distance
+		}
+	}
+
+	createSQL, err := c.createCollectionSQL(name, dimension, distance, options, embFunc)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Execute(ctx, createSQL); err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	c.storeIndexedMetadataKeys(name, options.IndexedMetadataKeys)
+
+	return &Collection{
+		client:           c,
+		name:             name,
+		dimension:        dimension,
+		distance:         distance,
+		embeddingFunc:    embFunc,
+		treatEmptyAsNull: options.TreatEmptyAsNull,
+		contentHash:      options.ContentHash,
+		vectorsOnly:      options.VectorsOnly,
+	}, nil
+}
+
+// fullTextIndexName is the full-text index on the document column of collections.
+const fullTextIndexName = "idx_fts"
+
+// createCollectionSQL returns the CREATE TABLE statement of a new collection.
+func (c *Client) createCollectionSQL(name string, dimension int, distance DistanceMetric, opts *CreateCollectionOptions, embFunc embedding.EmbeddingFunc) (string, error) {
+	definitions := []string{
+		fmt.Sprintf("%s VARBINARY(512) PRIMARY KEY NOT NULL", FieldID),
+		fmt.Sprintf("%s LONGTEXT", FieldDocument),
+		fmt.Sprintf("%s VECTOR(%d)", FieldEmbedding, dimension),
+	}
+	definitions = append(definitions, metadataColumnDefinitions(opts)...)
+	definitions = append(definitions,
+		fmt.Sprintf("FULLTEXT INDEX %s (%s) WITH PARSER ik", fullTextIndexName, FieldDocument),
+		fmt.Sprintf("VECTOR INDEX idx_%s (%s) WITH (%s)", FieldEmbedding, FieldEmbedding, vectorIndexOptions(distance, opts.Configuration)),
+	)
+	if opts.VectorsOnly {
+		definitions = vectorsOnlyDefinitions(dimension, distance, opts.Configuration)
+	}
+
+	fieldDefinitions, err := vectorFieldDefinitions(opts.Configuration)
+	if err != nil {
+		return "", err
+	}
+	definitions = append(definitions, fieldDefinitions...)
+	if c.configuredSchemaVersion() >= SchemaVersion2 {
+		definitions = append(definitions, schemaVersion2Definitions()...)
+	}
+	if opts.ContentHash {
+		hashDefinitions := contentHashDefinitions()
+		if c.configuredSchemaVersion() >= SchemaVersion2 {
+			// Schema version 2 has the column already; only make it unique
+			hashDefinitions = hashDefinitions[1:]
+		}
+		definitions = append(definitions, hashDefinitions...)
+	}
+
+	// The configuration is persisted in the table comment for GetCollection
+	meta := newCollectionMeta(opts.Configuration, embFunc)
+	meta.Dimension = dimension
+	meta.Distance = distance
+	meta.VectorsOnly = opts.VectorsOnly
+	meta.MetadataColumnType = opts.MetadataColumnType
+	meta.IndexedMetadataKeys = opts.IndexedMetadataKeys
+	tableOption, err := meta.tableOption()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("CREATE TABLE `%s` (\n\t%s\n) %s", c.GetTableName(name), strings.Join(definitions, ",\n\t"), tableOption), nil
+}
+
+// GetOrCreateCollection returns the collection if it exists, or else creates it
+// like CreateCollection.
+func (c *Client) GetOrCreateCollection(ctx context.Context, name string, opts ...CreateCollectionOption) (*Collection, error) {
+	return c.CreateCollection(ctx, name, append(opts, WithGetOrCreate(true))...)
+}
+
+// GetCollection returns an existing collection. Its dimension and distance are read
+// from the database; pass WithCollectionEmbeddingFunc to set the embedding function
+// used for documents written or queried without embeddings, and WithTreatEmptyAsNull
+// and WithContentHash as the collection was created with.
+func (c *Client) GetCollection(ctx context.Context, name string, opts ...CreateCollectionOption) (*Collection, error) {
+	options := &CreateCollectionOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
+	// Later statements go to the table of the collection's schema version
+	if _, err := c.resolveSchemaVersion(ctx, name); err != nil {
+		return nil, err
+	}
+
+	meta, hasMeta, err := c.readCollectionMeta(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	dimension, err := c.collectionDimension(ctx, name, meta, hasMeta)
+	if err != nil {
+		return nil, err
+	}
+	distance := DefaultDistanceMetric
+	if hasMeta {
+		distance = meta.Distance
+		c.storeIndexedMetadataKeys(name, meta.IndexedMetadataKeys)
+	}
+
+	vectorsOnly, err := c.collectionVectorsOnly(ctx, name, meta, hasMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing embedding function only matters once documents need embedding
+	embFunc, _ := c.collectionEmbeddingFunc(options)
+
+	return &Collection{
+		client:           c,
+		name:             name,
+		dimension:        dimension,
+		distance:         distance,
+		embeddingFunc:    embFunc,
+		treatEmptyAsNull: options.TreatEmptyAsNull,
+		contentHash:      options.ContentHash,
+		vectorsOnly:      vectorsOnly,
+	}, nil
+}
+
+// collectionEmbeddingFunc returns the embedding function of a collection: the one
+// set WithCollectionEmbeddingFunc, else the client's, else the default embedding
+// function. It returns ErrEmbeddingFunctionRequired if the default is unavailable.
+func (c *Client) collectionEmbeddingFunc(opts *CreateCollectionOptions) (embedding.EmbeddingFunc, error) {
+	if opts.EmbeddingFuncSet {
+		return opts.EmbeddingFunc, nil
+	}
+	if c.config != nil && c.config.EmbeddingFunc != nil {
+		return c.config.EmbeddingFunc, nil
+	}
+
+	embFunc, err := embedding.DefaultEmbeddingFunc()
+	if err != nil {
+		c.logger().Warnf("default embedding function unavailable: %v", err)
+		return nil, ErrEmbeddingFunctionRequired
+	}
+	return embFunc, nil
+}
+
+// HasCollection reports whether a collection exists.
+func (c *Client) HasCollection(ctx context.Context, name string) (bool, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return false, err
+	}
+	query := `
+		SELECT COUNT(*)
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`
+
+	var count int
+	if err := c.conn.QueryRow(ctx, query, c.GetTableName(name)).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check collection: %w", err)
+	}
+	return count > 0, nil
+}
+
+// DeleteCollection deletes a collection and all its documents. Deleting a
+// collection that does not exist is not an error.
+func (c *Client) DeleteCollection(ctx context.Context, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	if _, err := c.conn.Execute(ctx, fmt.Sprintf("DROP TABLE IF EXISTS `%s`", c.GetTableName(name))); err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	c.schemaVersions.Delete(name)
+	c.indexedMetadataKeys.Delete(name)
+	return nil
+}
+
+// ListCollections returns the name, dimension and distance of all collections in
+// the current database. Use ListCollectionNames if only the names are needed.
+func (c *Client) ListCollections(ctx context.Context) ([]CollectionInfo, error) {
+	names, err := c.ListCollectionNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metas, err := c.listCollectionMetas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]CollectionInfo, 0, len(names))
+	for _, name := range names {
+		if meta, ok := metas[name]; ok {
+			collections = append(collections, CollectionInfo{Name: name, Dimension: meta.Dimension, Distance: meta.Distance})
+			continue
+		}
+
+		// Collections created without metadata are read one by one
+		collection, err := c.GetCollection(ctx, name, WithCollectionEmbeddingFunc(nil))
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, CollectionInfo{
+			Name:      name,
+			Dimension: collection.Dimension(),
+			Distance:  collection.Distance(),
+		})
+	}
+	return collections, nil
+}
+
+// CountCollections returns the number of collections in the current database.
+func (c *Client) CountCollections(ctx context.Context) (int, error) {
+	names, err := c.ListCollectionNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(names), nil
+}
+
+// collectionAdd inserts documents into a collection in one transaction.
+func (c *Client) collectionAdd(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (inserted int64, err error) {
+	ctx, span := c.startSpan(ctx, "add", collectionName)
+	defer func() { span.end(int(inserted), err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	embeddings, err := c.embedRecords(ctx, ids, documents, opts.Embeddings, embFunc)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateRecordCounts(ids, documents, embeddings, opts.Metadatas); err != nil {
+		return 0, err
+	}
+
+	version := c.collectionSchemaVersion(collectionName)
+	insertSQL := insertRecordSQL(c.GetTableName(collectionName), opts, version)
+
+	err = c.retryOnDeadlock(ctx, func() error {
+		tx, err := c.conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		inserted = 0
+		for i, id := range ids {
+			args, err := recordArgs(id, i, documents, embeddings, opts, version)
+			if err != nil {
+				return err
+			}
+			res, err := tx.Execute(ctx, insertSQL, args...)
+			if err != nil {
+				return fmt.Errorf("failed to insert document %q: %w", id, err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to read affected rows: %w", err)
+			}
+			inserted += n
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// collectionUpdate updates the given columns of existing documents in one
+// transaction. Columns without new values are left unchanged.
+func (c *Client) collectionUpdate(ctx context.Context, collectionName string, ids []string, opts *UpdateOptions, embFunc embedding.EmbeddingFunc) (updated int64, err error) {
+	ctx, span := c.startSpan(ctx, "update", collectionName)
+	defer func() { span.end(int(updated), err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if opts.Documents == nil && opts.Embeddings == nil && opts.Metadatas == nil && len(opts.RemoveMetadataKeys) == 0 {
+		return 0, fmt.Errorf("%w: nothing to update", ErrInvalidParameter)
+	}
+	if err := validateRecordCounts(ids, opts.Documents, opts.Embeddings, opts.Metadatas); err != nil {
+		return 0, err
+	}
+	// A vectors-only collection has only the embedding to update
+	if opts.vectorsOnly && opts.Embeddings == nil {
+		return 0, fmt.Errorf("%w: nothing to update", ErrInvalidParameter)
+	}
+	if !opts.vectorsOnly {
+		if err := c.reembedUpdatedDocuments(ctx, opts, embFunc); err != nil {
+			return 0, err
+		}
+	}
+
+	tableName := c.GetTableName(collectionName)
+	hashDocuments := writesContentHash(opts.contentHash, c.collectionSchemaVersion(collectionName))
+
+	err = c.retryOnDeadlock(ctx, func() error {
+		tx, err := c.conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		updated = 0
+		for i, id := range ids {
+			var assignments []string
+			var args []interface{}
+			if opts.Documents != nil && !opts.vectorsOnly {
+				assignments = append(assignments, fmt.Sprintf("%s = ?", FieldDocument))
+				args = append(args, documentArg(opts.Documents[i], opts.emptyDocumentsAsNull))
+				if hashDocuments {
+					assignments = append(assignments, fmt.Sprintf("%s = ?", FieldContentHash))
+					args = append(args, contentHashArg(opts.Documents[i]))
+				}
+			}
+			if opts.Embeddings != nil {
+				assignments = append(assignments, fmt.Sprintf("%s = ?", FieldEmbedding))
+				args = append(args, vectorToString(opts.Embeddings[i]))
+			}
+			var metadataJSON *string
+			if opts.Metadatas != nil && !opts.vectorsOnly {
+				encoded, err := opts.Metadatas[i].ToJSON()
+				if err != nil {
+					return fmt.Errorf("failed to marshal metadata for %q: %w", id, err)
+				}
+				metadataJSON = &encoded
+			}
+			if assignment, metadataArgs, ok := metadataUpdateAssignment(metadataJSON, opts.RemoveMetadataKeys); ok && !opts.vectorsOnly {
+				assignments = append(assignments, assignment)
+				args = append(args, metadataArgs...)
+			}
+
+			updateSQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", tableName, strings.Join(assignments, ", "), FieldID)
+			res, err := tx.Execute(ctx, updateSQL, append(args, id)...)
+			if err != nil {
+				return fmt.Errorf("failed to update document %q: %w", id, err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to read affected rows: %w", err)
+			}
+			updated += n
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// collectionUpsert inserts documents or replaces existing ones with the same IDs,
+// in one transaction.
+func (c *Client) collectionUpsert(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (result UpsertResult, err error) {
+	ctx, span := c.startSpan(ctx, "upsert", collectionName)
+	defer func() { span.end(int(result.Inserted+result.Updated), err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return UpsertResult{}, nil
+	}
+	embeddings, err := c.embedRecords(ctx, ids, documents, opts.Embeddings, embFunc)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	if err := validateRecordCounts(ids, documents, embeddings, opts.Metadatas); err != nil {
+		return UpsertResult{}, err
+	}
+
+	version := c.collectionSchemaVersion(collectionName)
+	upsertSQL := upsertRecordSQL(c.GetTableName(collectionName), opts, version)
+
+	err = c.retryOnDeadlock(ctx, func() error {
+		tx, err := c.conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		result = UpsertResult{}
+		for i, id := range ids {
+			args, err := recordArgs(id, i, documents, embeddings, opts, version)
+			if err != nil {
+				return err
+			}
+			res, err := tx.Execute(ctx, upsertSQL, args...)
+			if err != nil {
+				return fmt.Errorf("failed to upsert document %q: %w", id, err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to read affected rows: %w", err)
+			}
+			// ON DUPLICATE KEY UPDATE affects 1 row for an insert, 2 for an update
+			// and 0 if the existing row already had the same values
+			if n == 1 {
+				result.Inserted++
+			} else {
+				result.Updated++
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	return result, nil
+}
+
+// collectionDelete deletes the documents matching ids and the metadata and
+// document filters, and returns the number of rows removed.
+func (c *Client) collectionDelete(ctx context.Context, collectionName string, ids []string, where Filter, whereDocument Filter) (deleted int64, err error) {
+	ctx, span := c.startSpan(ctx, "delete", collectionName)
+	defer func() { span.end(int(deleted), err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if err := validateFilters(where, whereDocument); err != nil {
+		return 0, err
+	}
+	deleteSQL, args, err := c.buildDeleteSQL(collectionName, ids, where, whereDocument)
+	if err != nil {
+		return 0, err
+	}
+
+	var result sql.Result
+	err = c.retryOnDeadlock(ctx, func() error {
+		result, err = c.conn.Execute(ctx, deleteSQL, args...)
+		return err
+	})
+	if err != nil {
+		if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+			return 0, notFound
+		}
+		return 0, fmt.Errorf("failed to delete documents: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// buildDeleteSQL builds the DELETE statement for a collection. At least one of ids,
+// where and whereDocument is required, so that a missing filter never deletes
+// every document.
+func (c *Client) buildDeleteSQL(collectionName string, ids []string, where Filter, whereDocument Filter) (string, []interface{}, error) {
+	if len(ids) == 0 && len(where) == 0 && len(whereDocument) == 0 {
+		return "", nil, fmt.Errorf("%w: delete requires ids, where or whereDocument", ErrInvalidParameter)
+	}
+	tableName := c.GetTableName(collectionName)
+
+	var conditions []string
+	var args []interface{}
+
+	// Filter by IDs
+	if len(ids) > 0 {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", FieldID, strings.Join(placeholders, ", ")))
+	}
+
+	// Add metadata filter
+	if where != nil {
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildMetadataFilter(where)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, filterArgs...)
+		}
+	}
+
+	// Add document filter
+	if whereDocument != nil {
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildDocumentFilter(whereDocument)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, filterArgs...)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, fmt.Errorf("%w: delete filters match every document", ErrInvalidParameter)
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, strings.Join(conditions, " AND ")), args, nil
+}
+
+// embedRecords returns the embeddings of records to write: embeddings if given,
+// otherwise the embeddings of documents generated with embFunc, in parallel as
+// configured by EmbeddingConcurrency.
+func (c *Client) embedRecords(ctx context.Context, ids []string, documents []string, embeddings [][]float32, embFunc embedding.EmbeddingFunc) ([][]float32, error) {
+	if embeddings != nil {
+		return embeddings, nil
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("%w: documents or embeddings are required", ErrInvalidParameter)
+	}
+	if embFunc == nil {
+		return nil, fmt.Errorf("document %q has no embedding: %w", ids[0], ErrEmbeddingFunctionRequired)
+	}
+
+	embeddings, err := c.embedDocuments(ctx, embFunc, documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	return embeddings, nil
+}
+
+// validateRecordCounts checks that documents, embeddings and metadatas, where given,
+// have one entry per ID.
+func validateRecordCounts(ids []string, documents []string, embeddings [][]float32, metadatas []Metadata) error {
+	if documents != nil && len(documents) != len(ids) {
+		return fmt.Errorf("%w: got %d documents for %d ids", ErrInvalidParameter, len(documents), len(ids))
+	}
+	if embeddings != nil && len(embeddings) != len(ids) {
+		return fmt.Errorf("%w: got %d embeddings for %d ids", ErrInvalidParameter, len(embeddings), len(ids))
+	}
+	if metadatas != nil && len(metadatas) != len(ids) {
+		return fmt.Errorf("%w: got %d metadatas for %d ids", ErrInvalidParameter, len(metadatas), len(ids))
+	}
+	return nil
+}
+
+// writesContentHash reports whether writes maintain the _content_hash column: that
+// of collections created WithContentHash, or of any schema version 2 table.
+func writesContentHash(contentHash bool, version int) bool {
+	return contentHash || version >= SchemaVersion2
+}
+
+// recordColumns returns the columns Add and Upsert write to a table of the given
+// schema version, in the order of the arguments returned by recordArgs. A
+// vectors-only collection has only the ID and embedding columns.
+func recordColumns(opts *AddOptions, version int) []string {
+	if opts.vectorsOnly {
+		return []string{FieldID, FieldEmbedding}
+	}
+	columns := []string{FieldID, FieldDocument, FieldEmbedding, FieldMetadata}
+	if writesContentHash(opts.contentHash, version) {
+		columns = append(columns, FieldContentHash)
+	}
+	return columns
+}
+
+// insertRecordSQL returns the INSERT of one record by Add. With contentHash, records
+// whose document is already stored are skipped by INSERT IGNORE, like CopyFrom does.
+func insertRecordSQL(tableName string, opts *AddOptions, version int) string {
+	columns := recordColumns(opts, version)
+	insert := "INSERT"
+	if opts.contentHash {
+		insert = "INSERT IGNORE"
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf("%s INTO %s (%s) VALUES (%s)", insert, tableName, strings.Join(columns, ", "), placeholders)
+}
+
+// upsertRecordSQL returns the INSERT of one record by Upsert, replacing every column
+// but the ID of an existing record. The metadata is merged instead with MergeMetadata.
+func upsertRecordSQL(tableName string, opts *AddOptions, version int) string {
+	columns := recordColumns(opts, version)
+	assignments := make([]string, 0, len(columns)-1)
+	for _, column := range columns[1:] {
+		if column == FieldMetadata {
+			assignments = append(assignments, upsertMetadataAssignment(opts.MergeMetadata))
+			continue
+		}
+		assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", column, column))
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
+		tableName, strings.Join(columns, ", "), placeholders, strings.Join(assignments, ", "))
+}
+
+// recordArgs returns the arguments of an INSERT of the record at index i, for the
+// columns of recordColumns. Missing documents and metadata are written as "" (NULL
+// if the collection treats empty documents as NULL) and an empty object.
+func recordArgs(id string, i int, documents []string, embeddings [][]float32, opts *AddOptions, version int) ([]interface{}, error) {
+	if opts.vectorsOnly {
+		return []interface{}{id, vectorToString(embeddings[i])}, nil
+	}
+	var document string
+	if documents != nil {
+		document = documents[i]
+	}
+	var metadata Metadata
+	if opts.Metadatas != nil {
+		metadata = opts.Metadatas[i]
+	}
+	metadataJSON, err := metadata.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata for %q: %w", id, err)
+	}
+	args := []interface{}{id, documentArg(document, opts.emptyDocumentsAsNull), vectorToString(embeddings[i]), metadataJSON}
+	if writesContentHash(opts.contentHash, version) {
+		args = append(args, contentHashArg(document))
+	}
+	return args, nil
+}
@@ -0,0 +1,29 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// ensureConnected connects the client on first use when AutoConnect is enabled, so
+// that the raw Query, Execute and QueryRow helpers and the collection management
+// operations work on a client whose connection has not been established yet, e.g.
+// an embedded client, which connects lazily. Collections are only obtained through
+// the latter, so their operations need no check. Without AutoConnect it returns
+// ErrNotConnected.
+//
+// Concurrent first calls are safe: the connection serializes Connect and returns
+// early once connected. A failed attempt is not remembered, so the next call
+// retries instead of failing for the lifetime of the client.
+func (c *Client) ensureConnected(ctx context.Context) error {
+	if c.conn.IsConnected() {
+		return nil
+	}
+	if c.config == nil || !c.config.AutoConnect {
+		return ErrNotConnected
+	}
+	if err := c.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	return nil
+}
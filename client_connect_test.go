@@ -0,0 +1,101 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lazyConnection is a connection that counts Connect calls, failing the first
+// failures of them
+type lazyConnection struct {
+	connection.Connection
+	mu        sync.Mutex
+	connected bool
+	connects  int
+	failures  int
+}
+
+func (l *lazyConnection) Connect(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.connected {
+		return nil
+	}
+	l.connects++
+	if l.failures > 0 {
+		l.failures--
+		return errors.New("connection refused")
+	}
+	l.connected = true
+	return nil
+}
+
+func (l *lazyConnection) IsConnected() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.connected
+}
+
+// TestClientEnsureConnected tests the lazy connect used by the raw Query/Execute helpers
+func TestClientEnsureConnected(t *testing.T) {
+	ctx := context.Background()
+
+	conn := &lazyConnection{}
+	client := &Client{conn: conn, config: &ClientConfig{AutoConnect: true}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.ensureConnected(ctx))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, conn.connects, "concurrent callers should connect once")
+
+	// A failed attempt is retried on the next call
+	conn = &lazyConnection{failures: 1}
+	client = &Client{conn: conn, config: &ClientConfig{AutoConnect: true}}
+	require.Error(t, client.ensureConnected(ctx))
+	require.NoError(t, client.ensureConnected(ctx))
+	assert.Equal(t, 2, conn.connects)
+
+	client = &Client{conn: &lazyConnection{}, config: &ClientConfig{AutoConnect: false}}
+	assert.ErrorIs(t, client.ensureConnected(ctx), ErrNotConnected)
+}
+
+// TestClientOperationsEnsureConnected tests that the raw helpers and collection
+// management operations connect first, and fail without AutoConnect
+func TestClientOperationsEnsureConnected(t *testing.T) {
+	ctx := context.Background()
+
+	client := &Client{conn: &lazyConnection{}, config: &ClientConfig{AutoConnect: false}}
+	_, err := client.Query(ctx, "SELECT 1")
+	assert.ErrorIs(t, err, ErrNotConnected)
+	_, err = client.Execute(ctx, "SELECT 1")
+	assert.ErrorIs(t, err, ErrNotConnected)
+	assert.ErrorIs(t, client.QueryRow(ctx, "SELECT 1").Scan(new(int)), ErrNotConnected)
+	_, err = client.HasCollection(ctx, "docs")
+	assert.ErrorIs(t, err, ErrNotConnected)
+	_, err = client.GetCollection(ctx, "docs")
+	assert.ErrorIs(t, err, ErrNotConnected)
+	_, err = client.ListCollectionNames(ctx)
+	assert.ErrorIs(t, err, ErrNotConnected)
+	assert.ErrorIs(t, client.DeleteCollection(ctx, "docs"), ErrNotConnected)
+
+	// With AutoConnect every call attempts to connect
+	conn := &lazyConnection{failures: 3}
+	client = &Client{conn: conn, config: &ClientConfig{AutoConnect: true}}
+	_, err = client.Execute(ctx, "SELECT 1")
+	assert.ErrorContains(t, err, "failed to connect")
+	assert.ErrorContains(t, client.QueryRow(ctx, "SELECT 1").Scan(new(int)), "connection refused")
+	_, err = client.CreateCollection(ctx, "docs", WithConfiguration(&HNSWConfiguration{Dimension: 3}))
+	assert.ErrorContains(t, err, "failed to connect")
+	assert.Equal(t, 3, conn.connects)
+}
@@ -0,0 +1,269 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+)
+
+const (
+	// DefaultCopyBatchSize is the number of rows per INSERT used by CopyFrom.
+	DefaultCopyBatchSize = 500
+	// DefaultCopyWorkers is the number of concurrent INSERT workers used by CopyFrom.
+	DefaultCopyWorkers = 4
+)
+
+// collectionCopyFrom bulk loads records into a collection with multi-row prepared INSERTs.
+func (c *Client) collectionCopyFrom(ctx context.Context, collectionName string, records <-chan Record, opts *CopyOptions, embFunc embedding.EmbeddingFunc) (copied int64, err error) {
+	ctx, span := c.startSpan(ctx, "copy_from", collectionName)
+	defer func() { span.end(int(copied), err) }()
+
+	stmts := newCopyStatements(c.conn, c.GetTableName(collectionName), opts)
+	defer stmts.close()
+
+	return copyRecords(ctx, records, opts, func(ctx context.Context, batch []Record) (int64, error) {
+		if err := embedMissingRecords(ctx, batch, embFunc); err != nil {
+			return 0, err
+		}
+		args, err := copyRecordArgs(batch, opts)
+		if err != nil {
+			return 0, err
+		}
+
+		ctx, cancel := c.writeContext(ctx)
+		defer cancel()
+
+		var inserted int64
+		err = c.retryOnDeadlock(ctx, func() error {
+			inserted, err = stmts.exec(ctx, len(batch), args)
+			return err
+		})
+		return inserted, err
+	})
+}
+
+// copyRecords drains records into batches and passes them to insert on a pool of
+// workers. insert returns the number of rows it stored. Batch errors are collected
+// rather than aborting the load, and returned joined along with the rows inserted.
+func copyRecords(ctx context.Context, records <-chan Record, opts *CopyOptions, insert func(ctx context.Context, batch []Record) (int64, error)) (int64, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultCopyBatchSize
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultCopyWorkers
+	}
+
+	var (
+		mu     sync.Mutex
+		copied int64
+		errs   []error
+		wg     sync.WaitGroup
+	)
+
+	batches := make(chan []Record, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				inserted, err := insert(ctx, batch)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to copy batch starting at id %q: %w", batch[0].ID, err))
+				} else {
+					copied += inserted
+					if opts.Progress != nil {
+						opts.Progress(copied)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	batch := make([]Record, 0, batchSize)
+	send := func() bool {
+		select {
+		case batches <- batch:
+			batch = make([]Record, 0, batchSize)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+drain:
+	for {
+		select {
+		case <-ctx.Done():
+			break drain
+		case record, ok := <-records:
+			if !ok {
+				if len(batch) > 0 {
+					send()
+				}
+				break drain
+			}
+			batch = append(batch, record)
+			if len(batch) == batchSize && !send() {
+				break drain
+			}
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return copied, errors.Join(errs...)
+}
+
+// embedMissingRecords fills in the embeddings of records that were given without one.
+func embedMissingRecords(ctx context.Context, batch []Record, embFunc embedding.EmbeddingFunc) error {
+	var texts []string
+	var indexes []int
+	for i, record := range batch {
+		if record.Embedding == nil {
+			texts = append(texts, record.Document)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	if embFunc == nil {
+		return fmt.Errorf("record %q has no embedding: %w", batch[indexes[0]].ID, ErrEmbeddingFunctionRequired)
+	}
+
+	embeddings, err := embedding.EmbedWithContext(ctx, embFunc, texts)
+	if err != nil {
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
+	for j, i := range indexes {
+		batch[i].Embedding = embeddings[j]
+	}
+	return nil
+}
+
+// copyRecordArgs flattens a batch into the arguments of a copy INSERT.
+func copyRecordArgs(batch []Record, opts *CopyOptions) ([]interface{}, error) {
+	args := make([]interface{}, 0, 5*len(batch))
+	for _, record := range batch {
+		if record.ID == "" {
+			return nil, fmt.Errorf("%w: record ID must not be empty", ErrInvalidParameter)
+		}
+		if opts.vectorsOnly {
+			if record.Document != "" || len(record.Metadata) > 0 {
+				return nil, fmt.Errorf("%w: record %q has a document or metadata, but the collection stores vectors only", ErrInvalidParameter, record.ID)
+			}
+			args = append(args, record.ID, vectorToString(record.Embedding))
+			continue
+		}
+		metadataJSON, err := record.Metadata.ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata for %q: %w", record.ID, err)
+		}
+		args = append(args, record.ID, documentArg(record.Document, opts.emptyDocumentsAsNull), vectorToString(record.Embedding), metadataJSON)
+		if opts.contentHash {
+			args = append(args, contentHashArg(record.Document))
+		}
+	}
+	return args, nil
+}
+
+// copyInsertSQL returns an INSERT of rows records into tableName. With contentHash,
+// the hash column is written too and rows with already stored content are skipped.
+// With vectorsOnly, only the ID and embedding columns are written.
+func copyInsertSQL(tableName string, rows int, opts *CopyOptions) string {
+	columns := []string{FieldID, FieldDocument, FieldEmbedding, FieldMetadata}
+	if opts.vectorsOnly {
+		columns = []string{FieldID, FieldEmbedding}
+	}
+	insert := "INSERT"
+	if opts.contentHash {
+		columns = append(columns, FieldContentHash)
+		insert = "INSERT IGNORE"
+	}
+
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	values := make([]string, rows)
+	for i := range values {
+		values[i] = placeholders
+	}
+	return fmt.Sprintf("%s INTO %s (%s) VALUES %s", insert, tableName, strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+// copyStatements prepares copy INSERTs once per batch size and shares them across
+// workers. Connections without a *sql.DB fall back to unprepared statements.
+type copyStatements struct {
+	conn      connection.Connection
+	tableName string
+	opts      *CopyOptions
+
+	mu    sync.Mutex
+	stmts map[int]*sql.Stmt
+}
+
+func newCopyStatements(conn connection.Connection, tableName string, opts *CopyOptions) *copyStatements {
+	return &copyStatements{conn: conn, tableName: tableName, opts: opts, stmts: make(map[int]*sql.Stmt)}
+}
+
+// exec inserts rows records with args, preparing the statement on first use,
+// and returns the number of rows stored.
+func (s *copyStatements) exec(ctx context.Context, rows int, args []interface{}) (int64, error) {
+	var result sql.Result
+	db, ok := s.conn.RawConnection().(*sql.DB)
+	if !ok || db == nil {
+		var err error
+		result, err = s.conn.Execute(ctx, copyInsertSQL(s.tableName, rows, s.opts), args...)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		stmt, err := s.prepare(ctx, db, rows)
+		if err != nil {
+			return 0, err
+		}
+		if result, err = stmt.ExecContext(ctx, args...); err != nil {
+			return 0, err
+		}
+	}
+	return result.RowsAffected()
+}
+
+func (s *copyStatements) prepare(ctx context.Context, db *sql.DB, rows int) (*sql.Stmt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stmt, ok := s.stmts[rows]; ok {
+		return stmt, nil
+	}
+	stmt, err := db.PrepareContext(ctx, copyInsertSQL(s.tableName, rows, s.opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	s.stmts[rows] = stmt
+	return stmt, nil
+}
+
+func (s *copyStatements) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stmt := range s.stmts {
+		stmt.Close()
+	}
+}
@@ -0,0 +1,170 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordsChannel returns a closed channel holding n records with sequential IDs
+func recordsChannel(n int) <-chan Record {
+	records := make(chan Record, n)
+	for i := 0; i < n; i++ {
+		records <- Record{
+			ID:        fmt.Sprintf("id%04d", i),
+			Document:  fmt.Sprintf("document %d", i),
+			Embedding: []float32{float32(i), 1, 2},
+			Metadata:  Metadata{"index": i},
+		}
+	}
+	close(records)
+	return records
+}
+
+// TestCopyRecordsBatches tests that records are split into batches across workers
+func TestCopyRecordsBatches(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	var seen []string
+	var progress []int64
+
+	opts := &CopyOptions{
+		BatchSize: 10,
+		Workers:   3,
+		Progress:  func(copied int64) { progress = append(progress, copied) },
+	}
+	copied, err := copyRecords(context.Background(), recordsChannel(35), opts, func(ctx context.Context, batch []Record) (int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(batch))
+		for _, record := range batch {
+			seen = append(seen, record.ID)
+		}
+		return int64(len(batch)), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(35), copied)
+	assert.ElementsMatch(t, []int{10, 10, 10, 5}, sizes)
+	assert.Len(t, seen, 35)
+
+	require.Len(t, progress, 4)
+	assert.Equal(t, int64(35), progress[3])
+	for i := 1; i < len(progress); i++ {
+		assert.Greater(t, progress[i], progress[i-1])
+	}
+}
+
+// TestCopyRecordsAggregatesErrors tests that failed batches do not stop the load
+func TestCopyRecordsAggregatesErrors(t *testing.T) {
+	errBatch := errors.New("batch failed")
+
+	opts := &CopyOptions{BatchSize: 5, Workers: 2}
+	copied, err := copyRecords(context.Background(), recordsChannel(20), opts, func(ctx context.Context, batch []Record) (int64, error) {
+		if batch[0].ID == "id0005" || batch[0].ID == "id0015" {
+			return 0, errBatch
+		}
+		return int64(len(batch)), nil
+	})
+	assert.Equal(t, int64(10), copied)
+	require.ErrorIs(t, err, errBatch)
+	assert.Contains(t, err.Error(), `"id0005"`)
+	assert.Contains(t, err.Error(), `"id0015"`)
+}
+
+// TestCopyRecordsCancelled tests that cancelling the context stops reading records
+func TestCopyRecordsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	records := make(chan Record) // Never closed
+
+	cancel()
+
+	opts := &CopyOptions{BatchSize: 2, Workers: 1}
+	copied, err := copyRecords(ctx, records, opts, func(ctx context.Context, batch []Record) (int64, error) {
+		return int64(len(batch)), nil
+	})
+	assert.Equal(t, int64(0), copied)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestCopyInsertSQL tests the multi-row INSERT and its arguments
+func TestCopyInsertSQL(t *testing.T) {
+	assert.Equal(t,
+		"INSERT INTO c$v1$docs (_id, document, embedding, metadata) VALUES (?, ?, ?, ?), (?, ?, ?, ?)",
+		copyInsertSQL("c$v1$docs", 2, &CopyOptions{}))
+
+	args, err := copyRecordArgs([]Record{
+		{ID: "a", Document: "", Embedding: []float32{1, 2}, Metadata: Metadata{"k": "v"}},
+		{ID: "b", Document: "text", Embedding: []float32{3, 4}},
+	}, &CopyOptions{emptyDocumentsAsNull: true})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", nil, "[1,2]", `{"k":"v"}`, "b", "text", "[3,4]", "{}"}, args)
+
+	_, err = copyRecordArgs([]Record{{Document: "no id"}}, &CopyOptions{})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestEmbedMissingRecords tests that only records without embeddings are embedded
+func TestEmbedMissingRecords(t *testing.T) {
+	batch := []Record{
+		{ID: "a", Document: "first", Embedding: []float32{9, 9, 9}},
+		{ID: "b", Document: "second"},
+	}
+	err := embedMissingRecords(context.Background(), batch, nil)
+	assert.ErrorIs(t, err, ErrEmbeddingFunctionRequired)
+
+	require.NoError(t, embedMissingRecords(context.Background(), batch, &lengthEmbedder{}))
+	assert.Equal(t, []float32{9, 9, 9}, batch[0].Embedding)
+	assert.Equal(t, []float32{float32(len("second")), 0, 0}, batch[1].Embedding)
+}
+
+// lengthEmbedder embeds each text as [len(text), 0, 0]
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) Embed(texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = []float32{float32(len(text)), 0, 0}
+	}
+	return embeddings, nil
+}
+
+func (lengthEmbedder) Dimension() int { return 3 }
+
+// TestCollectionCopyFrom tests bulk loading against a live database
+func TestCollectionCopyFrom(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_copy_from_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	var lastProgress int64
+	copied, err := collection.CopyFrom(ctx, recordsChannel(1234),
+		WithCopyBatchSize(100),
+		WithCopyWorkers(4),
+		WithCopyProgress(func(copied int64) { lastProgress = copied }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), copied)
+	assert.Equal(t, int64(1234), lastProgress)
+
+	count, err := collection.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1234, count)
+
+	result, err := collection.Get(ctx, []string{"id0042"})
+	require.NoError(t, err)
+	require.Len(t, result.IDs, 1)
+	assert.Equal(t, "document 42", result.Documents[0])
+}
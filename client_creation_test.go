@@ -113,6 +113,44 @@ func getOBPassword() string {
 	return os.Getenv("OB_PASSWORD")
 }
 
+// ==================== Test Helpers ====================
+
+// createTestClient connects a client to the seekdb Server configured by the
+// environment, skipping the test if the server is unreachable.
+func createTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	client, err := NewClient(
+		WithHost(getServerHost()),
+		WithPort(getServerPort()),
+		WithTenant("sys"), // Default tenant for seekdb Server
+		WithDatabase(getServerDatabase()),
+		WithUser(getServerUser()),
+		WithPassword(getServerPassword()),
+		WithAutoConnect(false),
+	)
+	require.NoError(t, err, "Failed to create server client")
+
+	if err := client.Connect(context.Background()); err != nil {
+		client.Close()
+		t.Skipf("Server connection failed (%s:%d): %v", getServerHost(), getServerPort(), err)
+	}
+	return client
+}
+
+// createTestCollection creates a collection of the given dimension with L2 distance
+// and no embedding function, so tests provide their own embeddings.
+func createTestCollection(t *testing.T, client *Client, name string, dimension int) *Collection {
+	t.Helper()
+
+	collection, err := client.CreateCollection(context.Background(), name,
+		WithConfiguration(&HNSWConfiguration{Dimension: dimension, Distance: DistanceL2}),
+		WithCollectionEmbeddingFunc(nil),
+	)
+	require.NoError(t, err, "Failed to create collection %s", name)
+	return collection
+}
+
 // testCollectionManagement tests common collection management interfaces
 func testCollectionManagement(t *testing.T, client *Client) {
 	ctx := context.Background()
@@ -427,3 +465,52 @@ func TestCreateOceanBaseClient(t *testing.T) {
 	// Test all collection management interfaces
 	testCollectionManagement(t, client)
 }
+
+func TestCreateServerClientAutoCreateDatabase(t *testing.T) {
+	ctx := context.Background()
+	databaseName := "test_autocreate_" + uuid.New().String()[:8]
+
+	// Create client pointing at a database that does not exist yet
+	client, err := NewClient(
+		WithHost(getServerHost()),
+		WithPort(getServerPort()),
+		WithTenant("sys"), // Default tenant for seekdb Server
+		WithDatabase(databaseName),
+		WithUser(getServerUser()),
+		WithPassword(getServerPassword()),
+		WithAutoConnect(false),
+		WithAutoCreateDatabase(true),
+	)
+	require.NoError(t, err, "Failed to create server client")
+	defer client.Close()
+
+	admin, err := NewAdminClient(
+		WithHost(getServerHost()),
+		WithPort(getServerPort()),
+		WithTenant("sys"),
+		WithUser(getServerUser()),
+		WithPassword(getServerPassword()),
+		WithAutoConnect(false),
+	)
+	require.NoError(t, err, "Failed to create admin client")
+	defer admin.Close()
+
+	if err := admin.Connect(ctx); err != nil {
+		t.Skipf("Server connection failed (%s:%d): %v\nHint: Please ensure seekdb Server is running on port %d",
+			getServerHost(), getServerPort(), err, getServerPort())
+	}
+	defer admin.DeleteDatabase(ctx, databaseName)
+
+	exists, err := admin.HasDatabase(ctx, databaseName)
+	require.NoError(t, err)
+	require.False(t, exists, "Database should not exist before connecting")
+
+	// Connect should provision the database and then connect to it
+	err = client.Connect(ctx)
+	require.NoError(t, err, "Failed to connect with auto-created database")
+	assert.True(t, client.IsConnected(), "Should be connected after Connect()")
+
+	exists, err = admin.HasDatabase(ctx, databaseName)
+	require.NoError(t, err)
+	assert.True(t, exists, "Database should be created by Connect()")
+}
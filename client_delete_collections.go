@@ -0,0 +1,82 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DeleteCollections deletes every collection whose name matches pattern and returns
+// how many were deleted. A pattern containing any of *, ? or [ is a glob as
+// understood by path.Match, e.g. "test_query_*"; any other pattern is a name prefix.
+//
+// A pattern that matches every collection, such as "", "*" or "?*", or that matches
+// all collections in the database, such as "[a-z]*" when all names are lowercase,
+// is rejected unless WithConfirmDeleteAll(true) is passed. If deleting a collection
+// fails, the collections deleted so far are counted and the error is returned.
+func (c *Client) DeleteCollections(ctx context.Context, pattern string, opts ...DeleteCollectionsOption) (int, error) {
+	options := &DeleteCollectionsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// Reject a malformed glob before deleting anything
+	if _, err := matchCollectionName(pattern, ""); err != nil {
+		return 0, err
+	}
+	if !options.ConfirmDeleteAll && matchesAnyCollectionName(pattern) {
+		return 0, fmt.Errorf("%w: pattern %q matches every collection; pass WithConfirmDeleteAll(true) to delete them all", ErrInvalidParameter, pattern)
+	}
+
+	names, err := c.ListCollectionNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var matches []string
+	for _, name := range names {
+		if matched, _ := matchCollectionName(pattern, name); matched {
+			matches = append(matches, name)
+		}
+	}
+	if !options.ConfirmDeleteAll && len(matches) > 0 && len(matches) == len(names) {
+		return 0, fmt.Errorf("%w: pattern %q matches all %d collections; pass WithConfirmDeleteAll(true) to delete them all", ErrInvalidParameter, pattern, len(names))
+	}
+
+	deleted := 0
+	for _, name := range matches {
+		if err := c.DeleteCollection(ctx, name); err != nil {
+			return deleted, fmt.Errorf("failed to delete collection %s: %w", name, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// matchCollectionName reports whether name matches a DeleteCollections pattern.
+func matchCollectionName(pattern, name string) (bool, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.HasPrefix(name, pattern), nil
+	}
+	matched, err := path.Match(pattern, name)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid collection name pattern %q: %v", ErrInvalidParameter, pattern, err)
+	}
+	return matched, nil
+}
+
+// probeCollectionNames are names of different shapes; a pattern matching all of
+// them is taken to match any collection name.
+var probeCollectionNames = []string{"a", "Z", "0", "_", "-", "docs", "Test_Collection-2", "c0ll3ction_with_a_much_longer_name_0123456789"}
+
+// matchesAnyCollectionName reports whether a DeleteCollections pattern matches
+// every collection name, e.g. "", "*", "**" or "?*".
+func matchesAnyCollectionName(pattern string) bool {
+	for _, name := range probeCollectionNames {
+		if matched, _ := matchCollectionName(pattern, name); !matched {
+			return false
+		}
+	}
+	return true
+}
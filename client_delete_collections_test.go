@@ -0,0 +1,92 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchCollectionName tests glob and prefix matching of DeleteCollections patterns
+func TestMatchCollectionName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"test_query_", "test_query_1a2b", true},
+		{"test_query_", "test_get_1a2b", false},
+		{"test_*", "test_query_1a2b", true},
+		{"test_*_1a2b", "test_get_1a2b", true},
+		{"test_?", "test_ab", false},
+		{"test_[ab]", "test_b", true},
+		{"", "anything", true},
+		{"*", "anything", true},
+	}
+	for _, tt := range tests {
+		matched, err := matchCollectionName(tt.pattern, tt.name)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, matched, "%q ~ %q", tt.pattern, tt.name)
+	}
+
+	_, err := matchCollectionName("test_[", "")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestDeleteCollectionsRequiresConfirmation tests that patterns matching everything are rejected
+func TestDeleteCollectionsRequiresConfirmation(t *testing.T) {
+	client := &Client{}
+	for _, pattern := range []string{"", "*", "**", "?*", "*?"} {
+		deleted, err := client.DeleteCollections(context.Background(), pattern)
+		assert.ErrorIs(t, err, ErrInvalidParameter, pattern)
+		assert.Zero(t, deleted)
+	}
+
+	_, err := client.DeleteCollections(context.Background(), "test_[")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	assert.False(t, matchesAnyCollectionName("[a-z]*"))
+	assert.False(t, matchesAnyCollectionName("test_"))
+	assert.False(t, matchesAnyCollectionName("?"))
+
+	// A pattern matching all collections in the database is rejected before any delete
+	db := registerStaticRows(t, "delete_collections_names", []string{"TABLE_NAME"},
+		[][]driver.Value{{[]byte("c$v1$docs")}, {[]byte("c$v1$notes")}})
+	client = &Client{conn: &staticConnection{db: db, key: "delete_collections_names"}, config: &ClientConfig{}}
+	deleted, err := client.DeleteCollections(context.Background(), "[a-z]*")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	assert.Zero(t, deleted)
+}
+
+// TestDeleteCollections tests deleting the collections matching a pattern
+func TestDeleteCollections(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	prefix := "test_delete_many_" + uuid.New().String()[:8] + "_"
+	for _, suffix := range []string{"a1", "a2", "b1"} {
+		createTestCollection(t, client, prefix+suffix, 3)
+	}
+	defer func() {
+		_, _ = client.DeleteCollections(ctx, prefix)
+	}()
+
+	deleted, err := client.DeleteCollections(ctx, prefix+"a*")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	exists, err := client.HasCollection(ctx, prefix+"a1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = client.HasCollection(ctx, prefix+"b1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	deleted, err = client.DeleteCollections(ctx, prefix)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
@@ -0,0 +1,58 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ListCollectionNames returns the names of all collections in the current database,
+// sorted. Unlike ListCollections, it reads only table names in a single query and
+// does not fetch each collection's dimension or distance. A collection present in
+// several schema versions is listed once.
+func (c *Client) ListCollectionNames(ctx context.Context) (names []string, err error) {
+	ctx, span := c.startSpan(ctx, "list_names", "")
+	defer func() { span.end(len(names), err) }()
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT TABLE_NAME
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME LIKE ?
+	`
+
+	// "_" is a LIKE wildcard too; collectionNameFromTable filters the extra matches
+	pattern := "c$v%"
+	if c.config != nil && c.config.TablePrefix != "" {
+		pattern = c.config.TablePrefix + "%"
+	}
+
+	rows, err := c.conn.Query(ctx, query, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection names: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		name, ok := c.collectionNameFromTable(tableName)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,37 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListCollectionNames tests listing collection names from table names
+func TestListCollectionNames(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "list_names_tables", []string{"TABLE_NAME"}, [][]driver.Value{
+		{[]byte("c$v2$notes")},
+		{[]byte("c$v1$docs")},
+		{[]byte("c$v2$docs")},
+		{[]byte("cxv1$other")},
+		{[]byte("app_users")},
+	})
+	client := &Client{
+		conn:   &staticConnection{db: db, key: "list_names_tables"},
+		config: &ClientConfig{},
+	}
+
+	// Collections in several schema versions are listed once; other tables are skipped
+	names, err := client.ListCollectionNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs", "notes"}, names)
+
+	// With a custom prefix, only tables carrying it are collections
+	WithTablePrefix("app_")(client.config)
+	names, err = client.ListCollectionNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users"}, names)
+}
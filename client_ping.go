@@ -0,0 +1,14 @@
+package goseekdb
+
+import "context"
+
+// Ping verifies the server is reachable. Connections that died, e.g. after a server
+// restart, are replaced by the connection pool, and a Ping or query that hits one is
+// retried once on another, so long-lived clients recover without a Close/NewClient
+// cycle. Writes are never retried.
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	return c.conn.Ping(ctx)
+}
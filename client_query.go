@@ -3,119 +3,598 @@ package goseekdb
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/ob-labs/seekdb-go/internal/connection"
 )
 
 // collectionQuery implements the Query operation for collections.
-func (c *Client) collectionQuery(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*QueryResult, error) {
-	// If query embeddings are provided, use them directly. If not, generate them from query texts.
-	var queryEmbeddings [][]float32
-	if opts.QueryEmbeddings != nil {
-		queryEmbeddings = opts.QueryEmbeddings
-	} else if len(queryTexts) > 0 {
-		if embFunc == nil {
-			return nil, ErrEmbeddingFunctionRequired
-		}
-		var err error
-		queryEmbeddings, err = embFunc.Embed(queryTexts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate query embeddings: %w", err)
-		}
-	} else {
-		return nil, fmt.Errorf("%w: must provide query_texts or query_embeddings", ErrInvalidParameter)
+func (c *Client) collectionQuery(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (result *QueryResult, err error) {
+	ctx, span := c.startSpan(ctx, "query", collectionName)
+	defer func() { span.end(countQueryHits(result), err) }()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	plan, err := c.prepareVectorQuery(ctx, collectionName, queryTexts, opts, embFunc, distance)
+	if err != nil {
+		return nil, err
+	}
+	opts, queryEmbeddings := plan.opts, plan.embeddings
+
+	tableName := c.GetTableName(collectionName)
+	include := opts.include()
+	result = &QueryResult{IDs: make([][]string, len(queryEmbeddings))}
+	if include.distances {
+		result.Distances = make([][]float64, len(queryEmbeddings))
+	}
+	if include.documents {
+		result.Documents = make([][]string, len(queryEmbeddings))
+	}
+	if include.metadatas {
+		result.Metadatas = make([][]Metadata, len(queryEmbeddings))
+	}
+	if include.embeddings {
+		result.Embeddings = make([][][]float32, len(queryEmbeddings))
 	}
 
-	tableName := GetTableName(collectionName)
-	result := &QueryResult{
-		IDs:        make([][]string, len(queryEmbeddings)),
-		Distances:  make([][]float64, len(queryEmbeddings)),
-		Documents:  make([][]string, len(queryEmbeddings)),
-		Metadatas:  make([][]Metadata, len(queryEmbeddings)),
-		Embeddings: make([][][]float32, len(queryEmbeddings)),
+	if opts.Stats {
+		result.Stats = make([]QueryStats, len(queryEmbeddings))
+	}
+	raw := newRawColumnScanner(opts.ExtraColumns)
+	if raw != nil {
+		result.RawColumns = make([][]map[string]interface{}, len(queryEmbeddings))
+	}
+
+	session, endSession, err := c.vectorQuerySession(ctx, opts.EfSearch)
+	if err != nil {
+		return nil, err
 	}
+	defer endSession()
 
 	// Execute query for each embedding
+	distanceFunc := c.distanceFuncName(plan.distance)
 	for i, queryEmb := range queryEmbeddings {
-		// Build WHERE clause from filters
-		var conditions []string
-		var args []interface{}
+		querySQL, queryArgs := buildVectorQuerySQL(tableName, plan.whereClause, plan.whereArgs, queryEmb, nResults, plan.distance, distanceFunc, opts)
+		start := time.Now()
+		queryDone := c.startSlowQueryTimer("query", collectionName, nResults, querySQL)
+		rows, err := session.Query(ctx, querySQL, queryArgs...)
+		queryDone()
+		if err != nil {
+			if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+				return nil, notFound
+			}
+			return nil, fmt.Errorf("failed to query collection: %w", err)
+		}
+
+		ids, distances, documents, metadatas, embeddings, rawColumns, err := c.scanQueryResults(rows, opts.EmbeddingDecodeWorkers, include, raw)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		result.IDs[i] = ids
+		if include.distances {
+			result.Distances[i] = distances
+		}
+		if include.documents {
+			result.Documents[i] = documents
+		}
+		if include.metadatas {
+			result.Metadatas[i] = metadatas
+		}
+		if include.embeddings {
+			result.Embeddings[i] = embeddings
+		}
+		if raw != nil {
+			result.RawColumns[i] = rawColumns
+		}
 
-		if opts.Where != nil {
-			clause, filterArgs, err := c.filterBuilder.BuildMetadataFilter(opts.Where)
-			if err != nil {
+		if opts.Stats {
+			result.Stats[i].Elapsed = time.Since(start)
+			// Explained on the same session, so session settings like ef_search apply
+			if err := c.explainVectorQuery(ctx, session, querySQL, queryArgs, VectorFieldColumn(opts.QueryField), &result.Stats[i]); err != nil {
 				return nil, err
 			}
-			if clause != "" {
-				conditions = append(conditions, clause)
-				args = append(args, filterArgs...)
+		}
+	}
+
+	return result, nil
+}
+
+// collectionQueryEach implements the QueryWithCallback operation for collections.
+// Rows are decoded and passed to fn one at a time; an error from fn closes the
+// result set and is returned as is.
+func (c *Client) collectionQueryEach(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric, fn func(SearchRecord) error) (err error) {
+	rowCount := 0
+	ctx, span := c.startSpan(ctx, "query", collectionName)
+	defer func() { span.end(rowCount, err) }()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	plan, err := c.prepareVectorQuery(ctx, collectionName, queryTexts, opts, embFunc, distance)
+	if err != nil {
+		return err
+	}
+	opts = plan.opts
+
+	session, endSession, err := c.vectorQuerySession(ctx, opts.EfSearch)
+	if err != nil {
+		return err
+	}
+	defer endSession()
+
+	tableName := c.GetTableName(collectionName)
+	distanceFunc := c.distanceFuncName(plan.distance)
+	raw := newRawColumnScanner(opts.ExtraColumns)
+	include := opts.include()
+	buf := getQueryScanBuffer()
+	defer putQueryScanBuffer(buf)
+	for i, queryEmb := range plan.embeddings {
+		querySQL, queryArgs := buildVectorQuerySQL(tableName, plan.whereClause, plan.whereArgs, queryEmb, nResults, plan.distance, distanceFunc, opts)
+		queryDone := c.startSlowQueryTimer("query", collectionName, nResults, querySQL)
+		rows, err := session.Query(ctx, querySQL, queryArgs...)
+		queryDone()
+		if err != nil {
+			if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+				return notFound
 			}
+			return fmt.Errorf("failed to query collection: %w", err)
 		}
 
-		if opts.WhereDocument != nil {
-			clause, filterArgs, err := c.filterBuilder.BuildDocumentFilter(opts.WhereDocument)
-			if err != nil {
-				return nil, err
+		err = func() error {
+			defer rows.Close()
+			for rank := 0; rows.Next(); rank++ {
+				record := SearchRecord{QueryIndex: i, Rank: rank}
+				if err := rows.Scan(raw.dest(include.dest(buf))...); err != nil {
+					return err
+				}
+				record.ID = buf.id
+				record.Distance = buf.distance
+				record.Document = buf.document.String // NULL documents are returned as ""
+				record.RawColumns = raw.row()
+				if include.metadatas {
+					if err := c.decodeMetadata(buf.metadataJSON, &record.Metadata); err != nil {
+						return fmt.Errorf("failed to decode metadata of %q: %w", record.ID, err)
+					}
+				}
+				if include.embeddings {
+					if err := json.Unmarshal([]byte(buf.embeddingJSON), &record.Embedding); err != nil {
+						return fmt.Errorf("failed to decode embedding of %q: %w", record.ID, err)
+					}
+				}
+
+				rowCount++
+				if err := fn(record); err != nil {
+					return err
+				}
 			}
-			if clause != "" {
-				conditions = append(conditions, clause)
-				args = append(args, filterArgs...)
+			return rows.Err()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vectorQueryPlan is what collectionQuery and collectionQueryEach need to run a
+// vector query, resolved by prepareVectorQuery.
+type vectorQueryPlan struct {
+	opts        *QueryOptions
+	embeddings  [][]float32
+	distance    DistanceMetric
+	whereClause string
+	whereArgs   []interface{}
+}
+
+// prepareVectorQuery validates the options of a vector query, then embeds the query
+// texts and resolves the distance metric and filters. Options are validated first,
+// so invalid ones fail before any embedding is generated.
+func (c *Client) prepareVectorQuery(ctx context.Context, collectionName string, queryTexts []string, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*vectorQueryPlan, error) {
+	if err := opts.Tiebreaker.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateExtraColumns(opts.ExtraColumns); err != nil {
+		return nil, err
+	}
+	if err := validateQueryInclude(opts.Include); err != nil {
+		return nil, err
+	}
+	if err := validateFilters(opts.Where, opts.WhereDocument); err != nil {
+		return nil, err
+	}
+	if opts.vectorsOnly {
+		if err := validateVectorsOnlyQuery(collectionName, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	queryEmbeddings, err := resolveQueryEmbeddings(ctx, queryTexts, opts, embFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.QueryField != "" {
+		// A named vector field is searched with its own distance metric
+		if distance, err = c.vectorFieldDistance(ctx, collectionName, opts.QueryField, distance); err != nil {
+			return nil, err
+		}
+	}
+
+	opts, err = c.autoExactOptions(ctx, collectionName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	whereClause, whereArgs, err := c.buildQueryWhereClause(collectionName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vectorQueryPlan{
+		opts:        opts,
+		embeddings:  queryEmbeddings,
+		distance:    distance,
+		whereClause: whereClause,
+		whereArgs:   whereArgs,
+	}, nil
+}
+
+// hnswEfSearchVariable is the session variable holding the HNSW search candidate list size.
+const hnswEfSearchVariable = "ob_hnsw_ef_search"
+
+// rowQuerier runs queries returning rows; both connections and transactions are one.
+type rowQuerier interface {
+	Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// sessionRestoreTimeout bounds restoring session variables after a query, which
+// also runs once the query's context is done.
+const sessionRestoreTimeout = 5 * time.Second
+
+// vectorQuerySession returns where to run vector queries and a function to call when
+// done. Without efSearch that is the connection itself. Otherwise it is a transaction,
+// which pins one session, with ob_hnsw_ef_search set to efSearch; ending the session
+// restores the previous value before the connection returns to the pool. SET is not
+// undone by a rollback, so if the value cannot be restored, even after the query
+// timed out or was canceled, the connection is closed instead.
+func (c *Client) vectorQuerySession(ctx context.Context, efSearch int) (rowQuerier, func(), error) {
+	if efSearch <= 0 {
+		return c.conn, func() {}, nil
+	}
+
+	tx, err := c.conn.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var previous int64
+	if err := tx.QueryRow(ctx, "SELECT @@"+hnswEfSearchVariable).Scan(&previous); err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("failed to read %s: %w", hnswEfSearchVariable, err)
+	}
+	if _, err := tx.Execute(ctx, fmt.Sprintf("SET %s = %d", hnswEfSearchVariable, efSearch)); err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("failed to set %s: %w", hnswEfSearchVariable, err)
+	}
+
+	return tx, func() {
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sessionRestoreTimeout)
+		defer cancel()
+		if _, err := tx.Execute(restoreCtx, fmt.Sprintf("SET %s = %d", hnswEfSearchVariable, previous)); err != nil {
+			if discarder, ok := tx.(connection.Discarder); ok {
+				c.logger().Warnf("failed to restore %s to %d, closing the connection: %v", hnswEfSearchVariable, previous, err)
+				discarder.Discard()
+				return
 			}
+			c.logger().Warnf("failed to restore %s to %d: %v", hnswEfSearchVariable, previous, err)
 		}
+		tx.Rollback()
+	}, nil
+}
 
-		whereClause := ""
-		if len(conditions) > 0 {
-			whereClause = "WHERE " + strings.Join(conditions, " AND ")
+// resolveQueryEmbeddings returns the query embeddings from the options, or
+// generates them from the query texts, given as an argument or with WithQueryTexts.
+func resolveQueryEmbeddings(ctx context.Context, queryTexts []string, opts *QueryOptions, embFunc embedding.EmbeddingFunc) ([][]float32, error) {
+	if opts.QueryTexts != nil {
+		if len(queryTexts) > 0 && !reflect.DeepEqual(queryTexts, opts.QueryTexts) {
+			return nil, fmt.Errorf("%w: query texts passed both as an argument and with WithQueryTexts", ErrInvalidParameter)
 		}
+		queryTexts = opts.QueryTexts
+	}
 
-		// Build vector search query
-		// Note: Actual syntax depends on SeekDB's vector search implementation
-		// Use the appropriate distance function based on the collection's distance metric
-		distanceFunc := distance.DistanceFuncName()
+	// If query embeddings are provided, use them directly. If not, generate them from query texts.
+	if opts.QueryEmbeddings != nil {
+		return opts.QueryEmbeddings, nil
+	}
+	if len(queryTexts) == 0 {
+		return nil, fmt.Errorf("%w: must provide query_texts or query_embeddings", ErrInvalidParameter)
+	}
+	if embFunc == nil {
+		return nil, ErrEmbeddingFunctionRequired
+	}
 
-		// Convert vector to string format for SQL (embed directly in query like Python version)
-		vectorStr := vectorToString(queryEmb)
+	queryEmbeddings, err := embedding.EmbedWithContext(ctx, embFunc, queryTexts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embeddings: %w", err)
+	}
+	return queryEmbeddings, nil
+}
 
-		// Build SQL query with vector distance calculation embedded directly as string literal
-		querySQL := fmt.Sprintf(`
-			SELECT %s, %s, %s, %s,
-			       %s(%s, '%s') AS distance
-			FROM %s
-			%s
-			ORDER BY %s(%s, '%s')
-			APPROXIMATE
-			LIMIT ?
-		`, FieldID, FieldDocument, FieldMetadata, FieldEmbedding,
-			distanceFunc, FieldEmbedding, vectorStr, tableName, whereClause, distanceFunc, FieldEmbedding, vectorStr)
+// buildQueryWhereClause builds the WHERE clause of a vector query from its filters.
+func (c *Client) buildQueryWhereClause(collectionName string, opts *QueryOptions) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
 
-		queryArgs := append(args, nResults)
-		rows, err := c.conn.Query(ctx, querySQL, queryArgs...)
+	if opts.Where != nil {
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildMetadataFilter(opts.Where)
 		if err != nil {
-			return nil, fmt.Errorf("failed to query collection: %w", err)
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, filterArgs...)
 		}
+	}
 
-		ids, distances, documents, metadatas, embeddings, err := c.scanQueryResults(rows)
-		rows.Close()
+	if opts.WhereDocument != nil {
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildDocumentFilter(opts.WhereDocument)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, filterArgs...)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// buildVectorQuerySQL builds the vector search statement for a single query embedding.
+// It returns the SQL and all of its arguments in placeholder order: the select-list
+// distance, the WHERE clause arguments, the ORDER BY arguments and the LIMIT.
+// distanceFunc is the server's function for distance, or "" for its default name.
+func buildVectorQuerySQL(tableName, whereClause string, whereArgs []interface{}, queryEmb []float32, nResults int, distance DistanceMetric, distanceFunc string, opts *QueryOptions) (string, []interface{}) {
+	// Build vector search query
+	// Note: Actual syntax depends on SeekDB's vector search implementation
+	// Use the appropriate distance function based on the collection's distance metric
+	if distanceFunc == "" {
+		distanceFunc = distance.DistanceFuncName()
+	}
+
+	// Convert vector to string format for SQL
+	vectorStr := vectorToString(queryEmb)
+
+	// Search the default embedding column unless a named vector field was selected
+	vectorColumn := vectorFieldColumnSQL(opts.QueryField)
+
+	// Bind the vector literal, so no value is interpolated into the statement text
+	// and the text is the same for every query vector
+	distanceExpr := fmt.Sprintf("%s(%s, ?)", distanceFunc, vectorColumn)
+	selectArgs := []interface{}{vectorStr}
+	orderArgs := []interface{}{vectorStr}
+	orderExpr := distanceExpr
+	approximate := "APPROXIMATE"
+	if opts.Exact {
+		approximate = "" // Full scan with exact distances
+	}
+
+	// Most similar first: ascending for distances, descending for similarities
+	direction, boostOp := "ASC", "-"
+	if distance.HigherIsBetter() {
+		direction, boostOp = "DESC", "+"
+	}
+
+	if opts.ScoreBoost != nil {
+		// Boosted score: distance - weight * metadata[key] (or similarity + weight *
+		// metadata[key]), ordered like plain distances. The vector index can only
+		// serve ORDER BY on the raw distance, so boosting falls back to an exact scan.
+		orderExpr = fmt.Sprintf("(%s %s ? * COALESCE(CAST(JSON_EXTRACT(%s, ?) AS DOUBLE), 0))", distanceExpr, boostOp, FieldMetadata)
+		orderArgs = append(orderArgs, opts.ScoreBoost.Weight, "$."+opts.ScoreBoost.MetadataKey)
+		approximate = ""
+	}
+
+	orderBy := orderExpr + " " + direction
+	if opts.Tiebreaker != nil {
+		// Secondary keys are not served by the vector index either
+		tiebreaker, tiebreakerArgs := opts.Tiebreaker.sql()
+		orderBy += ", " + tiebreaker
+		orderArgs = append(orderArgs, tiebreakerArgs...)
+		approximate = ""
+	}
+
+	var thresholdCmp string
+	var thresholdArgs []interface{}
+	if opts.MaxDistance != nil {
+		thresholdCmp = "<="
+		if distance.HigherIsBetter() {
+			thresholdCmp = ">="
+		}
+		if approximate == "" {
+			// An exact scan can filter on the distance directly
+			thresholdCondition := fmt.Sprintf("%s %s ?", distanceExpr, thresholdCmp)
+			if whereClause == "" {
+				whereClause = "WHERE " + thresholdCondition
+			} else {
+				whereClause += " AND " + thresholdCondition
+			}
+			whereArgs = append(append([]interface{}(nil), whereArgs...), selectArgs...)
+			whereArgs = append(whereArgs, *opts.MaxDistance)
+		} else {
+			// A distance condition would keep the vector index from serving the
+			// top-k, so the threshold filters its candidates in an outer query
+			thresholdArgs = []interface{}{*opts.MaxDistance}
+		}
+	}
+
+	// Only the included columns are selected. ORDER BY and an exact threshold repeat
+	// the distance expression, so excluding distances only drops it from the select
+	// list; the threshold query filters on it and still selects it from its candidates.
+	include := opts.include()
+	candidateInclude := include
+	candidateInclude.distances = include.distances || thresholdArgs != nil
+	if !candidateInclude.distances {
+		selectArgs = nil
+	}
+
+	// Build SQL query with vector distance calculation embedded directly as string literal
+	extraColumns := extraColumnsSQL(opts.ExtraColumns)
+	querySQL := fmt.Sprintf(`
+		SELECT %s%s
+		FROM %s
+		%s
+		ORDER BY %s
+		%s
+		LIMIT ?
+	`, candidateInclude.columns(vectorColumn, distanceExpr+" AS distance"), extraColumns, tableName, whereClause, orderBy, approximate)
+
+	if thresholdArgs != nil {
+		querySQL = fmt.Sprintf(`
+		SELECT %s%s
+		FROM (%s) AS candidates
+		WHERE distance %s ?
+		ORDER BY distance %s
+	`, include.columns(vectorColumn, "distance"), extraColumns, querySQL, thresholdCmp, direction)
+	}
+
+	args := append(selectArgs, whereArgs...)
+	args = append(args, orderArgs...)
+	args = append(args, nResults)
+	args = append(args, thresholdArgs...)
+	return querySQL, args
+}
+
+// collectionGet implements the Get operation for collections.
+func (c *Client) collectionGet(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (result *GetResult, err error) {
+	ctx, span := c.startSpan(ctx, "get", collectionName)
+	defer func() {
+		rowCount := 0
+		if result != nil {
+			rowCount = len(result.IDs)
+		}
+		span.end(rowCount, err)
+	}()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	if err := validateFilters(opts.Where, opts.WhereDocument); err != nil {
+		return nil, err
+	}
+	if chunkedGetIDs(ids, opts) {
+		return c.collectionGetChunked(ctx, collectionName, ids, opts)
+	}
+	return c.getDocuments(ctx, collectionName, ids, opts)
+}
+
+// getDocuments runs a single Get statement.
+func (c *Client) getDocuments(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*GetResult, error) {
+	querySQL, queryArgs, err := c.buildGetSQL(collectionName, ids, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryDone := c.startSlowQueryTimer("get", collectionName, opts.Limit, querySQL)
+	rows, err := c.conn.Query(ctx, querySQL, queryArgs...)
+	queryDone()
+	if err != nil {
+		if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+			return nil, notFound
+		}
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	defer rows.Close()
+
+	metadataKeys := projectedMetadataKeys(opts)
+	raw := newRawColumnScanner(opts.ExtraColumns)
+	result := &GetResult{}
+	for rows.Next() {
+		// NULL documents are returned as ""
+		id, document, metadataJSON, embeddingJSON, err := scanGetRow(rows, metadataKeys, raw)
 		if err != nil {
 			return nil, err
 		}
+		if raw != nil {
+			result.RawColumns = append(result.RawColumns, raw.row())
+		}
 
-		result.IDs[i] = ids
-		result.Distances[i] = distances
-		result.Documents[i] = documents
-		result.Metadatas[i] = metadatas
-		result.Embeddings[i] = embeddings
+		result.IDs = append(result.IDs, id)
+		if !opts.vectorsOnly {
+			result.Documents = append(result.Documents, document.String)
+
+			var metadata Metadata
+			if err := c.decodeMetadata(metadataJSON, &metadata); err == nil {
+				result.Metadatas = append(result.Metadatas, metadata)
+			}
+		}
+
+		var embedding []float32
+		if err := json.Unmarshal([]byte(embeddingJSON), &embedding); err == nil {
+			result.Embeddings = append(result.Embeddings, embedding)
+		}
 	}
 
 	return result, nil
 }
 
-// collectionGet implements the Get operation for collections.
-func (c *Client) collectionGet(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*GetResult, error) {
-	tableName := GetTableName(collectionName)
+// collectionGetStream implements the GetStream operation for collections.
+// The returned iterator owns the live rows and must be closed by the caller.
+func (c *Client) collectionGetStream(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*HitIterator, error) {
+	if err := validateFilters(opts.Where, opts.WhereDocument); err != nil {
+		return nil, err
+	}
+	querySQL, queryArgs, err := c.buildGetSQL(collectionName, ids, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.conn.Query(ctx, querySQL, queryArgs...)
+	if err != nil {
+		if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+			return nil, notFound
+		}
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+
+	it := newHitIterator(rows, c.config != nil && c.config.IntegerMetadata)
+	it.metadataKeys = projectedMetadataKeys(opts)
+	it.raw = newRawColumnScanner(opts.ExtraColumns)
+	return it, nil
+}
+
+// buildGetSQL builds the SELECT statement and arguments for a Get operation.
+func (c *Client) buildGetSQL(collectionName string, ids []string, opts *GetOptions) (string, []interface{}, error) {
+	tableName := c.GetTableName(collectionName)
+
+	if err := opts.OrderBy.validate(); err != nil {
+		return "", nil, err
+	}
+	if opts.OrderBy != nil && (opts.paginate || opts.Cursor != "") {
+		return "", nil, fmt.Errorf("%w: ordering by metadata cannot be combined with cursor pagination", ErrInvalidParameter)
+	}
+	if err := validateExtraColumns(opts.ExtraColumns); err != nil {
+		return "", nil, err
+	}
+	if opts.vectorsOnly {
+		if err := validateVectorsOnlyGet(collectionName, opts); err != nil {
+			return "", nil, err
+		}
+	}
 
 	var conditions []string
 	var args []interface{}
@@ -132,9 +611,9 @@ func (c *Client) collectionGet(ctx context.Context, collectionName string, ids [
 
 	// Add metadata filter
 	if opts.Where != nil {
-		clause, filterArgs, err := c.filterBuilder.BuildMetadataFilter(opts.Where)
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildMetadataFilter(opts.Where)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		if clause != "" {
 			conditions = append(conditions, clause)
@@ -144,9 +623,9 @@ func (c *Client) collectionGet(ctx context.Context, collectionName string, ids [
 
 	// Add document filter
 	if opts.WhereDocument != nil {
-		clause, filterArgs, err := c.filterBuilder.BuildDocumentFilter(opts.WhereDocument)
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildDocumentFilter(opts.WhereDocument)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		if clause != "" {
 			conditions = append(conditions, clause)
@@ -154,75 +633,166 @@ func (c *Client) collectionGet(ctx context.Context, collectionName string, ids [
 		}
 	}
 
+	// Keyset pagination: resume after the last ID of the previous page
+	if opts.Cursor != "" {
+		lastID, err := decodeGetCursor(opts.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("%s > ?", FieldID))
+		args = append(args, lastID)
+	}
+
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	querySQL := fmt.Sprintf(`
-		SELECT %s, %s, %s, %s
-		FROM %s
-		%s
-		LIMIT ? OFFSET ?
-	`, FieldID, FieldDocument, FieldMetadata, FieldEmbedding, tableName, whereClause)
-
 	limit := opts.Limit
-	if limit == 0 {
+	switch {
+	case opts.NoLimit:
+		if limit > 0 {
+			return "", nil, fmt.Errorf("%w: WithNoLimit cannot be combined with WithLimit", ErrInvalidParameter)
+		}
+		limit = math.MaxInt64 // MySQL has no LIMIT ALL; OFFSET requires a LIMIT
+	case limit == 0:
 		limit = 1000 // Default limit
+		// Do not truncate a lookup of more IDs than the default limit
+		if len(ids) > limit {
+			limit = len(ids)
+		}
 	}
 
-	queryArgs := append(args, limit, opts.Offset)
-	rows, err := c.conn.Query(ctx, querySQL, queryArgs...)
+	columns, queryArgs := getSelectColumns(projectedMetadataKeys(opts))
+	if opts.vectorsOnly {
+		// Constants keep the row layout of scanGetRow without columns to read
+		columns = fmt.Sprintf("%s, NULL AS %s, '{}' AS %s, %s", FieldID, FieldDocument, FieldMetadata, FieldEmbedding)
+	}
+	columns += extraColumnsSQL(opts.ExtraColumns)
+	queryArgs = append(queryArgs, args...)
+
+	var querySQL string
+	if opts.paginate || opts.Cursor != "" {
+		// Order by the primary key and skip OFFSET so each page is an index range scan
+		querySQL = fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			%s
+			ORDER BY %s
+			LIMIT ?
+		`, columns, tableName, whereClause, FieldID)
+		queryArgs = append(queryArgs, limit)
+	} else if opts.OrderBy != nil {
+		// Break ties by ID so pages do not overlap
+		orderBy, orderArgs := opts.OrderBy.sql()
+		querySQL = fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			%s
+			ORDER BY %s, %s
+			LIMIT ? OFFSET ?
+		`, columns, tableName, whereClause, orderBy, FieldID)
+		queryArgs = append(queryArgs, orderArgs...)
+		queryArgs = append(queryArgs, limit, opts.Offset)
+	} else {
+		querySQL = fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			%s
+			LIMIT ? OFFSET ?
+		`, columns, tableName, whereClause)
+		queryArgs = append(queryArgs, limit, opts.Offset)
+	}
+
+	return querySQL, queryArgs, nil
+}
+
+// collectionCount implements the Count operation for collections.
+func (c *Client) collectionCount(ctx context.Context, collectionName string, where Filter, whereDocument Filter) (count int, err error) {
+	ctx, span := c.startSpan(ctx, "count", collectionName)
+	defer func() { span.end(count, err) }()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	if err := validateFilters(where, whereDocument); err != nil {
+		return 0, err
+	}
+	querySQL, args, err := c.buildCountSQL(collectionName, where, whereDocument)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get documents: %w", err)
+		return 0, err
 	}
-	defer rows.Close()
 
-	var result GetResult
-	for rows.Next() {
-		var id, document, metadataJSON, embeddingJSON string
-		if err := rows.Scan(&id, &document, &metadataJSON, &embeddingJSON); err != nil {
-			return nil, err
+	row := c.conn.QueryRow(ctx, querySQL, args...)
+	if err := row.Scan(&count); err != nil {
+		if notFound := c.collectionNotFoundError(collectionName, err); notFound != nil {
+			return 0, notFound
 		}
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
 
-		result.IDs = append(result.IDs, id)
-		result.Documents = append(result.Documents, document)
+	return count, nil
+}
 
-		var metadata Metadata
-		if err := metadata.FromJSON(metadataJSON); err == nil {
-			result.Metadatas = append(result.Metadatas, metadata)
-		}
+// buildCountSQL builds the COUNT(*) statement for a collection, restricted by
+// optional metadata and document filters.
+func (c *Client) buildCountSQL(collectionName string, where Filter, whereDocument Filter) (string, []interface{}, error) {
+	tableName := c.GetTableName(collectionName)
 
-		var embedding []float32
-		if err := json.Unmarshal([]byte(embeddingJSON), &embedding); err == nil {
-			result.Embeddings = append(result.Embeddings, embedding)
+	var conditions []string
+	var args []interface{}
+
+	// Add metadata filter
+	if where != nil {
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildMetadataFilter(where)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, filterArgs...)
 		}
 	}
 
-	return &result, nil
-}
+	// Add document filter
+	if whereDocument != nil {
+		clause, filterArgs, err := c.collectionFilterBuilder(collectionName).BuildDocumentFilter(whereDocument)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, filterArgs...)
+		}
+	}
 
-// collectionCount implements the Count operation for collections.
-func (c *Client) collectionCount(ctx context.Context, collectionName string) (int, error) {
-	tableName := GetTableName(collectionName)
 	querySQL := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
-
-	row := c.conn.QueryRow(ctx, querySQL)
-	var count int
-	if err := row.Scan(&count); err != nil {
-		return 0, fmt.Errorf("failed to count documents: %w", err)
+	if len(conditions) > 0 {
+		querySQL += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	return count, nil
+	return querySQL, args, nil
 }
 
 // collectionHybridSearch implements hybrid search combining full-text and vector search
 // using DBMS_HYBRID_SEARCH.GET_SQL to generate and execute the query.
-func (c *Client) collectionHybridSearch(ctx context.Context, collectionName string, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*HybridSearchResult, error) {
-	tableName := GetTableName(collectionName)
+func (c *Client) collectionHybridSearch(ctx context.Context, collectionName string, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (result *HybridSearchResult, err error) {
+	ctx, span := c.startSpan(ctx, "hybrid_search", collectionName)
+	defer func() {
+		rowCount := 0
+		if result != nil {
+			rowCount = len(result.IDs)
+		}
+		span.end(rowCount, err)
+	}()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	tableName := c.GetTableName(collectionName)
 
 	// Build search_parm JSON
-	searchParm, err := c.buildSearchParm(query, knn, rank, nResults, embFunc)
+	searchParm, err := c.buildSearchParm(ctx, query, knn, rank, nResults, embFunc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build search_parm: %w", err)
 	}
@@ -233,9 +803,7 @@ func (c *Client) collectionHybridSearch(ctx context.Context, collectionName stri
 		return nil, fmt.Errorf("failed to marshal search_parm: %w", err)
 	}
 	searchParmJSON := string(searchParmBytes)
-
-	// Escape single quotes for SQL
-	escapedParams := strings.ReplaceAll(searchParmJSON, "'", "''")
+	span.setAttribute(SpanAttrSearchParm, searchParmJSON)
 
 	// Use a transaction to ensure SET and SELECT use the same connection
 	// This is necessary because @search_parm is a session variable
@@ -245,16 +813,15 @@ func (c *Client) collectionHybridSearch(ctx context.Context, collectionName stri
 	}
 	defer tx.Rollback()
 
-	// Set the search_parm variable
-	setSQL := fmt.Sprintf("SET @search_parm = '%s'", escapedParams)
-	_, err = tx.Execute(ctx, setSQL)
+	// Set the search_parm variable, binding the JSON rather than quoting it into the SQL
+	_, err = tx.Execute(ctx, "SET @search_parm = ?", searchParmJSON)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set search_parm: %w", err)
 	}
 
 	// Get SQL query from DBMS_HYBRID_SEARCH.GET_SQL
-	getSQLQuery := fmt.Sprintf("SELECT DBMS_HYBRID_SEARCH.GET_SQL('%s', @search_parm) as query_sql FROM dual", tableName)
-	row := tx.QueryRow(ctx, getSQLQuery)
+	getSQLQuery := "SELECT DBMS_HYBRID_SEARCH.GET_SQL(?, @search_parm) as query_sql FROM dual"
+	row := tx.QueryRow(ctx, getSQLQuery, tableName)
 
 	var querySQL sql.NullString
 	if err := row.Scan(&querySQL); err != nil {
@@ -263,27 +830,31 @@ func (c *Client) collectionHybridSearch(ctx context.Context, collectionName stri
 
 	if !querySQL.Valid || querySQL.String == "" {
 		// No SQL query returned, return empty results
-		return &HybridSearchResult{
+		result = &HybridSearchResult{
 			IDs:        []string{},
 			Distances:  []float64{},
 			Documents:  []string{},
 			Metadatas:  []Metadata{},
 			Embeddings: [][]float32{},
-		}, nil
+		}
+		c.explainHybridSearch(result, "", searchParmJSON)
+		return result, nil
 	}
 
 	// Remove any surrounding quotes if present
 	finalSQL := strings.Trim(strings.TrimSpace(querySQL.String), "'\"")
 
 	// Execute the returned SQL query
+	queryDone := c.startSlowQueryTimer("hybrid_search", collectionName, nResults, finalSQL)
 	rows, err := tx.Query(ctx, finalSQL)
+	queryDone()
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute hybrid search query: %w", err)
 	}
 	defer rows.Close()
 
 	// Transform results
-	result, err := c.transformHybridSearchResults(rows)
+	result, err = c.transformHybridSearchResults(rows)
 	if err != nil {
 		return nil, err
 	}
@@ -293,15 +864,29 @@ func (c *Client) collectionHybridSearch(ctx context.Context, collectionName stri
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	c.explainHybridSearch(result, finalSQL, searchParmJSON)
 	return result, nil
 }
 
+// explainHybridSearch records the generated SQL and search_parm in result if the
+// client was created with WithExplainHybrid.
+func (c *Client) explainHybridSearch(result *HybridSearchResult, querySQL, searchParm string) {
+	if c.config == nil || !c.config.ExplainHybrid {
+		return
+	}
+	result.DebugSQL = querySQL
+	result.DebugParm = searchParm
+}
+
 // buildSearchParm builds the search_parm JSON from query, knn, and rank parameters.
-func (c *Client) buildSearchParm(query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, embFunc embedding.EmbeddingFunc) (map[string]interface{}, error) {
+func (c *Client) buildSearchParm(ctx context.Context, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, embFunc embedding.EmbeddingFunc) (map[string]interface{}, error) {
 	searchParm := make(map[string]interface{})
 
 	// Build query part (full-text search or scalar query)
 	if query != nil {
+		if err := query.validateFullText(); err != nil {
+			return nil, err
+		}
 		queryExpr := c.buildQueryExpression(query)
 		if queryExpr != nil {
 			searchParm["query"] = queryExpr
@@ -310,7 +895,7 @@ func (c *Client) buildSearchParm(query *HybridSearchQuery, knn *HybridSearchKNN,
 
 	// Build knn part (vector search)
 	if knn != nil {
-		knnExpr, err := c.buildKNNExpression(knn, embFunc)
+		knnExpr, err := c.buildKNNExpression(ctx, knn, embFunc)
 		if err != nil {
 			return nil, err
 		}
@@ -379,7 +964,7 @@ func (c *Client) buildQueryExpression(query *HybridSearchQuery) map[string]inter
 
 	// Case 2: Full-text search (with or without metadata filtering)
 	if len(whereDocument) > 0 {
-		docQuery := c.buildDocumentQuery(whereDocument)
+		docQuery := c.buildDocumentQuery(whereDocument, query.Fields, query.Analyzer)
 		if docQuery != nil {
 			filterConditions := c.buildMetadataFilterForSearchParm(where)
 			if len(filterConditions) > 0 {
@@ -399,20 +984,16 @@ func (c *Client) buildQueryExpression(query *HybridSearchQuery) map[string]inter
 	return nil
 }
 
-// buildDocumentQuery builds document query from where_document condition using query_string.
-func (c *Client) buildDocumentQuery(whereDocument Filter) map[string]interface{} {
+// buildDocumentQuery builds document query from where_document condition using query_string,
+// searching fields (the document column if empty) with an optional analyzer.
+func (c *Client) buildDocumentQuery(whereDocument Filter, fields []string, analyzer string) map[string]interface{} {
 	if len(whereDocument) == 0 {
 		return nil
 	}
 
 	// Handle $contains - use query_string
 	if contains, ok := whereDocument["$contains"]; ok {
-		return map[string]interface{}{
-			"query_string": map[string]interface{}{
-				"fields": []string{"document"},
-				"query":  contains,
-			},
-		}
+		return queryStringExpression(contains, fields, analyzer)
 	}
 
 	// Handle $and with $contains
@@ -429,12 +1010,7 @@ func (c *Client) buildDocumentQuery(whereDocument Filter) map[string]interface{}
 				}
 			}
 			if len(containsQueries) > 0 {
-				return map[string]interface{}{
-					"query_string": map[string]interface{}{
-						"fields": []string{"document"},
-						"query":  strings.Join(containsQueries, " "),
-					},
-				}
+				return queryStringExpression(strings.Join(containsQueries, " "), fields, analyzer)
 			}
 		}
 	}
@@ -453,12 +1029,7 @@ func (c *Client) buildDocumentQuery(whereDocument Filter) map[string]interface{}
 				}
 			}
 			if len(containsQueries) > 0 {
-				return map[string]interface{}{
-					"query_string": map[string]interface{}{
-						"fields": []string{"document"},
-						"query":  strings.Join(containsQueries, " OR "),
-					},
-				}
+				return queryStringExpression(strings.Join(containsQueries, " OR "), fields, analyzer)
 			}
 		}
 	}
@@ -580,8 +1151,15 @@ func (c *Client) buildMetadataFilterConditions(condition Filter) []map[string]in
 					rangeConditions["gt"] = opValue
 				case "$gte":
 					rangeConditions["gte"] = opValue
+				case "$between":
+					// Malformed bounds add no condition, like other malformed operands
+					if low, high, err := parseBetweenRange(key, opValue); err == nil {
+						rangeConditions["gte"] = low
+						rangeConditions["lte"] = high
+					}
 				case "$in":
-					if inValues, ok := opValue.([]interface{}); ok {
+					// Accept any list, e.g. []string, as the SQL filters do
+					if inValues, ok := arrayOperandValues(opValue); ok {
 						var inConditions []map[string]interface{}
 						for _, val := range inValues {
 							inConditions = append(inConditions, map[string]interface{}{
@@ -596,8 +1174,22 @@ func (c *Client) buildMetadataFilterConditions(condition Filter) []map[string]in
 							})
 						}
 					}
+				case "$exists", "$nexists":
+					if exists, ok := opValue.(bool); ok {
+						existsCondition := map[string]interface{}{
+							"exists": map[string]interface{}{"field": fieldName},
+						}
+						if exists == (op == "$nexists") {
+							existsCondition = map[string]interface{}{
+								"bool": map[string]interface{}{
+									"must_not": []map[string]interface{}{existsCondition},
+								},
+							}
+						}
+						result = append(result, existsCondition)
+					}
 				case "$nin":
-					if ninValues, ok := opValue.([]interface{}); ok {
+					if ninValues, ok := arrayOperandValues(opValue); ok {
 						var ninConditions []map[string]interface{}
 						for _, val := range ninValues {
 							ninConditions = append(ninConditions, map[string]interface{}{
@@ -636,7 +1228,7 @@ func (c *Client) buildMetadataFilterConditions(condition Filter) []map[string]in
 }
 
 // buildKNNExpression builds the knn expression from HybridSearchKNN.
-func (c *Client) buildKNNExpression(knn *HybridSearchKNN, embFunc embedding.EmbeddingFunc) (map[string]interface{}, error) {
+func (c *Client) buildKNNExpression(ctx context.Context, knn *HybridSearchKNN, embFunc embedding.EmbeddingFunc) (map[string]interface{}, error) {
 	var queryVector []float32
 
 	// Handle vector generation
@@ -647,7 +1239,7 @@ func (c *Client) buildKNNExpression(knn *HybridSearchKNN, embFunc embedding.Embe
 		if embFunc == nil {
 			return nil, fmt.Errorf("knn.query_texts provided but no embedding function: %w", ErrEmbeddingFunctionRequired)
 		}
-		embeddings, err := embFunc.Embed(knn.QueryTexts)
+		embeddings, err := embedding.EmbedWithContext(ctx, embFunc, knn.QueryTexts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate embeddings from query_texts: %w", err)
 		}
@@ -704,59 +1296,42 @@ func (c *Client) transformHybridSearchResults(rows *sql.Rows) (*HybridSearchResu
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
-	// Create a map of column names for lookup
-	colMap := make(map[string]int)
-	for i, col := range cols {
-		colMap[strings.ToLower(col)] = i
-	}
+	// Scan into pooled destinations, reused for every row
+	buf := getHybridScanBuffer(cols)
+	defer putHybridScanBuffer(buf)
 
 	for rows.Next() {
-		// Create a slice to hold all column values
-		values := make([]interface{}, len(cols))
-		valuePtrs := make([]interface{}, len(cols))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
+		if err := rows.Scan(buf.ptrs...); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
 		// Extract ID
 		var id string
-		if idx, ok := colMap["id"]; ok {
-			id = c.convertToString(values[idx])
-		} else if idx, ok := colMap["_id"]; ok {
-			id = c.convertToString(values[idx])
+		if value, ok := buf.value("id", "_id"); ok {
+			id = c.convertToString(value)
 		}
 		result.IDs = append(result.IDs, id)
 
 		// Extract distance/score
 		var distance float64
-		if idx, ok := colMap["_distance"]; ok {
-			distance = c.convertToFloat64(values[idx])
-		} else if idx, ok := colMap["distance"]; ok {
-			distance = c.convertToFloat64(values[idx])
-		} else if idx, ok := colMap["_score"]; ok {
-			distance = c.convertToFloat64(values[idx])
-		} else if idx, ok := colMap["score"]; ok {
-			distance = c.convertToFloat64(values[idx])
+		if value, ok := buf.value("_distance", "distance", "_score", "score"); ok {
+			distance = c.convertToFloat64(value)
 		}
 		result.Distances = append(result.Distances, distance)
 
 		// Extract document
 		var document string
-		if idx, ok := colMap["document"]; ok {
-			document = c.convertToString(values[idx])
+		if value, ok := buf.value("document"); ok {
+			document = c.convertToString(value)
 		}
 		result.Documents = append(result.Documents, document)
 
 		// Extract metadata
 		var metadata Metadata
-		if idx, ok := colMap["metadata"]; ok {
-			metadataStr := c.convertToString(values[idx])
+		if value, ok := buf.value("metadata"); ok {
+			metadataStr := c.convertToString(value)
 			if metadataStr != "" {
-				json.Unmarshal([]byte(metadataStr), &metadata)
+				c.decodeMetadata(metadataStr, &metadata)
 			}
 		}
 		if metadata == nil {
@@ -766,8 +1341,8 @@ func (c *Client) transformHybridSearchResults(rows *sql.Rows) (*HybridSearchResu
 
 		// Extract embedding
 		var embedding []float32
-		if idx, ok := colMap["embedding"]; ok {
-			embStr := c.convertToString(values[idx])
+		if value, ok := buf.value("embedding"); ok {
+			embStr := c.convertToString(value)
 			if embStr != "" {
 				json.Unmarshal([]byte(embStr), &embedding)
 			}
@@ -820,44 +1395,113 @@ func (c *Client) convertToFloat64(v interface{}) float64 {
 	}
 }
 
-// scanQueryResults scans query results from rows.
-func (c *Client) scanQueryResults(rows *sql.Rows) ([]string, []float64, []string, []Metadata, [][]float32, error) {
+// scanQueryResults scans query results from rows, and the extra columns of raw, if
+// any, into one map per row. Rows hold the columns of include, and fields it
+// excludes are returned nil.
+// Embedding JSON is collected while scanning and decoded afterwards, using up to
+// decodeWorkers goroutines (sequentially if decodeWorkers <= 1).
+func (c *Client) scanQueryResults(rows *sql.Rows, decodeWorkers int, include queryInclude, raw *rawColumnScanner) ([]string, []float64, []string, []Metadata, [][]float32, []map[string]interface{}, error) {
 	var ids []string
 	var distances []float64
 	var documents []string
 	var metadatas []Metadata
-	var embeddings [][]float32
+	var rawColumns []map[string]interface{}
+
+	// Scan into pooled destinations, reused for every row
+	buf := getQueryScanBuffer()
+	defer putQueryScanBuffer(buf)
+	dest := raw.dest(include.dest(buf))
 
 	for rows.Next() {
-		var id, document, metadataJSON, embeddingJSON string
-		var distance float64
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, nil, nil, nil, nil, err
+		}
+		if raw != nil {
+			rawColumns = append(rawColumns, raw.row())
+		}
 
-		if err := rows.Scan(&id, &document, &metadataJSON, &embeddingJSON, &distance); err != nil {
-			return nil, nil, nil, nil, nil, err
+		ids = append(ids, buf.id)
+		if include.distances {
+			distances = append(distances, buf.distance)
+		}
+		if include.documents {
+			documents = append(documents, buf.document.String)
+		}
+		if include.metadatas {
+			var metadata Metadata
+			c.decodeMetadata(buf.metadataJSON, &metadata)
+			metadatas = append(metadatas, metadata)
 		}
+		if include.embeddings {
+			buf.embeddingJSONs = append(buf.embeddingJSONs, buf.embeddingJSON)
+		}
+	}
 
-		ids = append(ids, id)
-		distances = append(distances, distance)
-		documents = append(documents, document)
+	embeddings := decodeEmbeddings(buf.embeddingJSONs, decodeWorkers)
 
-		var metadata Metadata
-		metadata.FromJSON(metadataJSON)
-		metadatas = append(metadatas, metadata)
+	return ids, distances, documents, metadatas, embeddings, rawColumns, nil
+}
 
-		var embedding []float32
-		json.Unmarshal([]byte(embeddingJSON), &embedding)
-		embeddings = append(embeddings, embedding)
+// decodeEmbeddings decodes JSON-encoded vectors, preserving order. Vectors that fail
+// to decode are left nil. With more than one worker the input is split into
+// contiguous chunks decoded concurrently.
+func decodeEmbeddings(embeddingJSONs []string, workers int) [][]float32 {
+	if len(embeddingJSONs) == 0 {
+		return nil
+	}
+
+	embeddings := make([][]float32, len(embeddingJSONs))
+	decodeRange := func(start, end int) {
+		for i := start; i < end; i++ {
+			var embedding []float32
+			if err := json.Unmarshal([]byte(embeddingJSONs[i]), &embedding); err == nil {
+				embeddings[i] = embedding
+			}
+		}
+	}
+
+	if workers > len(embeddingJSONs) {
+		workers = len(embeddingJSONs)
+	}
+	if workers <= 1 {
+		decodeRange(0, len(embeddingJSONs))
+		return embeddings
 	}
 
-	return ids, distances, documents, metadatas, embeddings, nil
+	chunkSize := (len(embeddingJSONs) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(embeddingJSONs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(embeddingJSONs) {
+			end = len(embeddingJSONs)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			decodeRange(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+
+	return embeddings
 }
 
-// vectorToString converts a float32 slice to a string format for SQL embedding.
-// Format: [0.1,0.2,0.3] (matching Python's vector_str format)
-func vectorToString(vector []float32) string {
-	parts := make([]string, len(vector))
-	for i, v := range vector {
-		parts[i] = fmt.Sprintf("%v", v)
+// encodeGetCursor encodes the last ID of a page into an opaque pagination cursor.
+func encodeGetCursor(lastID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastID))
+}
+
+// decodeGetCursor decodes a pagination cursor produced by encodeGetCursor.
+func decodeGetCursor(cursor string) (string, error) {
+	lastID, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid cursor %q", ErrInvalidParameter, cursor)
 	}
-	return "[" + strings.Join(parts, ",") + "]"
+	return string(lastID), nil
+}
+
+// vectorToString converts a float32 slice to a string format for SQL embedding,
+// using VectorFormat. Format: [0.1,0.2,0.3] (matching Python's vector_str format)
+func vectorToString(vector []float32) string {
+	return VectorFormat(vector)
 }
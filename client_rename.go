@@ -0,0 +1,223 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// collectionRename renames a collection table and the indexes whose names were
+// derived from the old collection name, so introspection reports consistent names.
+// Indexes are renamed first and renamed back if the table cannot be renamed, so that
+// a failure leaves the collection as it was.
+//
+// Limitation: indexes are renamed in place with ALTER TABLE ... RENAME INDEX. Servers
+// that cannot rename a vector or full-text index (e.g. some MySQL-mode deployments)
+// return an error and the collection is not renamed; such indexes have to be dropped
+// and recreated manually.
+func (c *Client) collectionRename(ctx context.Context, oldName, newName string) (err error) {
+	ctx, span := c.startSpan(ctx, "rename", oldName)
+	defer func() { span.end(0, err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if err := validateCollectionName(newName); err != nil {
+		return err
+	}
+	if newName == oldName {
+		return nil
+	}
+
+	// The renamed table keeps its schema version
+	oldTable := c.GetTableName(oldName)
+	newTable := strings.TrimSuffix(oldTable, oldName) + newName
+	if len(newTable) > maxColumnNameLength {
+		return fmt.Errorf("%w: collection name %q is too long for table %s", ErrInvalidParameter, newName, newTable)
+	}
+
+	indexes, err := c.listIndexNames(ctx, oldTable)
+	if err != nil {
+		return err
+	}
+
+	var renamed [][2]string // Old and new names of the indexes renamed so far
+	defer func() {
+		if err != nil {
+			c.undoIndexRenames(ctx, oldTable, renamed)
+		}
+	}()
+
+	for _, index := range indexes {
+		newIndex, ok := renamedIndexName(index, oldName, newName)
+		if !ok {
+			continue
+		}
+		renameIndexSQL := fmt.Sprintf("ALTER TABLE `%s` RENAME INDEX `%s` TO `%s`", oldTable, index, newIndex)
+		if _, err := c.conn.Execute(ctx, renameIndexSQL); err != nil {
+			return fmt.Errorf("failed to rename index %s: %w", index, err)
+		}
+		renamed = append(renamed, [2]string{index, newIndex})
+	}
+
+	renameSQL := fmt.Sprintf("RENAME TABLE `%s` TO `%s`", oldTable, newTable)
+	if _, err := c.conn.Execute(ctx, renameSQL); err != nil {
+		return fmt.Errorf("failed to rename collection: %w", err)
+	}
+	if version, ok := c.schemaVersions.LoadAndDelete(oldName); ok {
+		c.schemaVersions.Store(newName, version)
+	}
+	if keys, ok := c.indexedMetadataKeys.LoadAndDelete(oldName); ok {
+		c.indexedMetadataKeys.Store(newName, keys)
+	}
+
+	return nil
+}
+
+// undoIndexRenames renames the indexes of a table back to their old names, in reverse
+// order, after a failed rename. It also runs after ctx was canceled or timed out.
+// Failures are logged, as the rename already failed.
+func (c *Client) undoIndexRenames(ctx context.Context, tableName string, renamed [][2]string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), undoRenameTimeout)
+	defer cancel()
+
+	for i := len(renamed) - 1; i >= 0; i-- {
+		oldIndex, newIndex := renamed[i][0], renamed[i][1]
+		undoSQL := fmt.Sprintf("ALTER TABLE `%s` RENAME INDEX `%s` TO `%s`", tableName, newIndex, oldIndex)
+		if _, err := c.conn.Execute(ctx, undoSQL); err != nil {
+			c.logger().Warnf("failed to rename index %s of %s back to %s: %v", newIndex, tableName, oldIndex, err)
+		}
+	}
+}
+
+// undoRenameTimeout bounds renaming indexes back after a failed rename.
+const undoRenameTimeout = 10 * time.Second
+
+// renamedIndexName returns the name of an index of collection oldName after renaming
+// the collection to newName. The indexes this client creates are never named after
+// the collection and are kept (see isClientIndexName). Other indexes, e.g. created by
+// other tools, are renamed if oldName is one of the '_'-separated parts of their name,
+// like vidx_docs or docs_title_idx; ok is false for all others.
+func renamedIndexName(index, oldName, newName string) (string, bool) {
+	if isClientIndexName(index) {
+		return "", false
+	}
+	padded := "_" + index + "_"
+	at := strings.Index(padded, "_"+oldName+"_")
+	if at < 0 {
+		return "", false
+	}
+	renamed := padded[:at+1] + newName + padded[at+1+len(oldName):]
+	return renamed[1 : len(renamed)-1], true
+}
+
+// isClientIndexName reports whether index is one of the indexes this client creates
+// in collection tables: the primary key, the full-text and vector indexes, those of
+// named vector fields and indexed metadata keys, and the content hash indexes.
+func isClientIndexName(index string) bool {
+	switch index {
+	case "PRIMARY", fullTextIndexName, "idx_" + FieldEmbedding, "uk" + FieldContentHash, "idx" + FieldContentHash:
+		return true
+	}
+	return strings.HasPrefix(index, "idx_"+VectorFieldColumnPrefix) || strings.HasPrefix(index, indexedMetadataIndexPrefix)
+}
+
+// validateCollectionName checks that name can name a collection: a non-empty name of
+// letters, digits, '_' and '-'.
+func validateCollectionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: collection name must not be empty", ErrInvalidParameter)
+	}
+	for _, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != '_' && r != '-' {
+			return fmt.Errorf("%w: collection name %q must contain only letters, digits, '_' or '-'", ErrInvalidParameter, name)
+		}
+	}
+	return nil
+}
+
+// SwapCollections atomically exchanges two collections, e.g. to switch readers from
+// a live collection to a shadow collection rebuilt alongside it (blue/green reindex).
+// Both collections must exist. The swap is a single statement,
+//
+//	RENAME TABLE a TO tmp, b TO a, tmp TO b
+//
+// which MySQL and OceanBase execute atomically: the renames happen left to right
+// while holding locks on all tables involved, so concurrent queries see either the
+// old or the new pair of tables, never a missing one, and if any rename fails none
+// take effect. Index names are left as they were, so they may refer to the other name.
+// Collection handles keep their names, and so refer to the swapped data afterwards.
+func (c *Client) SwapCollections(ctx context.Context, a, b string) (err error) {
+	ctx, span := c.startSpan(ctx, "swap", a)
+	defer func() { span.end(0, err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if a == "" || b == "" {
+		return fmt.Errorf("%w: collection names must not be empty", ErrInvalidParameter)
+	}
+	if a == b {
+		return fmt.Errorf("%w: cannot swap collection %q with itself", ErrInvalidParameter, a)
+	}
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	for _, name := range []string{a, b} {
+		exists, err := c.HasCollection(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", ErrCollectionNotFound, name)
+		}
+	}
+
+	if c.collectionSchemaVersion(a) != c.collectionSchemaVersion(b) {
+		return fmt.Errorf("%w: cannot swap collections %s and %s with different schema versions", ErrInvalidParameter, a, b)
+	}
+
+	tmpTable := c.GetTableName("swap_" + strings.ReplaceAll(uuid.New().String(), "-", "")[:12])
+	if _, err := c.conn.Execute(ctx, buildSwapSQL(c.GetTableName(a), c.GetTableName(b), tmpTable)); err != nil {
+		return fmt.Errorf("failed to swap collections: %w", err)
+	}
+	c.swapIndexedMetadataKeys(a, b)
+
+	return nil
+}
+
+// buildSwapSQL returns the single multi-table RENAME that exchanges tables a and b via tmp.
+func buildSwapSQL(a, b, tmp string) string {
+	return fmt.Sprintf("RENAME TABLE `%s` TO `%s`, `%s` TO `%s`, `%s` TO `%s`", a, tmp, b, a, tmp, b)
+}
+
+// listIndexNames returns the distinct index names of a table in the current database.
+func (c *Client) listIndexNames(ctx context.Context, tableName string) ([]string, error) {
+	query := `
+		SELECT DISTINCT INDEX_NAME
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`
+
+	rows, err := c.conn.Query(ctx, query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, rows.Err()
+}
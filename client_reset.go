@@ -0,0 +1,63 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL errors after which Reset falls back from TRUNCATE to DELETE.
+const (
+	mysqlErrTableAccessDenied    = 1142 // TRUNCATE needs the DROP privilege
+	mysqlErrSpecificAccessDenied = 1227
+	mysqlErrNotSupportedYet      = 1235 // e.g. tables with indexes TRUNCATE cannot rebuild
+)
+
+// collectionReset removes all rows of a collection, keeping the table, its schema and
+// its indexes. TRUNCATE TABLE is used where permitted, as it is much faster than
+// deleting row by row; otherwise all rows are deleted.
+func (c *Client) collectionReset(ctx context.Context, collectionName string) (err error) {
+	ctx, span := c.startSpan(ctx, "reset", collectionName)
+	defer func() { span.end(0, err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	exists, err := c.HasCollection(ctx, collectionName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, collectionName)
+	}
+
+	tableName := c.GetTableName(collectionName)
+	_, err = c.conn.Execute(ctx, fmt.Sprintf("TRUNCATE TABLE `%s`", tableName))
+	if err == nil {
+		return nil
+	}
+	if !isTruncateRejected(err) {
+		return fmt.Errorf("failed to truncate collection: %w", err)
+	}
+
+	if _, err := c.conn.Execute(ctx, fmt.Sprintf("DELETE FROM `%s`", tableName)); err != nil {
+		return fmt.Errorf("failed to delete all documents: %w", err)
+	}
+	return nil
+}
+
+// isTruncateRejected reports whether TRUNCATE failed because it is not permitted or
+// not supported for the table, so that deleting all rows may still succeed.
+func isTruncateRejected(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	switch mysqlErr.Number {
+	case mysqlErrTableAccessDenied, mysqlErrSpecificAccessDenied, mysqlErrNotSupportedYet:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTruncateRejected(t *testing.T) {
+	assert.True(t, isTruncateRejected(&mysql.MySQLError{Number: 1142, Message: "DROP command denied"}))
+	assert.True(t, isTruncateRejected(fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1235})))
+	assert.False(t, isTruncateRejected(&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}))
+	assert.False(t, isTruncateRejected(errors.New("connection refused")))
+}
+
+func TestCollectionResetFake(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "reset", dimension: 3, distance: DistanceL2}
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}))
+	require.NoError(t, err)
+
+	require.NoError(t, collection.Reset(ctx))
+	results, err := collection.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results.IDs)
+}
+
+// TestCollectionReset tests that Reset empties a collection but keeps it usable
+func TestCollectionReset(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_reset_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}))
+	require.NoError(t, err)
+
+	require.NoError(t, collection.Reset(ctx))
+	count, err := collection.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// The table and its vector index are still there
+	err = collection.Add(ctx, []string{"id3"}, []string{"doc 3"}, WithEmbeddings([][]float32{{7, 8, 9}}))
+	require.NoError(t, err)
+	results, err := collection.Query(ctx, nil, 1, WithQueryEmbeddings([][]float32{{7, 8, 9}}))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"id3"}}, results.IDs)
+
+	missing := &Collection{client: client, name: "test_reset_missing_" + uuid.New().String()[:8], dimension: 3}
+	assert.ErrorIs(t, missing.Reset(ctx), ErrCollectionNotFound)
+}
@@ -0,0 +1,61 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// mysqlErrDeadlock is returned when a transaction is rolled back to break a deadlock.
+	mysqlErrDeadlock = 1213
+
+	deadlockRetryBaseDelay = 10 * time.Millisecond
+	deadlockRetryMaxDelay  = 500 * time.Millisecond
+)
+
+// isDeadlock reports whether err was caused by a deadlock, which is safe to retry.
+func isDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDeadlock
+}
+
+// retryOnDeadlock runs fn, re-running it when it fails with a deadlock, up to the
+// configured DeadlockRetryAttempts. fn must be safe to re-run as a whole, i.e. it
+// must own its transaction.
+func (c *Client) retryOnDeadlock(ctx context.Context, fn func() error) error {
+	attempts := 1
+	if c.config != nil && c.config.DeadlockRetryAttempts > 1 {
+		attempts = c.config.DeadlockRetryAttempts
+	}
+	return retryOnDeadlock(ctx, attempts, fn)
+}
+
+// retryOnDeadlock runs fn up to attempts times while it fails with a deadlock,
+// sleeping with jittered exponential backoff between attempts.
+func retryOnDeadlock(ctx context.Context, attempts int, fn func() error) error {
+	delay := deadlockRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= attempts || !isDeadlock(err) {
+			return err
+		}
+
+		// Jitter keeps conflicting writers from retrying in lockstep
+		timer := time.NewTimer(delay/2 + time.Duration(rand.Int63n(int64(delay))))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > deadlockRetryMaxDelay {
+			delay = deadlockRetryMaxDelay
+		}
+	}
+}
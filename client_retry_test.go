@@ -0,0 +1,223 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetryOnDeadlock tests that only deadlock errors are retried
+func TestRetryOnDeadlock(t *testing.T) {
+	ctx := context.Background()
+	deadlock := fmt.Errorf("failed to add documents: %w", &mysql.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found"})
+
+	t.Run("retries deadlocks until success", func(t *testing.T) {
+		calls := 0
+		err := retryOnDeadlock(ctx, 3, func() error {
+			calls++
+			if calls < 3 {
+				return deadlock
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		err := retryOnDeadlock(ctx, 2, func() error {
+			calls++
+			return deadlock
+		})
+		assert.True(t, isDeadlock(err))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		calls := 0
+		other := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		err := retryOnDeadlock(ctx, 5, func() error {
+			calls++
+			return other
+		})
+		assert.Equal(t, other, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client := &Client{config: DefaultClientConfig()}
+		calls := 0
+		err := client.retryOnDeadlock(ctx, func() error {
+			calls++
+			return deadlock
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when context is done", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		calls := 0
+		err := retryOnDeadlock(cancelled, 5, func() error {
+			calls++
+			return deadlock
+		})
+		assert.True(t, errors.Is(err, deadlock))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// deadlockConnection is a connection whose first deadlocks writes fail with a
+// deadlock, in or out of a transaction
+type deadlockConnection struct {
+	connection.Connection
+	deadlocks int
+	begins    int
+	executes  int
+}
+
+func (d *deadlockConnection) execute() (sql.Result, error) {
+	d.executes++
+	if d.deadlocks > 0 {
+		d.deadlocks--
+		return nil, &mysql.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found"}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (d *deadlockConnection) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.execute()
+}
+
+func (d *deadlockConnection) Begin(ctx context.Context) (connection.Tx, error) {
+	d.begins++
+	return &deadlockTx{conn: d}, nil
+}
+
+type deadlockTx struct {
+	connection.Tx
+	conn *deadlockConnection
+}
+
+func (t *deadlockTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.conn.execute()
+}
+
+func (t *deadlockTx) Commit() error   { return nil }
+func (t *deadlockTx) Rollback() error { return nil }
+
+// TestWriteOperationsRetryOnDeadlock tests that add, update, upsert and delete
+// re-run their statements after a deadlock
+func TestWriteOperationsRetryOnDeadlock(t *testing.T) {
+	ctx := context.Background()
+	embeddings := [][]float32{{1, 2, 3}}
+
+	writes := map[string]func(client *Client) error{
+		"add": func(client *Client) error {
+			_, err := client.collectionAdd(ctx, "docs", []string{"a"}, nil, &AddOptions{Embeddings: embeddings}, nil)
+			return err
+		},
+		"update": func(client *Client) error {
+			_, err := client.collectionUpdate(ctx, "docs", []string{"a"}, &UpdateOptions{Embeddings: embeddings}, nil)
+			return err
+		},
+		"upsert": func(client *Client) error {
+			_, err := client.collectionUpsert(ctx, "docs", []string{"a"}, nil, &AddOptions{Embeddings: embeddings}, nil)
+			return err
+		},
+		"delete": func(client *Client) error {
+			_, err := client.collectionDelete(ctx, "docs", []string{"a"}, nil, nil)
+			return err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			conn := &deadlockConnection{deadlocks: 1}
+			client := &Client{conn: conn, config: &ClientConfig{DeadlockRetryAttempts: 2}, filterBuilder: NewFilterBuilder()}
+			require.NoError(t, write(client))
+			assert.Equal(t, 2, conn.executes)
+
+			conn = &deadlockConnection{deadlocks: 2}
+			client = &Client{conn: conn, config: &ClientConfig{DeadlockRetryAttempts: 2}, filterBuilder: NewFilterBuilder()}
+			err := write(client)
+			require.Error(t, err)
+			assert.True(t, isDeadlock(err))
+			assert.Equal(t, 2, conn.executes)
+		})
+	}
+}
+
+// TestDeadlockRetryConcurrentWrites induces a deadlock between two transactions
+// that lock the same rows in opposite order and checks both eventually commit.
+func TestDeadlockRetryConcurrentWrites(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+	client.config.DeadlockRetryAttempts = 5
+
+	collectionName := "test_deadlock_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"a", "b"}, []string{"doc a", "doc b"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+	)
+	require.NoError(t, err)
+
+	tableName := client.GetTableName(collectionName)
+	updateSQL := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", tableName, FieldDocument, FieldID)
+
+	// Both transactions lock their first row before either locks its second
+	var firstLocks sync.WaitGroup
+	firstLocks.Add(2)
+
+	var attempts [2]int
+	var wg sync.WaitGroup
+	for worker, order := range [][]string{{"a", "b"}, {"b", "a"}} {
+		wg.Add(1)
+		go func(worker int, order []string) {
+			defer wg.Done()
+			err := client.retryOnDeadlock(ctx, func() error {
+				attempts[worker]++
+				tx, err := client.conn.Begin(ctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				if _, err := tx.Execute(ctx, updateSQL, fmt.Sprintf("worker %d", worker), order[0]); err != nil {
+					return err
+				}
+				if attempts[worker] == 1 {
+					firstLocks.Done()
+					firstLocks.Wait()
+				}
+				if _, err := tx.Execute(ctx, updateSQL, fmt.Sprintf("worker %d", worker), order[1]); err != nil {
+					return err
+				}
+				return tx.Commit()
+			})
+			assert.NoError(t, err)
+		}(worker, order)
+	}
+	wg.Wait()
+
+	// At least one side was chosen as the deadlock victim and retried
+	assert.Greater(t, attempts[0]+attempts[1], 2)
+}
@@ -1,6 +1,9 @@
 package goseekdb
 
 import (
+	"context"
+	"crypto/tls"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +69,8 @@ func TestClientConfig(t *testing.T) {
 	assert.Equal(t, "test", config.Tenant)
 	assert.True(t, config.AutoConnect)
 	assert.NotZero(t, config.ConnectTimeout)
+	assert.Equal(t, "utf8mb4", config.ConnectionCharset)
+	assert.Equal(t, "utf8mb4_general_ci", config.ConnectionCollation)
 }
 
 func TestClientOptions(t *testing.T) {
@@ -88,6 +93,13 @@ func TestClientOptions(t *testing.T) {
 
 	WithTenant("prod")(config)
 	assert.Equal(t, "prod", config.Tenant)
+
+	tlsConfig := &tls.Config{ServerName: "example.com"}
+	WithTLS(tlsConfig)(config)
+	assert.Same(t, tlsConfig, config.TLSConfig)
+
+	WithTLSSkipVerify(true)(config)
+	assert.True(t, config.TLSSkipVerify)
 }
 
 func TestGetTableName(t *testing.T) {
@@ -108,6 +120,28 @@ func TestGetTableName(t *testing.T) {
 	}
 }
 
+func TestClientGetTableNameWithPrefix(t *testing.T) {
+	client, err := NewClient(WithHost("localhost"), WithAutoConnect(false))
+	require.NoError(t, err)
+	assert.Equal(t, "c$v1$docs", client.GetTableName("docs"))
+
+	client, err = NewClient(WithHost("localhost"), WithAutoConnect(false), WithTablePrefix("app1_"))
+	require.NoError(t, err)
+	assert.Equal(t, "app1_docs", client.GetTableName("docs"))
+}
+
+func TestValidateTablePrefix(t *testing.T) {
+	valid := []string{"", "app_", "c$v2$", "_tmp", "App9"}
+	for _, prefix := range valid {
+		assert.NoError(t, validateTablePrefix(prefix), prefix)
+	}
+
+	invalid := []string{"9app", "app-", "app prefix", "app`", "a;drop", strings.Repeat("a", 33)}
+	for _, prefix := range invalid {
+		assert.ErrorIs(t, validateTablePrefix(prefix), ErrInvalidParameter, prefix)
+	}
+}
+
 func TestMetadata(t *testing.T) {
 	metadata := Metadata{
 		"name":  "test",
@@ -135,6 +169,33 @@ func TestMetadata(t *testing.T) {
 	assert.Equal(t, "{}", jsonStr)
 }
 
+func TestMetadataFromJSONPreserveIntegers(t *testing.T) {
+	var metadata Metadata
+	err := metadata.FromJSONPreserveIntegers(`{"year": 2023, "score": 0.5, "big": 1e3, "neg": -7,
+		"huge": 18446744073709551616, "name": "x", "nested": {"n": 1, "list": [1, 2.5]}, "none": null}`)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2023), metadata["year"])
+	assert.Equal(t, 0.5, metadata["score"])
+	assert.Equal(t, float64(1000), metadata["big"])
+	assert.Equal(t, int64(-7), metadata["neg"])
+	assert.Equal(t, float64(18446744073709551616), metadata["huge"])
+	assert.Equal(t, "x", metadata["name"])
+	assert.Equal(t, map[string]interface{}{"n": int64(1), "list": []interface{}{int64(1), 2.5}}, metadata["nested"])
+	assert.Nil(t, metadata["none"])
+
+	require.NoError(t, metadata.FromJSONPreserveIntegers("{}"))
+	assert.Equal(t, Metadata{}, metadata)
+	assert.Error(t, metadata.FromJSONPreserveIntegers("{"))
+
+	// Results are only decoded this way if the client asks for it
+	client := &Client{config: &ClientConfig{}}
+	require.NoError(t, client.decodeMetadata(`{"year": 2023}`, &metadata))
+	assert.Equal(t, float64(2023), metadata["year"])
+	WithIntegerMetadata(true)(client.config)
+	require.NoError(t, client.decodeMetadata(`{"year": 2023}`, &metadata))
+	assert.Equal(t, int64(2023), metadata["year"])
+}
+
 func TestHNSWConfiguration(t *testing.T) {
 	config := &HNSWConfiguration{
 		Dimension: 384,
@@ -151,6 +212,38 @@ func TestDistanceMetrics(t *testing.T) {
 	assert.Equal(t, DistanceMetric("inner_product"), DistanceInnerProduct)
 }
 
+func TestDistanceMetricHigherIsBetter(t *testing.T) {
+	assert.True(t, DistanceInnerProduct.HigherIsBetter())
+	assert.False(t, DistanceL2.HigherIsBetter())
+	assert.False(t, DistanceCosine.HigherIsBetter())
+}
+
+func TestDistanceMetricValidate(t *testing.T) {
+	for _, d := range []DistanceMetric{DistanceL2, DistanceCosine, DistanceInnerProduct} {
+		assert.NoError(t, d.Validate())
+	}
+	assert.ErrorIs(t, DistanceMetric("cosin").Validate(), ErrInvalidParameter)
+	assert.ErrorIs(t, DistanceMetric("").Validate(), ErrInvalidParameter)
+
+	d, err := DistanceMetricFromString(" COSINE ")
+	require.NoError(t, err)
+	assert.Equal(t, DistanceCosine, d)
+
+	d, err = DistanceMetricFromString("ip")
+	require.NoError(t, err)
+	assert.Equal(t, DistanceInnerProduct, d)
+
+	_, err = DistanceMetricFromString("manhattan")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	assert.NoError(t, validateHNSWConfiguration(&HNSWConfiguration{Dimension: 3}))
+	assert.ErrorIs(t, validateHNSWConfiguration(&HNSWConfiguration{Dimension: 3, Distance: "cosin"}), ErrInvalidParameter)
+	assert.ErrorIs(t, validateHNSWConfiguration(&HNSWConfiguration{
+		Dimension:    3,
+		VectorFields: []VectorField{{Name: "title", Dimension: 3, Distance: "l1"}},
+	}), ErrInvalidParameter)
+}
+
 // Integration tests would go here
 // These would require an actual SeekDB instance running
 
@@ -192,3 +285,179 @@ func TestIntegration_BasicOperations(t *testing.T) {
 	require.NoError(t, err)
 }
 */
+
+func TestVectorFieldDefinitions(t *testing.T) {
+	config := &HNSWConfiguration{
+		Dimension: 384,
+		Distance:  DistanceCosine,
+		VectorFields: []VectorField{
+			{Name: "title", Dimension: 384},
+			{Name: "body", Dimension: 768, Distance: DistanceL2},
+		},
+	}
+
+	definitions, err := vectorFieldDefinitions(config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"`embedding_title` VECTOR(384)",
+		"VECTOR INDEX `idx_embedding_title` (`embedding_title`) WITH (distance=cosine, type=hnsw, lib=vsag)",
+		"`embedding_body` VECTOR(768)",
+		"VECTOR INDEX `idx_embedding_body` (`embedding_body`) WITH (distance=l2, type=hnsw, lib=vsag)",
+	}, definitions)
+
+	assert.Equal(t, FieldEmbedding, VectorFieldColumn(""))
+	assert.Equal(t, "embedding_title", VectorFieldColumn("title"))
+
+	_, err = vectorFieldDefinitions(&HNSWConfiguration{VectorFields: []VectorField{{Name: "a", Dimension: 3}, {Name: "a", Dimension: 3}}})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	_, err = vectorFieldDefinitions(&HNSWConfiguration{VectorFields: []VectorField{{Name: "a"}}})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	for _, name := range []string{"", "x; DROP TABLE t", "title`", "1title", "ti-tle", strings.Repeat("a", 60)} {
+		_, err = vectorFieldDefinitions(&HNSWConfiguration{VectorFields: []VectorField{{Name: name, Dimension: 3}}})
+		assert.ErrorIs(t, err, ErrInvalidParameter, name)
+	}
+}
+
+// TestVectorFieldValidation tests vector field names, dimensions and distances
+func TestVectorFieldValidation(t *testing.T) {
+	assert.NoError(t, validateVectorFieldName("title_2"))
+	assert.NoError(t, validateVectorFieldName("_body"))
+	assert.ErrorIs(t, validateVectorFieldName("x; DROP TABLE t"), ErrInvalidParameter)
+
+	assert.Equal(t, FieldEmbedding, vectorFieldColumnSQL(""))
+	assert.Equal(t, "`embedding_title`", vectorFieldColumnSQL("title"))
+
+	assert.NoError(t, checkVectorFieldDimension("title", 3, [][]float32{{1, 2, 3}, {4, 5, 6}}))
+	assert.ErrorIs(t, checkVectorFieldDimension("title", 3, [][]float32{{1, 2, 3}, {4, 5}}), ErrInvalidParameter)
+
+	meta := newCollectionMeta(&HNSWConfiguration{
+		Dimension: 3,
+		Distance:  DistanceCosine,
+		VectorFields: []VectorField{
+			{Name: "title", Dimension: 3, Distance: DistanceL2},
+			{Name: "body", Dimension: 3},
+		},
+	}, nil)
+	distance, err := meta.vectorFieldDistance("title", meta.Distance)
+	require.NoError(t, err)
+	assert.Equal(t, DistanceL2, distance)
+	distance, err = meta.vectorFieldDistance("body", meta.Distance)
+	require.NoError(t, err)
+	assert.Equal(t, DistanceCosine, distance)
+	_, err = meta.vectorFieldDistance("summary", meta.Distance)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	// Metadata of older collections does not list vector fields
+	distance, err = collectionMeta{Distance: DistanceCosine}.vectorFieldDistance("title", DistanceCosine)
+	require.NoError(t, err)
+	assert.Equal(t, DistanceCosine, distance)
+
+	// The persisted fields survive the table comment round trip
+	option, err := meta.tableOption()
+	require.NoError(t, err)
+	comment := strings.TrimSuffix(strings.TrimPrefix(option, "COMMENT = '"), "'")
+	parsed, ok, err := parseCollectionMeta(comment)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, meta.VectorFields, parsed.VectorFields)
+}
+
+// TestBuildVectorQuerySQLQueryField tests searching a quoted named vector field column
+func TestBuildVectorQuerySQLQueryField(t *testing.T) {
+	querySQL, _ := buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2}, 5, DistanceL2, "", &QueryOptions{QueryField: "title"})
+	assert.Contains(t, querySQL, "l2_distance(`embedding_title`, ?)")
+}
+
+func TestVectorIndexOptions(t *testing.T) {
+	assert.Equal(t, "distance=l2, type=hnsw, lib=vsag", vectorIndexOptions(DistanceL2, nil))
+	assert.Equal(t, "distance=l2, type=hnsw, lib=vsag", vectorIndexOptions(DistanceL2, &HNSWConfiguration{}))
+	assert.Equal(t, "distance=cosine, type=hnsw, lib=vsag, m=32, ef_construction=400, ef_search=128",
+		vectorIndexOptions(DistanceCosine, &HNSWConfiguration{M: 32, EfConstruction: 400, EfSearch: 128}))
+	assert.Equal(t, "distance=cosine, type=hnsw, lib=vsag, ef_search=100",
+		vectorIndexOptions(DistanceCosine, &HNSWConfiguration{EfSearch: 100}))
+
+	// Named vector field indexes are tuned like the default one
+	definitions, err := vectorFieldDefinitions(&HNSWConfiguration{M: 24, VectorFields: []VectorField{{Name: "title", Dimension: 3}}})
+	require.NoError(t, err)
+	assert.Equal(t, "VECTOR INDEX `idx_embedding_title` (`embedding_title`) WITH (distance=cosine, type=hnsw, lib=vsag, m=24)", definitions[1])
+
+	assert.ErrorIs(t, validateHNSWConfiguration(&HNSWConfiguration{M: -1}), ErrInvalidParameter)
+	assert.ErrorIs(t, validateHNSWConfiguration(&HNSWConfiguration{EfSearch: -1}), ErrInvalidParameter)
+}
+
+// TestCreateCollectionValidatesConfiguration tests that invalid configurations fail
+// before anything is sent to the server
+func TestCreateCollectionValidatesConfiguration(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{config: &ClientConfig{}}
+
+	_, err := client.CreateCollection(ctx, "docs", WithConfiguration(&HNSWConfiguration{Dimension: 3, Distance: "cosin"}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	_, err = client.CreateCollection(ctx, "docs", WithConfiguration(&HNSWConfiguration{Dimension: 3, M: -1}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestCreateCollectionSQL tests the CREATE TABLE statement of new collections
+func TestCreateCollectionSQL(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	createSQL, err := client.createCollectionSQL("docs", 3, DistanceL2, &CreateCollectionOptions{}, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(createSQL, "CREATE TABLE `c$v1$docs` ("), createSQL)
+	assert.Contains(t, createSQL, "_id VARBINARY(512) PRIMARY KEY NOT NULL,")
+	assert.Contains(t, createSQL, "embedding VECTOR(3),")
+	assert.Contains(t, createSQL, "FULLTEXT INDEX idx_fts (document) WITH PARSER ik,")
+	assert.Contains(t, createSQL, "VECTOR INDEX idx_embedding (embedding) WITH (distance=l2, type=hnsw, lib=vsag)")
+	assert.Contains(t, createSQL, "metadata JSON,")
+
+	createSQL, err = client.createCollectionSQL("docs", 3, DistanceL2, &CreateCollectionOptions{IndexedMetadataKeys: []string{"category"}}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, createSQL, "_meta_category VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.category'))) VIRTUAL,")
+	assert.Contains(t, createSQL, "KEY idx_meta_category (_meta_category),")
+
+	// The default index is tuned like named vector field indexes
+	createSQL, err = client.createCollectionSQL("docs", 3, DistanceCosine, &CreateCollectionOptions{Configuration: &HNSWConfiguration{M: 32, EfConstruction: 400}}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, createSQL, "VECTOR INDEX idx_embedding (embedding) WITH (distance=cosine, type=hnsw, lib=vsag, m=32, ef_construction=400)")
+
+	// Named vector fields get their own column and index
+	options := &CreateCollectionOptions{Configuration: &HNSWConfiguration{
+		Dimension:    3,
+		VectorFields: []VectorField{{Name: "title", Dimension: 8}},
+	}}
+	createSQL, err = client.createCollectionSQL("docs", 3, DistanceL2, options, nil)
+	require.NoError(t, err)
+	assert.Contains(t, createSQL, "`embedding_title` VECTOR(8),")
+	assert.Contains(t, createSQL, "VECTOR INDEX `idx_embedding_title` (`embedding_title`)")
+
+	options.Configuration.VectorFields = append(options.Configuration.VectorFields, VectorField{Name: "title", Dimension: 8})
+	_, err = client.createCollectionSQL("docs", 3, DistanceL2, options, nil)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestWriteSQLTableName tests that writes go to the table of the client's table prefix
+func TestWriteSQLTableName(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{TablePrefix: "app_"}}
+	add := &AddOptions{Embeddings: [][]float32{{1, 2, 3}}}
+
+	var dryRun *DryRunError
+	_, err := client.collectionAdd(ctx, "docs", []string{"id1"}, []string{"doc"}, add, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.True(t, strings.HasPrefix(dryRun.Statements[0].SQL, "INSERT INTO app_docs "), dryRun.Statements[0].SQL)
+
+	_, err = client.collectionUpsert(ctx, "docs", []string{"id1"}, []string{"doc"}, add, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.True(t, strings.HasPrefix(dryRun.Statements[0].SQL, "INSERT INTO app_docs "), dryRun.Statements[0].SQL)
+
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{Documents: []string{"new"}}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "UPDATE app_docs SET document = ? WHERE _id = ?", dryRun.Statements[0].SQL)
+
+	_, err = client.collectionDelete(ctx, "docs", []string{"id1"}, nil, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "DELETE FROM app_docs WHERE _id IN (?)", dryRun.Statements[0].SQL)
+}
@@ -0,0 +1,39 @@
+package goseekdb
+
+import (
+	"context"
+	"time"
+)
+
+// withOperationTimeout bounds ctx by timeout; a non-positive timeout leaves ctx as is.
+func withOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withDefaultTimeout bounds ctx by timeout unless it already has a deadline,
+// so per-call timeouts and caller deadlines take precedence over client defaults.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return withOperationTimeout(ctx, timeout)
+}
+
+// readContext applies the client's ReadTimeout to a read operation without a deadline.
+func (c *Client) readContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config == nil {
+		return ctx, func() {}
+	}
+	return withDefaultTimeout(ctx, c.config.ReadTimeout)
+}
+
+// writeContext applies the client's WriteTimeout to a write operation without a deadline.
+func (c *Client) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config == nil {
+		return ctx, func() {}
+	}
+	return withDefaultTimeout(ctx, c.config.WriteTimeout)
+}
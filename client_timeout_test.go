@@ -0,0 +1,144 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadlineRecorder records whether collection operations received a deadline.
+type deadlineRecorder struct {
+	fakeOperations
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (d *deadlineRecorder) collectionQuery(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*QueryResult, error) {
+	d.deadline, d.hasDeadline = ctx.Deadline()
+	return &QueryResult{}, nil
+}
+
+func (d *deadlineRecorder) collectionGet(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*GetResult, error) {
+	d.deadline, d.hasDeadline = ctx.Deadline()
+	return &GetResult{}, nil
+}
+
+// TestCollectionOperationTimeouts tests that per-call timeouts bound the context
+func TestCollectionOperationTimeouts(t *testing.T) {
+	ctx := context.Background()
+	recorder := &deadlineRecorder{}
+	collection := &Collection{client: recorder, name: "timeouts", dimension: 3, distance: DistanceL2}
+
+	_, err := collection.Query(ctx, nil, 1, WithTimeout(time.Minute))
+	require.NoError(t, err)
+	require.True(t, recorder.hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), recorder.deadline, 5*time.Second)
+
+	_, err = collection.Get(ctx, nil, WithGetTimeout(time.Second))
+	require.NoError(t, err)
+	require.True(t, recorder.hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Second), recorder.deadline, 500*time.Millisecond)
+
+	_, err = collection.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.False(t, recorder.hasDeadline)
+}
+
+// TestClientDefaultTimeouts tests that ReadTimeout/WriteTimeout only apply without a deadline
+func TestClientDefaultTimeouts(t *testing.T) {
+	config := DefaultClientConfig()
+	config.ReadTimeout = time.Minute
+	config.WriteTimeout = 2 * time.Minute
+	client := &Client{config: config}
+
+	readCtx, cancel := client.readContext(context.Background())
+	defer cancel()
+	deadline, ok := readCtx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+
+	writeCtx, cancel := client.writeContext(context.Background())
+	defer cancel()
+	deadline, ok = writeCtx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), deadline, 5*time.Second)
+
+	// An existing deadline, e.g. from a per-call timeout, takes precedence
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), time.Second)
+	defer cancelShort()
+	readCtx, cancel = client.readContext(shortCtx)
+	defer cancel()
+	deadline, _ = readCtx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 500*time.Millisecond)
+
+	// A zero timeout disables the default
+	config.ReadTimeout = 0
+	readCtx, cancel = client.readContext(context.Background())
+	defer cancel()
+	_, ok = readCtx.Deadline()
+	assert.False(t, ok)
+}
+
+// writeDeadlineConnection records whether writes received a deadline.
+type writeDeadlineConnection struct {
+	connection.Connection
+	tx          writeDeadlineTx
+	hasDeadline bool
+}
+
+func (w *writeDeadlineConnection) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	_, w.hasDeadline = ctx.Deadline()
+	return driver.RowsAffected(1), nil
+}
+
+func (w *writeDeadlineConnection) Begin(ctx context.Context) (connection.Tx, error) {
+	_, w.hasDeadline = ctx.Deadline()
+	return &w.tx, nil
+}
+
+type writeDeadlineTx struct {
+	connection.Tx
+}
+
+func (w *writeDeadlineTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (w *writeDeadlineTx) Commit() error   { return nil }
+func (w *writeDeadlineTx) Rollback() error { return nil }
+
+// TestWriteOperationsWriteTimeout tests that the client's WriteTimeout bounds add,
+// update, upsert and delete
+func TestWriteOperationsWriteTimeout(t *testing.T) {
+	ctx := context.Background()
+	conn := &writeDeadlineConnection{}
+	client := &Client{conn: conn, config: &ClientConfig{WriteTimeout: time.Minute}, filterBuilder: NewFilterBuilder()}
+	ids := []string{"a"}
+	embeddings := [][]float32{{1, 2, 3}}
+
+	_, err := client.collectionAdd(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	assert.True(t, conn.hasDeadline, "add")
+
+	conn.hasDeadline = false
+	_, err = client.collectionUpdate(ctx, "docs", ids, &UpdateOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	assert.True(t, conn.hasDeadline, "update")
+
+	conn.hasDeadline = false
+	_, err = client.collectionUpsert(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	assert.True(t, conn.hasDeadline, "upsert")
+
+	conn.hasDeadline = false
+	_, err = client.collectionDelete(ctx, "docs", ids, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, conn.hasDeadline, "delete")
+}
@@ -0,0 +1,181 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// vectorFieldDefinitions returns the column and vector index definitions for the
+// named vector fields of a collection, for use in its CREATE TABLE statement.
+func vectorFieldDefinitions(config *HNSWConfiguration) ([]string, error) {
+	if config == nil || len(config.VectorFields) == 0 {
+		return nil, nil
+	}
+	if err := validateHNSWConfiguration(config); err != nil {
+		return nil, err
+	}
+
+	var definitions []string
+	seen := make(map[string]bool)
+	for _, field := range config.VectorFields {
+		if err := validateVectorFieldName(field.Name); err != nil {
+			return nil, err
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("%w: duplicate vector field %q", ErrInvalidParameter, field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Dimension <= 0 {
+			return nil, fmt.Errorf("%w: vector field %q must have a positive dimension", ErrInvalidParameter, field.Name)
+		}
+
+		distance := field.Distance
+		if distance == "" {
+			distance = config.Distance
+		}
+		if distance == "" {
+			distance = DefaultDistanceMetric
+		}
+
+		column := VectorFieldColumn(field.Name)
+		definitions = append(definitions,
+			fmt.Sprintf("`%s` VECTOR(%d)", column, field.Dimension),
+			fmt.Sprintf("VECTOR INDEX `idx_%s` (`%s`) WITH (%s)", column, column, vectorIndexOptions(distance, config)),
+		)
+	}
+
+	return definitions, nil
+}
+
+// collectionSetVectorFields stores embeddings for named vector fields of existing rows.
+func (c *Client) collectionSetVectorFields(ctx context.Context, collectionName string, ids []string, namedEmbeddings map[string][][]float32) (err error) {
+	if len(namedEmbeddings) == 0 {
+		return nil
+	}
+
+	ctx, span := c.startSpan(ctx, "set_vector_fields", collectionName)
+	defer func() { span.end(len(ids), err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	// Iterate fields in a stable order so statements are deterministic
+	names := make([]string, 0, len(namedEmbeddings))
+	for name, embeddings := range namedEmbeddings {
+		if err := validateVectorFieldName(name); err != nil {
+			return err
+		}
+		if len(embeddings) != len(ids) {
+			return fmt.Errorf("%w: vector field %q has %d embeddings for %d ids", ErrInvalidParameter, name, len(embeddings), len(ids))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Check the embeddings against the dimension each field's column was declared with
+	for _, name := range names {
+		columnType, err := c.vectorColumnType(ctx, collectionName, VectorFieldColumn(name))
+		if err != nil {
+			return err
+		}
+		dimension, ok := parseVectorColumnDimension(columnType)
+		if !ok {
+			return fmt.Errorf("%w: collection %s has no vector field %q", ErrInvalidParameter, collectionName, name)
+		}
+		if err := checkVectorFieldDimension(name, dimension, namedEmbeddings[name]); err != nil {
+			return err
+		}
+	}
+
+	tableName := c.GetTableName(collectionName)
+
+	return c.retryOnDeadlock(ctx, func() error {
+		tx, err := c.conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		for _, name := range names {
+			updateSQL := fmt.Sprintf("UPDATE %s SET `%s` = ? WHERE %s = ?", tableName, VectorFieldColumn(name), FieldID)
+			for i, id := range ids {
+				if _, err := tx.Execute(ctx, updateSQL, vectorToString(namedEmbeddings[name][i]), id); err != nil {
+					return fmt.Errorf("failed to set vector field %q: %w", name, err)
+				}
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// validateVectorFieldName checks that a vector field name is a plain identifier of
+// letters, digits and underscores, as it is part of column and index names.
+func validateVectorFieldName(name string) error {
+	if !isPlainIdentifier(name) || len("idx_"+VectorFieldColumn(name)) > maxColumnNameLength {
+		return fmt.Errorf("%w: invalid vector field name %q, expected letters, digits and underscores not starting with a digit", ErrInvalidParameter, name)
+	}
+	return nil
+}
+
+// vectorFieldColumnSQL returns the column to search for a named vector field in a
+// statement, quoted, or the default embedding column for "".
+func vectorFieldColumnSQL(name string) string {
+	if name == "" {
+		return FieldEmbedding
+	}
+	return "`" + VectorFieldColumn(name) + "`"
+}
+
+// checkVectorFieldDimension checks that every embedding of a vector field has the
+// field's dimension.
+func checkVectorFieldDimension(name string, dimension int, embeddings [][]float32) error {
+	for i, embedding := range embeddings {
+		if len(embedding) != dimension {
+			return fmt.Errorf("%w: vector field %q embedding %d has dimension %d, expected %d",
+				ErrInvalidParameter, name, i, len(embedding), dimension)
+		}
+	}
+	return nil
+}
+
+// vectorFieldDistance returns the distance metric to search a named vector field
+// with: the field's own, or distance (the collection's) if it declares none.
+func (c *Client) vectorFieldDistance(ctx context.Context, collectionName, field string, distance DistanceMetric) (DistanceMetric, error) {
+	if err := validateVectorFieldName(field); err != nil {
+		return "", err
+	}
+	meta, ok, err := c.readCollectionMeta(ctx, collectionName)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return distance, nil
+	}
+	return meta.vectorFieldDistance(field, distance)
+}
+
+// vectorFieldDistance returns the distance of a named vector field declared in m,
+// or distance if the field declares none. Metadata without vector fields, e.g. of
+// collections created by older clients, falls back to distance as well.
+func (m collectionMeta) vectorFieldDistance(field string, distance DistanceMetric) (DistanceMetric, error) {
+	if len(m.VectorFields) == 0 {
+		return distance, nil
+	}
+	for _, f := range m.VectorFields {
+		if f.Name != field {
+			continue
+		}
+		if f.Distance != "" {
+			return f.Distance, nil
+		}
+		return distance, nil
+	}
+	return "", fmt.Errorf("%w: collection has no vector field %q", ErrInvalidParameter, field)
+}
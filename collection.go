@@ -2,7 +2,9 @@ package goseekdb
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/ob-labs/seekdb-go/embedding"
 )
 
@@ -14,19 +16,37 @@ type Collection struct {
 	dimension     int
 	distance      DistanceMetric
 	embeddingFunc embedding.EmbeddingFunc
+
+	// treatEmptyAsNull stores "" documents as NULL (see WithTreatEmptyAsNull).
+	treatEmptyAsNull bool
+	// contentHash dedupes documents by their SHA256 (see WithContentHash).
+	contentHash bool
+	// vectorsOnly means the table has no document and metadata columns (see WithVectorsOnly).
+	vectorsOnly bool
 }
 
 // collectionOperations defines the interface for collection operations on the client.
 // This is implemented by the Client type.
 type collectionOperations interface {
-	collectionAdd(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) error
-	collectionUpdate(ctx context.Context, collectionName string, ids []string, opts *UpdateOptions, embFunc embedding.EmbeddingFunc) error
-	collectionUpsert(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) error
-	collectionDelete(ctx context.Context, collectionName string, ids []string, where Filter, whereDocument Filter) error
+	collectionAdd(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (int64, error)
+	collectionUpdate(ctx context.Context, collectionName string, ids []string, opts *UpdateOptions, embFunc embedding.EmbeddingFunc) (int64, error)
+	collectionUpsert(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (UpsertResult, error)
+	collectionDelete(ctx context.Context, collectionName string, ids []string, where Filter, whereDocument Filter) (int64, error)
 	collectionQuery(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*QueryResult, error)
+	collectionQueryEach(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric, fn func(SearchRecord) error) error
 	collectionGet(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*GetResult, error)
-	collectionCount(ctx context.Context, collectionName string) (int, error)
+	collectionGetStream(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*HitIterator, error)
+	collectionCount(ctx context.Context, collectionName string, where Filter, whereDocument Filter) (int, error)
 	collectionHybridSearch(ctx context.Context, collectionName string, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*HybridSearchResult, error)
+	collectionSetVectorFields(ctx context.Context, collectionName string, ids []string, namedEmbeddings map[string][][]float32) error
+	collectionRename(ctx context.Context, oldName, newName string) error
+	collectionCopyFrom(ctx context.Context, collectionName string, records <-chan Record, opts *CopyOptions, embFunc embedding.EmbeddingFunc) (int64, error)
+	collectionFindByContentHash(ctx context.Context, collectionName string, hash string) (string, bool, error)
+	collectionExists(ctx context.Context, collectionName string, id string) (bool, error)
+	collectionReset(ctx context.Context, collectionName string) error
+	collectionCreateMetadataIndex(ctx context.Context, collectionName, key string, opts *MetadataIndexOptions) error
+	collectionDropMetadataIndex(ctx context.Context, collectionName, key string) error
+	collectionListMetadataIndexes(ctx context.Context, collectionName string) ([]string, error)
 }
 
 // Name returns the collection name.
@@ -44,40 +64,257 @@ func (c *Collection) Distance() DistanceMetric {
 	return c.distance
 }
 
+// VectorsOnly reports whether the collection stores only IDs and embeddings,
+// without documents or metadata (see WithVectorsOnly).
+func (c *Collection) VectorsOnly() bool {
+	return c.vectorsOnly
+}
+
 // Add adds documents to the collection.
 // If embeddings are not provided, they will be generated using the embedding function.
+// If ids is nil, a random UUID is generated per document; use AddAutoID to get them.
+// An ID given more than once fails the whole call with ErrDuplicateID.
 func (c *Collection) Add(ctx context.Context, ids []string, documents []string, opts ...AddOption) error {
+	_, err := c.AddN(ctx, ids, documents, opts...)
+	return err
+}
+
+// AddN adds documents like Add and returns the number of rows inserted.
+func (c *Collection) AddN(ctx context.Context, ids []string, documents []string, opts ...AddOption) (int64, error) {
+	options := &AddOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.emptyDocumentsAsNull = c.treatEmptyAsNull
+	options.contentHash = c.contentHash
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	if err := validateEmbeddingDimensions(options.Embeddings, c.dimension); err != nil {
+		return 0, err
+	}
+	if c.vectorsOnly {
+		if err := validateVectorsOnlyWrite(c.name, documents, options.Metadatas); err != nil {
+			return 0, err
+		}
+	}
+	if ids == nil {
+		ids = generateIDs(documents, options)
+	}
+	if err := validateUniqueIDs(ids); err != nil {
+		return 0, err
+	}
+
+	inserted, err := c.client.collectionAdd(ctx, c.name, ids, documents, options, c.embeddingFunc)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.client.collectionSetVectorFields(ctx, c.name, ids, options.NamedEmbeddings); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// AddAutoID adds documents like Add with a random UUID as the ID of each document,
+// and returns the generated IDs in document order. Documents may be nil when
+// embeddings are provided with WithEmbeddings.
+func (c *Collection) AddAutoID(ctx context.Context, documents []string, opts ...AddOption) ([]string, error) {
 	options := &AddOptions{}
 	for _, opt := range opts {
 		opt(options)
 	}
-	return c.client.collectionAdd(ctx, c.name, ids, documents, options, c.embeddingFunc)
+	ids := generateIDs(documents, options)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no documents or embeddings to add", ErrInvalidParameter)
+	}
+
+	if _, err := c.AddN(ctx, ids, documents, opts...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// generateIDs returns a random UUID for each record of an Add or Upsert, counted
+// from the documents or, without documents, the embeddings or metadatas.
+func generateIDs(documents []string, opts *AddOptions) []string {
+	n := len(documents)
+	if n == 0 {
+		n = len(opts.Embeddings)
+	}
+	if n == 0 {
+		n = len(opts.Metadatas)
+	}
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	return ids
+}
+
+// CopyFrom bulk loads records from a channel until it is closed, using batched
+// multi-row INSERTs on a pool of concurrent workers. It is meant for initial loads
+// of large datasets; unlike Add, records are not written atomically as a whole.
+// Failed batches do not stop the load: CopyFrom returns the number of rows copied
+// together with all batch errors joined. Cancelling ctx stops reading records.
+func (c *Collection) CopyFrom(ctx context.Context, records <-chan Record, opts ...CopyOption) (int64, error) {
+	options := &CopyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.emptyDocumentsAsNull = c.treatEmptyAsNull
+	options.contentHash = c.contentHash
+	options.vectorsOnly = c.vectorsOnly
+
+	return c.client.collectionCopyFrom(ctx, c.name, records, options, c.embeddingFunc)
+}
+
+// ExistsByContent reports whether a document with exactly this text is stored,
+// and if so its ID. The collection must have been created with WithContentHash.
+func (c *Collection) ExistsByContent(ctx context.Context, text string) (bool, string, error) {
+	if !c.contentHash {
+		return false, "", fmt.Errorf("%w: collection %q was not created with content hashing", ErrInvalidParameter, c.name)
+	}
+	if text == "" {
+		return false, "", nil // Empty documents are never hashed
+	}
+
+	id, found, err := c.client.collectionFindByContentHash(ctx, c.name, contentHash(text))
+	if err != nil {
+		return false, "", err
+	}
+	return found, id, nil
 }
 
 // Update updates existing documents in the collection.
+// Documents updated without embeddings are re-embedded with the collection's
+// embedding function, if it has one, unless WithSkipReembed is set.
 func (c *Collection) Update(ctx context.Context, ids []string, opts ...UpdateOption) error {
+	_, err := c.UpdateN(ctx, ids, opts...)
+	return err
+}
+
+// UpdateN updates documents like Update and returns the number of rows changed.
+// Rows whose values were already identical are not counted.
+func (c *Collection) UpdateN(ctx context.Context, ids []string, opts ...UpdateOption) (int64, error) {
 	options := &UpdateOptions{}
 	for _, opt := range opts {
 		opt(options)
 	}
+	options.emptyDocumentsAsNull = c.treatEmptyAsNull
+	options.contentHash = c.contentHash
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	if err := validateEmbeddingDimensions(options.Embeddings, c.dimension); err != nil {
+		return 0, err
+	}
+	if c.vectorsOnly {
+		if err := validateVectorsOnlyWrite(c.name, options.Documents, options.Metadatas); err != nil {
+			return 0, err
+		}
+		if len(options.RemoveMetadataKeys) > 0 {
+			return 0, vectorsOnlyError(c.name, "metadata")
+		}
+	}
+	if err := validateRemoveMetadataKeys(options.RemoveMetadataKeys); err != nil {
+		return 0, err
+	}
+
 	return c.client.collectionUpdate(ctx, c.name, ids, options, c.embeddingFunc)
 }
 
 // Upsert inserts or updates documents in the collection.
+// If ids is nil, a random UUID is generated per document, so all are inserted.
+// If an ID is given more than once, the last of its records wins: the earlier ones
+// are dropped before any SQL is issued and are not counted by UpsertN.
+// Existing rows are replaced as a whole unless WithUpsertMergeMetadata is set.
 func (c *Collection) Upsert(ctx context.Context, ids []string, documents []string, opts ...AddOption) error {
+	_, err := c.UpsertN(ctx, ids, documents, opts...)
+	return err
+}
+
+// UpsertN upserts documents like Upsert and reports how many were inserts and how many updates.
+func (c *Collection) UpsertN(ctx context.Context, ids []string, documents []string, opts ...AddOption) (UpsertResult, error) {
 	options := &AddOptions{}
 	for _, opt := range opts {
 		opt(options)
 	}
-	return c.client.collectionUpsert(ctx, c.name, ids, documents, options, c.embeddingFunc)
+	options.emptyDocumentsAsNull = c.treatEmptyAsNull
+	options.contentHash = c.contentHash
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	if err := validateEmbeddingDimensions(options.Embeddings, c.dimension); err != nil {
+		return UpsertResult{}, err
+	}
+	if c.vectorsOnly {
+		if err := validateVectorsOnlyWrite(c.name, documents, options.Metadatas); err != nil {
+			return UpsertResult{}, err
+		}
+		if options.MergeMetadata {
+			return UpsertResult{}, vectorsOnlyError(c.name, "metadata")
+		}
+	}
+	if ids == nil {
+		ids = generateIDs(documents, options)
+	}
+	ids, documents = lastWinsBatch(ids, documents, options)
+
+	result, err := c.client.collectionUpsert(ctx, c.name, ids, documents, options, c.embeddingFunc)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	if err := c.client.collectionSetVectorFields(ctx, c.name, ids, options.NamedEmbeddings); err != nil {
+		return UpsertResult{}, err
+	}
+	return result, nil
 }
 
 // Delete deletes documents from the collection.
 // You can delete by IDs, by filter, or both.
 func (c *Collection) Delete(ctx context.Context, ids []string, where Filter, whereDocument Filter) error {
+	_, err := c.DeleteN(ctx, ids, where, whereDocument)
+	return err
+}
+
+// DeleteN deletes documents like Delete and returns the number of rows removed.
+func (c *Collection) DeleteN(ctx context.Context, ids []string, where Filter, whereDocument Filter) (int64, error) {
 	return c.client.collectionDelete(ctx, c.name, ids, where, whereDocument)
 }
 
+// DeleteDryRun returns the documents that Delete would remove with the same
+// arguments, without deleting anything. All matches are returned, ordered by ID.
+func (c *Collection) DeleteDryRun(ctx context.Context, ids []string, where Filter, whereDocument Filter) (*GetResult, error) {
+	result := &GetResult{}
+	options := &GetOptions{
+		Where:         where,
+		WhereDocument: whereDocument,
+		Limit:         1000,
+		paginate:      true,
+		vectorsOnly:   c.vectorsOnly,
+	}
+
+	// Page through all matches; unlike Get, a preview must not be truncated
+	for {
+		page, err := c.client.collectionGet(ctx, c.name, ids, options)
+		if err != nil {
+			return nil, err
+		}
+		result.IDs = append(result.IDs, page.IDs...)
+		result.Documents = append(result.Documents, page.Documents...)
+		result.Metadatas = append(result.Metadatas, page.Metadatas...)
+		result.Embeddings = append(result.Embeddings, page.Embeddings...)
+
+		if len(page.IDs) < options.Limit {
+			return result, nil
+		}
+		options.Cursor = encodeGetCursor(page.IDs[len(page.IDs)-1])
+	}
+}
+
 // Query performs a vector similarity search.
 // Either queryTexts or QueryEmbeddings (via WithQueryEmbeddings option) must be provided.
 func (c *Collection) Query(ctx context.Context, queryTexts []string, nResults int, opts ...QueryOption) (*QueryResult, error) {
@@ -85,28 +322,261 @@ func (c *Collection) Query(ctx context.Context, queryTexts []string, nResults in
 	for _, opt := range opts {
 		opt(options)
 	}
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
 	return c.client.collectionQuery(ctx, c.name, queryTexts, nResults, options, c.embeddingFunc, c.distance)
 }
 
+// QueryWithCallback performs a vector similarity search like Query, but calls fn for
+// each hit as it is scanned, in rank order, instead of materializing a QueryResult.
+// If fn returns an error, scanning stops and that error is returned.
+func (c *Collection) QueryWithCallback(ctx context.Context, queryTexts []string, nResults int, fn func(SearchRecord) error, opts ...QueryOption) error {
+	options := &QueryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	return c.client.collectionQueryEach(ctx, c.name, queryTexts, nResults, options, c.embeddingFunc, c.distance, fn)
+}
+
+// QueryOne performs a vector similarity search for a single query embedding and
+// returns its hits in rank order. It is a shorthand for Query with
+// WithQueryEmbeddings([][]float32{embedding}) that unpacks the first result;
+// any WithQueryEmbeddings option is ignored.
+func (c *Collection) QueryOne(ctx context.Context, embedding []float32, nResults int, opts ...QueryOption) ([]Hit, error) {
+	if len(embedding) == 0 {
+		return nil, fmt.Errorf("%w: query embedding is empty", ErrInvalidParameter)
+	}
+
+	options := &QueryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.vectorsOnly = c.vectorsOnly
+	options.QueryEmbeddings = [][]float32{embedding}
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	result, err := c.client.collectionQuery(ctx, c.name, nil, nResults, options, c.embeddingFunc, c.distance)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.IDs) == 0 {
+		return []Hit{}, nil
+	}
+
+	hits := make([]Hit, len(result.IDs[0]))
+	for i, id := range result.IDs[0] {
+		hits[i].ID = id
+		if len(result.Distances) > 0 && i < len(result.Distances[0]) {
+			hits[i].Distance = result.Distances[0][i]
+		}
+		if len(result.Documents) > 0 && i < len(result.Documents[0]) {
+			hits[i].Document = result.Documents[0][i]
+		}
+		if len(result.Metadatas) > 0 && i < len(result.Metadatas[0]) {
+			hits[i].Metadata = result.Metadatas[0][i]
+		}
+		if len(result.Embeddings) > 0 && i < len(result.Embeddings[0]) {
+			hits[i].Embedding = result.Embeddings[0][i]
+		}
+		if len(result.RawColumns) > 0 && i < len(result.RawColumns[0]) {
+			hits[i].RawColumns = result.RawColumns[0][i]
+		}
+	}
+	return hits, nil
+}
+
 // Get retrieves documents from the collection.
 // You can filter by IDs, metadata filters, or document filters.
+// Lookups of more than 1000 IDs without an offset, cursor or WithGetOrderBy are
+// split into several queries; their documents are returned once each, in the order
+// of ids.
 func (c *Collection) Get(ctx context.Context, ids []string, opts ...GetOption) (*GetResult, error) {
 	options := &GetOptions{}
 	for _, opt := range opts {
 		opt(options)
 	}
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
 	return c.client.collectionGet(ctx, c.name, ids, options)
 }
 
+// Exists reports whether a document with the given ID is stored.
+func (c *Collection) Exists(ctx context.Context, id string) (bool, error) {
+	return c.client.collectionExists(ctx, c.name, id)
+}
+
+// GetItem retrieves the document with the given ID. It returns an error wrapping
+// ErrDocumentNotFound if there is none. Options other than WithGetInclude and
+// WithGetTimeout have no effect.
+func (c *Collection) GetItem(ctx context.Context, id string, opts ...GetOption) (*Hit, error) {
+	options := &GetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.vectorsOnly = c.vectorsOnly
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	result, err := c.client.collectionGet(ctx, c.name, []string{id}, &GetOptions{Include: options.Include, Limit: 1, vectorsOnly: c.vectorsOnly})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.IDs) == 0 {
+		return nil, fmt.Errorf("%w: %q in collection %s", ErrDocumentNotFound, id, c.name)
+	}
+
+	hit := &Hit{ID: result.IDs[0]}
+	if len(result.Documents) > 0 {
+		hit.Document = result.Documents[0]
+	}
+	if len(result.Metadatas) > 0 {
+		hit.Metadata = result.Metadatas[0]
+	}
+	if len(result.Embeddings) > 0 {
+		hit.Embedding = result.Embeddings[0]
+	}
+	return hit, nil
+}
+
+// GetStream retrieves documents like Get but returns an iterator over the live
+// result rows, so large result sets can be processed one row at a time.
+// Unlike Get, it returns all matching rows unless WithLimit is given.
+// The iterator must be closed by the caller.
+func (c *Collection) GetStream(ctx context.Context, ids []string, opts ...GetOption) (*HitIterator, error) {
+	options := &GetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.vectorsOnly = c.vectorsOnly
+	if options.Limit == 0 {
+		options.NoLimit = true // Rows are not buffered, so Get's default limit does not apply
+	}
+	return c.client.collectionGetStream(ctx, c.name, ids, options)
+}
+
+// GetPage retrieves one page of documents using keyset pagination ordered by ID.
+// Pass the returned NextCursor via WithGetCursor to fetch the following page;
+// unlike WithOffset, pages stay stable and cheap however deep the iteration goes.
+func (c *Collection) GetPage(ctx context.Context, opts ...GetOption) (*GetPageResult, error) {
+	options := &GetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.vectorsOnly = c.vectorsOnly
+	options.paginate = true
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	if options.NoLimit {
+		return nil, fmt.Errorf("%w: WithNoLimit cannot be combined with GetPage", ErrInvalidParameter)
+	}
+	limit := options.Limit
+	if limit == 0 {
+		limit = 1000 // Same default limit as Get
+	}
+	// Fetch one row more than the page, which tells whether another page follows
+	options.Limit = limit + 1
+
+	result, err := c.client.collectionGet(ctx, c.name, nil, options)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &GetPageResult{GetResult: *result}
+	if len(result.IDs) > limit {
+		truncateGetResult(&page.GetResult, limit)
+		page.NextCursor = encodeGetCursor(page.IDs[limit-1])
+	}
+	return page, nil
+}
+
 // Count returns the number of documents in the collection.
 func (c *Collection) Count(ctx context.Context) (int, error) {
-	return c.client.collectionCount(ctx, c.name)
+	return c.CountWithFilter(ctx, nil, nil)
+}
+
+// CountWithFilter returns the number of documents matching the metadata and document
+// filters, without fetching them. Nil filters match all documents.
+func (c *Collection) CountWithFilter(ctx context.Context, where Filter, whereDocument Filter) (int, error) {
+	return c.client.collectionCount(ctx, c.name, where, whereDocument)
 }
 
 // HybridSearch performs a hybrid search combining full-text and vector search.
 // Results are ranked using RRF (Reciprocal Rank Fusion).
-func (c *Collection) HybridSearch(ctx context.Context, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int) (*HybridSearchResult, error) {
-	return c.client.collectionHybridSearch(ctx, c.name, query, knn, rank, nResults, c.embeddingFunc, c.distance)
+func (c *Collection) HybridSearch(ctx context.Context, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, opts ...HybridSearchOption) (*HybridSearchResult, error) {
+	options := &HybridSearchOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	result, err := c.client.collectionHybridSearch(ctx, c.name, query, knn, rank, nResults, c.embeddingFunc, c.distance)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.NormalizedScores {
+		result.normalizeScores()
+	}
+	result.sortByMetadata(options.SecondarySort)
+	return result, nil
+}
+
+// Rename renames the collection, including indexes named after it.
+// On success the Collection refers to the new name.
+func (c *Collection) Rename(ctx context.Context, newName string) error {
+	if err := c.client.collectionRename(ctx, c.name, newName); err != nil {
+		return err
+	}
+	c.name = newName
+	return nil
+}
+
+// Reset removes all documents from the collection while keeping the collection
+// itself, including its configuration and indexes, e.g. to re-ingest data or
+// between tests. It returns an error wrapping ErrCollectionNotFound if the collection
+// does not exist. Reset is irreversible: the documents cannot be recovered.
+func (c *Collection) Reset(ctx context.Context) error {
+	return c.client.collectionReset(ctx, c.name)
+}
+
+// CreateMetadataIndex speeds up filters on a top-level metadata key by adding a
+// generated column holding the key's value and an index on it, as
+// WithIndexedMetadataKeys does at creation. $eq and $in filters with string values
+// on the key then use the index instead of evaluating JSON_EXTRACT on every row.
+// key must be a plain identifier (letters, digits and '_'). Indexing a key that is
+// already indexed fails unless WithMetadataIndexIfNotExists is set. Building the
+// index reads the whole collection, so it takes a while on large collections.
+func (c *Collection) CreateMetadataIndex(ctx context.Context, key string, opts ...MetadataIndexOption) error {
+	options := &MetadataIndexOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if c.vectorsOnly {
+		return vectorsOnlyError(c.name, "metadata")
+	}
+	return c.client.collectionCreateMetadataIndex(ctx, c.name, key, options)
+}
+
+// DropMetadataIndex removes the index and generated column of a metadata key added
+// with CreateMetadataIndex or WithIndexedMetadataKeys. The metadata itself is kept.
+func (c *Collection) DropMetadataIndex(ctx context.Context, key string) error {
+	return c.client.collectionDropMetadataIndex(ctx, c.name, key)
+}
+
+// ListMetadataIndexes returns the indexed metadata keys of the collection, sorted.
+func (c *Collection) ListMetadataIndexes(ctx context.Context) ([]string, error) {
+	return c.client.collectionListMetadataIndexes(ctx, c.name)
 }
 
 // Peek returns the first few items from the collection without any filtering.
@@ -115,5 +585,5 @@ func (c *Collection) Peek(ctx context.Context, limit int) (*GetResult, error) {
 	if limit <= 0 {
 		limit = 10 // Default peek limit
 	}
-	return c.client.collectionGet(ctx, c.name, nil, &GetOptions{Limit: limit})
+	return c.client.collectionGet(ctx, c.name, nil, &GetOptions{Limit: limit, vectorsOnly: c.vectorsOnly})
 }
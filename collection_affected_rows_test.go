@@ -0,0 +1,122 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionAffectedRows tests the row counts reported by AddN, UpdateN, UpsertN and DeleteN
+func TestCollectionAffectedRows(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_affected_rows_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	added, err := collection.AddN(ctx, []string{"id1", "id2", "id3"},
+		[]string{"doc one", "doc two", "doc three"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), added)
+
+	updated, err := collection.UpdateN(ctx, []string{"id1", "id2"},
+		WithUpdateDocuments([]string{"doc one v2", "doc two v2"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+
+	upserted, err := collection.UpsertN(ctx, []string{"id3", "id4"},
+		[]string{"doc three v2", "doc four"},
+		WithEmbeddings([][]float32{{3, 4, 5}, {4, 5, 6}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, UpsertResult{Inserted: 1, Updated: 1}, upserted)
+
+	deleted, err := collection.DeleteN(ctx, []string{"id1", "id4", "missing"}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	deleted, err = collection.DeleteN(ctx, []string{"missing"}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
+
+// TestCollectionAddNCountsRows tests that AddN and Add share the same write path
+func TestCollectionAddNCountsRows(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "affected_rows", dimension: 3, distance: DistanceL2}
+
+	added, err := collection.AddN(ctx, []string{"a", "b"}, []string{"first", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), added)
+
+	require.NoError(t, collection.Add(ctx, []string{"c"}, []string{"third"}))
+	assert.Len(t, store.rows, 3)
+
+	upserted, err := collection.UpsertN(ctx, []string{"d"}, []string{"fourth"})
+	require.NoError(t, err)
+	assert.Equal(t, UpsertResult{Inserted: 1}, upserted)
+}
+
+// affectedRowsConnection is a connection whose transactions report the next of
+// affected for each statement
+type affectedRowsConnection struct {
+	connection.Connection
+	affected []int64
+}
+
+func (a *affectedRowsConnection) Begin(ctx context.Context) (connection.Tx, error) {
+	return &affectedRowsTx{conn: a}, nil
+}
+
+type affectedRowsTx struct {
+	connection.Tx
+	conn *affectedRowsConnection
+}
+
+func (t *affectedRowsTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	n := t.conn.affected[0]
+	t.conn.affected = t.conn.affected[1:]
+	return driver.RowsAffected(n), nil
+}
+
+func (t *affectedRowsTx) Commit() error   { return nil }
+func (t *affectedRowsTx) Rollback() error { return nil }
+
+// TestWriteOperationsAffectedRows tests that add, update and upsert count the rows
+// the server reports as affected
+func TestWriteOperationsAffectedRows(t *testing.T) {
+	ctx := context.Background()
+	ids := []string{"a", "b", "c"}
+	embeddings := [][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}
+
+	conn := &affectedRowsConnection{affected: []int64{1, 0, 1}}
+	client := &Client{conn: conn, config: &ClientConfig{}}
+	added, err := client.collectionAdd(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), added)
+
+	conn.affected = []int64{1, 0, 1}
+	updated, err := client.collectionUpdate(ctx, "docs", ids, &UpdateOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated, "unchanged rows are not counted")
+
+	conn.affected = []int64{1, 2, 0}
+	upserted, err := client.collectionUpsert(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, UpsertResult{Inserted: 1, Updated: 2}, upserted)
+}
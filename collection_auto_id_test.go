@@ -0,0 +1,46 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionAddAutoID tests that generated IDs are returned and stored
+func TestCollectionAddAutoID(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "auto_id", dimension: 3, distance: DistanceL2}
+
+	ids, err := collection.AddAutoID(ctx, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}))
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.NotEqual(t, ids[0], ids[1])
+	for _, id := range ids {
+		parsed, err := uuid.Parse(id)
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(4), parsed.Version())
+	}
+
+	// Without documents, IDs are counted from the embeddings
+	vectorIDs, err := collection.AddAutoID(ctx, nil, WithEmbeddings([][]float32{{7, 8, 9}}))
+	require.NoError(t, err)
+	require.Len(t, vectorIDs, 1)
+
+	// Add and Upsert generate IDs for a nil ids slice
+	require.NoError(t, collection.Add(ctx, nil, []string{"doc 4"}, WithEmbeddings([][]float32{{1, 1, 1}})))
+	require.NoError(t, collection.Upsert(ctx, nil, []string{"doc 5"}, WithEmbeddings([][]float32{{2, 2, 2}})))
+
+	results, err := collection.Get(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, results.IDs, 5)
+	assert.Equal(t, append(ids, vectorIDs...), results.IDs[:3])
+	assert.Equal(t, []string{"doc 1", "doc 2", "", "doc 4", "doc 5"}, results.Documents)
+
+	_, err = collection.AddAutoID(ctx, nil)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
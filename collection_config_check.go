@@ -0,0 +1,35 @@
+package goseekdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrConfigMismatch is returned when getting or creating a collection finds an
+// existing collection whose configuration differs from the requested one.
+var ErrConfigMismatch = errors.New("collection configuration mismatch")
+
+// checkExistingCollectionConfig compares an existing collection returned by
+// get-or-create with the requested configuration, so that a changed dimension or
+// distance in code is not silently ignored because the old table persists. Fields
+// left unset in the request are not compared.
+func checkExistingCollectionConfig(existing *Collection, opts *CreateCollectionOptions) error {
+	if opts.IgnoreConfigMismatch || opts.Configuration == nil {
+		return nil
+	}
+	requested := opts.Configuration
+
+	var mismatches []string
+	if requested.Dimension > 0 && requested.Dimension != existing.dimension {
+		mismatches = append(mismatches, fmt.Sprintf("dimension %d, requested %d", existing.dimension, requested.Dimension))
+	}
+	if requested.Distance != "" && requested.Distance != existing.distance {
+		mismatches = append(mismatches, fmt.Sprintf("distance %s, requested %s", existing.distance, requested.Distance))
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: collection %q exists with %s; delete it, use a new name or pass WithIgnoreConfigMismatch(true)",
+		ErrConfigMismatch, existing.name, strings.Join(mismatches, " and "))
+}
@@ -0,0 +1,71 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckExistingCollectionConfig tests detecting configuration drift in get-or-create
+func TestCheckExistingCollectionConfig(t *testing.T) {
+	existing := &Collection{name: "docs", dimension: 384, distance: DistanceCosine}
+
+	options := func(opts ...CreateCollectionOption) *CreateCollectionOptions {
+		o := &CreateCollectionOptions{}
+		for _, opt := range opts {
+			opt(o)
+		}
+		return o
+	}
+
+	assert.NoError(t, checkExistingCollectionConfig(existing, options()))
+	assert.NoError(t, checkExistingCollectionConfig(existing, options(
+		WithConfiguration(&HNSWConfiguration{Dimension: 384, Distance: DistanceCosine}))))
+	assert.NoError(t, checkExistingCollectionConfig(existing, options(
+		WithConfiguration(&HNSWConfiguration{Dimension: 384}))), "an unset distance is not compared")
+
+	err := checkExistingCollectionConfig(existing, options(
+		WithConfiguration(&HNSWConfiguration{Dimension: 768, Distance: DistanceL2})))
+	assert.ErrorIs(t, err, ErrConfigMismatch)
+	assert.ErrorContains(t, err, `collection "docs" exists with dimension 384, requested 768 and distance cosine, requested l2`)
+
+	err = checkExistingCollectionConfig(existing, options(
+		WithConfiguration(&HNSWConfiguration{Distance: DistanceInnerProduct})))
+	assert.ErrorIs(t, err, ErrConfigMismatch)
+
+	assert.NoError(t, checkExistingCollectionConfig(existing, options(
+		WithConfiguration(&HNSWConfiguration{Dimension: 768}),
+		WithIgnoreConfigMismatch(true))))
+}
+
+// TestGetOrCreateCollectionConfigMismatch tests that get-or-create rejects an existing
+// collection with another configuration
+func TestGetOrCreateCollectionConfigMismatch(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_config_check_" + uuid.New().String()[:8]
+	createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	collection, err := client.GetOrCreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 3, Distance: DistanceL2}), WithCollectionEmbeddingFunc(nil))
+	require.NoError(t, err)
+	assert.Equal(t, 3, collection.Dimension())
+
+	_, err = client.GetOrCreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 4}), WithCollectionEmbeddingFunc(nil))
+	assert.ErrorIs(t, err, ErrConfigMismatch)
+
+	collection, err = client.GetOrCreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 4}), WithCollectionEmbeddingFunc(nil), WithIgnoreConfigMismatch(true))
+	require.NoError(t, err)
+	assert.Equal(t, 3, collection.Dimension())
+}
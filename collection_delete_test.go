@@ -0,0 +1,66 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionDeleteDryRun tests that a dry run previews exactly what Delete removes
+func TestCollectionDeleteDryRun(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_delete_dry_run_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2", "id3", "id4"},
+		[]string{"machine learning", "deep learning", "python tutorial", "go tutorial"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"category": "AI"}, {"category": "AI"}, {"category": "Programming"}, {"category": "AI"}}),
+	)
+	require.NoError(t, err)
+
+	ids := []string{"id1", "id2", "id3"}
+	where := Filter{"category": "AI"}
+
+	preview, err := collection.DeleteDryRun(ctx, ids, where, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id1", "id2"}, preview.IDs)
+	assert.Equal(t, []string{"machine learning", "deep learning"}, preview.Documents)
+
+	// Nothing was deleted by the dry run
+	count, err := collection.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	require.NoError(t, collection.Delete(ctx, ids, where, nil))
+
+	remaining, err := collection.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id3", "id4"}, remaining.IDs)
+}
+
+// TestCollectionDeleteDryRunPaginates tests that large previews are not truncated
+func TestCollectionDeleteDryRunPaginates(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "dry_run", dimension: 3, distance: DistanceL2}
+
+	for i := 0; i < 2500; i++ {
+		store.rows = append(store.rows, fakeRow{id: uuid.New().String()})
+	}
+
+	preview, err := collection.DeleteDryRun(ctx, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, preview.IDs, 2500)
+	assert.IsIncreasing(t, preview.IDs)
+}
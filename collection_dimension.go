@@ -0,0 +1,54 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// collectionDimension returns the dimension of a collection as declared by its
+// embedding column, e.g. 768 for VECTOR(768). The column type is what the server
+// enforces, so it is correct for collections created by other tools or clients
+// too. meta and hasMeta are the result of readCollectionMeta; the persisted
+// dimension is only used if the column type cannot be read, and a disagreement
+// between the two is logged.
+func (c *Client) collectionDimension(ctx context.Context, collectionName string, meta collectionMeta, hasMeta bool) (int, error) {
+	columnType, err := c.vectorColumnType(ctx, collectionName, FieldEmbedding)
+	if err != nil {
+		return 0, err
+	}
+
+	dimension, ok := parseVectorColumnDimension(columnType)
+	switch {
+	case ok && hasMeta && meta.Dimension != dimension:
+		c.logger().Warnf("collection %s: embedding column is %s but metadata records dimension %d; using %d",
+			collectionName, columnType, meta.Dimension, dimension)
+	case !ok && hasMeta:
+		return meta.Dimension, nil
+	case !ok:
+		return 0, fmt.Errorf("%w: collection %s has no vector column %s (type %q)",
+			ErrInvalidParameter, collectionName, FieldEmbedding, columnType)
+	}
+	return dimension, nil
+}
+
+// vectorColumnType returns the declared type of a column of a collection table as
+// reported by INFORMATION_SCHEMA.COLUMNS, or "" if the table has no such column.
+func (c *Client) vectorColumnType(ctx context.Context, collectionName, column string) (string, error) {
+	query := `
+		SELECT COLUMN_TYPE
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+	`
+
+	var columnType string
+	err := c.conn.QueryRow(ctx, query, c.GetTableName(collectionName), column).Scan(&columnType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read collection columns: %w", err)
+	}
+	return columnType, nil
+}
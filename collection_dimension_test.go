@@ -0,0 +1,67 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionDimension tests that the column type wins over the persisted metadata
+func TestCollectionDimension(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "dimension_column", []string{"COLUMN_TYPE"}, [][]driver.Value{{[]byte("VECTOR(768)")}})
+	client := &Client{conn: &staticConnection{db: db, key: "dimension_column"}, config: &ClientConfig{}}
+
+	dimension, err := client.collectionDimension(ctx, "docs", collectionMeta{Dimension: 384}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 768, dimension)
+
+	db = registerStaticRows(t, "dimension_no_column", []string{"COLUMN_TYPE"}, nil)
+	client.conn = &staticConnection{db: db, key: "dimension_no_column"}
+	dimension, err = client.collectionDimension(ctx, "docs", collectionMeta{Dimension: 384}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 384, dimension)
+	_, err = client.collectionDimension(ctx, "docs", collectionMeta{}, false)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestGetCollectionDimensionFromColumn tests that GetCollection reports the declared vector dimension
+func TestGetCollectionDimensionFromColumn(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_dimension_" + uuid.New().String()[:8]
+	_, err := client.CreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 768, Distance: DistanceCosine}),
+		WithCollectionEmbeddingFunc(nil),
+	)
+	require.NoError(t, err)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	collection, err := client.GetCollection(ctx, collectionName, WithCollectionEmbeddingFunc(nil))
+	require.NoError(t, err)
+	assert.Equal(t, 768, collection.Dimension())
+
+	// A table created by another tool has no persisted metadata
+	foreignName := "test_dimension_foreign_" + uuid.New().String()[:8]
+	tableName := client.GetTableName(foreignName)
+	_, err = client.conn.Execute(ctx, fmt.Sprintf(
+		"CREATE TABLE `%s` (%s VARBINARY(512) PRIMARY KEY NOT NULL, %s TEXT, %s VECTOR(768), %s JSON)",
+		tableName, FieldID, FieldDocument, FieldEmbedding, FieldMetadata))
+	require.NoError(t, err)
+	defer func() {
+		_, _ = client.conn.Execute(ctx, fmt.Sprintf("DROP TABLE IF EXISTS `%s`", tableName))
+	}()
+
+	foreign, err := client.GetCollection(ctx, foreignName, WithCollectionEmbeddingFunc(nil))
+	require.NoError(t, err)
+	assert.Equal(t, 768, foreign.Dimension())
+}
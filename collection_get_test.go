@@ -2,6 +2,11 @@ package goseekdb
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -237,3 +242,433 @@ func TestCollectionGetNonExistent(t *testing.T) {
 		assert.Len(t, results.IDs, 0)
 	})
 }
+
+// TestCollectionGetPage tests the keyset pagination statements of GetPage and WithGetCursor
+func TestCollectionGetPage(t *testing.T) {
+	ctx := context.Background()
+	row := func(id string) []driver.Value {
+		return []driver.Value{[]byte(id), []byte("doc"), []byte("{}"), []byte("[1,2,3]")}
+	}
+	columns := []string{"_id", "document", "metadata", "embedding"}
+	db := registerStaticRows(t, "get_page_full", columns, [][]driver.Value{row("id1"), row("id2"), row("id3")})
+	registerStaticRows(t, "get_page_last", columns, [][]driver.Value{row("id5")})
+	conn := &streamConnection{staticConnection: staticConnection{db: db, key: "get_page_full"}}
+	collection := &Collection{client: &Client{conn: conn, config: &ClientConfig{}}, name: "paged"}
+
+	// The first page is the lowest IDs, with one row more to detect a following page
+	page, err := collection.GetPage(ctx, WithLimit(2))
+	require.NoError(t, err)
+	querySQL := strings.Join(strings.Fields(conn.query), " ")
+	assert.Contains(t, querySQL, "FROM c$v1$paged ORDER BY _id LIMIT ?")
+	assert.NotContains(t, querySQL, "OFFSET")
+	assert.Equal(t, []interface{}{3}, conn.args)
+	assert.Equal(t, []string{"id1", "id2"}, page.IDs)
+	assert.Len(t, page.Documents, 2)
+	assert.Equal(t, encodeGetCursor("id2"), page.NextCursor)
+
+	// Following pages resume after the cursor's ID
+	conn.key = "get_page_last"
+	page, err = collection.GetPage(ctx, WithLimit(2), WithGetCursor(page.NextCursor))
+	require.NoError(t, err)
+	querySQL = strings.Join(strings.Fields(conn.query), " ")
+	assert.Contains(t, querySQL, "FROM c$v1$paged WHERE _id > ? ORDER BY _id LIMIT ?")
+	assert.Equal(t, []interface{}{"id2", 3}, conn.args)
+	assert.Equal(t, []string{"id5"}, page.IDs)
+	assert.Empty(t, page.NextCursor)
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := collection.GetPage(ctx, WithGetCursor("not base64!"))
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+	})
+
+	t.Run("cursor round trip", func(t *testing.T) {
+		lastID, err := decodeGetCursor(encodeGetCursor("doc/42"))
+		require.NoError(t, err)
+		assert.Equal(t, "doc/42", lastID)
+	})
+}
+
+// TestCollectionGetStream tests iterating over Get results with GetStream
+func TestCollectionGetStream(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_get_stream_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	ids := []string{uuid.New().String(), uuid.New().String(), uuid.New().String()}
+	err := collection.Add(ctx, ids,
+		[]string{"first document", "second document", "third document"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}, {3.0, 4.0, 5.0}}),
+		WithMetadatas([]Metadata{{"n": 1}, {"n": 2}, {"n": 3}}),
+	)
+	require.NoError(t, err)
+
+	t.Run("stream all rows", func(t *testing.T) {
+		it, err := collection.GetStream(ctx, nil)
+		require.NoError(t, err)
+		defer it.Close()
+
+		var streamed []string
+		for it.Next() {
+			hit := it.Item()
+			streamed = append(streamed, hit.ID)
+			assert.NotEmpty(t, hit.Document)
+			assert.Contains(t, hit.Metadata, "n")
+			assert.Len(t, hit.Embedding, 3)
+		}
+		require.NoError(t, it.Err())
+		assert.ElementsMatch(t, ids, streamed)
+
+		// Exhausted iterator stays exhausted and can be closed again
+		assert.False(t, it.Next())
+		assert.NoError(t, it.Close())
+	})
+
+	t.Run("stream with early close", func(t *testing.T) {
+		it, err := collection.GetStream(ctx, ids[:2])
+		require.NoError(t, err)
+		require.True(t, it.Next())
+		require.NoError(t, it.Close())
+		assert.False(t, it.Next())
+	})
+
+	t.Run("stream more rows than the Get default limit", func(t *testing.T) {
+		const total = 1500
+		moreIDs := make([]string, total-len(ids))
+		documents := make([]string, len(moreIDs))
+		embeddings := make([][]float32, len(moreIDs))
+		for i := range moreIDs {
+			moreIDs[i] = fmt.Sprintf("stream-%04d", i)
+			documents[i] = fmt.Sprintf("document %d", i)
+			embeddings[i] = []float32{float32(i), 1, 2}
+		}
+		require.NoError(t, collection.Add(ctx, moreIDs, documents, WithEmbeddings(embeddings)))
+
+		it, err := collection.GetStream(ctx, nil)
+		require.NoError(t, err)
+		defer it.Close()
+		count := 0
+		for it.Next() {
+			count++
+		}
+		require.NoError(t, it.Err())
+		assert.Equal(t, total, count)
+	})
+}
+
+// streamConnection is a connection whose queries return the static rows registered
+// under key and record their statement and arguments
+type streamConnection struct {
+	staticConnection
+	query string
+	args  []interface{}
+}
+
+func (s *streamConnection) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	s.query, s.args = query, args
+	return s.staticConnection.Query(ctx, query, args...)
+}
+
+// TestCollectionGetStreamLimit tests that GetStream returns all rows unless WithLimit is given
+func TestCollectionGetStreamLimit(t *testing.T) {
+	ctx := context.Background()
+	rows := make([][]driver.Value, 1500)
+	for i := range rows {
+		rows[i] = []driver.Value{[]byte(fmt.Sprintf("id%04d", i)), []byte("doc"), []byte("{}"), []byte("[1,2,3]")}
+	}
+	db := registerStaticRows(t, "get_stream_limit", []string{"_id", "document", "metadata", "embedding"}, rows)
+	conn := &streamConnection{staticConnection: staticConnection{db: db, key: "get_stream_limit"}}
+	client := &Client{conn: conn, config: &ClientConfig{}}
+	collection := &Collection{client: client, name: "docs"}
+
+	it, err := collection.GetStream(ctx, nil)
+	require.NoError(t, err)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.NoError(t, it.Close())
+	assert.Equal(t, len(rows), count)
+	assert.Contains(t, conn.args, math.MaxInt64)
+	assert.NotContains(t, conn.args, 1000)
+
+	it, err = collection.GetStream(ctx, nil, WithLimit(5))
+	require.NoError(t, err)
+	require.NoError(t, it.Close())
+	assert.Contains(t, conn.args, 5)
+	assert.NotContains(t, conn.args, math.MaxInt64)
+}
+
+// TestCollectionGetMultiByteDocuments tests that non-ASCII documents round-trip unchanged
+func TestCollectionGetMultiByteDocuments(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_get_utf8_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	documents := []string{
+		"Vector search 🚀 with emoji 👩‍💻",
+		"向量数据库支持中文文档",
+		"ベクトル検索 and Ünïcödé",
+	}
+	ids := []string{uuid.New().String(), uuid.New().String(), uuid.New().String()}
+	err := collection.Add(ctx, ids, documents,
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}, {3.0, 4.0, 5.0}}),
+		WithMetadatas([]Metadata{{"emoji": "🎉"}, {"lang": "中文"}, {"lang": "日本語"}}),
+	)
+	require.NoError(t, err)
+
+	for i, id := range ids {
+		results, err := collection.Get(ctx, []string{id})
+		require.NoError(t, err)
+		require.Len(t, results.Documents, 1)
+		assert.Equal(t, []byte(documents[i]), []byte(results.Documents[0]))
+	}
+
+	results, err := collection.Get(ctx, []string{ids[0]})
+	require.NoError(t, err)
+	assert.Equal(t, "🎉", results.Metadatas[0]["emoji"])
+}
+
+// TestCollectionCountWithFilter tests counting documents matching filters
+func TestCollectionCountWithFilter(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_count_filter_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"},
+		[]string{"machine learning", "deep learning", "python tutorial"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}, {3.0, 4.0, 5.0}}),
+		WithMetadatas([]Metadata{{"category": "AI"}, {"category": "AI"}, {"category": "Programming"}}),
+	)
+	require.NoError(t, err)
+
+	count, err := collection.CountWithFilter(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	total, err := collection.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, count, total)
+
+	count, err = collection.CountWithFilter(ctx, Filter{"category": "AI"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = collection.CountWithFilter(ctx, Filter{"category": "AI"}, Filter{"$contains": "deep"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+// TestBuildGetSQLOrderBy tests the generated SQL for ordering by a metadata field
+func TestBuildGetSQLOrderBy(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	options := &GetOptions{Limit: 10, Offset: 20}
+	WithGetOrderBy("timestamp", true)(options)
+	querySQL, args, err := client.buildGetSQL("docs", nil, options)
+	require.NoError(t, err)
+	assert.Contains(t, querySQL, "ORDER BY CASE WHEN JSON_TYPE(JSON_EXTRACT(metadata, ?)) IN ('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL') THEN CAST(JSON_EXTRACT(metadata, ?) AS DOUBLE) END DESC, JSON_UNQUOTE(JSON_EXTRACT(metadata, ?)) DESC, _id")
+	assert.Equal(t, []interface{}{"$.timestamp", "$.timestamp", "$.timestamp", 10, 20}, args)
+
+	for _, field := range []string{"", "1st", "a.b", "x') OR 1=1 --", "na me"} {
+		_, _, err := client.buildGetSQL("docs", nil, &GetOptions{OrderBy: &MetadataOrder{Key: field}})
+		assert.ErrorIs(t, err, ErrInvalidParameter, field)
+	}
+
+	// Cursor pages are ordered by ID
+	options.Cursor = encodeGetCursor("id1")
+	_, _, err = client.buildGetSQL("docs", nil, options)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestBuildGetSQLLimit tests the default limit for ID lookups and WithNoLimit
+func TestBuildGetSQLLimit(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	limitArg := func(ids []string, opts ...GetOption) interface{} {
+		options := &GetOptions{}
+		for _, opt := range opts {
+			opt(options)
+		}
+		_, args, err := client.buildGetSQL("docs", ids, options)
+		require.NoError(t, err)
+		return args[len(args)-2]
+	}
+
+	manyIDs := make([]string, 1500)
+	for i := range manyIDs {
+		manyIDs[i] = fmt.Sprintf("id%d", i)
+	}
+	assert.Equal(t, 1000, limitArg(nil))
+	assert.Equal(t, 1000, limitArg([]string{"id1", "id2"}))
+	assert.Equal(t, 1500, limitArg(manyIDs), "the default limit does not truncate an ID lookup")
+	assert.Equal(t, 10, limitArg(manyIDs, WithLimit(10)), "an explicit limit is kept")
+	assert.Equal(t, math.MaxInt64, limitArg(nil, WithNoLimit()))
+
+	_, _, err := client.buildGetSQL("docs", nil, &GetOptions{Limit: 10, NoLimit: true})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	collection := &Collection{client: &fakeOperations{}, name: "docs", dimension: 3}
+	_, err = collection.GetPage(context.Background(), WithNoLimit())
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestCollectionGetIDsWithFilters tests combining IDs with metadata and document filters
+func TestCollectionGetIDsWithFilters(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_get_ids_filters_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3", "id4"},
+		[]string{"deep learning", "deep sea", "machine learning", "deep learning again"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"category": "AI"}, {"category": "AI"}, {"category": "AI"}, {"category": "ML"}}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, []string{"id1", "id2", "id3", "id4"},
+		WithGetWhere(Filter{"category": "AI"}),
+		WithGetWhereDocument(Filter{"$contains": "deep"}),
+	)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2"}, results.IDs)
+
+	// Every filter narrows the ID list, none widens it
+	results, err = collection.Get(ctx, []string{"id2", "id3"},
+		WithGetWhere(Filter{"category": "AI"}),
+		WithGetWhereDocument(Filter{"$contains": "learning"}),
+		WithNoLimit(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id3"}, results.IDs)
+}
+
+// TestCollectionGetOrderBy tests paging through documents ordered by a metadata field
+func TestCollectionGetOrderBy(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_get_order_by_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"a", "b", "c", "d"}, []string{"doc a", "doc b", "doc c", "doc d"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"timestamp": 30}, {"timestamp": 4}, {"timestamp": 100}, {"timestamp": 4}}),
+	)
+	require.NoError(t, err)
+
+	// Numbers are compared numerically and ties are broken by ID
+	results, err := collection.Get(ctx, nil, WithGetOrderBy("timestamp", false), WithLimit(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "d"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetOrderBy("timestamp", false), WithLimit(2), WithOffset(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetOrderBy("timestamp", true))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a", "b", "d"}, results.IDs)
+}
+
+// TestCollectionExistsAndGetItem tests single-document lookups
+func TestCollectionExistsAndGetItem(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "items", dimension: 3, distance: DistanceL2}
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"first", "second"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"n": 1}, {"n": 2}}),
+	)
+	require.NoError(t, err)
+
+	exists, err := collection.Exists(ctx, "id2")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = collection.Exists(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	hit, err := collection.GetItem(ctx, "id2")
+	require.NoError(t, err)
+	assert.Equal(t, &Hit{ID: "id2", Document: "second", Metadata: Metadata{"n": float64(2)}, Embedding: []float32{4, 5, 6}}, hit)
+
+	_, err = collection.GetItem(ctx, "missing")
+	assert.ErrorIs(t, err, ErrDocumentNotFound)
+}
+
+// TestCollectionGetIntegerMetadata tests reading integer metadata as int64 and
+// filtering on it with either numeric type
+func TestCollectionGetIntegerMetadata(t *testing.T) {
+	client, err := NewClient(
+		WithHost(getServerHost()),
+		WithPort(getServerPort()),
+		WithTenant("sys"),
+		WithDatabase(getServerDatabase()),
+		WithUser(getServerUser()),
+		WithPassword(getServerPassword()),
+		WithIntegerMetadata(true),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Skipf("Server connection failed: %v", err)
+	}
+
+	collectionName := "test_integer_metadata_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err = collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}}),
+		WithMetadatas([]Metadata{{"year": 2023, "score": 0.5}, {"year": 2024, "score": 1.5}}),
+	)
+	require.NoError(t, err)
+
+	for _, year := range []interface{}{int64(2023), float64(2023)} {
+		results, err := collection.Get(ctx, nil, WithGetWhere(Filter{"year": Filter{"$eq": year}}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"id1"}, results.IDs)
+		assert.Equal(t, int64(2023), results.Metadatas[0]["year"])
+		assert.Equal(t, 0.5, results.Metadatas[0]["score"])
+	}
+}
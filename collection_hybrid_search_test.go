@@ -2,10 +2,14 @@ package goseekdb
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ob-labs/seekdb-go/internal/connection"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -389,3 +393,190 @@ func TestHybridSearchEmpty(t *testing.T) {
 		assert.Len(t, results.IDs, 0)
 	})
 }
+
+// TestHybridSearchNormalizedScores tests min-max normalization of fused scores
+func TestHybridSearchNormalizedScores(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{
+		hybridResult: &HybridSearchResult{
+			IDs:       []string{"a", "b", "c"},
+			Distances: []float64{0.032, 0.025, 0.016},
+		},
+	}
+	collection := &Collection{client: store, name: "hybrid", dimension: 3, distance: DistanceL2}
+
+	results, err := collection.HybridSearch(ctx, &HybridSearchQuery{}, nil, nil, 3, WithNormalizedScores(true))
+	require.NoError(t, err)
+	require.Len(t, results.Distances, 3)
+	assert.Equal(t, 1.0, results.Distances[0])
+	assert.InDelta(t, 0.5625, results.Distances[1], 1e-9)
+	assert.Equal(t, 0.0, results.Distances[2])
+	assert.Equal(t, []float64{0.032, 0.025, 0.016}, results.RawScores)
+
+	t.Run("equal scores normalize to 1", func(t *testing.T) {
+		result := &HybridSearchResult{Distances: []float64{0.5, 0.5}}
+		result.normalizeScores()
+		assert.Equal(t, []float64{1, 1}, result.Distances)
+	})
+
+	t.Run("scores are raw by default", func(t *testing.T) {
+		store.hybridResult = &HybridSearchResult{IDs: []string{"a"}, Distances: []float64{0.032}}
+		results, err := collection.HybridSearch(ctx, &HybridSearchQuery{}, nil, nil, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []float64{0.032}, results.Distances)
+		assert.Nil(t, results.RawScores)
+	})
+}
+
+// TestHybridSearchResultHits tests the typed view of hybrid search results
+func TestHybridSearchResultHits(t *testing.T) {
+	result := &HybridSearchResult{
+		IDs:       []string{"a", "b"},
+		Distances: []float64{0.032, 0.016},
+		Documents: []string{"doc a", "doc b"},
+		Metadatas: []Metadata{{"year": 2024}, {"year": 2023}},
+	}
+	assert.Equal(t, []Hit{
+		{ID: "a", Distance: 0.032, Document: "doc a", Metadata: Metadata{"year": 2024}},
+		{ID: "b", Distance: 0.016, Document: "doc b", Metadata: Metadata{"year": 2023}},
+	}, result.Hits())
+
+	// Embeddings are only set when returned
+	result.Embeddings = [][]float32{{1, 2}, {3, 4}}
+	hits := result.Hits()
+	assert.Equal(t, []float32{3, 4}, hits[1].Embedding)
+
+	// Missing fields stay empty
+	hits = (&HybridSearchResult{IDs: []string{"a"}}).Hits()
+	assert.Equal(t, []Hit{{ID: "a"}}, hits)
+	assert.Empty(t, (&HybridSearchResult{}).Hits())
+}
+
+// TestHybridSearchSecondarySort tests re-ordering hits with equal scores by metadata
+func TestHybridSearchSecondarySort(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{
+		hybridResult: &HybridSearchResult{
+			IDs:       []string{"a", "b", "c", "d", "e", "f"},
+			Distances: []float64{0.9, 0.5, 0.5, 0.5, 0.5, 0.1},
+			Documents: []string{"doc a", "doc b", "doc c", "doc d", "doc e", "doc f"},
+			Metadatas: []Metadata{
+				{"year": 2020},
+				{"year": 2021, "title": "b"},
+				{"title": "c"},
+				{"year": 2023, "title": "d"},
+				{"year": 2021, "title": "a"},
+				{"year": 2030},
+			},
+		},
+	}
+	collection := &Collection{client: store, name: "hybrid", dimension: 3, distance: DistanceL2}
+
+	results, err := collection.HybridSearch(ctx, &HybridSearchQuery{}, nil, nil, 6,
+		WithSecondarySort("year", true), WithSecondarySort("title", false))
+	require.NoError(t, err)
+	// Only the tied hits move; the one missing "year" goes last among them
+	assert.Equal(t, []string{"a", "d", "e", "b", "c", "f"}, results.IDs)
+	assert.Equal(t, []float64{0.9, 0.5, 0.5, 0.5, 0.5, 0.1}, results.Distances)
+	assert.Equal(t, []string{"doc a", "doc d", "doc e", "doc b", "doc c", "doc f"}, results.Documents)
+	assert.Equal(t, "a", results.Metadatas[2]["title"])
+
+	t.Run("missing values sort last in both directions", func(t *testing.T) {
+		for _, desc := range []bool{false, true} {
+			result := &HybridSearchResult{
+				IDs:       []string{"missing", "null", "x", "y"},
+				Metadatas: []Metadata{{}, {"k": nil}, {"k": "x"}, {"k": "y"}},
+			}
+			result.sortByMetadata([]SortField{{Field: "k", Desc: desc}})
+			assert.Equal(t, []string{"missing", "null"}, result.IDs[2:], "desc=%v", desc)
+		}
+	})
+
+	t.Run("mixed types", func(t *testing.T) {
+		values := []interface{}{true, "b", 2.5, int64(1), "a", false, []interface{}{1}, 3}
+		sort.SliceStable(values, func(i, j int) bool { return compareMetadataValues(values[i], values[j]) < 0 })
+		assert.Equal(t, []interface{}{int64(1), 2.5, 3, "a", "b", false, true, []interface{}{1}}, values)
+		assert.Equal(t, -1, compareMetadataValues(int64(1<<53), int64(1<<53+1)))
+	})
+}
+
+// TestExplainHybridSearch tests that the generated SQL and search_parm are only
+// returned with WithExplainHybrid
+func TestExplainHybridSearch(t *testing.T) {
+	config := DefaultClientConfig()
+	client := &Client{config: config}
+
+	result := &HybridSearchResult{}
+	client.explainHybridSearch(result, "SELECT 1", `{"knn":{}}`)
+	assert.Empty(t, result.DebugSQL)
+	assert.Empty(t, result.DebugParm)
+
+	WithExplainHybrid(true)(config)
+	client.explainHybridSearch(result, "SELECT 1", `{"knn":{}}`)
+	assert.Equal(t, "SELECT 1", result.DebugSQL)
+	assert.Equal(t, `{"knn":{}}`, result.DebugParm)
+}
+
+// hybridConnection is a connection whose transactions record their statements and
+// arguments, and whose DBMS_HYBRID_SEARCH.GET_SQL call returns no SQL
+type hybridConnection struct {
+	connection.Connection
+	db *sql.DB
+	tx *hybridTx
+}
+
+func (c *hybridConnection) Begin(ctx context.Context) (connection.Tx, error) {
+	c.tx = &hybridTx{db: c.db}
+	return c.tx, nil
+}
+
+type hybridStatement struct {
+	query string
+	args  []interface{}
+}
+
+type hybridTx struct {
+	connection.Tx
+	db    *sql.DB
+	stmts []hybridStatement
+}
+
+func (t *hybridTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.stmts = append(t.stmts, hybridStatement{query, args})
+	return nil, nil
+}
+
+func (t *hybridTx) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	t.stmts = append(t.stmts, hybridStatement{query, args})
+	return t.db.QueryRowContext(ctx, "hybrid_no_sql")
+}
+
+func (t *hybridTx) Rollback() error { return nil }
+
+// TestHybridSearchBindsSearchParm tests that search_parm values with quotes and
+// backslashes are bound instead of quoted into the SQL
+func TestHybridSearchBindsSearchParm(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "hybrid_no_sql", []string{"query_sql"}, [][]driver.Value{{nil}})
+	conn := &hybridConnection{db: db}
+	client := &Client{conn: conn, config: &ClientConfig{}}
+
+	value := `O'Reilly \ "AI"'); DROP TABLE docs; --`
+	result, err := client.collectionHybridSearch(ctx, "docs",
+		&HybridSearchQuery{Where: Filter{"category": value}},
+		&HybridSearchKNN{QueryEmbeddings: [][]float32{{1, 2, 3}}, NResults: 5},
+		nil, 5, nil, DistanceL2)
+	require.NoError(t, err)
+	assert.Empty(t, result.IDs)
+
+	require.Len(t, conn.tx.stmts, 2)
+	set, getSQL := conn.tx.stmts[0], conn.tx.stmts[1]
+	assert.Equal(t, "SET @search_parm = ?", set.query)
+	require.Len(t, set.args, 1)
+	searchParm, ok := set.args[0].(string)
+	require.True(t, ok)
+	assert.Contains(t, searchParm, `O'Reilly \\ \"AI\"'); DROP TABLE docs; --`)
+
+	assert.NotContains(t, getSQL.query, "docs")
+	assert.Equal(t, []interface{}{"c$v1$docs"}, getSQL.args)
+}
@@ -0,0 +1,31 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDocumentNotFound is returned by Collection.GetItem when no document has the ID.
+var ErrDocumentNotFound = errors.New("document not found")
+
+// collectionExists reports whether a document with the given ID is stored.
+func (c *Client) collectionExists(ctx context.Context, collectionName string, id string) (exists bool, err error) {
+	ctx, span := c.startSpan(ctx, "exists", collectionName)
+	defer func() {
+		rowCount := 0
+		if exists {
+			rowCount = 1
+		}
+		span.end(rowCount, err)
+	}()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	querySQL := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE %s = ?)", c.GetTableName(collectionName), FieldID)
+	if err := c.conn.QueryRow(ctx, querySQL, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check document existence: %w", err)
+	}
+	return exists, nil
+}
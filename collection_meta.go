@@ -0,0 +1,136 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+)
+
+// collectionMetaPrefix marks table comments written by this client, so comments
+// set by other tools are not mistaken for collection metadata.
+const collectionMetaPrefix = "seekdb:"
+
+// collectionMeta is the collection configuration persisted in the table comment
+// at creation time. It is authoritative when reading a collection back, instead of
+// inferring the dimension and distance from the column and index definitions.
+type collectionMeta struct {
+	Dimension         int            `json:"dimension"`
+	Distance          DistanceMetric `json:"distance"`
+	EmbeddingFunction string         `json:"embedding_function,omitempty"`
+	VectorsOnly       bool           `json:"vectors_only,omitempty"`
+
+	MetadataColumnType  MetadataColumnType `json:"metadata_column_type,omitempty"`
+	IndexedMetadataKeys []string           `json:"indexed_metadata_keys,omitempty"`
+
+	VectorFields []VectorField `json:"vector_fields,omitempty"`
+}
+
+// newCollectionMeta returns the metadata to persist for a new collection.
+func newCollectionMeta(config *HNSWConfiguration, embFunc embedding.EmbeddingFunc) collectionMeta {
+	meta := collectionMeta{
+		Dimension: DefaultVectorDimension,
+		Distance:  DefaultDistanceMetric,
+	}
+	if config != nil {
+		if config.Dimension > 0 {
+			meta.Dimension = config.Dimension
+		}
+		if config.Distance != "" {
+			meta.Distance = config.Distance
+		}
+		meta.VectorFields = config.VectorFields
+	}
+	if embFunc != nil {
+		meta.EmbeddingFunction = strings.TrimPrefix(fmt.Sprintf("%T", embFunc), "*")
+	}
+	return meta
+}
+
+// tableOption returns the COMMENT table option storing m, for use in CREATE TABLE.
+func (m collectionMeta) tableOption() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal collection metadata: %w", err)
+	}
+	comment := strings.ReplaceAll(collectionMetaPrefix+string(data), "'", "''")
+	return fmt.Sprintf("COMMENT = '%s'", comment), nil
+}
+
+// parseCollectionMeta parses a table comment. ok is false for tables created without
+// metadata (e.g. by older clients), whose configuration must still be inferred.
+func parseCollectionMeta(comment string) (meta collectionMeta, ok bool, err error) {
+	if !strings.HasPrefix(comment, collectionMetaPrefix) {
+		return collectionMeta{}, false, nil
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(comment, collectionMetaPrefix)), &meta); err != nil {
+		return collectionMeta{}, false, fmt.Errorf("invalid collection metadata %q: %w", comment, err)
+	}
+	if meta.Distance, err = DistanceMetricFromString(string(meta.Distance)); err != nil {
+		return collectionMeta{}, false, fmt.Errorf("invalid collection metadata %q: %w", comment, err)
+	}
+	return meta, true, nil
+}
+
+// readCollectionMeta reads the persisted metadata of a collection.
+func (c *Client) readCollectionMeta(ctx context.Context, collectionName string) (collectionMeta, bool, error) {
+	query := `
+		SELECT TABLE_COMMENT
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`
+
+	var comment string
+	err := c.conn.QueryRow(ctx, query, c.GetTableName(collectionName)).Scan(&comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return collectionMeta{}, false, fmt.Errorf("%w: %s", ErrCollectionNotFound, collectionName)
+	}
+	if err != nil {
+		return collectionMeta{}, false, fmt.Errorf("failed to read collection metadata: %w", err)
+	}
+	return parseCollectionMeta(comment)
+}
+
+// listCollectionMetas reads the persisted metadata of all collections in the
+// current database in one query, keyed by collection name. Collections without
+// metadata are omitted.
+func (c *Client) listCollectionMetas(ctx context.Context) (map[string]collectionMeta, error) {
+	query := `
+		SELECT TABLE_NAME, TABLE_COMMENT
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = DATABASE()
+	`
+
+	rows, err := c.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection metadata: %w", err)
+	}
+	defer rows.Close()
+
+	metas := make(map[string]collectionMeta)
+	for rows.Next() {
+		var tableName, comment string
+		if err := rows.Scan(&tableName, &comment); err != nil {
+			return nil, err
+		}
+		name, ok := c.collectionNameFromTable(tableName)
+		if !ok {
+			continue
+		}
+		meta, ok, err := parseCollectionMeta(comment)
+		if err != nil {
+			return nil, err
+		}
+		// A collection present in several schema versions reports the table
+		// this client resolves its name to
+		if _, seen := metas[name]; ok && (!seen || c.GetTableName(name) == tableName) {
+			metas[name] = meta
+		}
+	}
+
+	return metas, rows.Err()
+}
@@ -0,0 +1,105 @@
+package goseekdb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionMetaRoundTrip tests persisting collection metadata in a table comment
+func TestCollectionMetaRoundTrip(t *testing.T) {
+	meta := newCollectionMeta(&HNSWConfiguration{Dimension: 128, Distance: DistanceCosine}, &lengthEmbedder{})
+	assert.Equal(t, collectionMeta{Dimension: 128, Distance: DistanceCosine, EmbeddingFunction: "goseekdb.lengthEmbedder"}, meta)
+
+	option, err := meta.tableOption()
+	require.NoError(t, err)
+	assert.Equal(t, `COMMENT = 'seekdb:{"dimension":128,"distance":"cosine","embedding_function":"goseekdb.lengthEmbedder"}'`, option)
+
+	comment := strings.TrimSuffix(strings.TrimPrefix(option, "COMMENT = '"), "'")
+	parsed, ok, err := parseCollectionMeta(comment)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, meta, parsed)
+
+	// Defaults apply when no configuration is given
+	meta = newCollectionMeta(nil, nil)
+	assert.Equal(t, collectionMeta{Dimension: DefaultVectorDimension, Distance: DefaultDistanceMetric}, meta)
+}
+
+// TestParseCollectionMeta tests comments written by other clients or tools
+func TestParseCollectionMeta(t *testing.T) {
+	_, ok, err := parseCollectionMeta("")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = parseCollectionMeta("user table comment")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = parseCollectionMeta(`seekdb:{"dimension":3,"distance":"cosin"}`)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	_, _, err = parseCollectionMeta(`seekdb:{"dimension":`)
+	assert.Error(t, err)
+
+	// Quotes are escaped for the COMMENT literal
+	option, err := collectionMeta{Dimension: 3, Distance: DistanceL2, EmbeddingFunction: "it's"}.tableOption()
+	require.NoError(t, err)
+	assert.Contains(t, option, "it''s")
+}
+
+// TestCreateCollectionSQLMeta tests that CREATE TABLE persists the collection
+// configuration in the table comment
+func TestCreateCollectionSQLMeta(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+	options := &CreateCollectionOptions{
+		Configuration:       &HNSWConfiguration{Distance: DistanceCosine},
+		MetadataColumnType:  MetadataColumnJSON,
+		IndexedMetadataKeys: []string{"category"},
+	}
+	createSQL, err := client.createCollectionSQL("docs", 5, DistanceCosine, options, &lengthEmbedder{})
+	require.NoError(t, err)
+
+	i := strings.LastIndex(createSQL, ") COMMENT = '")
+	require.NotEqual(t, -1, i, createSQL)
+	comment := strings.TrimSuffix(createSQL[i+len(") COMMENT = '"):], "'")
+	meta, ok, err := parseCollectionMeta(comment)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, collectionMeta{
+		Dimension:           5,
+		Distance:            DistanceCosine,
+		EmbeddingFunction:   "goseekdb.lengthEmbedder",
+		MetadataColumnType:  MetadataColumnJSON,
+		IndexedMetadataKeys: []string{"category"},
+	}, meta)
+}
+
+// TestCollectionMetaAfterReconnect tests that distance and dimension survive a new client
+func TestCollectionMetaAfterReconnect(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_collection_meta_" + uuid.New().String()[:8]
+	_, err := client.CreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 7, Distance: DistanceCosine}),
+		WithCollectionEmbeddingFunc(nil),
+	)
+	require.NoError(t, err)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	other := createTestClient(t)
+	defer other.Close()
+
+	collection, err := other.GetCollection(ctx, collectionName, WithCollectionEmbeddingFunc(nil))
+	require.NoError(t, err)
+	assert.Equal(t, DistanceCosine, collection.Distance())
+	assert.Equal(t, 7, collection.Dimension())
+}
@@ -0,0 +1,31 @@
+package goseekdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrNoSuchTable is returned when a statement references a table that does not
+// exist, e.g. a collection deleted by another client.
+const mysqlErrNoSuchTable = 1146
+
+// isNoSuchTable reports whether err was caused by a missing table.
+func isNoSuchTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrNoSuchTable
+}
+
+// collectionNotFoundError returns an error wrapping ErrCollectionNotFound if err was
+// caused by the collection's table not existing, and nil otherwise. The driver error
+// stays wrapped too. The collection's detected schema version and indexed metadata
+// keys are forgotten, so a collection recreated under the same name is detected again.
+func (c *Client) collectionNotFoundError(collectionName string, err error) error {
+	if !isNoSuchTable(err) {
+		return nil
+	}
+	c.schemaVersions.Delete(collectionName)
+	c.indexedMetadataKeys.Delete(collectionName)
+	return fmt.Errorf("%w: %s: %w", ErrCollectionNotFound, collectionName, err)
+}
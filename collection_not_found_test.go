@@ -0,0 +1,51 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionNotFoundError(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+	client.schemaVersions.Store("gone", SchemaVersion1)
+
+	driverErr := &mysql.MySQLError{Number: 1146, Message: "Table 'test.c$v1$gone' doesn't exist"}
+	err := client.collectionNotFoundError("gone", fmt.Errorf("wrapped: %w", driverErr))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+	assert.Contains(t, err.Error(), "gone")
+	var mysqlErr *mysql.MySQLError
+	assert.ErrorAs(t, err, &mysqlErr)
+
+	// The detected schema version is forgotten
+	_, ok := client.schemaVersions.Load("gone")
+	assert.False(t, ok)
+
+	assert.NoError(t, client.collectionNotFoundError("gone", &mysql.MySQLError{Number: 1213}))
+	assert.NoError(t, client.collectionNotFoundError("gone", errors.New("connection refused")))
+}
+
+// TestDeletedCollectionNotFound tests reading a collection deleted after it was opened
+func TestDeletedCollectionNotFound(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_deleted_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	require.NoError(t, client.DeleteCollection(ctx, collectionName))
+
+	_, err := collection.Get(ctx, nil)
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+	_, err = collection.Count(ctx)
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+	_, err = collection.Query(ctx, nil, 1, WithQueryEmbeddings([][]float32{{1, 2, 3}}))
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+}
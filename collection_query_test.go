@@ -2,9 +2,15 @@ package goseekdb
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/ob-labs/seekdb-go/internal/connection"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -227,3 +233,574 @@ func TestCollectionQueryEmpty(t *testing.T) {
 		assert.Len(t, results.IDs[0], 0)
 	})
 }
+
+// TestCollectionQueryScoreBoost tests that WithScoreBoost reorders similarly distant results
+func TestCollectionQueryScoreBoost(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_query_boost_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	// "near" is slightly closer to the query vector, "popular" has a much higher popularity
+	err := collection.Add(ctx, []string{"near", "popular"}, []string{"near document", "popular document"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {1.0, 2.0, 3.1}}),
+		WithMetadatas([]Metadata{{"popularity": 1}, {"popularity": 100}}),
+	)
+	require.NoError(t, err)
+
+	queryVector := []float32{1.0, 2.0, 3.0}
+
+	t.Run("without boost nearest result wins", func(t *testing.T) {
+		results, err := collection.Query(ctx, nil, 2,
+			WithQueryEmbeddings([][]float32{queryVector}),
+		)
+		require.NoError(t, err)
+		require.Len(t, results.IDs[0], 2)
+		assert.Equal(t, "near", results.IDs[0][0])
+	})
+
+	t.Run("boost reorders by popularity", func(t *testing.T) {
+		results, err := collection.Query(ctx, nil, 2,
+			WithQueryEmbeddings([][]float32{queryVector}),
+			WithScoreBoost("popularity", 0.01),
+		)
+		require.NoError(t, err)
+		require.Len(t, results.IDs[0], 2)
+		assert.Equal(t, "popular", results.IDs[0][0])
+		// Reported distances are the raw, unboosted values
+		assert.Greater(t, results.Distances[0][0], results.Distances[0][1])
+	})
+}
+
+// TestBuildVectorQuerySQL tests the generated vector search SQL
+func TestBuildVectorQuerySQL(t *testing.T) {
+	queryVector := []float32{1, 2, 3}
+
+	t.Run("plain query uses approximate index", func(t *testing.T) {
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceL2, "", &QueryOptions{})
+		assert.Contains(t, querySQL, "ORDER BY l2_distance(embedding, ?)")
+		assert.Contains(t, querySQL, "APPROXIMATE")
+		assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", 5}, args)
+	})
+
+	t.Run("boosted query orders by blended score", func(t *testing.T) {
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceCosine, "", &QueryOptions{
+			ScoreBoost: &ScoreBoost{MetadataKey: "popularity", Weight: 0.5},
+		})
+		assert.Contains(t, querySQL, "ORDER BY (cosine_distance(embedding, ?) - ? * COALESCE(CAST(JSON_EXTRACT(metadata, ?) AS DOUBLE), 0))")
+		assert.NotContains(t, querySQL, "APPROXIMATE")
+		assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", 0.5, "$.popularity", 5}, args)
+	})
+
+	t.Run("exact query skips the approximate index", func(t *testing.T) {
+		options := &QueryOptions{}
+		WithExact(true)(options)
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceL2, "", options)
+		assert.Contains(t, querySQL, "ORDER BY l2_distance(embedding, ?) ASC")
+		assert.NotContains(t, querySQL, "APPROXIMATE")
+		assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", 5}, args)
+	})
+
+	t.Run("inner product orders most similar first", func(t *testing.T) {
+		querySQL, _ := buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceInnerProduct, "", &QueryOptions{})
+		assert.Contains(t, querySQL, "ORDER BY inner_product(embedding, ?) DESC")
+
+		querySQL, _ = buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceInnerProduct, "", &QueryOptions{
+			ScoreBoost: &ScoreBoost{MetadataKey: "popularity", Weight: 0.5},
+		})
+		assert.Contains(t, querySQL, "ORDER BY (inner_product(embedding, ?) + ? * COALESCE(CAST(JSON_EXTRACT(metadata, ?) AS DOUBLE), 0)) DESC")
+	})
+
+	t.Run("tiebreaker orders equal distances by metadata", func(t *testing.T) {
+		options := &QueryOptions{}
+		WithQueryTiebreaker("timestamp", true)(options)
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceL2, "", options)
+		assert.Contains(t, querySQL, "ORDER BY l2_distance(embedding, ?) ASC, CASE WHEN JSON_TYPE(JSON_EXTRACT(metadata, ?))")
+		assert.Contains(t, querySQL, "JSON_UNQUOTE(JSON_EXTRACT(metadata, ?)) DESC")
+		assert.NotContains(t, querySQL, "APPROXIMATE")
+		assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", "$.timestamp", "$.timestamp", "$.timestamp", 5}, args)
+	})
+
+	t.Run("query binds the vector", func(t *testing.T) {
+		whereArgs := []interface{}{"$.category", "AI"}
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "WHERE JSON_EXTRACT(metadata, ?) = ?", whereArgs, queryVector, 5, DistanceL2, "", &QueryOptions{})
+		assert.Contains(t, querySQL, "l2_distance(embedding, ?) AS distance")
+		assert.Contains(t, querySQL, "ORDER BY l2_distance(embedding, ?)")
+		assert.NotContains(t, querySQL, "[1,2,3]")
+		assert.Equal(t, []interface{}{"[1,2,3]", "$.category", "AI", "[1,2,3]", 5}, args)
+
+		// The statement text does not depend on the query vector
+		otherSQL, _ := buildVectorQuerySQL("c$v1$test", "WHERE JSON_EXTRACT(metadata, ?) = ?", whereArgs, []float32{4, 5, 6}, 5, DistanceL2, "", &QueryOptions{})
+		assert.Equal(t, querySQL, otherSQL)
+	})
+
+	t.Run("max distance filters approximate candidates in an outer query", func(t *testing.T) {
+		options := &QueryOptions{}
+		WithMaxDistance(0.5)(options)
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "WHERE JSON_EXTRACT(metadata, ?) = ?", []interface{}{"$.category", "AI"}, queryVector, 5, DistanceL2, "", options)
+		assert.Contains(t, querySQL, "APPROXIMATE")
+		assert.Contains(t, querySQL, "FROM (")
+		assert.Contains(t, querySQL, "WHERE distance <= ?")
+		assert.Contains(t, querySQL, "ORDER BY distance ASC")
+		assert.Equal(t, []interface{}{"[1,2,3]", "$.category", "AI", "[1,2,3]", 5, 0.5}, args)
+
+		querySQL, _ = buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceInnerProduct, "", options)
+		assert.Contains(t, querySQL, "WHERE distance >= ?")
+		assert.Contains(t, querySQL, "ORDER BY distance DESC")
+	})
+
+	t.Run("max distance filters exact scans in the WHERE clause", func(t *testing.T) {
+		options := &QueryOptions{}
+		WithExact(true)(options)
+		WithMaxDistance(0.5)(options)
+		whereArgs := []interface{}{"$.category", "AI"}
+		querySQL, args := buildVectorQuerySQL("c$v1$test", "WHERE JSON_EXTRACT(metadata, ?) = ?", whereArgs, queryVector, 5, DistanceL2, "", options)
+		assert.NotContains(t, querySQL, "APPROXIMATE")
+		assert.NotContains(t, querySQL, "FROM (")
+		assert.Contains(t, querySQL, "WHERE JSON_EXTRACT(metadata, ?) = ? AND l2_distance(embedding, ?) <= ?")
+		assert.Equal(t, []interface{}{"[1,2,3]", "$.category", "AI", "[1,2,3]", 0.5, "[1,2,3]", 5}, args)
+		assert.Equal(t, []interface{}{"$.category", "AI"}, whereArgs)
+
+		querySQL, args = buildVectorQuerySQL("c$v1$test", "", nil, queryVector, 5, DistanceL2, "", options)
+		assert.Contains(t, querySQL, "WHERE l2_distance(embedding, ?) <= ?")
+		assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", 0.5, "[1,2,3]", 5}, args)
+	})
+}
+
+// TestCollectionQueryParameterizedVector tests queries binding the query vector
+// next to filter values that contain quotes and backslashes
+func TestCollectionQueryParameterizedVector(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_query_param_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	category := `O'Reilly \ "AI"`
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{`doc 'one' \ 1`, "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}, {9.0, 9.0, 9.0}}),
+		WithMetadatas([]Metadata{{"category": category}, {"category": category}, {"category": "Other"}}),
+	)
+	require.NoError(t, err)
+
+	queryOpts := []QueryOption{
+		WithQueryEmbeddings([][]float32{{1.0, 2.0, 3.0}, {9.0, 9.0, 8.0}}),
+		WithWhere(Filter{"category": category}),
+	}
+
+	results, err := collection.Query(ctx, nil, 2, queryOpts...)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2"}, results.IDs[0])
+	assert.ElementsMatch(t, []string{"id1", "id2"}, results.IDs[1])
+	assert.Contains(t, results.Documents[0], `doc 'one' \ 1`)
+	require.Len(t, results.Distances, 2)
+	assert.InDelta(t, 0, results.Distances[0][0], 1e-6)
+}
+
+// TestCollectionQueryExact tests that exact search returns the true nearest neighbors
+func TestCollectionQueryExact(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_query_exact_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}, {9.0, 9.0, 9.0}}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Query(ctx, nil, 3,
+		WithQueryEmbeddings([][]float32{{9.0, 9.0, 8.0}}),
+		WithExact(true),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"id3", "id2", "id1"}}, results.IDs)
+	assert.IsNonDecreasing(t, results.Distances[0])
+}
+
+// TestCollectionQueryMaxDistance tests that hits beyond the threshold are dropped
+func TestCollectionQueryMaxDistance(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_query_max_distance_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}, {9.0, 1.0, 0.0}}),
+	)
+	require.NoError(t, err)
+
+	queryEmbeddings := WithQueryEmbeddings([][]float32{{1.0, 2.0, 3.0}})
+	all, err := collection.Query(ctx, nil, 3, queryEmbeddings, WithExact(true))
+	require.NoError(t, err)
+	require.Equal(t, []string{"id1", "id2", "id3"}, all.IDs[0])
+	threshold := (all.Distances[0][1] + all.Distances[0][2]) / 2
+
+	for _, exact := range []bool{false, true} {
+		results, err := collection.Query(ctx, nil, 3, queryEmbeddings, WithExact(exact), WithMaxDistance(threshold))
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"id1", "id2"}}, results.IDs, "exact=%v", exact)
+	}
+}
+
+// makeEmbeddingJSONs returns n JSON-encoded random vectors of the given dimension
+func makeEmbeddingJSONs(n, dimension int) []string {
+	rng := rand.New(rand.NewSource(42))
+	embeddingJSONs := make([]string, n)
+	for i := range embeddingJSONs {
+		vector := make([]float32, dimension)
+		for j := range vector {
+			vector[j] = rng.Float32()
+		}
+		embeddingJSONs[i] = vectorToString(vector)
+	}
+	return embeddingJSONs
+}
+
+// TestDecodeEmbeddingsParallel tests that parallel decoding matches sequential decoding
+func TestDecodeEmbeddingsParallel(t *testing.T) {
+	embeddingJSONs := makeEmbeddingJSONs(257, 64)
+	embeddingJSONs[10] = "not json"
+
+	sequential := decodeEmbeddings(embeddingJSONs, 1)
+	require.Len(t, sequential, len(embeddingJSONs))
+	assert.Nil(t, sequential[10])
+
+	for _, workers := range []int{2, 3, 8, 1000} {
+		parallel := decodeEmbeddings(embeddingJSONs, workers)
+		assert.Equal(t, sequential, parallel, "workers=%d", workers)
+	}
+
+	assert.Nil(t, decodeEmbeddings(nil, 4))
+}
+
+func BenchmarkDecodeEmbeddings(b *testing.B) {
+	embeddingJSONs := makeEmbeddingJSONs(1000, 1536)
+
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				decodeEmbeddings(embeddingJSONs, workers)
+			}
+		})
+	}
+}
+
+// TestCollectionQueryWithCallback tests per-hit callbacks and early abort
+func TestCollectionQueryWithCallback(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "callback", dimension: 3, distance: DistanceL2}
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {9, 9, 9}}),
+	)
+	require.NoError(t, err)
+
+	t.Run("visits hits in rank order", func(t *testing.T) {
+		var ids []string
+		var ranks []int
+		err := collection.QueryWithCallback(ctx, nil, 3, func(record SearchRecord) error {
+			ids = append(ids, record.ID)
+			ranks = append(ranks, record.Rank)
+			return nil
+		}, WithQueryEmbeddings([][]float32{{1, 2, 3}}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id1", "id2", "id3"}, ids)
+		assert.Equal(t, []int{0, 1, 2}, ranks)
+	})
+
+	t.Run("callback error stops scanning", func(t *testing.T) {
+		store.scanned = 0
+		errStop := errors.New("stop")
+		var ids []string
+		err := collection.QueryWithCallback(ctx, nil, 3, func(record SearchRecord) error {
+			ids = append(ids, record.ID)
+			if len(ids) == 1 {
+				return errStop
+			}
+			return nil
+		}, WithQueryEmbeddings([][]float32{{1, 2, 3}, {9, 9, 9}}))
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, []string{"id1"}, ids)
+		assert.Equal(t, 1, store.scanned)
+	})
+}
+
+func TestCollectionQueryOne(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "query_one", dimension: 3, distance: DistanceL2}
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {9, 9, 9}}),
+	)
+	require.NoError(t, err)
+
+	hits, err := collection.QueryOne(ctx, []float32{2, 3, 4}, 2,
+		WithQueryEmbeddings([][]float32{{9, 9, 9}})) // Ignored in favor of the argument
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "id2", hits[0].ID)
+	assert.Equal(t, "doc 2", hits[0].Document)
+	assert.Equal(t, 0.0, hits[0].Distance)
+	assert.Equal(t, "id1", hits[1].ID)
+	assert.Greater(t, hits[1].Distance, 0.0)
+
+	_, err = collection.QueryOne(ctx, nil, 2)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+func TestResolveQueryEmbeddingsQueryTexts(t *testing.T) {
+	ctx := context.Background()
+
+	embeddings, err := resolveQueryEmbeddings(ctx, nil, &QueryOptions{QueryTexts: []string{"ab", "abcd"}}, lengthEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{2, 0, 0}, {4, 0, 0}}, embeddings)
+
+	// The same texts both ways are not a conflict
+	embeddings, err = resolveQueryEmbeddings(ctx, []string{"ab"}, &QueryOptions{QueryTexts: []string{"ab"}}, lengthEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{2, 0, 0}}, embeddings)
+
+	_, err = resolveQueryEmbeddings(ctx, []string{"ab"}, &QueryOptions{QueryTexts: []string{"cd"}}, lengthEmbedder{})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	// Query embeddings take precedence
+	embeddings, err = resolveQueryEmbeddings(ctx, nil, &QueryOptions{QueryTexts: []string{"ab"}, QueryEmbeddings: [][]float32{{1, 1, 1}}}, lengthEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 1, 1}}, embeddings)
+}
+
+// TestCollectionQueryWithCallbackServer tests that callbacks see the same hits as Query
+func TestCollectionQueryWithCallbackServer(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_query_callback_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {9, 9, 9}}),
+	)
+	require.NoError(t, err)
+
+	queryOpt := WithQueryEmbeddings([][]float32{{1, 2, 3}})
+	results, err := collection.Query(ctx, nil, 3, queryOpt)
+	require.NoError(t, err)
+
+	var ids []string
+	err = collection.QueryWithCallback(ctx, nil, 3, func(record SearchRecord) error {
+		ids = append(ids, record.ID)
+		return nil
+	}, queryOpt)
+	require.NoError(t, err)
+	assert.Equal(t, results.IDs[0], ids)
+
+	calls := 0
+	errStop := errors.New("stop")
+	err = collection.QueryWithCallback(ctx, nil, 3, func(record SearchRecord) error {
+		calls++
+		return errStop
+	}, queryOpt)
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
+
+// efSearchConnection is a connection whose transactions record their statements
+// and read ob_hnsw_ef_search and query rows from static result sets
+type efSearchConnection struct {
+	connection.Connection
+	db *sql.DB
+	tx *efSearchTx
+
+	failRestore bool   // Fail restoring ef_search
+	onQuery     func() // Called by vector queries, e.g. to cancel their context
+}
+
+func (c *efSearchConnection) Begin(ctx context.Context) (connection.Tx, error) {
+	c.tx = &efSearchTx{db: c.db, failRestore: c.failRestore, onQuery: c.onQuery}
+	return c.tx, nil
+}
+
+type efSearchTx struct {
+	connection.Tx
+	db         *sql.DB
+	stmts      []string
+	rolledBack bool
+	discarded  bool
+	ctxErrs    []error // Context errors seen by Execute
+
+	failRestore bool
+	onQuery     func()
+}
+
+func (t *efSearchTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.stmts = append(t.stmts, query)
+	t.ctxErrs = append(t.ctxErrs, ctx.Err())
+	if t.failRestore && len(t.ctxErrs) > 1 {
+		return nil, errors.New("invalid connection")
+	}
+	return nil, nil
+}
+
+func (t *efSearchTx) Discard() error {
+	t.discarded = true
+	return nil
+}
+
+func (t *efSearchTx) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	t.stmts = append(t.stmts, query)
+	return t.db.QueryRowContext(ctx, "ef_search_previous")
+}
+
+func (t *efSearchTx) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	t.stmts = append(t.stmts, "vector query")
+	if t.onQuery != nil {
+		t.onQuery()
+	}
+	return t.db.QueryContext(context.WithoutCancel(ctx), "ef_search_rows")
+}
+
+func (t *efSearchTx) Rollback() error {
+	t.rolledBack = true
+	return nil
+}
+
+// TestQueryEfSearch tests that WithEfSearch sets ef_search on the query's session
+// and restores it afterwards
+func TestQueryEfSearch(t *testing.T) {
+	ctx := context.Background()
+	registerStaticRows(t, "ef_search_previous", []string{"@@ob_hnsw_ef_search"}, [][]driver.Value{{int64(64)}})
+	db := registerStaticRows(t, "ef_search_rows", []string{"_id", "document", "metadata", "embedding", "distance"}, makeHybridRows(2))
+	conn := &efSearchConnection{db: db}
+	client := &Client{conn: conn, config: &ClientConfig{}}
+
+	options := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2, 3}, {4, 5, 6}}}
+	WithEfSearch(200)(options)
+	result, err := client.collectionQuery(ctx, "docs", nil, 2, options, nil, DistanceL2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"id0", "id1"}, {"id0", "id1"}}, result.IDs)
+
+	// Both query embeddings run in the session with ef_search set
+	require.NotNil(t, conn.tx)
+	assert.Equal(t, []string{
+		"SELECT @@ob_hnsw_ef_search",
+		"SET ob_hnsw_ef_search = 200",
+		"vector query",
+		"vector query",
+		"SET ob_hnsw_ef_search = 64",
+	}, conn.tx.stmts)
+	assert.True(t, conn.tx.rolledBack)
+}
+
+// TestQueryEfSearchRestore tests that ef_search is restored after the query's context
+// is canceled, and that the connection is closed if it cannot be restored
+func TestQueryEfSearchRestore(t *testing.T) {
+	registerStaticRows(t, "ef_search_previous", []string{"@@ob_hnsw_ef_search"}, [][]driver.Value{{int64(64)}})
+	db := registerStaticRows(t, "ef_search_rows", []string{"_id", "document", "metadata", "embedding", "distance"}, makeHybridRows(2))
+
+	t.Run("restored after cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		conn := &efSearchConnection{db: db, onQuery: cancel}
+		client := &Client{conn: conn, config: &ClientConfig{}}
+
+		options := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2, 3}}}
+		WithEfSearch(200)(options)
+		_, _ = client.collectionQuery(ctx, "docs", nil, 2, options, nil, DistanceL2)
+
+		require.NotNil(t, conn.tx)
+		assert.Equal(t, "SET ob_hnsw_ef_search = 64", conn.tx.stmts[len(conn.tx.stmts)-1])
+		assert.Equal(t, []error{nil, nil}, conn.tx.ctxErrs)
+		assert.True(t, conn.tx.rolledBack)
+		assert.False(t, conn.tx.discarded)
+	})
+
+	t.Run("connection closed if restore fails", func(t *testing.T) {
+		conn := &efSearchConnection{db: db, failRestore: true}
+		logger := &recordingLogger{}
+		client := &Client{conn: conn, config: &ClientConfig{Logger: logger}}
+
+		options := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2, 3}}}
+		WithEfSearch(200)(options)
+		_, err := client.collectionQuery(context.Background(), "docs", nil, 2, options, nil, DistanceL2)
+		require.NoError(t, err)
+
+		require.NotNil(t, conn.tx)
+		assert.True(t, conn.tx.discarded)
+		assert.False(t, conn.tx.rolledBack)
+		require.Len(t, logger.warnings, 1)
+		assert.Contains(t, logger.warnings[0], "closing the connection")
+	})
+}
+
+// TestQueryValidatesBeforeEmbedding tests that invalid query options fail before
+// the query texts are embedded
+func TestQueryValidatesBeforeEmbedding(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{config: &ClientConfig{}}
+	embedder := &countingEmbedder{}
+
+	_, err := client.collectionQuery(ctx, "docs", []string{"query"}, 2, &QueryOptions{Include: []string{"ids"}}, embedder, DistanceL2)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	err = client.collectionQueryEach(ctx, "docs", []string{"query"}, 2, &QueryOptions{ExtraColumns: []string{"bad column"}}, embedder, DistanceL2, func(SearchRecord) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	assert.Equal(t, int32(0), embedder.calls.Load())
+}
+
+// TestQueryWithCallbackDecodeError tests that rows that cannot be decoded fail the
+// query instead of reaching the callback with empty fields
+func TestQueryWithCallbackDecodeError(t *testing.T) {
+	registerStaticRows(t, "ef_search_previous", []string{"@@ob_hnsw_ef_search"}, [][]driver.Value{{int64(64)}})
+	db := registerStaticRows(t, "ef_search_rows", []string{"_id", "document", "metadata", "embedding", "distance"}, [][]driver.Value{
+		{[]byte("id0"), []byte("document 0"), []byte(`{}`), []byte("not a vector"), float64(1)},
+	})
+	client := &Client{conn: &efSearchConnection{db: db}, config: &ClientConfig{}}
+
+	options := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2, 3}}}
+	WithEfSearch(200)(options)
+	calls := 0
+	err := client.collectionQueryEach(context.Background(), "docs", nil, 2, options, nil, DistanceL2, func(SearchRecord) error {
+		calls++
+		return nil
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decode embedding of "id0"`)
+	assert.Equal(t, 0, calls)
+}
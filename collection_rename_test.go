@@ -0,0 +1,217 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionRename tests renaming a collection together with its indexes
+func TestCollectionRename(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	suffix := uuid.New().String()[:8]
+	oldName := "test_rename_old_" + suffix
+	newName := "test_rename_new_" + suffix
+	collection := createTestCollection(t, client, oldName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, oldName)
+		_ = client.DeleteCollection(ctx, newName)
+	}()
+
+	ctx := context.Background()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"first document", "second document"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}}),
+	)
+	require.NoError(t, err)
+
+	err = collection.Rename(ctx, newName)
+	require.NoError(t, err)
+	assert.Equal(t, newName, collection.Name())
+
+	exists, err := client.HasCollection(ctx, oldName)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = client.HasCollection(ctx, newName)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	t.Run("index names follow the new collection name", func(t *testing.T) {
+		indexes, err := client.listIndexNames(ctx, client.GetTableName(newName))
+		require.NoError(t, err)
+		for _, index := range indexes {
+			assert.False(t, strings.Contains(index, oldName), "index %s still refers to old name", index)
+		}
+	})
+
+	t.Run("vector index still serves queries", func(t *testing.T) {
+		results, err := collection.Query(ctx, nil, 1,
+			WithQueryEmbeddings([][]float32{{1.0, 2.0, 3.0}}),
+		)
+		require.NoError(t, err)
+		require.Len(t, results.IDs[0], 1)
+		assert.Equal(t, "id1", results.IDs[0][0])
+	})
+
+	t.Run("empty name is rejected", func(t *testing.T) {
+		err := collection.Rename(ctx, "")
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		assert.Equal(t, newName, collection.Name())
+	})
+}
+
+// TestRenamedIndexName tests renaming only indexes named after the collection
+func TestRenamedIndexName(t *testing.T) {
+	for _, tt := range []struct{ index, want string }{
+		{"vidx_docs", "vidx_articles"},
+		{"docs_title_idx", "articles_title_idx"},
+		{"idx_docs_embedding", "idx_articles_embedding"},
+		{"docs", "articles"},
+	} {
+		index, ok := renamedIndexName(tt.index, "docs", "articles")
+		assert.True(t, ok, tt.index)
+		assert.Equal(t, tt.want, index)
+	}
+
+	// Index names merely containing the collection name are kept
+	for _, index := range []string{"vidx_docs2", "mydocs_idx", "docs-old_idx"} {
+		_, ok := renamedIndexName(index, "docs", "articles")
+		assert.False(t, ok, index)
+	}
+
+	// None of the indexes the client creates is renamed, even if its parts match the
+	// collection name
+	client := &Client{config: &ClientConfig{}}
+	options := &CreateCollectionOptions{
+		Configuration:       &HNSWConfiguration{Dimension: 3, VectorFields: []VectorField{{Name: "title", Dimension: 3}}},
+		IndexedMetadataKeys: []string{"category"},
+	}
+	createSQL, err := client.createCollectionSQL("docs", 3, DistanceL2, options, nil)
+	require.NoError(t, err)
+	indexes := regexp.MustCompile("(?:INDEX|KEY) `?([a-z_]+)`? \\(").FindAllStringSubmatch(createSQL, -1)
+	require.NotEmpty(t, indexes)
+	for _, match := range indexes {
+		assert.True(t, isClientIndexName(match[1]), match[1])
+		for _, collectionName := range []string{"idx", "fts", "embedding", "title", "meta", "content", "hash", "uk"} {
+			_, ok := renamedIndexName(match[1], collectionName, "renamed")
+			assert.False(t, ok, "%s of collection %s", match[1], collectionName)
+		}
+	}
+	for _, index := range []string{"PRIMARY", "idx_fts", "idx_embedding", "idx_embedding_title", "idx_meta_category", "uk_content_hash", "idx_content_hash"} {
+		assert.True(t, isClientIndexName(index), index)
+	}
+}
+
+// TestValidateCollectionName tests the names collections can be renamed to
+func TestValidateCollectionName(t *testing.T) {
+	for _, name := range []string{"docs", "Docs_2", "my-docs"} {
+		assert.NoError(t, validateCollectionName(name), name)
+	}
+	for _, name := range []string{"", "a`b", "docs; DROP TABLE t", "my docs", "dötsch"} {
+		assert.ErrorIs(t, validateCollectionName(name), ErrInvalidParameter, name)
+	}
+}
+
+// renameConnection is a connection whose queries return the static rows registered
+// under key, and whose statements are recorded and fail if they start with failOn
+type renameConnection struct {
+	staticConnection
+	failOn string
+	stmts  []string
+}
+
+func (c *renameConnection) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.stmts = append(c.stmts, query)
+	if strings.HasPrefix(query, c.failOn) {
+		return nil, errors.New("rename failed")
+	}
+	return nil, nil
+}
+
+// TestCollectionRenameUndoesIndexRenames tests renaming indexes back when the table rename fails
+func TestCollectionRenameUndoesIndexRenames(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "rename_indexes", []string{"INDEX_NAME"},
+		[][]driver.Value{{[]byte("PRIMARY")}, {[]byte("idx_fts")}, {[]byte("idx_embedding")}, {[]byte("vidx_docs")}})
+	conn := &renameConnection{staticConnection: staticConnection{db: db, key: "rename_indexes"}, failOn: "RENAME TABLE"}
+	client := &Client{conn: conn, config: &ClientConfig{}}
+
+	err := client.collectionRename(ctx, "docs", "articles")
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"ALTER TABLE `c$v1$docs` RENAME INDEX `vidx_docs` TO `vidx_articles`",
+		"RENAME TABLE `c$v1$docs` TO `c$v1$articles`",
+		"ALTER TABLE `c$v1$docs` RENAME INDEX `vidx_articles` TO `vidx_docs`",
+	}, conn.stmts)
+
+	conn.stmts = nil
+	assert.ErrorIs(t, client.collectionRename(ctx, "docs", "bad`name"), ErrInvalidParameter)
+	assert.Empty(t, conn.stmts)
+}
+
+// TestBuildSwapSQL tests the multi-table rename used to swap collections
+func TestBuildSwapSQL(t *testing.T) {
+	assert.Equal(t,
+		"RENAME TABLE `c$v1$blue` TO `c$v1$tmp`, `c$v1$green` TO `c$v1$blue`, `c$v1$tmp` TO `c$v1$green`",
+		buildSwapSQL("c$v1$blue", "c$v1$green", "c$v1$tmp"))
+}
+
+// TestSwapCollections tests that two populated collections exchange their data
+func TestSwapCollections(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	suffix := uuid.New().String()[:8]
+	blueName := "test_swap_blue_" + suffix
+	greenName := "test_swap_green_" + suffix
+	blue := createTestCollection(t, client, blueName, 3)
+	green := createTestCollection(t, client, greenName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, blueName)
+		_ = client.DeleteCollection(ctx, greenName)
+	}()
+
+	ctx := context.Background()
+	require.NoError(t, blue.Add(ctx, []string{"old1"}, []string{"old index"},
+		WithEmbeddings([][]float32{{1, 2, 3}})))
+	require.NoError(t, green.Add(ctx, []string{"new1", "new2"}, []string{"new index", "new index 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}})))
+
+	require.NoError(t, client.SwapCollections(ctx, blueName, greenName))
+
+	// Existing handles keep their names and now serve the other collection's rows
+	result, err := blue.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"new1", "new2"}, result.IDs)
+
+	result, err = green.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old1"}, result.IDs)
+
+	results, err := blue.Query(ctx, nil, 1, WithQueryEmbeddings([][]float32{{2, 3, 4}}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new2"}, results.IDs[0])
+
+	t.Run("missing collection is rejected", func(t *testing.T) {
+		err := client.SwapCollections(ctx, blueName, "test_swap_missing_"+suffix)
+		assert.ErrorIs(t, err, ErrCollectionNotFound)
+	})
+
+	t.Run("self swap is rejected", func(t *testing.T) {
+		err := client.SwapCollections(ctx, blueName, blueName)
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+	})
+}
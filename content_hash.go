@@ -0,0 +1,60 @@
+package goseekdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// contentHash returns the hex SHA256 of a document, as stored in FieldContentHash.
+func contentHash(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentHashArg returns the value to bind for the content hash column. Empty
+// documents get NULL, which the unique index does not compare, so documents
+// without text (e.g. embedding-only rows) are never collapsed into one.
+func contentHashArg(document string) interface{} {
+	if document == "" {
+		return nil
+	}
+	return contentHash(document)
+}
+
+// contentHashDefinitions returns the column and unique index definitions for
+// a collection created with WithContentHash, for use in its CREATE TABLE statement.
+func contentHashDefinitions() []string {
+	return []string{
+		fmt.Sprintf("%s CHAR(64) NULL", FieldContentHash),
+		fmt.Sprintf("UNIQUE KEY uk%s (%s)", FieldContentHash, FieldContentHash),
+	}
+}
+
+// collectionFindByContentHash returns the ID of the document with the given content hash.
+func (c *Client) collectionFindByContentHash(ctx context.Context, collectionName string, hash string) (id string, found bool, err error) {
+	ctx, span := c.startSpan(ctx, "find_by_content_hash", collectionName)
+	defer func() {
+		rowCount := 0
+		if found {
+			rowCount = 1
+		}
+		span.end(rowCount, err)
+	}()
+
+	ctx, cancel := c.readContext(ctx)
+	defer cancel()
+
+	querySQL := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? LIMIT 1", FieldID, c.GetTableName(collectionName), FieldContentHash)
+	err = c.conn.QueryRow(ctx, querySQL, hash).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up content hash: %w", err)
+	}
+	return id, true, nil
+}
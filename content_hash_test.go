@@ -0,0 +1,105 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContentHash tests hashing and how the hash column is written
+func TestContentHash(t *testing.T) {
+	// echo -n "hello" | sha256sum
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", contentHash("hello"))
+	assert.Equal(t, contentHash("hello"), contentHashArg("hello"))
+	assert.Nil(t, contentHashArg(""))
+
+	assert.Equal(t,
+		"INSERT IGNORE INTO c$v1$docs (_id, document, embedding, metadata, _content_hash) VALUES (?, ?, ?, ?, ?)",
+		copyInsertSQL("c$v1$docs", 1, &CopyOptions{contentHash: true}))
+
+	args, err := copyRecordArgs([]Record{{ID: "a", Document: "hello", Embedding: []float32{1}}}, &CopyOptions{contentHash: true})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "hello", "[1]", "{}", contentHash("hello")}, args)
+
+	// Add and Upsert write the hash like CopyFrom
+	add := &AddOptions{contentHash: true}
+	assert.Equal(t,
+		"INSERT IGNORE INTO c$v1$docs (_id, document, embedding, metadata, _content_hash) VALUES (?, ?, ?, ?, ?)",
+		insertRecordSQL("c$v1$docs", add, SchemaVersion1))
+	assert.Equal(t,
+		"INSERT INTO c$v1$docs (_id, document, embedding, metadata, _content_hash) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE document = VALUES(document), embedding = VALUES(embedding), metadata = VALUES(metadata), _content_hash = VALUES(_content_hash)",
+		upsertRecordSQL("c$v1$docs", add, SchemaVersion1))
+	args, err = recordArgs("a", 0, []string{"hello"}, [][]float32{{1}}, add, SchemaVersion1)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "hello", "[1]", "{}", contentHash("hello")}, args)
+	assert.Equal(t, "INSERT INTO c$v1$docs (_id, document, embedding, metadata) VALUES (?, ?, ?, ?)", insertRecordSQL("c$v1$docs", &AddOptions{}, SchemaVersion1))
+
+	// Collections created WithContentHash get the uniquely indexed column
+	client := &Client{config: &ClientConfig{}}
+	createSQL, err := client.createCollectionSQL("docs", 3, DistanceL2, &CreateCollectionOptions{ContentHash: true}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, createSQL, "_content_hash CHAR(64) NULL")
+	assert.Contains(t, createSQL, "UNIQUE KEY uk_content_hash (_content_hash)")
+}
+
+// TestCollectionExistsByContent tests looking up documents by their text
+func TestCollectionExistsByContent(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "dedupe", dimension: 3, distance: DistanceL2}
+
+	_, _, err := collection.ExistsByContent(ctx, "hello")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	collection.contentHash = true
+	require.NoError(t, collection.Add(ctx, []string{"id1"}, []string{"hello"}))
+
+	exists, id, err := collection.ExistsByContent(ctx, "hello")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "id1", id)
+
+	exists, _, err = collection.ExistsByContent(ctx, "hello!")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// TestContentHashDedupe tests that identical documents are stored once
+func TestContentHashDedupe(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_content_hash_" + uuid.New().String()[:8]
+	collection, err := client.CreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 3, Distance: DistanceL2}),
+		WithCollectionEmbeddingFunc(nil),
+		WithContentHash(true),
+	)
+	require.NoError(t, err)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	added, err := collection.AddN(ctx, []string{"id1"}, []string{"duplicate chunk"},
+		WithEmbeddings([][]float32{{1, 2, 3}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), added)
+
+	added, err = collection.AddN(ctx, []string{"id2"}, []string{"duplicate chunk"},
+		WithEmbeddings([][]float32{{1, 2, 3}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), added)
+
+	count, err := collection.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	exists, id, err := collection.ExistsByContent(ctx, "duplicate chunk")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "id1", id)
+}
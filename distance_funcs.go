@@ -0,0 +1,95 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// DistanceFuncMap maps distance metrics to the SQL functions computing them on a
+// server. Metrics missing from the map use DistanceMetric.DistanceFuncName.
+type DistanceFuncMap map[DistanceMetric]string
+
+// mysqlErrNoSuchFunction is returned when a statement calls an unknown function.
+const mysqlErrNoSuchFunction = 1305
+
+// distanceMetrics lists the supported metrics in probing order.
+var distanceMetrics = []DistanceMetric{DistanceL2, DistanceCosine, DistanceInnerProduct}
+
+// distanceFuncCandidates lists the function names of each metric across server
+// builds, most common first. Function names are case-insensitive, so spellings such
+// as COSINE_DISTANCE need no entry of their own.
+var distanceFuncCandidates = map[DistanceMetric][]string{
+	DistanceL2:           {"l2_distance", "vec_l2_distance"},
+	DistanceCosine:       {"cosine_distance", "vec_cosine_distance"},
+	DistanceInnerProduct: {"inner_product", "vec_inner_product"},
+}
+
+// distanceFuncName returns the SQL function for distance on this client's server:
+// the name set WithDistanceFuncs, else the name detected on connect, else the
+// default name.
+func (c *Client) distanceFuncName(distance DistanceMetric) string {
+	if c.config != nil {
+		if name := c.config.DistanceFuncs[distance]; name != "" {
+			return name
+		}
+		if c.config.detectedDistanceFuncs != nil {
+			if detected := c.config.detectedDistanceFuncs.Load(); detected != nil && (*detected)[distance] != "" {
+				return (*detected)[distance]
+			}
+		}
+	}
+	return distance.DistanceFuncName()
+}
+
+// detectDistanceFuncs probes which distance function names the server provides, for
+// metrics not set WithDistanceFuncs, and remembers them for distanceFuncName. A
+// metric for which no candidate exists keeps its default name, so queries using it
+// report the server's error.
+func (c *Client) detectDistanceFuncs(ctx context.Context) error {
+	if c.config == nil || c.config.detectedDistanceFuncs == nil {
+		return nil
+	}
+
+	detected := make(DistanceFuncMap)
+	for _, distance := range distanceMetrics {
+		if c.config.DistanceFuncs[distance] != "" {
+			continue
+		}
+		name, err := c.probeDistanceFunc(ctx, distanceFuncCandidates[distance])
+		if err != nil {
+			return fmt.Errorf("failed to detect %s distance function: %w", distance, err)
+		}
+		if name == "" {
+			c.logger().Warnf("server provides none of the %s distance functions %v", distance, distanceFuncCandidates[distance])
+			continue
+		}
+		if name != distance.DistanceFuncName() {
+			c.logger().Debugf("using %s for %s distance", name, distance)
+		}
+		detected[distance] = name
+	}
+
+	c.config.detectedDistanceFuncs.Store(&detected)
+	return nil
+}
+
+// probeDistanceFunc returns the first of names the server can call, or "" if none.
+func (c *Client) probeDistanceFunc(ctx context.Context, names []string) (string, error) {
+	for _, name := range names {
+		// names are constants, never user input
+		var distance sql.NullFloat64
+		err := c.conn.QueryRow(ctx, fmt.Sprintf("SELECT %s('[1]', '[1]')", name)).Scan(&distance)
+		if err == nil {
+			return name, nil
+		}
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrNoSuchFunction {
+			return "", err
+		}
+	}
+	return "", nil
+}
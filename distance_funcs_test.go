@@ -0,0 +1,81 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// probeConnection is a connection on which only the functions in available exist
+type probeConnection struct {
+	connection.Connection
+	db        *sql.DB
+	available []string
+	probes    []string
+}
+
+func (p *probeConnection) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (p *probeConnection) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	p.probes = append(p.probes, query)
+	for _, name := range p.available {
+		if strings.HasPrefix(query, "SELECT "+name+"(") {
+			return p.db.QueryRowContext(ctx, "distance_probe")
+		}
+	}
+	return errRow(ctx, &mysql.MySQLError{Number: 1305, Message: fmt.Sprintf("FUNCTION %s does not exist", query)})
+}
+
+// TestDetectDistanceFuncs tests probing the distance function names of a server
+func TestDetectDistanceFuncs(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "distance_probe", []string{"d"}, [][]driver.Value{{0.0}})
+	conn := &probeConnection{db: db, available: []string{"l2_distance", "vec_cosine_distance"}}
+	config := DefaultClientConfig()
+	WithDistanceFuncs(DistanceFuncMap{DistanceInnerProduct: "my_inner_product"})(config)
+	client := &Client{conn: conn, config: config}
+
+	assert.Equal(t, "cosine_distance", client.distanceFuncName(DistanceCosine))
+	require.NoError(t, client.detectDistanceFuncs(ctx))
+
+	assert.Equal(t, "l2_distance", client.distanceFuncName(DistanceL2))
+	assert.Equal(t, "vec_cosine_distance", client.distanceFuncName(DistanceCosine))
+	// Overridden metrics are not probed
+	assert.Equal(t, "my_inner_product", client.distanceFuncName(DistanceInnerProduct))
+	assert.Equal(t, []string{
+		"SELECT l2_distance('[1]', '[1]')",
+		"SELECT cosine_distance('[1]', '[1]')",
+		"SELECT vec_cosine_distance('[1]', '[1]')",
+	}, conn.probes)
+
+	querySQL, _ := buildVectorQuerySQL("c$v1$test", "", nil, []float32{1}, 5, DistanceCosine, client.distanceFuncName(DistanceCosine), &QueryOptions{})
+	assert.Contains(t, querySQL, "vec_cosine_distance(embedding, ?)")
+
+	// Metrics without any known function keep their default name
+	conn.available = nil
+	config.DistanceFuncs = nil
+	require.NoError(t, client.detectDistanceFuncs(ctx))
+	assert.Equal(t, "inner_product", client.distanceFuncName(DistanceInnerProduct))
+
+	// Connect detects the names
+	conn = &probeConnection{db: db, available: []string{"vec_l2_distance"}}
+	client = &Client{conn: conn, config: DefaultClientConfig()}
+	require.NoError(t, client.Connect(ctx))
+	assert.Equal(t, "vec_l2_distance", client.distanceFuncName(DistanceL2))
+	assert.NotEmpty(t, conn.probes)
+
+	// Configurations built by hand use the defaults
+	plain := &Client{conn: conn, config: &ClientConfig{}}
+	require.NoError(t, plain.detectDistanceFuncs(ctx))
+	assert.Equal(t, "cosine_distance", plain.distanceFuncName(DistanceCosine))
+}
@@ -0,0 +1,30 @@
+package goseekdb
+
+import "fmt"
+
+// Documents can be stored as SQL NULL (e.g. rows written by other tools, or with
+// WithTreatEmptyAsNull) or as "". Reads return both as "", and document filters
+// treat both as "no document".
+
+// documentArg returns the value to bind for a document column, mapping "" to NULL
+// when emptyAsNull is set.
+func documentArg(document string, emptyAsNull bool) interface{} {
+	if emptyAsNull && document == "" {
+		return nil
+	}
+	return document
+}
+
+// buildDocumentExistsCondition builds the SQL condition for the $exists document
+// operator. {"$exists": true} matches rows with a non-empty document; {"$exists": false}
+// matches rows whose document is NULL or "".
+func buildDocumentExistsCondition(value interface{}) (string, []interface{}, error) {
+	exists, ok := value.(bool)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: $exists on document requires a boolean, got %T", ErrInvalidParameter, value)
+	}
+	if exists {
+		return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", FieldDocument, FieldDocument), nil, nil
+	}
+	return fmt.Sprintf("(%s IS NULL OR %s = '')", FieldDocument, FieldDocument), nil, nil
+}
@@ -0,0 +1,147 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addOptionsRecorder records the options passed to collectionAdd.
+type addOptionsRecorder struct {
+	fakeOperations
+	opts *AddOptions
+}
+
+func (a *addOptionsRecorder) collectionAdd(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (int64, error) {
+	a.opts = opts
+	return int64(len(ids)), nil
+}
+
+// TestDocumentArg tests how empty documents are bound
+func TestDocumentArg(t *testing.T) {
+	assert.Equal(t, "", documentArg("", false))
+	assert.Nil(t, documentArg("", true))
+	assert.Equal(t, "text", documentArg("text", true))
+
+	ctx := context.Background()
+	recorder := &addOptionsRecorder{}
+	collection := &Collection{client: recorder, name: "empty_docs", dimension: 3, treatEmptyAsNull: true}
+	require.NoError(t, collection.Add(ctx, []string{"id1"}, []string{""}))
+	assert.True(t, recorder.opts.emptyDocumentsAsNull)
+
+	// Add, Upsert and Update bind "" documents as NULL
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{}}
+	add := &AddOptions{Embeddings: [][]float32{{1, 2, 3}}, emptyDocumentsAsNull: true}
+	var dryRun *DryRunError
+	_, err := client.collectionAdd(ctx, "docs", []string{"id1"}, []string{""}, add, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, []interface{}{"id1", nil, "[1,2,3]", "{}"}, dryRun.Statements[0].Args)
+
+	_, err = client.collectionUpsert(ctx, "docs", []string{"id1"}, []string{""}, add, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Nil(t, dryRun.Statements[0].Args[1])
+
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{Documents: []string{""}, emptyDocumentsAsNull: true}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, []interface{}{nil, "id1"}, dryRun.Statements[0].Args)
+}
+
+// TestBuildDocumentExistsCondition tests the SQL generated for document $exists
+func TestBuildDocumentExistsCondition(t *testing.T) {
+	clause, args, err := buildDocumentExistsCondition(true)
+	require.NoError(t, err)
+	assert.Equal(t, "(document IS NOT NULL AND document <> '')", clause)
+	assert.Empty(t, args)
+
+	clause, _, err = buildDocumentExistsCondition(false)
+	require.NoError(t, err)
+	assert.Equal(t, "(document IS NULL OR document = '')", clause)
+
+	_, _, err = buildDocumentExistsCondition("yes")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	clause, _, err = NewFilterBuilder().BuildDocumentFilter(Filter{"$exists": false})
+	require.NoError(t, err)
+	assert.Equal(t, "(document IS NULL OR document = '')", clause)
+}
+
+// TestCollectionNullAndEmptyDocuments tests reads and filters over NULL and "" documents
+func TestCollectionNullAndEmptyDocuments(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_null_docs_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"empty", "text"}, []string{"", "machine learning"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}}),
+	)
+	require.NoError(t, err)
+
+	// A row written without a document, as another tool might
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s, %s, %s, %s) VALUES (?, NULL, ?, ?)",
+		client.GetTableName(collectionName), FieldID, FieldDocument, FieldEmbedding, FieldMetadata)
+	_, err = client.conn.Execute(ctx, insertSQL, "null", "[3,4,5]", "{}")
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, []string{"empty", "null"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"empty", "null"}, results.IDs)
+	assert.Equal(t, []string{"", ""}, results.Documents)
+
+	queryResults, err := collection.Query(ctx, nil, 3, WithQueryEmbeddings([][]float32{{3, 4, 5}}))
+	require.NoError(t, err)
+	assert.Len(t, queryResults.IDs[0], 3)
+
+	results, err = collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$contains": "learning"}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"text"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$exists": false}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"empty", "null"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$exists": true}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"text"}, results.IDs)
+}
+
+// TestCollectionTreatEmptyAsNull tests that WithTreatEmptyAsNull applies to the
+// collections returned by CreateCollection and GetCollection
+func TestCollectionTreatEmptyAsNull(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_empty_null_" + uuid.New().String()[:8]
+	collection, err := client.CreateCollection(ctx, collectionName,
+		WithConfiguration(&HNSWConfiguration{Dimension: 3, Distance: DistanceL2}),
+		WithCollectionEmbeddingFunc(nil),
+		WithTreatEmptyAsNull(true),
+	)
+	require.NoError(t, err)
+	defer client.DeleteCollection(ctx, collectionName)
+	assert.True(t, collection.treatEmptyAsNull)
+
+	err = collection.Add(ctx, []string{"empty"}, []string{""}, WithEmbeddings([][]float32{{1, 2, 3}}))
+	require.NoError(t, err)
+
+	var isNull bool
+	query := fmt.Sprintf("SELECT %s IS NULL FROM %s WHERE %s = ?", FieldDocument, client.GetTableName(collectionName), FieldID)
+	require.NoError(t, client.conn.QueryRow(ctx, query, "empty").Scan(&isNull))
+	assert.True(t, isNull)
+
+	collection, err = client.GetCollection(ctx, collectionName, WithTreatEmptyAsNull(true))
+	require.NoError(t, err)
+	assert.True(t, collection.treatEmptyAsNull)
+}
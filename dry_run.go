@@ -0,0 +1,140 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+
+	"github.com/ob-labs/seekdb-go/internal/connection"
+)
+
+// DryRunStatement is a statement an operation would have run in dry run mode.
+type DryRunStatement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// DryRunError is returned by operations of a client created WithDryRun(true). It
+// holds the statements the operation generated, in order; none of them was executed.
+// Use errors.As to retrieve it.
+type DryRunError struct {
+	Statements []DryRunStatement
+}
+
+func (e *DryRunError) Error() string {
+	sqls := make([]string, len(e.Statements))
+	for i, stmt := range e.Statements {
+		sqls[i] = stmt.SQL
+	}
+	return "dry run, not executed: " + strings.Join(sqls, "; ")
+}
+
+// dryRunConnection captures the statements of operations instead of running them.
+// Statements reading INFORMATION_SCHEMA are still run, so collections can be opened
+// and their table resolved.
+//
+// Reads fail with a DryRunError right away, as the operation cannot continue without
+// their rows. Writes in a transaction are recorded and reported when it commits, so
+// e.g. hybrid search reports both the SET of search_parm and the GET_SQL call. Writes
+// outside a transaction fail with a DryRunError.
+type dryRunConnection struct {
+	connection.Connection
+}
+
+// newDryRunConnection wraps conn for WithDryRun.
+func newDryRunConnection(conn connection.Connection) *dryRunConnection {
+	return &dryRunConnection{Connection: conn}
+}
+
+// isCatalogQuery reports whether query only reads the catalog and may be run.
+func isCatalogQuery(query string) bool {
+	return strings.Contains(strings.ToUpper(query), "INFORMATION_SCHEMA.")
+}
+
+func (d *dryRunConnection) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, (&dryRunTx{}).capture(query, args)
+}
+
+func (d *dryRunConnection) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if isCatalogQuery(query) {
+		return d.Connection.Query(ctx, query, args...)
+	}
+	return nil, (&dryRunTx{}).capture(query, args)
+}
+
+func (d *dryRunConnection) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if isCatalogQuery(query) {
+		return d.Connection.QueryRow(ctx, query, args...)
+	}
+	return errRow(ctx, (&dryRunTx{}).capture(query, args))
+}
+
+// RawConnection returns nil, so that operations preparing statements on the
+// underlying *sql.DB, such as CopyFrom, fall back to Execute and are captured too.
+func (d *dryRunConnection) RawConnection() interface{} {
+	return nil
+}
+
+// Begin starts a recording transaction; no transaction is opened on the server.
+func (d *dryRunConnection) Begin(ctx context.Context) (connection.Tx, error) {
+	return &dryRunTx{}, nil
+}
+
+// dryRunTx records the statements of a transaction in dry run mode.
+type dryRunTx struct {
+	statements []DryRunStatement
+}
+
+// record appends a statement, copying its arguments.
+func (t *dryRunTx) record(query string, args []interface{}) {
+	t.statements = append(t.statements, DryRunStatement{SQL: query, Args: append([]interface{}(nil), args...)})
+}
+
+// capture records a statement and returns the DryRunError ending the operation.
+func (t *dryRunTx) capture(query string, args []interface{}) error {
+	t.record(query, args)
+	return &DryRunError{Statements: t.statements}
+}
+
+func (t *dryRunTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (t *dryRunTx) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, t.capture(query, args)
+}
+
+func (t *dryRunTx) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return errRow(ctx, t.capture(query, args))
+}
+
+// Commit reports the recorded statements, which were not executed.
+func (t *dryRunTx) Commit() error {
+	if len(t.statements) == 0 {
+		return nil
+	}
+	return &DryRunError{Statements: t.statements}
+}
+
+func (t *dryRunTx) Rollback() error {
+	return nil
+}
+
+// errRow returns a row whose Scan returns err. sql.Row cannot be built outside
+// database/sql, so it comes from a pool whose connections fail to open with err.
+func errRow(ctx context.Context, err error) *sql.Row {
+	db := sql.OpenDB(errConnector{err})
+	defer db.Close()
+	return db.QueryRowContext(ctx, "")
+}
+
+// errConnector is a driver.Connector failing every connection attempt with err.
+type errConnector struct {
+	err error
+}
+
+func (c errConnector) Connect(context.Context) (driver.Conn, error) { return nil, c.err }
+func (c errConnector) Driver() driver.Driver                        { return c }
+func (c errConnector) Open(string) (driver.Conn, error)             { return nil, c.err }
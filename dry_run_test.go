@@ -0,0 +1,82 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDryRun tests that operations return their SQL instead of running it
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "dry_run_tables", []string{"TABLE_NAME"}, [][]driver.Value{
+		{[]byte("c$v1$docs")},
+	})
+	config := &ClientConfig{}
+	WithDryRun(true)(config)
+	client := &Client{conn: clientConnection(config, &staticConnection{db: db, key: "dry_run_tables"}), config: config}
+
+	// Catalog lookups still run
+	names, err := client.ListCollectionNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, names)
+
+	var dryRun *DryRunError
+	_, err = client.collectionGet(ctx, "docs", []string{"id1"}, &GetOptions{})
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Statements, 1)
+	assert.Contains(t, dryRun.Statements[0].SQL, "FROM c$v1$docs")
+	assert.Contains(t, dryRun.Statements[0].Args, "id1")
+
+	_, err = client.collectionCount(ctx, "docs", nil, nil)
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Statements, 1)
+	assert.Contains(t, dryRun.Statements[0].SQL, "SELECT COUNT(*)")
+
+	// Hybrid search reports the search_parm it sets and the GET_SQL call
+	_, err = client.collectionHybridSearch(ctx, "docs",
+		&HybridSearchQuery{Where: Filter{"category": "AI"}}, nil, nil, 5, nil, DistanceL2)
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Statements, 2)
+	assert.Equal(t, "SET @search_parm = ?", dryRun.Statements[0].SQL)
+	assert.Contains(t, dryRun.Statements[1].SQL, "DBMS_HYBRID_SEARCH.GET_SQL")
+	assert.Equal(t, []interface{}{"c$v1$docs"}, dryRun.Statements[1].Args)
+
+	// Writes in a transaction are reported when it commits
+	tx, err := client.conn.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Execute(ctx, "DELETE FROM `c$v1$docs` WHERE _id = ?", "id1")
+	require.NoError(t, err)
+	err = tx.Commit()
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, []DryRunStatement{{SQL: "DELETE FROM `c$v1$docs` WHERE _id = ?", Args: []interface{}{"id1"}}}, dryRun.Statements)
+
+	_, err = client.conn.Execute(ctx, "DELETE FROM `c$v1$docs`")
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "dry run, not executed: DELETE FROM `c$v1$docs`", dryRun.Error())
+
+	// The underlying pool is hidden, so prepared statements cannot bypass the capture
+	assert.Nil(t, client.conn.RawConnection())
+	_, err = newCopyStatements(client.conn, "c$v1$docs", &CopyOptions{}).exec(ctx, 1, []interface{}{"id1", "doc", "[1,2,3]", "{}"})
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, copyInsertSQL("c$v1$docs", 1, &CopyOptions{}), dryRun.Statements[0].SQL)
+
+	// NewClient wraps the connection WithDryRun
+	client, err = NewClient(WithHost("localhost"), WithAutoConnect(false), WithDryRun(true))
+	require.NoError(t, err)
+	assert.IsType(t, &dryRunConnection{}, client.conn)
+	client, err = NewClient(WithHost("localhost"), WithAutoConnect(false))
+	require.NoError(t, err)
+	_, isDryRun := client.conn.(*dryRunConnection)
+	assert.False(t, isDryRun)
+}
+
+func TestErrRow(t *testing.T) {
+	want := errors.New("boom")
+	var n int
+	assert.ErrorIs(t, errRow(context.Background(), want).Scan(&n), want)
+}
@@ -0,0 +1,77 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+)
+
+// embedDocuments generates the embeddings of documents written without them, on up
+// to ClientConfig.EmbeddingConcurrency goroutines.
+func (c *Client) embedDocuments(ctx context.Context, embFunc embedding.EmbeddingFunc, documents []string) ([][]float32, error) {
+	concurrency := 1
+	if c.config != nil {
+		concurrency = c.config.EmbeddingConcurrency
+	}
+	return embedConcurrently(ctx, embFunc, documents, concurrency)
+}
+
+// embedConcurrently embeds texts with fn, split into n contiguous chunks embedded
+// concurrently, and returns the embeddings in the order of texts. The first error
+// cancels the context passed to the remaining chunks; embedding functions that do
+// not implement embedding.ContextEmbedder only see it before they start. n <= 1
+// embeds all texts in one call.
+func embedConcurrently(ctx context.Context, fn embedding.EmbeddingFunc, texts []string, n int) ([][]float32, error) {
+	if n > len(texts) {
+		n = len(texts)
+	}
+	if n <= 1 {
+		embeddings, err := embedding.EmbedWithContext(ctx, fn, texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(embeddings) != len(texts) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+		}
+		return embeddings, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg         sync.WaitGroup
+		once       sync.Once
+		firstErr   error
+		embeddings = make([][]float32, len(texts))
+		chunkSize  = (len(texts) + n - 1) / n
+	)
+	for start := 0; start < len(texts); start += chunkSize {
+		end := min(start+chunkSize, len(texts))
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			chunk, err := embedding.EmbedWithContext(ctx, fn, texts[start:end])
+			if err == nil && len(chunk) != end-start {
+				err = fmt.Errorf("expected %d embeddings, got %d", end-start, len(chunk))
+			}
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			copy(embeddings[start:end], chunk)
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,101 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbedder embeds like lengthEmbedder and counts its calls; it fails
+// chunks containing failOn and waits for cancellation in chunks containing block.
+type countingEmbedder struct {
+	calls  atomic.Int32
+	failOn string
+	block  string
+}
+
+func (e *countingEmbedder) Embed(texts []string) ([][]float32, error) {
+	return e.EmbedContext(context.Background(), texts)
+}
+
+func (e *countingEmbedder) EmbedContext(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls.Add(1)
+	for _, text := range texts {
+		switch text {
+		case e.failOn:
+			return nil, fmt.Errorf("cannot embed %q", text)
+		case e.block:
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+	}
+	return lengthEmbedder{}.Embed(texts)
+}
+
+func (e *countingEmbedder) Dimension() int { return 3 }
+
+// TestEmbedConcurrently tests that concurrent embedding keeps the document order
+func TestEmbedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee", "ffffff", "ggggggg"}
+	want, err := lengthEmbedder{}.Embed(texts)
+	require.NoError(t, err)
+
+	for _, n := range []int{0, 1, 2, 3, 7, 20} {
+		embedder := &countingEmbedder{}
+		embeddings, err := embedConcurrently(ctx, embedder, texts, n)
+		require.NoError(t, err, n)
+		assert.Equal(t, want, embeddings, n)
+		assert.EqualValues(t, max(1, min(n, len(texts))), embedder.calls.Load(), n)
+	}
+
+	// The client option sets the number of goroutines
+	client := &Client{config: &ClientConfig{}}
+	WithEmbeddingConcurrency(4)(client.config)
+	embedder := &countingEmbedder{}
+	embeddings, err := client.embedDocuments(ctx, embedder, texts)
+	require.NoError(t, err)
+	assert.Equal(t, want, embeddings)
+	assert.EqualValues(t, 4, embedder.calls.Load())
+}
+
+// TestEmbedConcurrentlyError tests that a failing chunk cancels the others
+func TestEmbedConcurrentlyError(t *testing.T) {
+	embedder := &countingEmbedder{failOn: "bad", block: "slow"}
+	embeddings, err := embedConcurrently(context.Background(), embedder, []string{"slow", "ok", "bad", "ok"}, 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot embed "bad"`)
+	assert.False(t, errors.Is(err, context.Canceled))
+	assert.Nil(t, embeddings)
+}
+
+// TestAddEmbedsConcurrently tests that Add and Upsert embed documents with the
+// configured concurrency
+func TestAddEmbedsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{EmbeddingConcurrency: 2}}
+	ids := []string{"id1", "id2", "id3", "id4"}
+	documents := []string{"a", "bb", "ccc", "dddd"}
+
+	var dryRun *DryRunError
+	embedder := &countingEmbedder{}
+	_, err := client.collectionAdd(ctx, "docs", ids, documents, &AddOptions{}, embedder)
+	require.ErrorAs(t, err, &dryRun)
+	assert.EqualValues(t, 2, embedder.calls.Load())
+	assert.Contains(t, dryRun.Statements[len(dryRun.Statements)-1].Args, "[4,0,0]")
+
+	embedder = &countingEmbedder{}
+	_, err = client.collectionUpsert(ctx, "docs", ids, documents, &AddOptions{}, embedder)
+	require.ErrorAs(t, err, &dryRun)
+	assert.EqualValues(t, 2, embedder.calls.Load())
+
+	embedder = &countingEmbedder{failOn: "ccc"}
+	_, err = client.collectionAdd(ctx, "docs", ids, documents, &AddOptions{}, embedder)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to generate embeddings")
+}
@@ -0,0 +1,236 @@
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheFileExt is the extension of cached embedding files.
+const cacheFileExt = ".emb"
+
+// CachedEmbeddingFunc wraps an EmbeddingFunc with a local filesystem cache.
+// Each embedding is stored as a little-endian float32 file under the cache
+// directory, named by the SHA256 of the input text and the embedding dimension.
+// Use a separate directory per model: the cache cannot tell apart two models
+// that share a dimension.
+type CachedEmbeddingFunc struct {
+	inner      EmbeddingFunc
+	dir        string
+	maxEntries int
+	ttl        time.Duration
+	mu         sync.Mutex
+}
+
+// CacheOption is a functional option for configuring a CachedEmbeddingFunc.
+type CacheOption func(*CachedEmbeddingFunc)
+
+// WithCacheMaxEntries caps the number of cached embeddings. When exceeded, the least
+// recently used entries are removed. Zero (the default) means unlimited.
+func WithCacheMaxEntries(maxEntries int) CacheOption {
+	return func(c *CachedEmbeddingFunc) {
+		c.maxEntries = maxEntries
+	}
+}
+
+// WithCacheTTL expires cached embeddings that have not been used for ttl.
+// Zero (the default) means entries never expire.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CachedEmbeddingFunc) {
+		c.ttl = ttl
+	}
+}
+
+// NewCachedEmbeddingFunc creates an embedding function that serves repeated texts
+// from a cache in dir and only calls inner for texts it has not seen.
+func NewCachedEmbeddingFunc(inner EmbeddingFunc, dir string, opts ...CacheOption) (*CachedEmbeddingFunc, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("inner embedding function is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &CachedEmbeddingFunc{
+		inner: inner,
+		dir:   dir,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Embed converts texts to embedding vectors, using cached embeddings where available.
+func (c *CachedEmbeddingFunc) Embed(texts []string) ([][]float32, error) {
+	return c.EmbedContext(context.Background(), texts)
+}
+
+// EmbedContext converts texts to embedding vectors, using cached embeddings where
+// available. Only cache misses are passed to the inner function, in one call.
+func (c *CachedEmbeddingFunc) EmbedContext(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	embeddings := make([][]float32, len(texts))
+	var missTexts []string
+	var missIndexes []int
+	for i, text := range texts {
+		if embedding, ok := c.load(text); ok {
+			embeddings[i] = embedding
+			continue
+		}
+		missTexts = append(missTexts, text)
+		missIndexes = append(missIndexes, i)
+	}
+
+	if len(missTexts) == 0 {
+		return embeddings, nil
+	}
+
+	missEmbeddings, err := EmbedWithContext(ctx, c.inner, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(missEmbeddings) != len(missTexts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(missTexts), len(missEmbeddings))
+	}
+
+	for j, i := range missIndexes {
+		embeddings[i] = missEmbeddings[j]
+		if err := c.store(missTexts[j], missEmbeddings[j]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.evict(); err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// Dimension returns the embedding dimension of the inner function.
+func (c *CachedEmbeddingFunc) Dimension() int {
+	return c.inner.Dimension()
+}
+
+// path returns the cache file path for text.
+func (c *CachedEmbeddingFunc) path(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	name := hex.EncodeToString(sum[:]) + "-" + strconv.Itoa(c.inner.Dimension()) + cacheFileExt
+	return filepath.Join(c.dir, name)
+}
+
+// load reads the cached embedding for text. Expired or unreadable entries are misses.
+func (c *CachedEmbeddingFunc) load(text string) ([]float32, bool) {
+	path := c.path(text)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if c.ttl > 0 && time.Since(info.ModTime()) > c.ttl {
+		os.Remove(path)
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) != 4*c.inner.Dimension() {
+		return nil, false
+	}
+
+	embedding := make([]float32, len(data)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+
+	// Record the use so eviction and TTL are based on last access
+	now := time.Now()
+	os.Chtimes(path, now, now)
+
+	return embedding, true
+}
+
+// store writes the embedding for text, via a temporary file so readers never see partial entries.
+func (c *CachedEmbeddingFunc) store(text string, embedding []float32) error {
+	data := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+	}
+
+	path := c.path(text)
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write embedding cache: %w", err)
+	}
+	return nil
+}
+
+// evict removes the least recently used entries beyond maxEntries.
+func (c *CachedEmbeddingFunc) evict() error {
+	if c.maxEntries <= 0 {
+		return nil
+	}
+
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read embedding cache: %w", err)
+	}
+
+	type cacheEntry struct {
+		path    string
+		modTime time.Time
+	}
+	var entries []cacheEntry
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), cacheFileExt) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue // Removed concurrently
+		}
+		entries = append(entries, cacheEntry{path: filepath.Join(c.dir, dirEntry.Name()), modTime: info.ModTime()})
+	}
+
+	if len(entries) <= c.maxEntries {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	for _, entry := range entries[:len(entries)-c.maxEntries] {
+		if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict embedding cache entry: %w", err)
+		}
+	}
+	return nil
+}
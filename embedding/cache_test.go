@@ -0,0 +1,110 @@
+package embedding
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbedder embeds a text as [len(text), 1, 2] and records the texts it was asked for.
+type countingEmbedder struct {
+	calls [][]string
+}
+
+func (c *countingEmbedder) Embed(texts []string) ([][]float32, error) {
+	c.calls = append(c.calls, texts)
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = []float32{float32(len(text)), 1, 2}
+	}
+	return embeddings, nil
+}
+
+func (c *countingEmbedder) Dimension() int {
+	return 3
+}
+
+func cacheEntries(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+cacheFileExt))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestCachedEmbeddingFunc(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingEmbedder{}
+	ef, err := NewCachedEmbeddingFunc(inner, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, ef.Dimension())
+
+	embeddings, err := ef.Embed([]string{"a", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 1, 2}, {2, 1, 2}}, embeddings)
+	assert.Len(t, cacheEntries(t, dir), 2)
+
+	// Only misses reach the inner function; results keep input order
+	embeddings, err = ef.Embed([]string{"ccc", "a", "dddd", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{3, 1, 2}, {1, 1, 2}, {4, 1, 2}, {2, 1, 2}}, embeddings)
+	assert.Equal(t, [][]string{{"a", "bb"}, {"ccc", "dddd"}}, inner.calls)
+
+	// The cache survives across instances
+	inner2 := &countingEmbedder{}
+	ef2, err := NewCachedEmbeddingFunc(inner2, dir)
+	require.NoError(t, err)
+	_, err = ef2.Embed([]string{"a", "ccc"})
+	require.NoError(t, err)
+	assert.Empty(t, inner2.calls)
+}
+
+func TestCachedEmbeddingFuncCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingEmbedder{}
+	ef, err := NewCachedEmbeddingFunc(inner, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(ef.path("a"), []byte{1, 2, 3}, 0o644))
+	embeddings, err := ef.Embed([]string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 1, 2}}, embeddings)
+	assert.Len(t, inner.calls, 1)
+}
+
+func TestCachedEmbeddingFuncMaxEntries(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingEmbedder{}
+	ef, err := NewCachedEmbeddingFunc(inner, dir, WithCacheMaxEntries(2))
+	require.NoError(t, err)
+
+	_, err = ef.Embed([]string{"old", "recent"})
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(ef.path("old"), past, past))
+
+	_, err = ef.Embed([]string{"new"})
+	require.NoError(t, err)
+	assert.Len(t, cacheEntries(t, dir), 2)
+	assert.NoFileExists(t, ef.path("old"))
+	assert.FileExists(t, ef.path("recent"))
+	assert.FileExists(t, ef.path("new"))
+}
+
+func TestCachedEmbeddingFuncTTL(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingEmbedder{}
+	ef, err := NewCachedEmbeddingFunc(inner, dir, WithCacheTTL(time.Minute))
+	require.NoError(t, err)
+
+	_, err = ef.Embed([]string{"a", "b"})
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(ef.path("a"), past, past))
+
+	_, err = ef.Embed([]string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"a"}}, inner.calls)
+}
@@ -0,0 +1,106 @@
+package embedding
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Kinds of checksums a downloaded file can be verified against.
+const (
+	checksumSHA256  = "sha256"   // SHA256 of the content, as published for LFS files
+	checksumGitBlob = "git-blob" // Git blob ID (SHA1 of "blob <size>\x00" + content)
+)
+
+// pinnedChecksums are the SHA256 (hex) of the default model's files (HFModelID),
+// keyed by local file name. They are checked whatever the server publishes, so a
+// mirror set with HF_ENDPOINT that sends no checksum headers cannot serve other
+// files. WithONNXChecksums takes precedence over them.
+var pinnedChecksums = map[string]string{
+	"model.onnx":     "6fd5d72fe4589f189f8ebc006442dbb529bb7ce38f8082112682524616046452",
+	"tokenizer.json": "be50c3628f2bf5bb5e3a7f17b1f74611b2561a3a27eeab05e5aa30f411572037",
+}
+
+// fileChecksum is the expected checksum of a file; an empty sum verifies nothing.
+type fileChecksum struct {
+	kind string
+	sum  string
+}
+
+// publishedChecksum returns the checksum the Hugging Face hub publishes for a
+// download. Files stored in LFS (e.g. model.onnx) are redirected to a CDN, and
+// the redirect carries their SHA256 in X-Linked-Etag; other files (e.g.
+// tokenizer.json) are served directly with their git blob ID as ETag.
+func publishedChecksum(resp *http.Response) fileChecksum {
+	for r := resp; r != nil; {
+		if sum := etagValue(r.Header.Get("X-Linked-Etag")); isHex(sum, sha256.Size) {
+			return fileChecksum{kind: checksumSHA256, sum: sum}
+		}
+		if r.Request == nil {
+			break
+		}
+		r = r.Request.Response // The redirect that led to this response, if any
+	}
+
+	redirected := resp.Request != nil && resp.Request.Response != nil
+	if sum := etagValue(resp.Header.Get("ETag")); !redirected && isHex(sum, sha1.Size) {
+		return fileChecksum{kind: checksumGitBlob, sum: sum}
+	}
+	return fileChecksum{}
+}
+
+// verify checks that the file at path matches the checksum.
+func (c fileChecksum) verify(path string) error {
+	if c.sum == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var h hash.Hash
+	switch c.kind {
+	case checksumSHA256:
+		h = sha256.New()
+	case checksumGitBlob:
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		h = sha1.New()
+		fmt.Fprintf(h, "blob %d\x00", info.Size())
+	default:
+		return fmt.Errorf("unsupported checksum kind %q", c.kind)
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, c.sum) {
+		return fmt.Errorf("checksum mismatch: expected %s %s, got %s", c.kind, c.sum, actual)
+	}
+	return nil
+}
+
+// etagValue strips the weak prefix and quotes from an ETag header value.
+func etagValue(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
+
+// isHex reports whether s is the hex encoding of n bytes.
+func isHex(s string, n int) bool {
+	if len(s) != 2*n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,163 @@
+package embedding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testModelContent = "model bytes"
+
+// testModelSHA256 is the SHA256 of testModelContent
+var testModelSHA256 = func() string {
+	sum := sha256.Sum256([]byte(testModelContent))
+	return hex.EncodeToString(sum[:])
+}()
+
+// testModelBlobID is the git blob ID of testModelContent (git hash-object)
+const testModelBlobID = "eb040d69ed30446320c20353f8c879c69a28bb62"
+
+// newModelServer serves testModelContent, with /lfs redirecting like the Hugging
+// Face hub does for LFS files and /blob served directly with its git blob ID.
+func newModelServer(t *testing.T, linkedEtag, etag string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lfs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Linked-Etag", `"`+linkedEtag+`"`)
+		http.Redirect(w, r, "/cdn", http.StatusFound)
+	})
+	mux.HandleFunc("/cdn", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testModelContent))
+	})
+	mux.HandleFunc("/blob", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `W/"`+etag+`"`)
+		w.Write([]byte(testModelContent))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadFileVerifiesChecksums(t *testing.T) {
+	server := newModelServer(t, testModelSHA256, testModelBlobID)
+	dir := t.TempDir()
+
+	for _, path := range []string{"/lfs", "/blob"} {
+		dest := filepath.Join(dir, "ok"+filepath.Base(path))
+		require.NoError(t, downloadFile(server.Client(), server.URL+path, dest, "", NopLogger{}), path)
+		data, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		assert.Equal(t, testModelContent, string(data))
+	}
+
+	// A pinned checksum takes precedence over the published one
+	dest := filepath.Join(dir, "pinned")
+	require.NoError(t, downloadFile(server.Client(), server.URL+"/blob", dest, testModelSHA256, NopLogger{}))
+	err := downloadFile(server.Client(), server.URL+"/lfs", filepath.Join(dir, "wrong"), "00"+testModelSHA256[2:], NopLogger{})
+	assert.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, filepath.Join(dir, "wrong"))
+}
+
+func TestDownloadFileRejectsCorruptDownloads(t *testing.T) {
+	badSHA256 := "00" + testModelSHA256[2:]
+	badBlobID := "00" + testModelBlobID[2:]
+	server := newModelServer(t, badSHA256, badBlobID)
+	dir := t.TempDir()
+
+	for _, path := range []string{"/lfs", "/blob"} {
+		dest := filepath.Join(dir, "model"+filepath.Base(path))
+		err := downloadFile(server.Client(), server.URL+path, dest, "", NopLogger{})
+		assert.ErrorContains(t, err, "checksum mismatch", path)
+		assert.NoFileExists(t, dest)
+	}
+
+	// Neither the partial file nor temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDownloadFileRejectsTruncatedDownloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte(testModelContent))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	err := downloadFile(server.Client(), server.URL, dest, "", NopLogger{})
+	assert.Error(t, err)
+	assert.NoFileExists(t, dest)
+}
+
+func TestDownloadFileWarnsWithoutChecksum(t *testing.T) {
+	// A mirror that publishes no checksum
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testModelContent))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	logger := &recordingLogger{}
+	require.NoError(t, downloadFile(server.Client(), server.URL+"/vocab.txt", filepath.Join(dir, "vocab.txt"), "", logger))
+	assert.Equal(t, []string{"No checksum available for " + server.URL + "/vocab.txt, the download is not verified"}, logger.warnings)
+
+	logger.warnings = nil
+	require.NoError(t, downloadFile(server.Client(), server.URL+"/model.onnx", filepath.Join(dir, "model.onnx"), testModelSHA256, logger))
+	assert.Empty(t, logger.warnings)
+}
+
+func TestDownloadModelVerifiesPinnedChecksums(t *testing.T) {
+	// A mirror that publishes no checksum and serves other files than the pinned ones
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testModelContent))
+	}))
+	defer server.Close()
+	t.Setenv("HF_ENDPOINT", server.URL)
+
+	for _, file := range []string{"model.onnx", "tokenizer.json"} {
+		assert.True(t, isHex(pinnedChecksums[file], sha256.Size), file)
+	}
+
+	ef := &ONNXEmbeddingFunction{httpClient: server.Client(), logger: NopLogger{}}
+	modelDir := t.TempDir()
+	err := ef.downloadModelIfNeeded(modelDir)
+	assert.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, filepath.Join(modelDir, "model.onnx"))
+	assert.NoFileExists(t, filepath.Join(modelDir, "tokenizer.json"))
+}
+
+// TestPinnedChecksumsMatchHub downloads the default model's files from the Hugging
+// Face hub (or HF_ENDPOINT) and checks them against pinnedChecksums, so a stale pin
+// is caught when the model is updated upstream. It needs network access.
+func TestPinnedChecksumsMatchHub(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping model download in short mode")
+	}
+	endpoint := os.Getenv("HF_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://huggingface.co"
+	}
+	client := &http.Client{Timeout: DefaultModelDownloadTimeout}
+	probe := &http.Client{Timeout: 10 * time.Second}
+	resp, err := probe.Head(endpoint)
+	if err != nil {
+		t.Skipf("%s is unreachable: %v", endpoint, err)
+	}
+	resp.Body.Close()
+
+	dir := t.TempDir()
+	for hfPath, file := range map[string]string{"onnx/model.onnx": "model.onnx", "tokenizer.json": "tokenizer.json"} {
+		url := fmt.Sprintf("%s/%s/resolve/main/%s", endpoint, HFModelID, hfPath)
+		err := downloadFile(client, url, filepath.Join(dir, file), pinnedChecksums[file], NopLogger{})
+		require.NoError(t, err, file)
+	}
+}
@@ -1,10 +1,23 @@
 package embedding
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 )
 
+// DefaultHTTPTimeout bounds each request made by network-backed embedding
+// functions that were not given their own client.
+const DefaultHTTPTimeout = 60 * time.Second
+
+// newDefaultHTTPClient returns the client used when none is injected. Unlike
+// http.DefaultClient it has a timeout, so a stalled server cannot hang an embed forever.
+func newDefaultHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
+
 // EmbeddingFunc is a function that converts text to embeddings.
 // It takes either a single string or a slice of strings and returns embeddings.
 type EmbeddingFunc interface {
@@ -18,6 +31,27 @@ type EmbeddingFunc interface {
 	Dimension() int
 }
 
+// ContextEmbedder is an optional interface for embedding functions that can honor
+// the caller's context. Network-backed embedders should implement it so that a
+// cancelled or timed-out operation also aborts the in-flight HTTP request.
+type ContextEmbedder interface {
+	// EmbedContext converts text to embedding vectors, stopping early if ctx is done.
+	EmbedContext(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedWithContext embeds texts using fn, passing ctx through when fn implements
+// ContextEmbedder. Other embedding functions are called via Embed after checking
+// that ctx has not already been cancelled.
+func EmbedWithContext(ctx context.Context, fn EmbeddingFunc, texts []string) ([][]float32, error) {
+	if ce, ok := fn.(ContextEmbedder); ok {
+		return ce.EmbedContext(ctx, texts)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fn.Embed(texts)
+}
+
 var (
 	defaultEmbeddingFunc EmbeddingFunc
 	defaultOnce          sync.Once
@@ -0,0 +1,106 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// httpTestEmbedder is a minimal network-backed embedder used to exercise
+// context propagation without depending on a real embedding service.
+type httpTestEmbedder struct {
+	url    string
+	client *http.Client
+}
+
+func (h *httpTestEmbedder) Embed(texts []string) ([][]float32, error) {
+	return h.EmbedContext(context.Background(), texts)
+}
+
+func (h *httpTestEmbedder) EmbedContext(ctx context.Context, texts []string) ([][]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var embeddings [][]float32
+	if err := json.NewDecoder(resp.Body).Decode(&embeddings); err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+func (h *httpTestEmbedder) Dimension() int {
+	return 3
+}
+
+// staticEmbedder implements only EmbeddingFunc.
+type staticEmbedder struct{}
+
+func (staticEmbedder) Embed(texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i := range texts {
+		embeddings[i] = []float32{1, 2, 3}
+	}
+	return embeddings, nil
+}
+
+func (staticEmbedder) Dimension() int {
+	return 3
+}
+
+func TestEmbedWithContextCancelsHTTPCall(t *testing.T) {
+	aborted := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(5 * time.Second):
+			w.Write([]byte("[[1,2,3]]"))
+		}
+	}))
+	defer server.Close()
+
+	ef := &httpTestEmbedder{url: server.URL, client: server.Client()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := EmbedWithContext(ctx, ef, []string{"hello"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	select {
+	case <-aborted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not observe the aborted request")
+	}
+}
+
+func TestEmbedWithContextFallsBackToEmbed(t *testing.T) {
+	embeddings, err := EmbedWithContext(context.Background(), staticEmbedder{}, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = EmbedWithContext(ctx, staticEmbedder{}, []string{"a"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
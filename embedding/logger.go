@@ -0,0 +1,19 @@
+package embedding
+
+// Logger receives diagnostic messages from embedding functions, such as model
+// download progress. It has the same methods as goseekdb.Logger, so one
+// implementation can be passed to both packages.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// NopLogger discards all messages. It is the default logger.
+type NopLogger struct{}
+
+func (NopLogger) Debugf(format string, args ...interface{}) {}
+func (NopLogger) Infof(format string, args ...interface{})  {}
+func (NopLogger) Warnf(format string, args ...interface{})  {}
+func (NopLogger) Errorf(format string, args ...interface{}) {}
@@ -1,13 +1,17 @@
 package embedding
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/sugarme/tokenizer"
 	"github.com/sugarme/tokenizer/pretrained"
@@ -23,20 +27,112 @@ const (
 	Dimension = 384
 	// MaxTokens is the maximum sequence length
 	MaxTokens = 256
+	// DefaultModelDownloadTimeout bounds each model file download
+	DefaultModelDownloadTimeout = 10 * time.Minute
+
+	// OutputLastHiddenState selects mean pooling over the token embeddings (default)
+	OutputLastHiddenState = "last_hidden_state"
+	// OutputPooler selects the model's pooler_output node (CLS token + dense + tanh)
+	OutputPooler = "pooler_output"
+)
+
+// PoolingStrategy selects how token embeddings from last_hidden_state are combined
+// into one embedding per text.
+type PoolingStrategy string
+
+const (
+	// PoolingMean averages the embeddings of all non-padding tokens (default)
+	PoolingMean PoolingStrategy = "mean"
+	// PoolingCLS uses the embedding of the first ([CLS]) token
+	PoolingCLS PoolingStrategy = "cls"
+	// PoolingMax takes the element-wise maximum over all non-padding tokens
+	PoolingMax PoolingStrategy = "max"
 )
 
 // ONNXEmbeddingFunction implements EmbeddingFunc using ONNX Runtime.
 type ONNXEmbeddingFunction struct {
-	modelPath string
-	tokenizer *tokenizer.Tokenizer
-	mu        sync.Mutex
-	once      sync.Once
-	initErr   error
+	modelPath  string
+	dimension  int               // Size of the embeddings the model produces
+	maxTokens  int               // Sequences are truncated to this many tokens
+	output     string            // Model output the embeddings are read from
+	pooling    PoolingStrategy   // How last_hidden_state is pooled
+	normalize  bool              // L2-normalize embeddings after pooling
+	httpClient *http.Client      // Used to download the model
+	logger     Logger            // Receives model download progress
+	checksums  map[string]string // Expected SHA256 of model files, by file name
+	tokenizer  *tokenizer.Tokenizer
+	session    *ort.DynamicAdvancedSession // Created once, reused across Embed calls
+	closed     bool
+	mu         sync.Mutex
+	once       sync.Once
+	initErr    error
+}
+
+// ONNXOption is a functional option for configuring an ONNXEmbeddingFunction.
+type ONNXOption func(*ONNXEmbeddingFunction)
+
+// WithONNXOutput selects the model output embeddings are computed from:
+// OutputLastHiddenState (mean pooled, the default) or OutputPooler for models
+// trained to use pooler_output. Reading the wrong output silently degrades
+// embedding quality, so the choice is checked against the model on first use.
+func WithONNXOutput(output string) ONNXOption {
+	return func(e *ONNXEmbeddingFunction) {
+		e.output = output
+	}
+}
+
+// WithONNXPooling sets how token embeddings are pooled when reading
+// OutputLastHiddenState: PoolingMean (the default), PoolingCLS or PoolingMax. It
+// must match the pooling the model was used with elsewhere for embeddings to be
+// comparable. It has no effect with OutputPooler, which is pooled by the model.
+func WithONNXPooling(pooling PoolingStrategy) ONNXOption {
+	return func(e *ONNXEmbeddingFunction) {
+		e.pooling = pooling
+	}
+}
+
+// WithONNXNormalize enables L2 normalization of embeddings after pooling, as done
+// by sentence-transformers models with a Normalize module. Disabled by default.
+func WithONNXNormalize(normalize bool) ONNXOption {
+	return func(e *ONNXEmbeddingFunction) {
+		e.normalize = normalize
+	}
+}
+
+// WithONNXHTTPClient sets the HTTP client used to download the model, e.g. to go
+// through a proxy. By default a client with DefaultModelDownloadTimeout is used.
+func WithONNXHTTPClient(client *http.Client) ONNXOption {
+	return func(e *ONNXEmbeddingFunction) {
+		if client != nil {
+			e.httpClient = client
+		}
+	}
+}
+
+// WithONNXLogger sets the logger receiving model download progress. By default
+// nothing is logged.
+func WithONNXLogger(logger Logger) ONNXOption {
+	return func(e *ONNXEmbeddingFunction) {
+		if logger != nil {
+			e.logger = logger
+		}
+	}
+}
+
+// WithONNXChecksums pins the expected SHA256 (hex) of downloaded model files, keyed
+// by file name ("model.onnx", "tokenizer.json"), replacing the built-in checksums
+// of the default model. A download not matching its checksum fails and is
+// discarded. Files without a pinned checksum are verified against the checksum
+// published by the server; if there is none, a warning is logged.
+func WithONNXChecksums(checksums map[string]string) ONNXOption {
+	return func(e *ONNXEmbeddingFunction) {
+		e.checksums = checksums
+	}
 }
 
 // NewONNXEmbeddingFunction creates a new ONNX-based embedding function.
 // It automatically downloads the model if not cached.
-func NewONNXEmbeddingFunction() (*ONNXEmbeddingFunction, error) {
+func NewONNXEmbeddingFunction(opts ...ONNXOption) (*ONNXEmbeddingFunction, error) {
 	// Get cache directory
 	cacheDir, err := getCacheDir()
 	if err != nil {
@@ -46,7 +142,19 @@ func NewONNXEmbeddingFunction() (*ONNXEmbeddingFunction, error) {
 	modelDir := filepath.Join(cacheDir, "onnx_models", ModelName, "onnx")
 
 	ef := &ONNXEmbeddingFunction{
-		modelPath: filepath.Join(modelDir, "model.onnx"),
+		modelPath:  filepath.Join(modelDir, "model.onnx"),
+		dimension:  Dimension,
+		maxTokens:  MaxTokens,
+		output:     OutputLastHiddenState,
+		pooling:    PoolingMean,
+		httpClient: newDefaultHTTPClient(DefaultModelDownloadTimeout),
+		logger:     NopLogger{},
+	}
+	for _, opt := range opts {
+		opt(ef)
+	}
+	if err := ef.validateOptions(); err != nil {
+		return nil, err
 	}
 
 	// Download model if needed
@@ -60,6 +168,56 @@ func NewONNXEmbeddingFunction() (*ONNXEmbeddingFunction, error) {
 	return ef, nil
 }
 
+// NewONNXEmbeddingFunctionFromPath creates an ONNX-based embedding function from a
+// local model directory containing model.onnx and tokenizer.json, e.g. for a
+// different sentence-transformers model or an air-gapped host. Nothing is
+// downloaded. dim is the size of the embeddings the model produces and maxTokens
+// the length sequences are truncated to.
+func NewONNXEmbeddingFunctionFromPath(modelDir string, dim, maxTokens int, opts ...ONNXOption) (*ONNXEmbeddingFunction, error) {
+	if dim <= 0 {
+		return nil, fmt.Errorf("embedding dimension must be positive, got %d", dim)
+	}
+	if maxTokens <= 0 {
+		return nil, fmt.Errorf("max tokens must be positive, got %d", maxTokens)
+	}
+	for _, file := range []string{"model.onnx", "tokenizer.json"} {
+		if _, err := os.Stat(filepath.Join(modelDir, file)); err != nil {
+			return nil, fmt.Errorf("failed to find model file: %w", err)
+		}
+	}
+
+	ef := &ONNXEmbeddingFunction{
+		modelPath:  filepath.Join(modelDir, "model.onnx"),
+		dimension:  dim,
+		maxTokens:  maxTokens,
+		output:     OutputLastHiddenState,
+		pooling:    PoolingMean,
+		httpClient: newDefaultHTTPClient(DefaultModelDownloadTimeout),
+		logger:     NopLogger{},
+	}
+	for _, opt := range opts {
+		opt(ef)
+	}
+	if err := ef.validateOptions(); err != nil {
+		return nil, err
+	}
+
+	return ef, nil
+}
+
+// validateOptions checks the options that can be validated without loading the model.
+func (e *ONNXEmbeddingFunction) validateOptions() error {
+	if e.output != OutputLastHiddenState && e.output != OutputPooler {
+		return fmt.Errorf("unsupported ONNX output %q: use %q or %q", e.output, OutputLastHiddenState, OutputPooler)
+	}
+	switch e.pooling {
+	case PoolingMean, PoolingCLS, PoolingMax:
+	default:
+		return fmt.Errorf("unsupported pooling strategy %q: use %q, %q or %q", e.pooling, PoolingMean, PoolingCLS, PoolingMax)
+	}
+	return nil
+}
+
 // getCacheDir returns the cache directory path
 func getCacheDir() (string, error) {
 	home, err := os.UserHomeDir()
@@ -101,7 +259,7 @@ func (e *ONNXEmbeddingFunction) downloadModelIfNeeded(modelDir string) error {
 		hfEndpoint = "https://hf-mirror.com"
 	}
 
-	fmt.Printf("Downloading model from %s...\n", hfEndpoint)
+	e.logger.Infof("Downloading model %s from %s", HFModelID, hfEndpoint)
 
 	// Files to download (HF path -> local filename)
 	filesToDownload := map[string]string{
@@ -119,19 +277,27 @@ func (e *ONNXEmbeddingFunction) downloadModelIfNeeded(modelDir string) error {
 
 		url := fmt.Sprintf("%s/%s/resolve/main/%s", hfEndpoint, HFModelID, hfPath)
 
-		fmt.Printf("Downloading %s...\n", localFile)
-		if err := downloadFile(url, localPath); err != nil {
+		e.logger.Debugf("Downloading %s to %s", url, localPath)
+		expectedSHA256 := e.checksums[localFile]
+		if expectedSHA256 == "" {
+			expectedSHA256 = pinnedChecksums[localFile]
+		}
+		if err := downloadFile(e.httpClient, url, localPath, expectedSHA256, e.logger); err != nil {
 			return fmt.Errorf("failed to download %s: %w", localFile, err)
 		}
 	}
 
-	fmt.Println("Model downloaded successfully!")
+	e.logger.Infof("Model %s downloaded to %s", HFModelID, modelDir)
 	return nil
 }
 
-// downloadFile downloads a file from URL to the destination path
-func downloadFile(url, dest string) error {
-	resp, err := http.Get(url)
+// downloadFile downloads a file from URL to the destination path. The file is
+// written to a temporary file next to dest and only renamed to dest once it is
+// complete and matches its checksum: expectedSHA256 if set, otherwise the one the
+// server published (see publishedChecksum). A download without either is kept
+// unverified and logged as a warning. On failure nothing is left at dest.
+func downloadFile(client *http.Client, url, dest, expectedSHA256 string, logger Logger) error {
+	resp, err := client.Get(url)
 	if err != nil {
 		return err
 	}
@@ -141,14 +307,35 @@ func downloadFile(url, dest string) error {
 		return fmt.Errorf("bad status: %s", resp.Status)
 	}
 
-	out, err := os.Create(dest)
+	tmp, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after the rename
+
+	written, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		return err
 	}
-	defer out.Close()
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return fmt.Errorf("incomplete download: got %d of %d bytes", written, resp.ContentLength)
+	}
+
+	checksum := fileChecksum{kind: checksumSHA256, sum: expectedSHA256}
+	if expectedSHA256 == "" {
+		checksum = publishedChecksum(resp)
+	}
+	if checksum.sum == "" {
+		logger.Warnf("No checksum available for %s, the download is not verified", url)
+	}
+	if err := checksum.verify(tmp.Name()); err != nil {
+		return err
+	}
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	return os.Rename(tmp.Name(), dest)
 }
 
 var (
@@ -229,7 +416,7 @@ func getOnnxLibraryPath() (string, error) {
 	return libPath, nil
 }
 
-// initORT initializes the ONNX session (called once)
+// initORT initializes the tokenizer and ONNX session (called once)
 func (e *ONNXEmbeddingFunction) initORT() error {
 	// Initialize ONNX Runtime globally (once for entire process)
 	ortInitOnce.Do(func() {
@@ -255,15 +442,17 @@ func (e *ONNXEmbeddingFunction) initORT() error {
 			return
 		}
 
-		// Configure truncation and padding to match Python implementation (max_length=256)
-		// This ensures consistent embedding dimensions across Python and Go
+		// Configure truncation to match Python implementation (max_length=256).
+		// Padding is to the longest sequence of a batch rather than a fixed
+		// maxTokens: padded positions are masked out of pooling, so this gives the
+		// same embeddings while short texts no longer pay for 256 tokens.
 		tk.WithTruncation(&tokenizer.TruncationParams{
-			MaxLength: MaxTokens,
+			MaxLength: e.maxTokens,
 			Strategy:  tokenizer.LongestFirst,
 			Stride:    0,
 		})
 		tk.WithPadding(&tokenizer.PaddingParams{
-			Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithFixed(MaxTokens)),
+			Strategy:  *tokenizer.NewPaddingStrategy(tokenizer.WithBatchLongest()),
 			Direction: tokenizer.Right,
 			PadId:     0,
 			PadTypeId: 0,
@@ -271,6 +460,35 @@ func (e *ONNXEmbeddingFunction) initORT() error {
 		})
 
 		e.tokenizer = tk
+
+		// Make sure the model actually exposes the configured output
+		_, outputs, err := ort.GetInputOutputInfo(e.modelPath)
+		if err != nil {
+			e.initErr = fmt.Errorf("failed to inspect ONNX model: %w", err)
+			return
+		}
+		outputNames := make([]string, len(outputs))
+		for i, output := range outputs {
+			outputNames[i] = output.Name
+		}
+		if err := validateONNXOutput(outputNames, e.output); err != nil {
+			e.initErr = err
+			return
+		}
+
+		// Loading the model dominates the cost of a session, so create it once.
+		// A dynamic session takes tensors per Run, so any batch size can reuse it.
+		session, err := ort.NewDynamicAdvancedSession(
+			e.modelPath,
+			[]string{"input_ids", "attention_mask", "token_type_ids"},
+			[]string{e.output},
+			nil,
+		)
+		if err != nil {
+			e.initErr = fmt.Errorf("failed to create ONNX session: %w", err)
+			return
+		}
+		e.session = session
 	})
 
 	return e.initErr
@@ -282,14 +500,30 @@ const DefaultBatchSize = 32
 // Embed converts texts to embedding vectors.
 // It processes texts in batches of DefaultBatchSize (250) for efficiency.
 func (e *ONNXEmbeddingFunction) Embed(texts []string) ([][]float32, error) {
-	return e.EmbedWithBatchSize(texts, DefaultBatchSize)
+	return e.EmbedWithBatchSizeContext(context.Background(), texts, DefaultBatchSize)
+}
+
+// EmbedContext converts texts to embedding vectors like Embed, stopping between
+// batches once ctx is done. A batch already running on the model is not interrupted.
+func (e *ONNXEmbeddingFunction) EmbedContext(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.EmbedWithBatchSizeContext(ctx, texts, DefaultBatchSize)
 }
 
 // EmbedWithBatchSize converts texts to embedding vectors with a custom batch size.
 func (e *ONNXEmbeddingFunction) EmbedWithBatchSize(texts []string, batchSize int) ([][]float32, error) {
+	return e.EmbedWithBatchSizeContext(context.Background(), texts, batchSize)
+}
+
+// EmbedWithBatchSizeContext converts texts to embedding vectors with a custom batch
+// size, checking ctx before each batch. Smaller batches make cancellation take
+// effect sooner.
+func (e *ONNXEmbeddingFunction) EmbedWithBatchSizeContext(ctx context.Context, texts []string, batchSize int) ([][]float32, error) {
 	if len(texts) == 0 {
 		return [][]float32{}, nil
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	if batchSize <= 0 {
 		batchSize = DefaultBatchSize
@@ -298,6 +532,10 @@ func (e *ONNXEmbeddingFunction) EmbedWithBatchSize(texts []string, batchSize int
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.closed {
+		return nil, fmt.Errorf("ONNX embedding function is closed")
+	}
+
 	// Initialize ONNX runtime on the first Embed call
 	if err := e.initORT(); err != nil {
 		return nil, err
@@ -307,6 +545,11 @@ func (e *ONNXEmbeddingFunction) EmbedWithBatchSize(texts []string, batchSize int
 	allEmbeddings := make([][]float32, 0, len(texts))
 
 	for i := 0; i < len(texts); i += batchSize {
+		// Waiting for the lock or a previous batch may have outlived the deadline
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("embedding stopped before batch starting at index %d: %w", i, err)
+		}
+
 		end := i + batchSize
 		if end > len(texts) {
 			end = len(texts)
@@ -336,31 +579,10 @@ func (e *ONNXEmbeddingFunction) embedBatch(texts []string) ([][]float32, error)
 		encodings[i] = enc
 	}
 
-	// Prepare input data - use fixed MaxTokens (256) to match Python implementation
+	// Prepare input data padded to the longest sequence in the batch
 	batchLen := int64(len(texts))
-	seqLength := int64(MaxTokens)
-
-	// ONNX runtime Go bindings require flat 1D slices.
-	// A 2D Go slice is a slice of pointers to separate allocations - non-contiguous memory.
-	// ONNX runtime expects a single contiguous block of memory.
-	inputIDs := make([]int64, batchLen*seqLength)
-	attentionMask := make([]int64, batchLen*seqLength)
-	tokenTypeIDs := make([]int64, batchLen*seqLength)
-
-	for i, enc := range encodings {
-		ids := enc.GetIds()
-		mask := enc.GetAttentionMask()
-		typeIds := enc.GetTypeIds()
-
-		// Copy tokens - tokenizer already handles truncation/padding to MaxTokens
-		for j := 0; j < MaxTokens && j < len(ids); j++ {
-			offset := i*MaxTokens + j
-			inputIDs[offset] = int64(ids[j])
-			attentionMask[offset] = int64(mask[j])
-			tokenTypeIDs[offset] = int64(typeIds[j])
-		}
-		// Padding is already zeros from make()
-	}
+	inputIDs, attentionMask, tokenTypeIDs, seqLen := buildInputTensorsData(encodings, e.maxTokens)
+	seqLength := int64(seqLen)
 
 	// Create input tensors
 	inputShape := ort.NewShape(batchLen, seqLength)
@@ -383,40 +605,106 @@ func (e *ONNXEmbeddingFunction) embedBatch(texts []string) ([][]float32, error)
 	}
 	defer tokenTypeIDsTensor.Destroy()
 
-	// Create output tensor
-	outputShape := ort.NewShape(batchLen, seqLength, int64(Dimension))
+	// Create output tensor: pooler_output has one vector per text,
+	// last_hidden_state one per token
+	outputShape := ort.NewShape(batchLen, seqLength, int64(e.dimension))
+	if e.output == OutputPooler {
+		outputShape = ort.NewShape(batchLen, int64(e.dimension))
+	}
 	outputTensor, err := ort.NewEmptyTensor[float32](outputShape)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create output tensor: %w", err)
 	}
 	defer outputTensor.Destroy()
 
-	// Create session with pre-allocated tensors
-	session, err := ort.NewAdvancedSession(
-		e.modelPath,
-		[]string{"input_ids", "attention_mask", "token_type_ids"},
-		[]string{"last_hidden_state"},
+	// Run inference on the cached session
+	if err := e.session.Run(
 		[]ort.Value{inputIDsTensor, attentionMaskTensor, tokenTypeIDsTensor},
 		[]ort.Value{outputTensor},
-		nil,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create ONNX session: %w", err)
+	); err != nil {
+		return nil, fmt.Errorf("failed to run inference: %w", err)
 	}
-	defer session.Destroy()
 
-	// Run inference
-	if err := session.Run(); err != nil {
-		return nil, fmt.Errorf("failed to run inference: %w", err)
+	embeddings := poolOutput(e.output, e.pooling, outputTensor.GetData(), attentionMask, int(batchLen), int(seqLength), e.dimension)
+	if e.normalize {
+		for _, embedding := range embeddings {
+			l2Normalize(embedding)
+		}
 	}
+	return embeddings, nil
+}
 
-	// Get output data
-	lastHiddenState := outputTensor.GetData()
+// validateONNXOutput checks that the configured output is one the model exposes.
+func validateONNXOutput(modelOutputs []string, output string) error {
+	for _, name := range modelOutputs {
+		if name == output {
+			return nil
+		}
+	}
+	return fmt.Errorf("ONNX model has no %q output (available: %s)", output, strings.Join(modelOutputs, ", "))
+}
 
-	// Apply mean pooling with L2 normalization
-	embeddings := meanPooling(lastHiddenState, attentionMask, int(batchLen), int(seqLength), Dimension)
+// poolOutput turns the raw data of the selected model output into one embedding per
+// text, pooling last_hidden_state with the given strategy.
+func poolOutput(output string, pooling PoolingStrategy, data []float32, attentionMask []int64, batchSize, seqLength, hiddenSize int) [][]float32 {
+	if output == OutputPooler {
+		// Already pooled by the model: one row of hiddenSize values per text
+		embeddings := make([][]float32, batchSize)
+		for i := range embeddings {
+			embeddings[i] = append([]float32(nil), data[i*hiddenSize:(i+1)*hiddenSize]...)
+		}
+		return embeddings
+	}
 
-	return embeddings, nil
+	switch pooling {
+	case PoolingCLS:
+		return clsPooling(data, batchSize, seqLength, hiddenSize)
+	case PoolingMax:
+		return maxPooling(data, attentionMask, batchSize, seqLength, hiddenSize)
+	}
+	// Apply mean pooling over last_hidden_state
+	return meanPooling(data, attentionMask, batchSize, seqLength, hiddenSize)
+}
+
+// buildInputTensorsData flattens a batch of encodings into input_ids, attention_mask
+// and token_type_ids, padding every sequence to the longest one in the batch
+// (at most maxTokens). Padded positions get a zero attention mask.
+//
+// ONNX runtime Go bindings require flat 1D slices.
+// A 2D Go slice is a slice of pointers to separate allocations - non-contiguous memory.
+// ONNX runtime expects a single contiguous block of memory.
+func buildInputTensorsData(encodings []*tokenizer.Encoding, maxTokens int) (inputIDs, attentionMask, tokenTypeIDs []int64, seqLength int) {
+	for _, enc := range encodings {
+		if n := len(enc.GetIds()); n > seqLength {
+			seqLength = n
+		}
+	}
+	if seqLength > maxTokens {
+		seqLength = maxTokens
+	}
+	if seqLength == 0 {
+		seqLength = 1 // ONNX rejects zero-length dimensions
+	}
+
+	inputIDs = make([]int64, len(encodings)*seqLength)
+	attentionMask = make([]int64, len(encodings)*seqLength)
+	tokenTypeIDs = make([]int64, len(encodings)*seqLength)
+
+	for i, enc := range encodings {
+		ids := enc.GetIds()
+		mask := enc.GetAttentionMask()
+		typeIds := enc.GetTypeIds()
+
+		for j := 0; j < seqLength && j < len(ids); j++ {
+			offset := i*seqLength + j
+			inputIDs[offset] = int64(ids[j])
+			attentionMask[offset] = int64(mask[j])
+			tokenTypeIDs[offset] = int64(typeIds[j])
+		}
+		// Padding is already zeros from make()
+	}
+
+	return inputIDs, attentionMask, tokenTypeIDs, seqLength
 }
 
 // meanPooling applies mean pooling over token embeddings (matching Python implementation).
@@ -452,14 +740,77 @@ func meanPooling(lastHiddenState []float32, attentionMask []int64, batchSize, se
 	return embeddings
 }
 
+// clsPooling takes the embedding of the first token of each sequence, which is
+// [CLS] for BERT-style tokenizers.
+func clsPooling(lastHiddenState []float32, batchSize, seqLength, hiddenSize int) [][]float32 {
+	embeddings := make([][]float32, batchSize)
+	for i := range embeddings {
+		start := i * seqLength * hiddenSize
+		embeddings[i] = append([]float32(nil), lastHiddenState[start:start+hiddenSize]...)
+	}
+	return embeddings
+}
+
+// maxPooling takes the element-wise maximum over the non-masked tokens of each sequence.
+func maxPooling(lastHiddenState []float32, attentionMask []int64, batchSize, seqLength, hiddenSize int) [][]float32 {
+	embeddings := make([][]float32, batchSize)
+
+	for i := 0; i < batchSize; i++ {
+		embedding := make([]float32, hiddenSize)
+		seen := false
+
+		for j := 0; j < seqLength; j++ {
+			if attentionMask[i*seqLength+j] == 0 {
+				continue
+			}
+			for k := 0; k < hiddenSize; k++ {
+				value := lastHiddenState[i*seqLength*hiddenSize+j*hiddenSize+k]
+				if !seen || value > embedding[k] {
+					embedding[k] = value
+				}
+			}
+			seen = true
+		}
+
+		embeddings[i] = embedding
+	}
+
+	return embeddings
+}
+
+// l2Normalize scales embedding in place to unit length. Zero vectors are left as is.
+func l2Normalize(embedding []float32) {
+	var sum float64
+	for _, v := range embedding {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for k := range embedding {
+		embedding[k] /= norm
+	}
+}
+
 // Dimension returns the embedding dimension
 func (e *ONNXEmbeddingFunction) Dimension() int {
-	return Dimension
+	return e.dimension
 }
 
-// Close cleans up resources
+// Close releases the cached ONNX session. The embedding function cannot be used afterwards.
 func (e *ONNXEmbeddingFunction) Close() error {
-	// Session is created and destroyed per inference call
-	// No cleanup needed
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.closed = true
+	if e.session == nil {
+		return nil
+	}
+	err := e.session.Destroy()
+	e.session = nil
+	if err != nil {
+		return fmt.Errorf("failed to destroy ONNX session: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,201 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sugarme/tokenizer"
+)
+
+func TestBuildInputTensorsDataPadsToBatchMax(t *testing.T) {
+	encodings := []*tokenizer.Encoding{
+		{Ids: []int{101, 7, 102}, TypeIds: []int{0, 0, 0}, AttentionMask: []int{1, 1, 1}},
+		{Ids: []int{101, 8, 9, 10, 102}, TypeIds: []int{0, 0, 0, 0, 0}, AttentionMask: []int{1, 1, 1, 1, 1}},
+	}
+
+	inputIDs, attentionMask, tokenTypeIDs, seqLength := buildInputTensorsData(encodings, MaxTokens)
+	assert.Equal(t, 5, seqLength)
+	assert.Equal(t, []int64{101, 7, 102, 0, 0, 101, 8, 9, 10, 102}, inputIDs)
+	assert.Equal(t, []int64{1, 1, 1, 0, 0, 1, 1, 1, 1, 1}, attentionMask)
+	assert.Len(t, tokenTypeIDs, 10)
+
+	// Sequences are capped at maxTokens
+	long := make([]int, MaxTokens+10)
+	_, _, _, seqLength = buildInputTensorsData([]*tokenizer.Encoding{{Ids: long, TypeIds: long, AttentionMask: long}}, MaxTokens)
+	assert.Equal(t, MaxTokens, seqLength)
+	_, _, _, seqLength = buildInputTensorsData([]*tokenizer.Encoding{{Ids: long, TypeIds: long, AttentionMask: long}}, 128)
+	assert.Equal(t, 128, seqLength)
+}
+
+func TestMeanPoolingIgnoresPadding(t *testing.T) {
+	// One sequence of 2 real tokens with hidden size 2
+	hidden := []float32{1, 2, 3, 4}
+	unpadded := meanPooling(hidden, []int64{1, 1}, 1, 2, 2)
+
+	// The same sequence padded to 4 tokens, with arbitrary hidden states for padding
+	paddedHidden := []float32{1, 2, 3, 4, 100, 100, -50, 7}
+	padded := meanPooling(paddedHidden, []int64{1, 1, 0, 0}, 1, 4, 2)
+
+	assert.Equal(t, [][]float32{{2, 3}}, unpadded)
+	assert.Equal(t, unpadded, padded)
+}
+
+func TestValidateONNXOutput(t *testing.T) {
+	outputs := []string{OutputLastHiddenState, OutputPooler}
+	assert.NoError(t, validateONNXOutput(outputs, OutputPooler))
+	assert.NoError(t, validateONNXOutput(outputs, OutputLastHiddenState))
+
+	err := validateONNXOutput([]string{OutputLastHiddenState}, OutputPooler)
+	assert.ErrorContains(t, err, "pooler_output")
+}
+
+func TestPoolOutputReadsPoolerOutput(t *testing.T) {
+	// Two texts, hidden size 2: pooler_output is used as-is, ignoring the attention mask
+	pooler := []float32{0.1, 0.2, 0.3, 0.4}
+	mask := []int64{1, 1, 0, 1, 0, 0}
+	assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, poolOutput(OutputPooler, PoolingMean, pooler, mask, 2, 3, 2))
+
+	// last_hidden_state is mean pooled over unmasked tokens
+	hidden := []float32{1, 2, 3, 4, 100, 100}
+	assert.Equal(t, [][]float32{{2, 3}}, poolOutput(OutputLastHiddenState, PoolingMean, hidden, []int64{1, 1, 0}, 1, 3, 2))
+}
+
+func TestPoolOutputStrategies(t *testing.T) {
+	// One text of 3 tokens (the last one padding), hidden size 2
+	hidden := []float32{1, 5, 3, -2, 100, 100}
+	mask := []int64{1, 1, 0}
+	assert.Equal(t, [][]float32{{1, 5}}, poolOutput(OutputLastHiddenState, PoolingCLS, hidden, mask, 1, 3, 2))
+	assert.Equal(t, [][]float32{{3, 5}}, poolOutput(OutputLastHiddenState, PoolingMax, hidden, mask, 1, 3, 2))
+	assert.Equal(t, [][]float32{{2, 1.5}}, poolOutput(OutputLastHiddenState, PoolingMean, hidden, mask, 1, 3, 2))
+
+	// The strategy does not apply to pooler_output
+	assert.Equal(t, [][]float32{{1, 5}}, poolOutput(OutputPooler, PoolingMax, []float32{1, 5}, mask, 1, 3, 2))
+}
+
+func TestL2Normalize(t *testing.T) {
+	embedding := []float32{3, 4}
+	l2Normalize(embedding)
+	assert.InDeltaSlice(t, []float32{0.6, 0.8}, embedding, 1e-6)
+
+	zero := []float32{0, 0}
+	l2Normalize(zero)
+	assert.Equal(t, []float32{0, 0}, zero)
+}
+
+// TestONNXEmbedContextCancelled tests that a done context stops embedding before
+// the model is loaded
+func TestONNXEmbedContextCancelled(t *testing.T) {
+	ef := &ONNXEmbeddingFunction{}
+	var _ ContextEmbedder = ef
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := EmbedWithContext(ctx, ef, []string{"hello"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	embeddings, err := ef.EmbedWithBatchSizeContext(ctx, nil, 1)
+	require.NoError(t, err)
+	assert.Empty(t, embeddings)
+}
+
+func TestNewONNXEmbeddingFunctionRejectsUnknownOutput(t *testing.T) {
+	_, err := NewONNXEmbeddingFunction(WithONNXOutput("token_embeddings"))
+	assert.ErrorContains(t, err, "unsupported ONNX output")
+}
+
+func TestNewONNXEmbeddingFunctionFromPath(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewONNXEmbeddingFunctionFromPath(dir, 768, 512)
+	assert.ErrorContains(t, err, "model.onnx")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "model.onnx"), []byte("onnx"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tokenizer.json"), []byte("{}"), 0644))
+
+	ef, err := NewONNXEmbeddingFunctionFromPath(dir, 768, 512)
+	require.NoError(t, err)
+	assert.Equal(t, 768, ef.Dimension())
+	assert.Equal(t, 512, ef.maxTokens)
+	assert.Equal(t, filepath.Join(dir, "model.onnx"), ef.modelPath)
+
+	_, err = NewONNXEmbeddingFunctionFromPath(dir, 0, 512)
+	assert.Error(t, err)
+	_, err = NewONNXEmbeddingFunctionFromPath(dir, 768, 0)
+	assert.Error(t, err)
+	_, err = NewONNXEmbeddingFunctionFromPath(dir, 768, 512, WithONNXOutput("logits"))
+	assert.Error(t, err)
+	_, err = NewONNXEmbeddingFunctionFromPath(dir, 768, 512, WithONNXPooling("sum"))
+	assert.ErrorContains(t, err, "unsupported pooling strategy")
+
+	ef, err = NewONNXEmbeddingFunctionFromPath(dir, 768, 512, WithONNXPooling(PoolingCLS), WithONNXNormalize(true))
+	require.NoError(t, err)
+	assert.Equal(t, PoolingCLS, ef.pooling)
+	assert.True(t, ef.normalize)
+}
+
+func TestDownloadFileUsesClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("model bytes"))
+	}))
+	defer server.Close()
+
+	spy := &spyRoundTripper{}
+	dest := filepath.Join(t.TempDir(), "model.onnx")
+	require.NoError(t, downloadFile(&http.Client{Transport: spy}, server.URL+"/model.onnx", dest, "", NopLogger{}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&spy.requests))
+
+	data, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "model bytes", string(data))
+}
+
+// recordingLogger collects formatted info messages and warnings
+type recordingLogger struct {
+	NopLogger
+	messages []string
+	warnings []string
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+func TestDownloadModelLogsProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("model bytes"))
+	}))
+	defer server.Close()
+	t.Setenv("HF_ENDPOINT", server.URL)
+
+	logger := &recordingLogger{}
+	ef := &ONNXEmbeddingFunction{
+		httpClient: server.Client(),
+		logger:     logger,
+		checksums:  map[string]string{"model.onnx": testModelSHA256, "tokenizer.json": testModelSHA256},
+	}
+	modelDir := t.TempDir()
+	require.NoError(t, ef.downloadModelIfNeeded(modelDir))
+	assert.Equal(t, []string{
+		"Downloading model " + HFModelID + " from " + server.URL,
+		"Model " + HFModelID + " downloaded to " + modelDir,
+	}, logger.messages)
+
+	// Nothing is downloaded or logged once the files exist
+	logger.messages = nil
+	require.NoError(t, ef.downloadModelIfNeeded(modelDir))
+	assert.Empty(t, logger.messages)
+
+	WithONNXLogger(nil)(ef)
+	assert.Equal(t, logger, ef.logger)
+}
@@ -0,0 +1,276 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOpenAIModel is the default OpenAI embedding model
+	DefaultOpenAIModel = "text-embedding-3-small"
+	// DefaultOpenAIBaseURL is the default OpenAI API base URL
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+	// DefaultOpenAIBatchSize is the maximum number of inputs per embeddings request
+	DefaultOpenAIBatchSize = 2048
+	// DefaultOpenAIMaxBatchTokens is the maximum (estimated) number of tokens per embeddings request
+	DefaultOpenAIMaxBatchTokens = 300000
+	// DefaultOpenAIMaxRetries is the number of retries on rate-limit (429) and 5xx responses
+	DefaultOpenAIMaxRetries = 5
+)
+
+// openAIModelDimensions lists the output dimensions of known OpenAI embedding models.
+var openAIModelDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+// OpenAIEmbeddingFunction implements EmbeddingFunc using the OpenAI embeddings REST API.
+type OpenAIEmbeddingFunction struct {
+	apiKey         string
+	model          string
+	baseURL        string
+	dimension      int
+	batchSize      int
+	maxBatchTokens int
+	maxRetries     int
+	retryBaseDelay time.Duration
+	httpClient     *http.Client
+}
+
+// OpenAIOption is a functional option for configuring an OpenAIEmbeddingFunction.
+type OpenAIOption func(*OpenAIEmbeddingFunction)
+
+// WithOpenAIModel sets the embedding model name.
+func WithOpenAIModel(model string) OpenAIOption {
+	return func(e *OpenAIEmbeddingFunction) {
+		e.model = model
+	}
+}
+
+// WithOpenAIBaseURL overrides the API base URL, e.g. for Azure OpenAI or a proxy.
+func WithOpenAIBaseURL(baseURL string) OpenAIOption {
+	return func(e *OpenAIEmbeddingFunction) {
+		e.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithOpenAIDimension sets the embedding dimension. It is required for models the
+// package does not know; for text-embedding-3 models a smaller value requests
+// shortened embeddings from the API.
+func WithOpenAIDimension(dimension int) OpenAIOption {
+	return func(e *OpenAIEmbeddingFunction) {
+		e.dimension = dimension
+	}
+}
+
+// WithOpenAIBatchSize sets the maximum number of inputs sent per request.
+func WithOpenAIBatchSize(batchSize int) OpenAIOption {
+	return func(e *OpenAIEmbeddingFunction) {
+		e.batchSize = batchSize
+	}
+}
+
+// WithOpenAIMaxRetries sets how many times a rate-limited or failed request is retried.
+func WithOpenAIMaxRetries(maxRetries int) OpenAIOption {
+	return func(e *OpenAIEmbeddingFunction) {
+		e.maxRetries = maxRetries
+	}
+}
+
+// WithOpenAIHTTPClient sets the HTTP client used for API requests, so embedders can
+// share a transport with custom timeouts, proxies and connection pooling.
+// By default each function uses its own client with DefaultHTTPTimeout.
+func WithOpenAIHTTPClient(client *http.Client) OpenAIOption {
+	return func(e *OpenAIEmbeddingFunction) {
+		if client != nil {
+			e.httpClient = client
+		}
+	}
+}
+
+// NewOpenAIEmbeddingFunction creates an embedding function backed by the OpenAI embeddings API.
+func NewOpenAIEmbeddingFunction(apiKey string, opts ...OpenAIOption) (*OpenAIEmbeddingFunction, error) {
+	e := &OpenAIEmbeddingFunction{
+		apiKey:         apiKey,
+		model:          DefaultOpenAIModel,
+		baseURL:        DefaultOpenAIBaseURL,
+		batchSize:      DefaultOpenAIBatchSize,
+		maxBatchTokens: DefaultOpenAIMaxBatchTokens,
+		maxRetries:     DefaultOpenAIMaxRetries,
+		retryBaseDelay: time.Second,
+		httpClient:     newDefaultHTTPClient(DefaultHTTPTimeout),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.apiKey == "" {
+		return nil, fmt.Errorf("OpenAI API key is required")
+	}
+	if e.batchSize <= 0 || e.batchSize > DefaultOpenAIBatchSize {
+		e.batchSize = DefaultOpenAIBatchSize
+	}
+	if e.dimension <= 0 {
+		dimension, ok := openAIModelDimensions[e.model]
+		if !ok {
+			return nil, fmt.Errorf("unknown dimension for OpenAI model %q: use WithOpenAIDimension", e.model)
+		}
+		e.dimension = dimension
+	}
+
+	return e, nil
+}
+
+// openAIEmbeddingRequest is the request body of the embeddings endpoint.
+type openAIEmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+// openAIEmbeddingResponse is the response body of the embeddings endpoint.
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Embed converts texts to embedding vectors.
+func (e *OpenAIEmbeddingFunction) Embed(texts []string) ([][]float32, error) {
+	return e.EmbedContext(context.Background(), texts)
+}
+
+// EmbedContext converts texts to embedding vectors, aborting in-flight requests when ctx is done.
+// Inputs are split into batches that respect the per-request item and token limits.
+func (e *OpenAIEmbeddingFunction) EmbedContext(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	allEmbeddings := make([][]float32, 0, len(texts))
+	for _, batch := range e.batches(texts) {
+		batchEmbeddings, err := e.embedBatch(ctx, texts[batch[0]:batch[1]])
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed batch starting at index %d: %w", batch[0], err)
+		}
+		allEmbeddings = append(allEmbeddings, batchEmbeddings...)
+	}
+
+	return allEmbeddings, nil
+}
+
+// batches splits texts into [start, end) ranges of at most batchSize items and
+// maxBatchTokens estimated tokens (about 4 bytes per token).
+func (e *OpenAIEmbeddingFunction) batches(texts []string) [][2]int {
+	var batches [][2]int
+	start, tokens := 0, 0
+	for i, text := range texts {
+		textTokens := len(text)/4 + 1
+		if i > start && (i-start >= e.batchSize || tokens+textTokens > e.maxBatchTokens) {
+			batches = append(batches, [2]int{start, i})
+			start, tokens = i, 0
+		}
+		tokens += textTokens
+	}
+	return append(batches, [2]int{start, len(texts)})
+}
+
+// embedBatch sends a single embeddings request, retrying on 429 and 5xx responses with backoff.
+func (e *OpenAIEmbeddingFunction) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	reqBody := openAIEmbeddingRequest{Model: e.model, Input: texts}
+	// text-embedding-3 models can return shortened embeddings on request
+	if native, ok := openAIModelDimensions[e.model]; ok && strings.HasPrefix(e.model, "text-embedding-3") && e.dimension != native {
+		reqBody.Dimensions = e.dimension
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+		resp, err := e.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		if retryable && attempt < e.maxRetries {
+			if err := sleepContext(ctx, e.retryDelay(attempt, resp.Header.Get("Retry-After"))); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var parsed openAIEmbeddingResponse
+		if err := json.Unmarshal(respBody, &parsed); err != nil && resp.StatusCode == http.StatusOK {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			if parsed.Error != nil && parsed.Error.Message != "" {
+				return nil, fmt.Errorf("bad status: %s: %s", resp.Status, parsed.Error.Message)
+			}
+			return nil, fmt.Errorf("bad status: %s", resp.Status)
+		}
+		if len(parsed.Data) != len(texts) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(parsed.Data))
+		}
+
+		// Results carry their input index; don't rely on response order
+		sort.Slice(parsed.Data, func(i, j int) bool { return parsed.Data[i].Index < parsed.Data[j].Index })
+		embeddings := make([][]float32, len(parsed.Data))
+		for i, d := range parsed.Data {
+			embeddings[i] = d.Embedding
+		}
+		return embeddings, nil
+	}
+}
+
+// retryDelay returns how long to wait before retrying, honoring a Retry-After header in seconds.
+func (e *OpenAIEmbeddingFunction) retryDelay(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return e.retryBaseDelay << attempt
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Dimension returns the embedding dimension of the configured model.
+func (e *OpenAIEmbeddingFunction) Dimension() int {
+	return e.dimension
+}
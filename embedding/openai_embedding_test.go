@@ -0,0 +1,167 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOpenAITestServer returns a server answering embeddings requests with
+// [len(input), index] vectors, in reverse order to exercise index sorting.
+func newOpenAITestServer(t *testing.T, requests *[]openAIEmbeddingRequest, rateLimited int32) *httptest.Server {
+	var limited int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		if atomic.AddInt32(&limited, 1) <= rateLimited {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+			return
+		}
+
+		var req openAIEmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+
+		type datum struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		var data []datum
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, datum{Index: i, Embedding: []float32{float32(len(req.Input[i])), float32(i)}})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestOpenAIEmbeddingFunction(t *testing.T) {
+	var requests []openAIEmbeddingRequest
+	server := newOpenAITestServer(t, &requests, 0)
+	defer server.Close()
+
+	ef, err := NewOpenAIEmbeddingFunction("test-key",
+		WithOpenAIBaseURL(server.URL+"/v1/"),
+		WithOpenAIBatchSize(2),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1536, ef.Dimension())
+
+	embeddings, err := ef.Embed([]string{"a", "bb", "ccc", "dddd", "eeeee"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {2, 1}, {3, 0}, {4, 1}, {5, 0}}, embeddings)
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, DefaultOpenAIModel, requests[0].Model)
+	assert.Equal(t, []string{"a", "bb"}, requests[0].Input)
+	assert.Equal(t, []string{"eeeee"}, requests[2].Input)
+	assert.Zero(t, requests[0].Dimensions)
+}
+
+func TestOpenAIEmbeddingFunctionRetriesRateLimit(t *testing.T) {
+	var requests []openAIEmbeddingRequest
+	server := newOpenAITestServer(t, &requests, 2)
+	defer server.Close()
+
+	ef, err := NewOpenAIEmbeddingFunction("test-key", WithOpenAIBaseURL(server.URL+"/v1"))
+	require.NoError(t, err)
+
+	embeddings, err := ef.Embed([]string{"hello"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{5, 0}}, embeddings)
+	assert.Len(t, requests, 1)
+
+	// Give up once retries are exhausted
+	server2 := newOpenAITestServer(t, &requests, 10)
+	defer server2.Close()
+	ef, err = NewOpenAIEmbeddingFunction("test-key", WithOpenAIBaseURL(server2.URL+"/v1"), WithOpenAIMaxRetries(1))
+	require.NoError(t, err)
+	_, err = ef.Embed([]string{"hello"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limited")
+}
+
+func TestOpenAIEmbeddingFunctionOptions(t *testing.T) {
+	_, err := NewOpenAIEmbeddingFunction("")
+	assert.Error(t, err)
+
+	_, err = NewOpenAIEmbeddingFunction("key", WithOpenAIModel("custom-model"))
+	assert.Error(t, err)
+
+	ef, err := NewOpenAIEmbeddingFunction("key", WithOpenAIModel("custom-model"), WithOpenAIDimension(768))
+	require.NoError(t, err)
+	assert.Equal(t, 768, ef.Dimension())
+
+	ef, err = NewOpenAIEmbeddingFunction("key", WithOpenAIModel("text-embedding-3-large"))
+	require.NoError(t, err)
+	assert.Equal(t, 3072, ef.Dimension())
+}
+
+func TestOpenAIEmbeddingFunctionBatchesByTokens(t *testing.T) {
+	ef, err := NewOpenAIEmbeddingFunction("key")
+	require.NoError(t, err)
+	ef.maxBatchTokens = 10
+
+	long := strings.Repeat("x", 32) // ~9 tokens
+	batches := ef.batches([]string{long, "a", long, long})
+	assert.Equal(t, [][2]int{{0, 2}, {2, 3}, {3, 4}}, batches)
+}
+
+func TestOpenAIEmbeddingFunctionContext(t *testing.T) {
+	var requests []openAIEmbeddingRequest
+	server := newOpenAITestServer(t, &requests, 0)
+	defer server.Close()
+
+	ef, err := NewOpenAIEmbeddingFunction("test-key", WithOpenAIBaseURL(server.URL+"/v1"))
+	require.NoError(t, err)
+
+	var _ ContextEmbedder = ef
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = EmbedWithContext(ctx, ef, []string{"hello"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, requests)
+}
+
+// spyRoundTripper counts the requests sent through it.
+type spyRoundTripper struct {
+	requests int32
+}
+
+func (s *spyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&s.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOpenAIEmbeddingFunctionHTTPClient(t *testing.T) {
+	var requests []openAIEmbeddingRequest
+	server := newOpenAITestServer(t, &requests, 0)
+	defer server.Close()
+
+	spy := &spyRoundTripper{}
+	ef, err := NewOpenAIEmbeddingFunction("test-key",
+		WithOpenAIBaseURL(server.URL+"/v1"),
+		WithOpenAIHTTPClient(&http.Client{Transport: spy}),
+	)
+	require.NoError(t, err)
+
+	_, err = ef.Embed([]string{"hello"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&spy.requests))
+
+	// The default client must not be http.DefaultClient, which never times out
+	ef, err = NewOpenAIEmbeddingFunction("test-key")
+	require.NoError(t, err)
+	assert.NotSame(t, http.DefaultClient, ef.httpClient)
+	assert.Equal(t, DefaultHTTPTimeout, ef.httpClient.Timeout)
+}
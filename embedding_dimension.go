@@ -0,0 +1,26 @@
+package goseekdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDimensionMismatch is returned when an embedding does not have the vector
+// dimension of its collection.
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+// validateEmbeddingDimensions checks that every embedding has the given dimension,
+// so a wrong vector is reported before the server rejects the whole statement.
+// Nil embeddings (to be generated) and an unknown dimension (0) are not checked.
+func validateEmbeddingDimensions(embeddings [][]float32, dimension int) error {
+	if embeddings == nil || dimension <= 0 {
+		return nil
+	}
+	for i, emb := range embeddings {
+		if len(emb) != dimension {
+			return fmt.Errorf("%w: embedding at index %d has length %d, expected %d",
+				ErrDimensionMismatch, i, len(emb), dimension)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateEmbeddingDimensions tests the per-embedding dimension check
+func TestValidateEmbeddingDimensions(t *testing.T) {
+	assert.NoError(t, validateEmbeddingDimensions(nil, 3))
+	assert.NoError(t, validateEmbeddingDimensions([][]float32{{1, 2, 3}}, 0))
+	assert.NoError(t, validateEmbeddingDimensions([][]float32{{1, 2, 3}, {4, 5, 6}}, 3))
+
+	err := validateEmbeddingDimensions([][]float32{{1, 2, 3}, {4, 5}}, 3)
+	require.ErrorIs(t, err, ErrDimensionMismatch)
+	assert.Contains(t, err.Error(), "index 1")
+	assert.Contains(t, err.Error(), "length 2, expected 3")
+}
+
+// TestCollectionWriteDimensionMismatch tests that writes with a wrong embedding
+// length are rejected before reaching the client
+func TestCollectionWriteDimensionMismatch(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "docs", dimension: 3}
+
+	bad := WithEmbeddings([][]float32{{1, 2, 3}, {1, 2, 3, 4}})
+	_, err := collection.AddN(ctx, []string{"a", "b"}, []string{"x", "y"}, bad)
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+	_, err = collection.UpsertN(ctx, []string{"a", "b"}, []string{"x", "y"}, bad)
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+	_, err = collection.UpdateN(ctx, []string{"a"}, WithUpdateEmbeddings([][]float32{{1}}))
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+	assert.Empty(t, store.rows)
+
+	// Embeddings of the right length are written
+	_, err = collection.AddN(ctx, []string{"a"}, []string{"x"}, WithEmbeddings([][]float32{{1, 2, 3}}))
+	require.NoError(t, err)
+	assert.Len(t, store.rows, 1)
+}
@@ -0,0 +1,19 @@
+package goseekdb
+
+import "errors"
+
+// Errors returned by client and collection operations. Operations wrap them with
+// details, so compare with errors.Is.
+var (
+	// ErrInvalidParameter is returned for invalid arguments or options.
+	ErrInvalidParameter = errors.New("invalid parameter")
+	// ErrCollectionNotFound is returned for operations on a collection that does not exist.
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrDatabaseNotFound is returned for operations on a database that does not exist.
+	ErrDatabaseNotFound = errors.New("database not found")
+	// ErrEmbeddingFunctionRequired is returned when documents must be embedded but
+	// no embedding function is available.
+	ErrEmbeddingFunctionRequired = errors.New("embedding function required")
+	// ErrNotConnected is returned for operations on a client that is not connected.
+	ErrNotConnected = errors.New("not connected")
+)
@@ -139,21 +139,23 @@ func main() {
 		ids[i] = uuid.New().String()
 	}
 
-	// 3.1 Add single item
-	singleID := uuid.New().String()
+	// 3.1 Add single item, letting the library generate its ID
 	singleEmbedding := make([]float32, dimension)
 	for j := range singleEmbedding {
 		singleEmbedding[j] = rand.Float32()
 	}
 
-	collection.Add(ctx,
-		[]string{singleID},
+	singleIDs, err := collection.AddAutoID(ctx,
 		[]string{"This is a single document"},
 		goseekdb.WithEmbeddings([][]float32{singleEmbedding}),
 		goseekdb.WithMetadatas([]goseekdb.Metadata{
 			{"type": "single", "category": "test"},
 		}),
 	)
+	if err != nil {
+		log.Fatalf("Failed to add document: %v", err)
+	}
+	fmt.Printf("Added document with generated ID %s\n", singleIDs[0])
 
 	// 3.2 Add multiple items
 	collection.Add(ctx, ids, documents,
@@ -364,7 +366,7 @@ func main() {
 	// 6.10 Query with specific fields
 	results, _ = collection.Query(ctx, nil, 2,
 		goseekdb.WithQueryEmbeddings([][]float32{queryVector}),
-		goseekdb.WithInclude([]string{"documents", "metadatas", "embeddings"}),
+		goseekdb.WithInclude([]string{"documents", "metadatas", "embeddings", "distances"}),
 	)
 	_ = results
 
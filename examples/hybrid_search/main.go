@@ -121,9 +121,8 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results:")
 	if hybridResult1 != nil {
-		for i, id := range hybridResult1.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
+		for i, hit := range hybridResult1.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
 		}
 	}
 
@@ -178,10 +177,9 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results (independent filters):")
 	if hybridResult2 != nil {
-		for i, id := range hybridResult2.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
-			fmt.Printf("      %+v\n", metadatas[idx])
+		for i, hit := range hybridResult2.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
+			fmt.Printf("      %+v\n", hit.Metadata)
 		}
 	}
 
@@ -228,9 +226,8 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results (full-text + vector fusion):")
 	if hybridResult3 != nil {
-		for i, id := range hybridResult3.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
+		for i, hit := range hybridResult3.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
 		}
 	}
 
@@ -285,10 +282,9 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results:")
 	if hybridResult4 != nil {
-		for i, id := range hybridResult4.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
-			fmt.Printf("      %+v\n", metadatas[idx])
+		for i, hit := range hybridResult4.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
+			fmt.Printf("      %+v\n", hit.Metadata)
 		}
 	}
 
@@ -334,9 +330,8 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results (RRF fusion):")
 	if hybridResult5 != nil {
-		for i, id := range hybridResult5.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
+		for i, hit := range hybridResult5.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
 		}
 	}
 
@@ -390,10 +385,9 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results:")
 	if hybridResult6 != nil {
-		for i, id := range hybridResult6.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
-			fmt.Printf("      %+v\n", metadatas[idx])
+		for i, hit := range hybridResult6.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
+			fmt.Printf("      %+v\n", hit.Metadata)
 		}
 	}
 
@@ -441,9 +435,8 @@ func main() {
 
 	fmt.Println("\nhybrid_search() Results:")
 	if hybridResult7 != nil {
-		for i, id := range hybridResult7.IDs {
-			idx := findIndex(ids, id)
-			fmt.Printf("  %d. %s\n", i+1, documents[idx])
+		for i, hit := range hybridResult7.Hits() {
+			fmt.Printf("  %d. %s\n", i+1, hit.Document)
 		}
 	}
 
@@ -0,0 +1,149 @@
+package goseekdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Chroma export layout written by ExportChroma:
+//
+//	<dir>/collection.json       {"name": "...", "dimension": 384, "metadata": {"hnsw:space": "l2"}}
+//	<dir>/records-00000.json    {"ids": [...], "embeddings": [[...]], "documents": [...], "metadatas": [...]}
+//	<dir>/records-00001.json    ...
+//
+// collection.json holds the arguments for chromadb's create_collection; each records
+// file holds at most chromaExportBatchSize records and is a valid keyword-argument set
+// for Collection.add, so a collection can be imported in Python with:
+//
+//	info = json.load(open(os.path.join(dir, "collection.json")))
+//	col = client.create_collection(info["name"], metadata=info["metadata"])
+//	for path in sorted(glob.glob(os.path.join(dir, "records-*.json"))):
+//	    col.add(**json.load(open(path)))
+//
+// Empty metadata is written as null because Chroma rejects empty metadata dicts.
+const (
+	chromaCollectionFile  = "collection.json"
+	chromaRecordsPattern  = "records-%05d.json"
+	chromaRecordsGlob     = "records-*.json"
+	chromaExportBatchSize = 1000
+)
+
+// chromaCollection is the content of collection.json.
+type chromaCollection struct {
+	Name      string                 `json:"name"`
+	Dimension int                    `json:"dimension"`
+	Metadata  map[string]interface{} `json:"metadata"`
+}
+
+// chromaRecords is the content of a records file.
+type chromaRecords struct {
+	IDs        []string    `json:"ids"`
+	Embeddings [][]float32 `json:"embeddings"`
+	Documents  []string    `json:"documents"`
+	Metadatas  []Metadata  `json:"metadatas"`
+}
+
+// chromaSpace maps a distance metric to Chroma's hnsw:space value.
+func chromaSpace(distance DistanceMetric) string {
+	switch distance {
+	case DistanceCosine:
+		return "cosine"
+	case DistanceInnerProduct:
+		return "ip"
+	default:
+		return "l2"
+	}
+}
+
+// ExportChroma writes the collection's documents, embeddings and metadata to dir
+// in a layout that can be imported into Chroma (see the format described above).
+// The directory is created if needed; existing export files in it are overwritten,
+// and records files of an earlier export are removed first.
+func (c *Collection) ExportChroma(ctx context.Context, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	if err := removeChromaRecords(dir); err != nil {
+		return err
+	}
+
+	info := chromaCollection{
+		Name:      c.name,
+		Dimension: c.dimension,
+		Metadata:  map[string]interface{}{"hnsw:space": chromaSpace(c.distance)},
+	}
+	if err := writeJSONFile(filepath.Join(dir, chromaCollectionFile), info); err != nil {
+		return err
+	}
+
+	cursor := ""
+	for batch := 0; ; batch++ {
+		opts := []GetOption{
+			WithLimit(chromaExportBatchSize),
+			WithGetInclude([]string{"documents", "metadatas", "embeddings"}),
+		}
+		if cursor != "" {
+			opts = append(opts, WithGetCursor(cursor))
+		}
+		page, err := c.GetPage(ctx, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to read collection: %w", err)
+		}
+		if len(page.IDs) == 0 {
+			return nil
+		}
+
+		records := chromaRecords{
+			IDs:        page.IDs,
+			Embeddings: page.Embeddings,
+			Documents:  page.Documents,
+			Metadatas:  make([]Metadata, len(page.IDs)),
+		}
+		for i := range page.IDs {
+			if i < len(page.Metadatas) && len(page.Metadatas[i]) > 0 {
+				records.Metadatas[i] = page.Metadatas[i]
+			}
+		}
+		if err := writeJSONFile(filepath.Join(dir, fmt.Sprintf(chromaRecordsPattern, batch)), records); err != nil {
+			return err
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// removeChromaRecords removes the records files in dir, so that files of an earlier,
+// larger export are not imported along with a new one.
+func removeChromaRecords(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read export directory: %w", err)
+	}
+	for _, entry := range entries {
+		if match, _ := filepath.Match(chromaRecordsGlob, entry.Name()); !match || entry.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// writeJSONFile writes v as JSON to path.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
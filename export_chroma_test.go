@@ -0,0 +1,114 @@
+package goseekdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionExportChroma(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "export_test", dimension: 3, distance: DistanceCosine}
+
+	total := chromaExportBatchSize + 5
+	ids := make([]string, total)
+	documents := make([]string, total)
+	embeddings := make([][]float32, total)
+	metadatas := make([]Metadata, total)
+	for i := 0; i < total; i++ {
+		ids[i] = fmt.Sprintf("id%05d", i)
+		documents[i] = fmt.Sprintf("document %d", i)
+		embeddings[i] = []float32{float32(i), 1, 2}
+		if i%2 == 0 {
+			metadatas[i] = Metadata{"index": i}
+		}
+	}
+	require.NoError(t, collection.Add(ctx, ids, documents, WithEmbeddings(embeddings), WithMetadatas(metadatas)))
+
+	dir := t.TempDir()
+	require.NoError(t, collection.ExportChroma(ctx, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, chromaCollectionFile))
+	require.NoError(t, err)
+	var info map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &info))
+	assert.Equal(t, "export_test", info["name"])
+	assert.Equal(t, float64(3), info["dimension"])
+	assert.Equal(t, map[string]interface{}{"hnsw:space": "cosine"}, info["metadata"])
+
+	files, err := filepath.Glob(filepath.Join(dir, "records-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var exported []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var records map[string][]json.RawMessage
+		require.NoError(t, json.Unmarshal(data, &records))
+		assert.Len(t, records, 4)
+
+		// Chroma's add() requires parallel arrays of equal length
+		n := len(records["ids"])
+		assert.Len(t, records["embeddings"], n)
+		assert.Len(t, records["documents"], n)
+		assert.Len(t, records["metadatas"], n)
+		assert.LessOrEqual(t, n, chromaExportBatchSize)
+
+		for i := 0; i < n; i++ {
+			var id string
+			require.NoError(t, json.Unmarshal(records["ids"][i], &id))
+			exported = append(exported, id)
+
+			var vector []float32
+			require.NoError(t, json.Unmarshal(records["embeddings"][i], &vector))
+			assert.Len(t, vector, 3)
+
+			var metadata Metadata
+			require.NoError(t, json.Unmarshal(records["metadatas"][i], &metadata))
+			if metadata != nil {
+				assert.NotEmpty(t, metadata)
+			}
+		}
+	}
+	assert.Equal(t, ids, exported)
+
+	data, err = os.ReadFile(files[0])
+	require.NoError(t, err)
+	var first chromaRecords
+	require.NoError(t, json.Unmarshal(data, &first))
+	assert.Equal(t, "document 0", first.Documents[0])
+	assert.Equal(t, float64(0), first.Metadatas[0]["index"])
+	assert.Nil(t, first.Metadatas[1])
+}
+
+func TestCollectionExportChromaRemovesStaleRecords(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "export_test", dimension: 3, distance: DistanceL2}
+	require.NoError(t, collection.Add(ctx, []string{"id1"}, []string{"document"}, WithEmbeddings([][]float32{{1, 2, 3}})))
+
+	// Left behind by an earlier, larger export, next to an unrelated file
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "records-00000.json"), []byte(`{"ids":["old"]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "records-00001.json"), []byte(`{"ids":["old"]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0o644))
+
+	require.NoError(t, collection.ExportChroma(ctx, dir))
+
+	files, err := filepath.Glob(filepath.Join(dir, chromaRecordsGlob))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "records-00000.json")}, files)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"ids":["id1"]`)
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
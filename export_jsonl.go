@@ -0,0 +1,187 @@
+package goseekdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// JSONL format written by ExportJSONL and read by ImportJSONL: one Hit per line,
+//
+//	{"id":"doc1","document":"...","metadata":{"k":"v"},"embedding":[0.1,0.2]}
+//
+// Empty fields are omitted.
+const (
+	// DefaultImportBatchSize is the number of records ImportJSONL upserts at a time.
+	DefaultImportBatchSize = 500
+
+	jsonlExportPageSize = 1000
+)
+
+// ExportJSONL writes every document of the collection to w as one JSON line with
+// its ID, document, metadata and embedding, e.g. for backups or to move a collection
+// between environments with ImportJSONL. Rows are read a page at a time, so the
+// collection is never held in memory as a whole.
+func (c *Collection) ExportJSONL(ctx context.Context, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	cursor := ""
+	for {
+		opts := []GetOption{
+			WithLimit(jsonlExportPageSize),
+			WithGetInclude([]string{"documents", "metadatas", "embeddings"}),
+		}
+		if cursor != "" {
+			opts = append(opts, WithGetCursor(cursor))
+		}
+		page, err := c.GetPage(ctx, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to read collection: %w", err)
+		}
+
+		for i, id := range page.IDs {
+			hit := Hit{ID: id}
+			if i < len(page.Documents) {
+				hit.Document = page.Documents[i]
+			}
+			if i < len(page.Metadatas) {
+				hit.Metadata = page.Metadatas[i]
+			}
+			if i < len(page.Embeddings) {
+				hit.Embedding = page.Embeddings[i]
+			}
+			if err := enc.Encode(hit); err != nil {
+				return fmt.Errorf("failed to write record %q: %w", id, err)
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// ImportJSONL loads records written by ExportJSONL from r into the named collection
+// with batched upserts and returns the number of records read. If the collection
+// does not exist it is created with the dimension of the first record's embedding
+// (unless WithImportCollectionOptions sets a configuration); records without an
+// embedding are embedded by the collection's embedding function.
+func (c *Client) ImportJSONL(ctx context.Context, collectionName string, r io.Reader, opts ...ImportOption) (int64, error) {
+	options := &ImportOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var collection *Collection
+	var imported int64
+	err := readJSONLBatches(dec, options.BatchSize, func(batch []Hit) error {
+		if collection == nil {
+			var err error
+			if collection, err = c.importCollection(ctx, collectionName, batch[0], options); err != nil {
+				return err
+			}
+		}
+		if err := upsertHits(ctx, collection, batch); err != nil {
+			return err
+		}
+		imported += int64(len(batch))
+		return nil
+	})
+	return imported, err
+}
+
+// importCollection returns the collection to import into, creating it if needed.
+func (c *Client) importCollection(ctx context.Context, collectionName string, first Hit, options *ImportOptions) (*Collection, error) {
+	exists, err := c.HasCollection(ctx, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return c.GetCollection(ctx, collectionName, options.CollectionOptions...)
+	}
+
+	createOptions := options.CollectionOptions
+	if len(first.Embedding) > 0 {
+		distance := options.Distance
+		if distance == "" {
+			distance = DefaultDistanceMetric
+		}
+		config := &HNSWConfiguration{Dimension: len(first.Embedding), Distance: distance}
+		// Options given by the caller come last and win
+		createOptions = append([]CreateCollectionOption{WithConfiguration(config)}, createOptions...)
+	}
+	return c.CreateCollection(ctx, collectionName, createOptions...)
+}
+
+// readJSONLBatches decodes records from dec and passes them to fn in batches of up
+// to batchSize (DefaultImportBatchSize if batchSize <= 0). A batch never mixes
+// records with and without embeddings, so each batch can be upserted in one call.
+func readJSONLBatches(dec *json.Decoder, batchSize int, fn func([]Hit) error) error {
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	var batch []Hit
+	for line := 1; ; line++ {
+		var hit Hit
+		err := dec.Decode(&hit)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode record %d: %w", line, err)
+		}
+		if hit.ID == "" {
+			return fmt.Errorf("%w: record %d has no id", ErrInvalidParameter, line)
+		}
+
+		if len(batch) > 0 && (len(batch) == batchSize || (len(batch[0].Embedding) > 0) != (len(hit.Embedding) > 0)) {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+		batch = append(batch, hit)
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// upsertHits upserts a batch of records into collection.
+func upsertHits(ctx context.Context, collection *Collection, batch []Hit) error {
+	ids := make([]string, len(batch))
+	documents := make([]string, len(batch))
+	metadatas := make([]Metadata, len(batch))
+	var embeddings [][]float32
+	for i, hit := range batch {
+		ids[i] = hit.ID
+		documents[i] = hit.Document
+		metadatas[i] = hit.Metadata
+		if len(hit.Embedding) > 0 {
+			embeddings = append(embeddings, hit.Embedding)
+		}
+	}
+
+	opts := []AddOption{WithMetadatas(metadatas)}
+	if embeddings != nil {
+		opts = append(opts, WithEmbeddings(embeddings))
+	}
+	if err := collection.Upsert(ctx, ids, documents, opts...); err != nil {
+		return fmt.Errorf("failed to import records %q to %q: %w", ids[0], ids[len(ids)-1], err)
+	}
+	return nil
+}
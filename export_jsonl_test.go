@@ -0,0 +1,121 @@
+package goseekdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionExportJSONL(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "export_jsonl", dimension: 3, distance: DistanceL2}
+
+	total := jsonlExportPageSize + 3
+	ids := make([]string, total)
+	documents := make([]string, total)
+	embeddings := make([][]float32, total)
+	metadatas := make([]Metadata, total)
+	for i := 0; i < total; i++ {
+		ids[i] = fmt.Sprintf("id%05d", i)
+		documents[i] = fmt.Sprintf("document %d", i)
+		embeddings[i] = []float32{float32(i), 1, 2}
+		metadatas[i] = Metadata{"index": i}
+	}
+	require.NoError(t, collection.Add(ctx, ids, documents, WithEmbeddings(embeddings), WithMetadatas(metadatas)))
+
+	var buf bytes.Buffer
+	require.NoError(t, collection.ExportJSONL(ctx, &buf))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, total)
+	assert.JSONEq(t, `{"id":"id00001","document":"document 1","metadata":{"index":1},"embedding":[1,1,2]}`, lines[1])
+
+	// Import the export into another collection
+	target := &fakeOperations{}
+	targetCollection := &Collection{client: target, name: "import_jsonl", dimension: 3, distance: DistanceL2}
+	var batches []int
+	err := readJSONLBatches(json.NewDecoder(&buf), 400, func(batch []Hit) error {
+		batches = append(batches, len(batch))
+		return upsertHits(ctx, targetCollection, batch)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{400, 400, 203}, batches)
+
+	restored, err := targetCollection.Get(ctx, nil, WithLimit(total))
+	require.NoError(t, err)
+	assert.Equal(t, ids, restored.IDs)
+	assert.Equal(t, documents, restored.Documents)
+	assert.Equal(t, embeddings, restored.Embeddings)
+}
+
+func TestReadJSONLBatches(t *testing.T) {
+	input := `{"id":"a","embedding":[1,2]}
+{"id":"b","embedding":[3,4]}
+{"id":"c","document":"embed me"}
+{"id":"d","embedding":[5,6]}
+`
+	var batches [][]string
+	err := readJSONLBatches(json.NewDecoder(strings.NewReader(input)), 10, func(batch []Hit) error {
+		var ids []string
+		for _, hit := range batch {
+			ids = append(ids, hit.ID)
+		}
+		batches = append(batches, ids)
+		return nil
+	})
+	require.NoError(t, err)
+	// Records with and without embeddings are not mixed in one batch
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}, {"d"}}, batches)
+
+	noop := func([]Hit) error { return nil }
+	err = readJSONLBatches(json.NewDecoder(strings.NewReader(`{"document":"no id"}`)), 10, noop)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	err = readJSONLBatches(json.NewDecoder(strings.NewReader("{\"id\":\"a\"}\nnot json\n")), 10, noop)
+	assert.ErrorContains(t, err, "record 2")
+}
+
+// TestImportJSONL tests exporting a collection and importing it as a new one
+func TestImportJSONL(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	sourceName := "test_export_jsonl_" + uuid.New().String()[:8]
+	targetName := "test_import_jsonl_" + uuid.New().String()[:8]
+	source := createTestCollection(t, client, sourceName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, sourceName)
+		_ = client.DeleteCollection(ctx, targetName)
+	}()
+
+	err := source.Add(ctx, []string{"id1", "id2"}, []string{"first", "second"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"n": 1}, {"n": 2}}),
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, source.ExportJSONL(ctx, &buf))
+
+	imported, err := client.ImportJSONL(ctx, targetName, &buf, WithImportDistance(DistanceL2))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), imported)
+
+	target, err := client.GetCollection(ctx, targetName)
+	require.NoError(t, err)
+	assert.Equal(t, 3, target.Dimension())
+
+	results, err := target.Get(ctx, []string{"id1", "id2"}, WithGetInclude([]string{"documents", "metadatas", "embeddings"}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2"}, results.IDs)
+	assert.ElementsMatch(t, []string{"first", "second"}, results.Documents)
+}
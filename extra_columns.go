@@ -0,0 +1,104 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxColumnNameLength is MySQL's identifier length limit.
+const maxColumnNameLength = 64
+
+// validateExtraColumns checks that columns added with WithExtraColumns or
+// WithGetExtraColumns are plain identifiers, distinct, and not columns already
+// returned in their own result fields.
+func validateExtraColumns(columns []string) error {
+	seen := make(map[string]bool)
+	for _, column := range columns {
+		if column == "" || len(column) > maxColumnNameLength {
+			return fmt.Errorf("%w: extra column %q must have 1 to %d characters", ErrInvalidParameter, column, maxColumnNameLength)
+		}
+		for i, r := range column {
+			isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+			isDigit := r >= '0' && r <= '9'
+			if !isLetter && r != '_' && r != '$' && (!isDigit || i == 0) {
+				return fmt.Errorf("%w: extra column %q must start with a letter, '_' or '$' and contain only letters, digits, '_' or '$'", ErrInvalidParameter, column)
+			}
+		}
+
+		// Column names are case-insensitive
+		lower := strings.ToLower(column)
+		switch lower {
+		case FieldID, FieldDocument, FieldMetadata, FieldEmbedding, "distance":
+			return fmt.Errorf("%w: extra column %q is already part of the result", ErrInvalidParameter, column)
+		}
+		if seen[lower] {
+			return fmt.Errorf("%w: duplicate extra column %q", ErrInvalidParameter, column)
+		}
+		seen[lower] = true
+	}
+	return nil
+}
+
+// extraColumnsSQL returns the select list suffix for extra columns, e.g.
+// ", `tenant_id`", or "" for none. Names are quoted so that columns named like
+// keywords work.
+func extraColumnsSQL(columns []string) string {
+	var b strings.Builder
+	for _, column := range columns {
+		b.WriteString(", `")
+		b.WriteString(column)
+		b.WriteByte('`')
+	}
+	return b.String()
+}
+
+// rawColumnScanner holds the scan destinations of extra columns, which follow the
+// built-in columns of a row. A nil scanner scans no extra columns.
+type rawColumnScanner struct {
+	columns []string
+	values  []interface{}
+	ptrs    []interface{}
+}
+
+// newRawColumnScanner returns a scanner for columns, or nil if there are none.
+func newRawColumnScanner(columns []string) *rawColumnScanner {
+	if len(columns) == 0 {
+		return nil
+	}
+	s := &rawColumnScanner{
+		columns: columns,
+		values:  make([]interface{}, len(columns)),
+		ptrs:    make([]interface{}, len(columns)),
+	}
+	for i := range s.values {
+		s.ptrs[i] = &s.values[i]
+	}
+	return s
+}
+
+// dest returns builtIn followed by the extra column destinations, without
+// modifying builtIn.
+func (s *rawColumnScanner) dest(builtIn []interface{}) []interface{} {
+	if s == nil {
+		return builtIn
+	}
+	dest := make([]interface{}, 0, len(builtIn)+len(s.ptrs))
+	return append(append(dest, builtIn...), s.ptrs...)
+}
+
+// row returns the extra columns of the last scanned row by name. Text and binary
+// values, which the driver returns as bytes, are converted to strings; SQL NULL is nil.
+func (s *rawColumnScanner) row() map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	row := make(map[string]interface{}, len(s.columns))
+	for i, column := range s.columns {
+		value := s.values[i]
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		row[column] = value
+	}
+	return row
+}
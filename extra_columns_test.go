@@ -0,0 +1,100 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExtraColumns(t *testing.T) {
+	assert.NoError(t, validateExtraColumns(nil))
+	assert.NoError(t, validateExtraColumns([]string{"tenant_id", "_created", "$v", "Order2"}))
+
+	for _, columns := range [][]string{
+		{""},
+		{"2col"},
+		{"tenant-id"},
+		{"tenant_id`; DROP TABLE docs; --"},
+		{"Document"},
+		{"distance"},
+		{"tenant_id", "TENANT_ID"},
+		{string(make([]byte, 65))},
+	} {
+		assert.ErrorIs(t, validateExtraColumns(columns), ErrInvalidParameter, "%q", columns)
+	}
+}
+
+// TestExtraColumnsSQL tests that extra columns follow the built-in ones
+func TestExtraColumnsSQL(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	options := &GetOptions{Limit: 10}
+	WithGetMetadataKeys([]string{"title"})(options)
+	WithGetExtraColumns([]string{"tenant_id", "order"})(options)
+	querySQL, _, err := client.buildGetSQL("docs", nil, options)
+	require.NoError(t, err)
+	assert.Contains(t, querySQL, "SELECT _id, document, '{}' AS metadata, embedding, JSON_EXTRACT(metadata, ?), `tenant_id`, `order`\n")
+
+	options.ExtraColumns = []string{"bad name"}
+	_, _, err = client.buildGetSQL("docs", nil, options)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	queryOptions := &QueryOptions{}
+	WithExtraColumns([]string{"tenant_id"})(queryOptions)
+	querySQL, _ = buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", queryOptions)
+	assert.Contains(t, querySQL, "AS distance, `tenant_id`\n")
+
+	// The threshold query passes the columns through
+	WithMaxDistance(0.5)(queryOptions)
+	querySQL, _ = buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", queryOptions)
+	assert.Contains(t, querySQL, "SELECT _id, document, metadata, embedding, distance, `tenant_id`\n")
+}
+
+// TestExtraColumnsScan tests returning extra columns from Get, GetStream and Query
+func TestExtraColumnsScan(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "get_extra_columns", []string{"_id", "document", "metadata", "embedding", "tenant_id", "score"}, [][]driver.Value{
+		{[]byte("id1"), []byte("doc 1"), []byte("{}"), []byte("[1,2,3]"), []byte("acme"), int64(7)},
+		{[]byte("id2"), []byte("doc 2"), []byte("{}"), []byte("[4,5,6]"), nil, int64(8)},
+	})
+	client := &Client{
+		conn:   &staticConnection{db: db, key: "get_extra_columns"},
+		config: &ClientConfig{},
+	}
+	options := &GetOptions{ExtraColumns: []string{"tenant_id", "score"}}
+
+	result, err := client.collectionGet(ctx, "docs", nil, options)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"tenant_id": "acme", "score": int64(7)},
+		{"tenant_id": nil, "score": int64(8)},
+	}, result.RawColumns)
+
+	it, err := client.collectionGetStream(ctx, "docs", nil, options)
+	require.NoError(t, err)
+	defer it.Close()
+	require.True(t, it.Next())
+	assert.Equal(t, map[string]interface{}{"tenant_id": "acme", "score": int64(7)}, it.Item().RawColumns)
+
+	// Query and QueryWithCallback return them per hit
+	db = registerStaticRows(t, "query_extra_columns", []string{"_id", "document", "metadata", "embedding", "distance", "tenant_id"}, [][]driver.Value{
+		{[]byte("id1"), []byte("doc 1"), []byte("{}"), []byte("[1,2,3]"), 0.5, []byte("acme")},
+	})
+	client.conn = &staticConnection{db: db, key: "query_extra_columns"}
+	queryOptions := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2, 3}}, ExtraColumns: []string{"tenant_id"}}
+	queryResult, err := client.collectionQuery(ctx, "docs", nil, 1, queryOptions, nil, DistanceL2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]map[string]interface{}{{{"tenant_id": "acme"}}}, queryResult.RawColumns)
+
+	var records []SearchRecord
+	err = client.collectionQueryEach(ctx, "docs", nil, 1, queryOptions, nil, DistanceL2, func(record SearchRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, map[string]interface{}{"tenant_id": "acme"}, records[0].RawColumns)
+}
@@ -0,0 +1,275 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+)
+
+const (
+	// FederatedSourceKey is the metadata key FederatedQuery sets on each result to
+	// the name of the collection it came from.
+	FederatedSourceKey = "_collection"
+
+	// DefaultFederatedParallelism is the number of collections FederatedQuery
+	// searches at the same time.
+	DefaultFederatedParallelism = 4
+)
+
+// FederatedQuery searches several collections for one query text, e.g. for RAG over
+// multiple knowledge bases, and returns a single result list ranked by distance.
+// The query is embedded once, with the embedding function of the first collection
+// that has one (or taken from WithQueryEmbeddings), and the collections are searched
+// concurrently, at most WithFederatedParallelism at a time. Each result's metadata
+// is tagged with its source collection under FederatedSourceKey. opts apply to every
+// collection.
+//
+// Distances are only comparable between collections with the same distance metric,
+// so the collections should share one, or at least its direction (see
+// DistanceMetric.HigherIsBetter); collections ranking the other way are skipped.
+//
+// A collection that cannot be searched does not fail the call: the results of the
+// others are returned along with an error joining the per-collection errors. If no
+// collection could be searched, the result is nil.
+func (c *Client) FederatedQuery(ctx context.Context, collections []string, queryText string, nResults int, opts ...QueryOption) (*QueryResult, error) {
+	options := &QueryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if len(collections) == 0 {
+		return nil, fmt.Errorf("%w: at least one collection is required", ErrInvalidParameter)
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	var errs []error
+	var opened []*Collection
+	for _, name := range collections {
+		collection, err := c.GetCollection(ctx, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collection %s: %w", name, err))
+			continue
+		}
+		opened = append(opened, collection)
+	}
+	if len(opened) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	result, err := federatedQuery(ctx, opened, queryText, nResults, options)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+// federatedQuery implements FederatedQuery over opened collections.
+func federatedQuery(ctx context.Context, collections []*Collection, queryText string, nResults int, options *QueryOptions) (*QueryResult, error) {
+	queryEmbeddings := options.QueryEmbeddings
+	if len(queryEmbeddings) == 0 {
+		var err error
+		if queryEmbeddings, err = embedFederatedQuery(ctx, collections, queryText); err != nil {
+			return nil, err
+		}
+	}
+	if len(queryEmbeddings) != 1 {
+		return nil, fmt.Errorf("%w: federated query takes one query embedding, got %d", ErrInvalidParameter, len(queryEmbeddings))
+	}
+
+	parallelism := options.FederatedParallelism
+	if parallelism <= 0 {
+		parallelism = DefaultFederatedParallelism
+	}
+	distance := collections[0].distance
+
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, parallelism)
+		results = make([]*QueryResult, len(collections))
+		errs    = make([]error, len(collections))
+	)
+	for i, collection := range collections {
+		if collection.distance.HigherIsBetter() != distance.HigherIsBetter() {
+			errs[i] = fmt.Errorf("collection %s: %w: distance %s is not comparable with %s",
+				collection.name, ErrInvalidParameter, collection.distance, distance)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, collection *Collection) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			collectionOptions := *options
+			collectionOptions.QueryEmbeddings = queryEmbeddings
+			collectionOptions.vectorsOnly = collection.vectorsOnly
+			// Hits are merged by distance, so it is selected even if not included
+			collectionOptions.Include = includeDistances(options.Include)
+			result, err := collection.client.collectionQuery(ctx, collection.name, nil, nResults, &collectionOptions, collection.embeddingFunc, collection.distance)
+			if err != nil {
+				errs[i] = fmt.Errorf("collection %s: %w", collection.name, err)
+				return
+			}
+			tagQueryResultSource(result, collection.name)
+			results[i] = result
+		}(i, collection)
+	}
+	wg.Wait()
+
+	var succeeded []*QueryResult
+	for _, result := range results {
+		if result != nil {
+			succeeded = append(succeeded, result)
+		}
+	}
+	if len(succeeded) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	merged := MergeQueryResults(nResults, distance, succeeded...)
+	if !options.include().distances {
+		merged.Distances = nil
+	}
+	return merged, errors.Join(errs...)
+}
+
+// embedFederatedQuery embeds the query text with the first embedding function
+// among collections.
+func embedFederatedQuery(ctx context.Context, collections []*Collection, queryText string) ([][]float32, error) {
+	for _, collection := range collections {
+		if collection.embeddingFunc == nil {
+			continue
+		}
+		embeddings, err := embedding.EmbedWithContext(ctx, collection.embeddingFunc, []string{queryText})
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed query: %w", err)
+		}
+		return embeddings, nil
+	}
+	return nil, fmt.Errorf("%w: no collection has an embedding function; use WithQueryEmbeddings", ErrEmbeddingFunctionRequired)
+}
+
+// tagQueryResultSource sets FederatedSourceKey in the metadata of every hit in result.
+func tagQueryResultSource(result *QueryResult, collectionName string) {
+	for q := range result.IDs {
+		for len(result.Metadatas) <= q {
+			result.Metadatas = append(result.Metadatas, nil)
+		}
+		for len(result.Metadatas[q]) < len(result.IDs[q]) {
+			result.Metadatas[q] = append(result.Metadatas[q], nil)
+		}
+		for i, metadata := range result.Metadatas[q] {
+			if metadata == nil {
+				metadata = Metadata{}
+				result.Metadatas[q][i] = metadata
+			}
+			metadata[FederatedSourceKey] = collectionName
+		}
+	}
+}
+
+// MergeQueryResults merges query results for the same query embeddings, e.g. from
+// several collections, into one result keeping the nResults best hits per query.
+// Hits are ranked by distance in the direction of the given metric (ascending, or
+// descending if it is HigherIsBetter); ties keep the order of results. IDs are not
+// deduplicated, since equal IDs in different collections are different documents.
+func MergeQueryResults(nResults int, distance DistanceMetric, results ...*QueryResult) *QueryResult {
+	merged := &QueryResult{}
+
+	queries := 0
+	hasRawColumns := false
+	for _, result := range results {
+		if result != nil && len(result.IDs) > queries {
+			queries = len(result.IDs)
+		}
+		if result != nil && result.RawColumns != nil {
+			hasRawColumns = true
+		}
+	}
+
+	for q := 0; q < queries; q++ {
+		type hit struct {
+			result *QueryResult
+			index  int
+		}
+		var hits []hit
+		for _, result := range results {
+			if result == nil || q >= len(result.IDs) {
+				continue
+			}
+			for i := range result.IDs[q] {
+				hits = append(hits, hit{result: result, index: i})
+			}
+		}
+
+		distanceOf := func(h hit) float64 {
+			if q < len(h.result.Distances) && h.index < len(h.result.Distances[q]) {
+				return h.result.Distances[q][h.index]
+			}
+			return 0
+		}
+		sort.SliceStable(hits, func(i, j int) bool {
+			if distance.HigherIsBetter() {
+				return distanceOf(hits[i]) > distanceOf(hits[j])
+			}
+			return distanceOf(hits[i]) < distanceOf(hits[j])
+		})
+		if nResults > 0 && len(hits) > nResults {
+			hits = hits[:nResults]
+		}
+
+		ids := make([]string, 0, len(hits))
+		distances := make([]float64, 0, len(hits))
+		documents := make([]string, 0, len(hits))
+		metadatas := make([]Metadata, 0, len(hits))
+		embeddings := make([][]float32, 0, len(hits))
+		var rawColumns []map[string]interface{}
+		for _, h := range hits {
+			r, i := h.result, h.index
+			ids = append(ids, r.IDs[q][i])
+			distances = append(distances, distanceOf(h))
+
+			var document string
+			if q < len(r.Documents) && i < len(r.Documents[q]) {
+				document = r.Documents[q][i]
+			}
+			documents = append(documents, document)
+
+			var metadata Metadata
+			if q < len(r.Metadatas) && i < len(r.Metadatas[q]) {
+				metadata = r.Metadatas[q][i]
+			}
+			metadatas = append(metadatas, metadata)
+
+			var emb []float32
+			if q < len(r.Embeddings) && i < len(r.Embeddings[q]) {
+				emb = r.Embeddings[q][i]
+			}
+			embeddings = append(embeddings, emb)
+
+			if hasRawColumns {
+				var raw map[string]interface{}
+				if q < len(r.RawColumns) && i < len(r.RawColumns[q]) {
+					raw = r.RawColumns[q][i]
+				}
+				rawColumns = append(rawColumns, raw)
+			}
+		}
+
+		merged.IDs = append(merged.IDs, ids)
+		merged.Distances = append(merged.Distances, distances)
+		merged.Documents = append(merged.Documents, documents)
+		merged.Metadatas = append(merged.Metadatas, metadatas)
+		merged.Embeddings = append(merged.Embeddings, embeddings)
+		if hasRawColumns {
+			merged.RawColumns = append(merged.RawColumns, rawColumns)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,100 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCollectionUnavailable = errors.New("collection unavailable")
+
+// failingQueryOperations is a fakeOperations whose queries fail
+type failingQueryOperations struct {
+	fakeOperations
+}
+
+func (f *failingQueryOperations) collectionQuery(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*QueryResult, error) {
+	return nil, errCollectionUnavailable
+}
+
+// newFederatedCollection returns a collection over an in-memory store holding
+// one row per document, embedded with lengthEmbedder
+func newFederatedCollection(t *testing.T, name string, documents ...string) *Collection {
+	collection := &Collection{client: &fakeOperations{}, name: name, dimension: 3, distance: DistanceL2, embeddingFunc: lengthEmbedder{}}
+	ids := make([]string, len(documents))
+	for i := range documents {
+		ids[i] = name + "-" + documents[i]
+	}
+	embeddings, err := lengthEmbedder{}.Embed(documents)
+	require.NoError(t, err)
+	require.NoError(t, collection.Add(context.Background(), ids, documents, WithEmbeddings(embeddings)))
+	return collection
+}
+
+// TestFederatedQuery tests fusing the results of several collections
+func TestFederatedQuery(t *testing.T) {
+	ctx := context.Background()
+	docs := newFederatedCollection(t, "docs", "a", "abcd", "abcdefgh")
+	faq := newFederatedCollection(t, "faq", "ab", "abcdefghijklmnop")
+
+	// The query "abc" embeds to length 3: nearest are abcd (1), ab (1), a (2)
+	result, err := federatedQuery(ctx, []*Collection{docs, faq}, "abc", 3, &QueryOptions{FederatedParallelism: 1})
+	require.NoError(t, err)
+	require.Len(t, result.IDs, 1)
+	assert.Equal(t, []string{"docs-abcd", "faq-ab", "docs-a"}, result.IDs[0])
+	assert.Equal(t, []float64{1, 1, 2}, result.Distances[0])
+	assert.Equal(t, []string{"abcd", "ab", "a"}, result.Documents[0])
+
+	sources := make([]interface{}, len(result.Metadatas[0]))
+	for i, metadata := range result.Metadatas[0] {
+		sources[i] = metadata[FederatedSourceKey]
+	}
+	assert.Equal(t, []interface{}{"docs", "faq", "docs"}, sources)
+
+	t.Run("failed collections are reported with the other results", func(t *testing.T) {
+		broken := &Collection{client: &failingQueryOperations{}, name: "broken", distance: DistanceL2}
+		result, err := federatedQuery(ctx, []*Collection{docs, broken, faq}, "abc", 2, &QueryOptions{})
+		require.ErrorIs(t, err, errCollectionUnavailable)
+		assert.Contains(t, err.Error(), "collection broken")
+		assert.Equal(t, []string{"docs-abcd", "faq-ab"}, result.IDs[0])
+
+		result, err = federatedQuery(ctx, []*Collection{broken}, "abc", 2, &QueryOptions{QueryEmbeddings: [][]float32{{3, 0, 0}}})
+		require.ErrorIs(t, err, errCollectionUnavailable)
+		assert.Nil(t, result)
+	})
+
+	t.Run("collections ranking the other way are skipped", func(t *testing.T) {
+		similarity := &Collection{client: &fakeOperations{}, name: "ip", distance: DistanceInnerProduct}
+		result, err := federatedQuery(ctx, []*Collection{docs, similarity}, "abc", 1, &QueryOptions{})
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		assert.Equal(t, []string{"docs-abcd"}, result.IDs[0])
+	})
+
+	t.Run("query is embedded with the first embedding function", func(t *testing.T) {
+		bare := &Collection{client: &fakeOperations{}, name: "bare", distance: DistanceL2}
+		_, err := federatedQuery(ctx, []*Collection{bare}, "abc", 1, &QueryOptions{})
+		assert.ErrorIs(t, err, ErrEmbeddingFunctionRequired)
+
+		result, err := federatedQuery(ctx, []*Collection{bare, docs}, "abc", 1, &QueryOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docs-abcd"}, result.IDs[0])
+	})
+}
+
+// TestMergeQueryResults tests ranking merged hits in the metric's direction
+func TestMergeQueryResults(t *testing.T) {
+	a := &QueryResult{IDs: [][]string{{"a1", "a2"}}, Distances: [][]float64{{0.9, 0.1}}}
+	b := &QueryResult{IDs: [][]string{{"b1"}}, Distances: [][]float64{{0.5}}}
+
+	merged := MergeQueryResults(2, DistanceInnerProduct, a, b)
+	assert.Equal(t, [][]string{{"a1", "b1"}}, merged.IDs)
+	assert.Equal(t, [][]float64{{0.9, 0.5}}, merged.Distances)
+	assert.Equal(t, [][]string{{"", ""}}, merged.Documents)
+
+	merged = MergeQueryResults(0, DistanceL2, a, nil, b)
+	assert.Equal(t, [][]string{{"a2", "b1", "a1"}}, merged.IDs)
+}
@@ -0,0 +1,97 @@
+package goseekdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// buildMetadataArrayCondition builds the SQL condition for the $contains and $all
+// metadata operators, which match array-valued metadata such as
+// {"tags": ["ml", "nlp"]}:
+//
+//   - {"tags": {"$contains": "nlp"}} matches documents whose tags contain "nlp".
+//   - {"tags": {"$contains": ["nlp", "cv"]}} matches tags containing any of the values.
+//   - {"tags": {"$all": ["ml", "nlp"]}} matches tags containing all of the values.
+//
+// A scalar metadata value is treated like an array of one element. Unlike the document
+// $contains operator, which is a full-text search, values are compared as JSON values.
+func buildMetadataArrayCondition(key, op string, value interface{}) (string, []interface{}, error) {
+	path := "$." + key
+	contains := fmt.Sprintf("JSON_CONTAINS(JSON_EXTRACT(%s, ?), ?)", FieldMetadata)
+
+	values, isList := arrayOperandValues(value)
+	switch op {
+	case "$contains":
+		if !isList {
+			candidate, err := marshalArrayOperand(key, op, value)
+			if err != nil {
+				return "", nil, err
+			}
+			return contains, []interface{}{path, candidate}, nil
+		}
+		if len(values) == 0 {
+			return "", nil, fmt.Errorf("%w: $contains on metadata key %q requires at least one value", ErrInvalidParameter, key)
+		}
+		conditions := make([]string, len(values))
+		args := make([]interface{}, 0, 2*len(values))
+		for i, v := range values {
+			candidate, err := marshalArrayOperand(key, op, v)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions[i] = contains
+			args = append(args, path, candidate)
+		}
+		if len(conditions) == 1 {
+			return conditions[0], args, nil
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+
+	case "$all":
+		if !isList || len(values) == 0 {
+			return "", nil, fmt.Errorf("%w: $all on metadata key %q requires a non-empty list, got %v", ErrInvalidParameter, key, value)
+		}
+		for _, v := range values {
+			if !isFilterScalar(v) {
+				return "", nil, fmt.Errorf("%w: $all on metadata key %q requires scalar values, got %T", ErrInvalidParameter, key, v)
+			}
+		}
+		// JSON_CONTAINS with an array candidate requires every element to be present
+		candidate, err := json.Marshal(values)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: $all on metadata key %q: %v", ErrInvalidParameter, key, err)
+		}
+		return contains, []interface{}{path, string(candidate)}, nil
+
+	default:
+		return "", nil, fmt.Errorf("%w: unsupported array operator %s", ErrInvalidParameter, op)
+	}
+}
+
+// arrayOperandValues returns the elements of value if it is a list.
+func arrayOperandValues(value interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, true
+}
+
+// marshalArrayOperand encodes a scalar operand as the JSON document JSON_CONTAINS
+// looks for.
+func marshalArrayOperand(key, op string, value interface{}) (string, error) {
+	if value == nil || !isFilterScalar(value) {
+		return "", fmt.Errorf("%w: %s on metadata key %q requires a string, number or boolean, got %T", ErrInvalidParameter, op, key, value)
+	}
+	candidate, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s on metadata key %q: %v", ErrInvalidParameter, op, key, err)
+	}
+	return string(candidate), nil
+}
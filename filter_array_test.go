@@ -0,0 +1,92 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildMetadataArrayCondition tests the SQL generated for $contains and $all
+func TestBuildMetadataArrayCondition(t *testing.T) {
+	clause, args, err := buildMetadataArrayCondition("tags", "$contains", "nlp")
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_CONTAINS(JSON_EXTRACT(metadata, ?), ?)", clause)
+	assert.Equal(t, []interface{}{"$.tags", `"nlp"`}, args)
+
+	clause, args, err = buildMetadataArrayCondition("tags", "$contains", []string{"nlp", "cv"})
+	require.NoError(t, err)
+	assert.Equal(t, "(JSON_CONTAINS(JSON_EXTRACT(metadata, ?), ?) OR JSON_CONTAINS(JSON_EXTRACT(metadata, ?), ?))", clause)
+	assert.Equal(t, []interface{}{"$.tags", `"nlp"`, "$.tags", `"cv"`}, args)
+
+	clause, args, err = buildMetadataArrayCondition("years", "$all", []interface{}{2023, 2024})
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_CONTAINS(JSON_EXTRACT(metadata, ?), ?)", clause)
+	assert.Equal(t, []interface{}{"$.years", "[2023,2024]"}, args)
+
+	for _, tc := range []struct {
+		op    string
+		value interface{}
+	}{
+		{"$contains", []string{}},
+		{"$contains", nil},
+		{"$contains", map[string]interface{}{"a": 1}},
+		{"$contains", []interface{}{[]string{"nested"}}},
+		{"$all", "nlp"},
+		{"$all", []string{}},
+		{"$all", []interface{}{map[string]interface{}{"a": 1}}},
+	} {
+		_, _, err := buildMetadataArrayCondition("tags", tc.op, tc.value)
+		assert.ErrorIs(t, err, ErrInvalidParameter, "%s %v", tc.op, tc.value)
+	}
+
+	// The Query and Get filter builder
+	clause, args, err = NewFilterBuilder().BuildMetadataFilter(Filter{"tags": Filter{"$all": []string{"ml"}, "$contains": "nlp"}})
+	require.NoError(t, err)
+	assert.Equal(t, "(JSON_CONTAINS(JSON_EXTRACT(metadata, ?), ?)) AND (JSON_CONTAINS(JSON_EXTRACT(metadata, ?), ?))", clause)
+	assert.Equal(t, []interface{}{"$.tags", `["ml"]`, "$.tags", `"nlp"`}, args)
+}
+
+// TestCollectionGetArrayMetadataFilters tests $contains and $all on overlapping tag arrays
+func TestCollectionGetArrayMetadataFilters(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_array_filters_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3", "id4"}, []string{"doc 1", "doc 2", "doc 3", "doc 4"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{
+			{"tags": []string{"ml", "nlp"}},
+			{"tags": []string{"nlp", "cv"}},
+			{"tags": []string{"cv"}},
+			{"tags": "nlp"},
+		}),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		where Filter
+		ids   []string
+	}{
+		{"contains", Filter{"tags": Filter{"$contains": "nlp"}}, []string{"id1", "id2", "id4"}},
+		{"contains any", Filter{"tags": Filter{"$contains": []string{"ml", "cv"}}}, []string{"id1", "id2", "id3"}},
+		{"all", Filter{"tags": Filter{"$all": []string{"nlp", "cv"}}}, []string{"id2"}},
+		{"negated", Filter{"$not": Filter{"tags": Filter{"$contains": "cv"}}}, []string{"id1", "id4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := collection.Get(ctx, nil, WithGetWhere(tt.where))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.ids, results.IDs)
+		})
+	}
+}
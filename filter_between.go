@@ -0,0 +1,59 @@
+package goseekdb
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// buildMetadataBetweenCondition builds the SQL condition for the $between metadata
+// operator. {"score": {"$between": [80, 90]}} is an inclusive range, equivalent to
+// {"score": {"$gte": 80, "$lte": 90}}.
+func buildMetadataBetweenCondition(key string, value interface{}) (string, []interface{}, error) {
+	low, high, err := parseBetweenRange(key, value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("JSON_EXTRACT(%s, ?) BETWEEN ? AND ?", FieldMetadata), []interface{}{"$." + key, low, high}, nil
+}
+
+// parseBetweenRange returns the bounds of a $between operand, which must be a list of
+// two numbers or two strings with the lower bound first.
+func parseBetweenRange(key string, value interface{}) (low, high interface{}, err error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Len() != 2 {
+		return nil, nil, fmt.Errorf("%w: $between on metadata key %q requires a list of two bounds, got %v", ErrInvalidParameter, key, value)
+	}
+	low, high = v.Index(0).Interface(), v.Index(1).Interface()
+
+	if lowNum, ok := betweenNumber(low); ok {
+		if highNum, ok := betweenNumber(high); ok {
+			if lowNum > highNum {
+				return nil, nil, fmt.Errorf("%w: $between on metadata key %q has lower bound %v above upper bound %v", ErrInvalidParameter, key, low, high)
+			}
+			return low, high, nil
+		}
+	}
+	if lowStr, ok := low.(string); ok {
+		if highStr, ok := high.(string); ok {
+			if lowStr > highStr {
+				return nil, nil, fmt.Errorf("%w: $between on metadata key %q has lower bound %q above upper bound %q", ErrInvalidParameter, key, lowStr, highStr)
+			}
+			return low, high, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: $between on metadata key %q requires two numbers or two strings, got %T and %T", ErrInvalidParameter, key, low, high)
+}
+
+// betweenNumber returns value as a float64 if it is of a numeric type.
+func betweenNumber(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
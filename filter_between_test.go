@@ -0,0 +1,85 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildMetadataBetweenCondition tests the SQL generated for $between
+func TestBuildMetadataBetweenCondition(t *testing.T) {
+	clause, args, err := buildMetadataBetweenCondition("score", []interface{}{80, 90})
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_EXTRACT(metadata, ?) BETWEEN ? AND ?", clause)
+	assert.Equal(t, []interface{}{"$.score", 80, 90}, args)
+
+	_, args, err = buildMetadataBetweenCondition("date", []string{"2024-01-01", "2024-12-31"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"$.date", "2024-01-01", "2024-12-31"}, args)
+
+	_, _, err = buildMetadataBetweenCondition("score", []interface{}{0.5, int64(1)})
+	assert.NoError(t, err)
+
+	for _, value := range []interface{}{
+		[]interface{}{80},
+		[]interface{}{80, 90, 100},
+		[]interface{}{90, 80},
+		[]interface{}{"b", "a"},
+		[]interface{}{80, "90"},
+		[]interface{}{true, false},
+		80,
+		nil,
+	} {
+		_, _, err := buildMetadataBetweenCondition("score", value)
+		assert.ErrorIs(t, err, ErrInvalidParameter, "%v", value)
+	}
+
+	// The Query and Get filter builder
+	clause, args, err = NewFilterBuilder().BuildMetadataFilter(Filter{"score": Filter{"$between": []int{80, 90}}})
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_EXTRACT(metadata, ?) BETWEEN ? AND ?", clause)
+	assert.Equal(t, []interface{}{"$.score", 80, 90}, args)
+
+	_, _, err = NewFilterBuilder().BuildMetadataFilter(Filter{"score": Filter{"$between": []int{90, 80}}})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestHybridSearchBetweenFilter tests $between as a gte/lte range in search_parm filters
+func TestHybridSearchBetweenFilter(t *testing.T) {
+	c := &Client{}
+	field := "(JSON_EXTRACT(metadata, '$.score'))"
+
+	conditions := c.buildMetadataFilterConditions(Filter{"score": Filter{"$between": []interface{}{80, 90}}})
+	assert.Equal(t, []map[string]interface{}{
+		{"range": map[string]interface{}{field: map[string]interface{}{"gte": 80, "lte": 90}}},
+	}, conditions)
+
+	assert.Empty(t, c.buildMetadataFilterConditions(Filter{"score": Filter{"$between": []interface{}{90, 80}}}))
+}
+
+// TestCollectionGetBetweenFilter tests filtering on an inclusive range
+func TestCollectionGetBetweenFilter(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_between_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2", "id3", "id4"}, []string{"doc 1", "doc 2", "doc 3", "doc 4"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"score": 79}, {"score": 80}, {"score": 90}, {"score": 91}}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, nil, WithGetWhere(Filter{"score": Filter{"$between": []interface{}{80, 90}}}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id2", "id3"}, results.IDs)
+}
@@ -0,0 +1,23 @@
+package goseekdb
+
+import "fmt"
+
+// buildMetadataExistsCondition builds the SQL condition for the $exists and $nexists
+// metadata operators. {"key": {"$exists": true}} matches documents whose metadata has
+// key (even if its value is JSON null); {"$exists": false} and {"$nexists": true} match
+// documents without it.
+func buildMetadataExistsCondition(key, op string, value interface{}) (string, []interface{}, error) {
+	exists, ok := value.(bool)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s on metadata key %q requires a boolean, got %T", ErrInvalidParameter, op, key, value)
+	}
+	if op == "$nexists" {
+		exists = !exists
+	}
+
+	check := "IS NULL"
+	if exists {
+		check = "IS NOT NULL"
+	}
+	return fmt.Sprintf("JSON_EXTRACT(%s, ?) %s", FieldMetadata, check), []interface{}{"$." + key}, nil
+}
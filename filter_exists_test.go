@@ -0,0 +1,84 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildMetadataExistsCondition tests the SQL generated for $exists and $nexists
+func TestBuildMetadataExistsCondition(t *testing.T) {
+	clause, args, err := buildMetadataExistsCondition("color", "$exists", true)
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_EXTRACT(metadata, ?) IS NOT NULL", clause)
+	assert.Equal(t, []interface{}{"$.color"}, args)
+
+	clause, _, err = buildMetadataExistsCondition("color", "$exists", false)
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_EXTRACT(metadata, ?) IS NULL", clause)
+
+	clause, _, err = buildMetadataExistsCondition("color", "$nexists", true)
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_EXTRACT(metadata, ?) IS NULL", clause)
+
+	_, _, err = buildMetadataExistsCondition("color", "$exists", "yes")
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestFilterBuilderExists tests that metadata filters build $exists and $nexists
+func TestFilterBuilderExists(t *testing.T) {
+	b := NewFilterBuilder()
+	clause, args, err := b.BuildMetadataFilter(Filter{"color": Filter{"$exists": true}, "size": Filter{"$nexists": true}})
+	require.NoError(t, err)
+	assert.Equal(t, "(JSON_EXTRACT(metadata, ?) IS NOT NULL) AND (JSON_EXTRACT(metadata, ?) IS NULL)", clause)
+	assert.Equal(t, []interface{}{"$.color", "$.size"}, args)
+
+	_, _, err = b.BuildMetadataFilter(Filter{"color": Filter{"$exists": "yes"}})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestHybridSearchExistsFilter tests $exists and $nexists in search_parm filters
+func TestHybridSearchExistsFilter(t *testing.T) {
+	c := &Client{}
+	exists := map[string]interface{}{"exists": map[string]interface{}{"field": "(JSON_EXTRACT(metadata, '$.color'))"}}
+	notExists := map[string]interface{}{"bool": map[string]interface{}{"must_not": []map[string]interface{}{exists}}}
+
+	assert.Equal(t, []map[string]interface{}{exists}, c.buildMetadataFilterConditions(Filter{"color": Filter{"$exists": true}}))
+	assert.Equal(t, []map[string]interface{}{notExists}, c.buildMetadataFilterConditions(Filter{"color": Filter{"$exists": false}}))
+	assert.Equal(t, []map[string]interface{}{notExists}, c.buildMetadataFilterConditions(Filter{"color": Filter{"$nexists": true}}))
+}
+
+// TestCollectionGetExistsFilter tests filtering on keys present in some documents only
+func TestCollectionGetExistsFilter(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_exists_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1", "doc 2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}),
+		WithMetadatas([]Metadata{{"color": "red"}, {"size": 2}, {"color": "blue", "size": 3}}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, nil, WithGetWhere(Filter{"color": Filter{"$exists": true}}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id3"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetWhere(Filter{"color": Filter{"$exists": false}}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id2"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetWhere(Filter{"size": Filter{"$nexists": true}}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1"}, results.IDs)
+}
@@ -0,0 +1,34 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// buildDocumentLikeCondition builds the SQL condition for the $like and $ilike
+// document operators. Unlike $contains, which goes through the full-text index and
+// matches whole tokens, these do a plain SQL LIKE scan of the document text:
+//
+//   - A value containing % or _ is used verbatim as a LIKE pattern ("%learn%", "go_").
+//   - Any other value matches as a literal substring ("learn" behaves like "%learn%").
+//
+// $ilike always compares case-insensitively; for $like, case sensitivity follows the
+// collation of the document column.
+func buildDocumentLikeCondition(op string, value interface{}) (string, []interface{}, error) {
+	pattern, ok := value.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s requires a string, got %T", ErrInvalidParameter, op, value)
+	}
+	if !strings.ContainsAny(pattern, "%_") {
+		pattern = "%" + strings.ReplaceAll(pattern, `\`, `\\`) + "%"
+	}
+
+	switch op {
+	case "$like":
+		return fmt.Sprintf("%s LIKE ?", FieldDocument), []interface{}{pattern}, nil
+	case "$ilike":
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(?)", FieldDocument), []interface{}{pattern}, nil
+	default:
+		return "", nil, fmt.Errorf("%w: unsupported document operator %s", ErrInvalidParameter, op)
+	}
+}
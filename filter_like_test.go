@@ -0,0 +1,79 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildDocumentLikeCondition tests the SQL generated for $like and $ilike
+func TestBuildDocumentLikeCondition(t *testing.T) {
+	clause, args, err := buildDocumentLikeCondition("$like", "%learn%")
+	require.NoError(t, err)
+	assert.Equal(t, "document LIKE ?", clause)
+	assert.Equal(t, []interface{}{"%learn%"}, args)
+
+	clause, args, err = buildDocumentLikeCondition("$ilike", "Learn")
+	require.NoError(t, err)
+	assert.Equal(t, "LOWER(document) LIKE LOWER(?)", clause)
+	assert.Equal(t, []interface{}{"%Learn%"}, args)
+
+	_, args, err = buildDocumentLikeCondition("$like", `C:\docs`)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{`%C:\\docs%`}, args)
+
+	_, _, err = buildDocumentLikeCondition("$like", 42)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestFilterBuilderLike tests that document filters build $like and $ilike
+func TestFilterBuilderLike(t *testing.T) {
+	b := NewFilterBuilder()
+	clause, args, err := b.BuildDocumentFilter(Filter{"$or": []Filter{{"$like": "go_"}, {"$ilike": "Learn"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "(document LIKE ?) OR (LOWER(document) LIKE LOWER(?))", clause)
+	assert.Equal(t, []interface{}{"go_", "%Learn%"}, args)
+
+	_, _, err = b.BuildDocumentFilter(Filter{"$ilike": 42})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestCollectionGetLikeFilter tests $like and $ilike document filters
+func TestCollectionGetLikeFilter(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_like_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2", "id3"},
+		[]string{"Machine learning basics", "Deep Learning", "Python tutorial"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$like": "%learn%"}))
+	require.NoError(t, err)
+	assert.Contains(t, results.IDs, "id1")
+	assert.NotContains(t, results.IDs, "id3")
+
+	results, err = collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$like": "% learn%"}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$ilike": "%learn%"}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2"}, results.IDs)
+
+	results, err = collection.Get(ctx, nil, WithGetWhereDocument(Filter{"$ilike": "python"}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id3"}, results.IDs)
+}
@@ -0,0 +1,131 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// metadataConditionBuilder builds the SQL condition for a single metadata key and
+// its value, e.g. "age" and {"$gt": 18}.
+type metadataConditionBuilder func(key string, value interface{}) (string, []interface{}, error)
+
+// buildLogicalMetadataCondition builds the SQL condition for a metadata filter,
+// resolving the $and, $or, $nor and $not operators recursively and passing every
+// other key to leaf. It follows the semantics of hybrid search filters: $and, $or
+// and $nor take a list of filters, $not takes one filter, and multiple keys in one
+// filter are combined with AND; $nor matches when none of its filters does. Every
+// operand is parenthesized, so nesting is preserved whatever the leaf conditions
+// contain. Keys are visited in sorted order, which keeps the SQL and its arguments
+// deterministic.
+func buildLogicalMetadataCondition(filter Filter, leaf metadataConditionBuilder) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	for _, key := range sortedFilterKeys(filter) {
+		value := filter[key]
+
+		var clause string
+		var clauseArgs []interface{}
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			clause, clauseArgs, err = buildLogicalMetadataList(key, value, leaf)
+		case "$not":
+			sub, ok := asFilter(value)
+			if !ok {
+				return "", nil, fmt.Errorf("%w: $not requires a filter, got %T", ErrInvalidParameter, value)
+			}
+			clause, clauseArgs, err = buildLogicalMetadataCondition(sub, leaf)
+			if clause != "" {
+				clause = "NOT (" + clause + ")"
+			}
+		default:
+			clause, clauseArgs, err = leaf(key, value)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, clauseArgs...)
+		}
+	}
+
+	return joinConditions(conditions, "AND"), args, nil
+}
+
+// buildLogicalMetadataList builds the condition for an $and, $or or $nor list of
+// filters. An empty list adds no condition.
+func buildLogicalMetadataList(op string, value interface{}, leaf metadataConditionBuilder) (string, []interface{}, error) {
+	subFilters, ok := asFilterList(value)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s requires a list of filters, got %T", ErrInvalidParameter, op, value)
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, sub := range subFilters {
+		clause, clauseArgs, err := buildLogicalMetadataCondition(sub, leaf)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, clauseArgs...)
+		}
+	}
+
+	switch op {
+	case "$or":
+		return joinConditions(conditions, "OR"), args, nil
+	case "$nor":
+		if len(conditions) == 0 {
+			return "", nil, nil
+		}
+		return "NOT (" + joinConditions(conditions, "OR") + ")", args, nil
+	}
+	return joinConditions(conditions, "AND"), args, nil
+}
+
+// joinConditions joins conditions with the operator, parenthesizing each operand.
+func joinConditions(conditions []string, operator string) string {
+	if len(conditions) <= 1 {
+		return strings.Join(conditions, "")
+	}
+	return "(" + strings.Join(conditions, ") "+operator+" (") + ")"
+}
+
+// asFilter returns value as a Filter if it is a filter map.
+func asFilter(value interface{}) (Filter, bool) {
+	switch v := value.(type) {
+	case Filter:
+		return v, true
+	case map[string]interface{}:
+		return Filter(v), true
+	}
+	return nil, false
+}
+
+// asFilterList returns value as a list of filters if it is one.
+func asFilterList(value interface{}) ([]Filter, bool) {
+	switch v := value.(type) {
+	case []Filter:
+		return v, true
+	case []map[string]interface{}:
+		filters := make([]Filter, len(v))
+		for i, m := range v {
+			filters[i] = Filter(m)
+		}
+		return filters, true
+	case []interface{}:
+		filters := make([]Filter, len(v))
+		for i, item := range v {
+			filter, ok := asFilter(item)
+			if !ok {
+				return nil, false
+			}
+			filters[i] = filter
+		}
+		return filters, true
+	}
+	return nil, false
+}
@@ -0,0 +1,165 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// comparisonLeaf builds plain comparisons for testing the logical operators
+func comparisonLeaf(key string, value interface{}) (string, []interface{}, error) {
+	ops, ok := asFilter(value)
+	if !ok {
+		return "JSON_EXTRACT(metadata, ?) = ?", []interface{}{"$." + key, value}, nil
+	}
+	symbols := map[string]string{"$eq": "=", "$ne": "!=", "$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}
+	var conditions []string
+	var args []interface{}
+	for _, op := range []string{"$eq", "$ne", "$gt", "$gte", "$lt", "$lte"} {
+		if v, ok := ops[op]; ok {
+			conditions = append(conditions, fmt.Sprintf("JSON_EXTRACT(metadata, ?) %s ?", symbols[op]))
+			args = append(args, "$."+key, v)
+		}
+	}
+	return joinConditions(conditions, "AND"), args, nil
+}
+
+// TestBuildLogicalMetadataCondition tests nesting of $and, $or and $not
+func TestBuildLogicalMetadataCondition(t *testing.T) {
+	t.Run("nested $or inside $and", func(t *testing.T) {
+		filter := Filter{"$and": []interface{}{
+			map[string]interface{}{"category": "AI"},
+			map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"score": map[string]interface{}{"$gte": 90}},
+				map[string]interface{}{"year": Filter{"$gt": 2020, "$lt": 2024}},
+			}},
+		}}
+		clause, args, err := buildLogicalMetadataCondition(filter, comparisonLeaf)
+		require.NoError(t, err)
+		assert.Equal(t, "(JSON_EXTRACT(metadata, ?) = ?) AND "+
+			"((JSON_EXTRACT(metadata, ?) >= ?) OR ((JSON_EXTRACT(metadata, ?) > ?) AND (JSON_EXTRACT(metadata, ?) < ?)))", clause)
+		assert.Equal(t, []interface{}{"$.category", "AI", "$.score", 90, "$.year", 2020, "$.year", 2024}, args)
+	})
+
+	t.Run("$not negates a group", func(t *testing.T) {
+		filter := Filter{"$not": Filter{"$or": []Filter{{"a": 1}, {"b": 2}}}}
+		clause, args, err := buildLogicalMetadataCondition(filter, comparisonLeaf)
+		require.NoError(t, err)
+		assert.Equal(t, "NOT ((JSON_EXTRACT(metadata, ?) = ?) OR (JSON_EXTRACT(metadata, ?) = ?))", clause)
+		assert.Equal(t, []interface{}{"$.a", 1, "$.b", 2}, args)
+	})
+
+	t.Run("$nor negates the disjunction", func(t *testing.T) {
+		filter := Filter{"$nor": []interface{}{map[string]interface{}{"a": 1}, Filter{"b": Filter{"$lt": 2}}}}
+		clause, args, err := buildLogicalMetadataCondition(filter, comparisonLeaf)
+		require.NoError(t, err)
+		assert.Equal(t, "NOT ((JSON_EXTRACT(metadata, ?) = ?) OR (JSON_EXTRACT(metadata, ?) < ?))", clause)
+		assert.Equal(t, []interface{}{"$.a", 1, "$.b", 2}, args)
+
+		clause, _, err = buildLogicalMetadataCondition(Filter{"$nor": []Filter{}}, comparisonLeaf)
+		require.NoError(t, err)
+		assert.Empty(t, clause)
+	})
+
+	t.Run("top-level keys are combined with AND", func(t *testing.T) {
+		filter := Filter{"b": 2, "a": 1, "$or": []interface{}{map[string]interface{}{"c": 3}}}
+		clause, args, err := buildLogicalMetadataCondition(filter, comparisonLeaf)
+		require.NoError(t, err)
+		assert.Equal(t, "(JSON_EXTRACT(metadata, ?) = ?) AND (JSON_EXTRACT(metadata, ?) = ?) AND (JSON_EXTRACT(metadata, ?) = ?)", clause)
+		assert.Equal(t, []interface{}{"$.c", 3, "$.a", 1, "$.b", 2}, args)
+	})
+
+	t.Run("empty lists add no condition", func(t *testing.T) {
+		clause, args, err := buildLogicalMetadataCondition(Filter{"$and": []interface{}{}, "$or": []Filter{}}, comparisonLeaf)
+		require.NoError(t, err)
+		assert.Empty(t, clause)
+		assert.Empty(t, args)
+	})
+
+	t.Run("malformed operands", func(t *testing.T) {
+		_, _, err := buildLogicalMetadataCondition(Filter{"$and": Filter{"a": 1}}, comparisonLeaf)
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		_, _, err = buildLogicalMetadataCondition(Filter{"$or": []interface{}{"a"}}, comparisonLeaf)
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		_, _, err = buildLogicalMetadataCondition(Filter{"$not": []interface{}{}}, comparisonLeaf)
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+		_, _, err = buildLogicalMetadataCondition(Filter{"$nor": Filter{"a": 1}}, comparisonLeaf)
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+	})
+}
+
+// TestFilterBuilderLogical tests that the Query and Get filter builders resolve
+// logical operators like hybrid search filters
+func TestFilterBuilderLogical(t *testing.T) {
+	builder := NewFilterBuilder()
+
+	clause, args, err := builder.BuildMetadataFilter(Filter{
+		"$and": []interface{}{
+			map[string]interface{}{"category": "AI"},
+			map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"score": Filter{"$gte": 90}},
+				map[string]interface{}{"$not": Filter{"year": Filter{"$lt": 2020}}},
+			}},
+		},
+		"$nor": []Filter{{"tag": Filter{"$in": []interface{}{"x", "y"}}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "((JSON_EXTRACT(metadata, ?) = ?) AND ((JSON_EXTRACT(metadata, ?) >= ?) OR (NOT (JSON_EXTRACT(metadata, ?) < ?)))) AND "+
+		"(NOT (JSON_EXTRACT(metadata, ?) IN (?, ?)))", clause)
+	assert.Equal(t, []interface{}{"$.category", "AI", "$.score", 90, "$.year", 2020, "$.tag", "x", "y"}, args)
+
+	clause, args, err = builder.BuildDocumentFilter(Filter{"$not": Filter{"$regex": "^a"}})
+	require.NoError(t, err)
+	assert.Equal(t, "NOT (document REGEXP ?)", clause)
+	assert.Equal(t, []interface{}{"^a"}, args)
+}
+
+// TestCollectionLogicalFilters tests that Get and Query apply nested $and/$or like HybridSearch
+func TestCollectionLogicalFilters(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_logical_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2", "id3", "id4"}, []string{"doc 1", "doc 2", "doc 3", "doc 4"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{
+			{"category": "AI", "score": 95},
+			{"category": "AI", "score": 50, "year": 2022},
+			{"category": "DB", "score": 99},
+			{"category": "AI", "score": 10, "year": 2019},
+		}),
+	)
+	require.NoError(t, err)
+
+	where := Filter{"$and": []interface{}{
+		map[string]interface{}{"category": Filter{"$eq": "AI"}},
+		map[string]interface{}{"$or": []interface{}{
+			map[string]interface{}{"score": Filter{"$gte": 90}},
+			map[string]interface{}{"year": Filter{"$gt": 2020}},
+		}},
+	}}
+
+	results, err := collection.Get(ctx, nil, WithGetWhere(where))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2"}, results.IDs)
+
+	queryResults, err := collection.Query(ctx, nil, 10,
+		WithQueryEmbeddings([][]float32{{1, 2, 3}}), WithWhere(where))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2"}, queryResults.IDs[0])
+
+	results, err = collection.Get(ctx, nil, WithGetWhere(Filter{"$not": Filter{"category": Filter{"$eq": "AI"}}}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id3"}, results.IDs)
+}
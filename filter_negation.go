@@ -0,0 +1,51 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// buildMetadataNotEqualCondition builds the SQL condition for the $ne metadata
+// operator. A missing key is treated like a null value, as in hybrid search
+// filters, where $ne is a must_not term: {"category": {"$ne": "AI"}} matches
+// documents without a category, and {"category": {"$ne": nil}} matches documents
+// whose category is set to a non-null value.
+func buildMetadataNotEqualCondition(key string, value interface{}) (string, []interface{}, error) {
+	path := "$." + key
+	if value == nil {
+		return fmt.Sprintf("JSON_TYPE(JSON_EXTRACT(%s, ?)) <> 'NULL'", FieldMetadata), []interface{}{path}, nil
+	}
+	if !isFilterScalar(value) {
+		return "", nil, fmt.Errorf("%w: $ne on metadata key %q requires a scalar value, got %T", ErrInvalidParameter, key, value)
+	}
+	// Without the IS NULL check, "<>" against a missing key is NULL and the row is dropped
+	return fmt.Sprintf("(JSON_EXTRACT(%s, ?) IS NULL OR JSON_EXTRACT(%s, ?) <> ?)", FieldMetadata, FieldMetadata),
+		[]interface{}{path, path, value}, nil
+}
+
+// buildMetadataNotInCondition builds the SQL condition for the $nin metadata
+// operator. Like $ne, it matches documents without the key. An empty list excludes
+// nothing and adds no condition.
+func buildMetadataNotInCondition(key string, value interface{}) (string, []interface{}, error) {
+	values, ok := arrayOperandValues(value)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: $nin on metadata key %q requires a list, got %T", ErrInvalidParameter, key, value)
+	}
+	if len(values) == 0 {
+		return "", nil, nil
+	}
+
+	path := "$." + key
+	placeholders := make([]string, len(values))
+	args := []interface{}{path, path}
+	for i, v := range values {
+		// NOT IN with a NULL element is never true, which would silently match nothing
+		if v == nil || !isFilterScalar(v) {
+			return "", nil, fmt.Errorf("%w: $nin on metadata key %q requires non-null scalar values, got %v", ErrInvalidParameter, key, v)
+		}
+		placeholders[i] = "?"
+		args = append(args, v)
+	}
+	return fmt.Sprintf("(JSON_EXTRACT(%s, ?) IS NULL OR JSON_EXTRACT(%s, ?) NOT IN (%s))", FieldMetadata, FieldMetadata, strings.Join(placeholders, ", ")),
+		args, nil
+}
@@ -0,0 +1,111 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildMetadataNegatedConditions tests the SQL generated for $ne and $nin
+func TestBuildMetadataNegatedConditions(t *testing.T) {
+	clause, args, err := buildMetadataNotEqualCondition("category", "AI")
+	require.NoError(t, err)
+	assert.Equal(t, "(JSON_EXTRACT(metadata, ?) IS NULL OR JSON_EXTRACT(metadata, ?) <> ?)", clause)
+	assert.Equal(t, []interface{}{"$.category", "$.category", "AI"}, args)
+
+	clause, args, err = buildMetadataNotEqualCondition("category", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_TYPE(JSON_EXTRACT(metadata, ?)) <> 'NULL'", clause)
+	assert.Equal(t, []interface{}{"$.category"}, args)
+
+	clause, args, err = buildMetadataNotInCondition("tag", []string{"python", "neural"})
+	require.NoError(t, err)
+	assert.Equal(t, "(JSON_EXTRACT(metadata, ?) IS NULL OR JSON_EXTRACT(metadata, ?) NOT IN (?, ?))", clause)
+	assert.Equal(t, []interface{}{"$.tag", "$.tag", "python", "neural"}, args)
+
+	clause, args, err = buildMetadataNotInCondition("tag", []interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, clause)
+	assert.Empty(t, args)
+
+	_, _, err = buildMetadataNotEqualCondition("tag", []string{"a"})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	for _, value := range []interface{}{"python", nil, []interface{}{"a", nil}, []interface{}{map[string]interface{}{}}} {
+		_, _, err := buildMetadataNotInCondition("tag", value)
+		assert.ErrorIs(t, err, ErrInvalidParameter, "%v", value)
+	}
+
+	// The Query and Get filter builder
+	clause, args, err = NewFilterBuilder().BuildMetadataFilter(Filter{"category": Filter{"$ne": "AI"}, "tag": Filter{"$nin": []string{"python"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "((JSON_EXTRACT(metadata, ?) IS NULL OR JSON_EXTRACT(metadata, ?) <> ?)) AND "+
+		"((JSON_EXTRACT(metadata, ?) IS NULL OR JSON_EXTRACT(metadata, ?) NOT IN (?)))", clause)
+	assert.Equal(t, []interface{}{"$.category", "$.category", "AI", "$.tag", "$.tag", "python"}, args)
+}
+
+// TestHybridSearchInFilterListTypes tests that $in and $nin accept typed lists in search_parm filters
+func TestHybridSearchInFilterListTypes(t *testing.T) {
+	c := &Client{}
+	field := "(JSON_EXTRACT(metadata, '$.tag'))"
+
+	conditions := c.buildMetadataFilterConditions(Filter{"tag": Filter{"$nin": []string{"python"}}})
+	assert.Equal(t, []map[string]interface{}{
+		{"bool": map[string]interface{}{"must_not": []map[string]interface{}{{"term": map[string]interface{}{field: "python"}}}}},
+	}, conditions)
+
+	conditions = c.buildMetadataFilterConditions(Filter{"tag": Filter{"$in": []string{"python"}}})
+	assert.Equal(t, []map[string]interface{}{
+		{"bool": map[string]interface{}{"should": []map[string]interface{}{{"term": map[string]interface{}{field: "python"}}}}},
+	}, conditions)
+}
+
+// TestCollectionNegatedMetadataFilters tests that Query and Get exclude the same rows for $ne and $nin
+func TestCollectionNegatedMetadataFilters(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_negated_filters_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2", "id3", "id4"}, []string{"doc 1", "doc 2", "doc 3", "doc 4"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{
+			{"category": "AI", "tag": "python"},
+			{"category": "ML", "tag": "neural"},
+			{"category": "CV", "tag": "vision"},
+			{},
+		}),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		where Filter
+		ids   []string
+	}{
+		{"ne", Filter{"category": Filter{"$ne": "AI"}}, []string{"id2", "id3", "id4"}},
+		{"ne null", Filter{"category": Filter{"$ne": nil}}, []string{"id1", "id2", "id3"}},
+		{"nin", Filter{"tag": Filter{"$nin": []string{"python", "neural"}}}, []string{"id3", "id4"}},
+		{"nin combined", Filter{"tag": Filter{"$nin": []string{"python"}}, "category": Filter{"$ne": "CV"}}, []string{"id2", "id4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := collection.Get(ctx, nil, WithGetWhere(tt.where))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.ids, results.IDs)
+
+			queryResults, err := collection.Query(ctx, nil, 10,
+				WithQueryEmbeddings([][]float32{{1, 2, 3}}), WithWhere(tt.where))
+			require.NoError(t, err)
+			require.Len(t, queryResults.IDs, 1)
+			assert.ElementsMatch(t, tt.ids, queryResults.IDs[0])
+		})
+	}
+}
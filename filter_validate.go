@@ -0,0 +1,175 @@
+package goseekdb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Operators accepted in metadata filters, on a metadata key.
+var metadataFilterOperators = map[string]bool{
+	"$eq": true, "$ne": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$between": true, "$in": true, "$nin": true, "$exists": true, "$nexists": true,
+	"$contains": true, "$all": true,
+}
+
+// Operators accepted in document filters.
+var documentFilterOperators = map[string]bool{
+	"$contains": true, "$regex": true, "$like": true, "$ilike": true, "$exists": true,
+}
+
+// Validate checks that the filter is a well-formed metadata filter: every operator
+// is known and has an operand of the right shape, e.g. $in takes a list and $and a
+// list of filters. It returns an error wrapping ErrInvalidParameter that names the
+// offending operator, so that typos fail early instead of producing an empty
+// clause or an SQL error.
+func (f Filter) Validate() error {
+	for _, key := range sortedFilterKeys(f) {
+		value := f[key]
+		switch key {
+		case "$and", "$or", "$nor", "$not":
+			if err := validateLogicalOperand(key, value, Filter.Validate); err != nil {
+				return err
+			}
+		default:
+			if strings.HasPrefix(key, "$") {
+				return fmt.Errorf("%w: unknown metadata filter operator %s", ErrInvalidParameter, key)
+			}
+			if err := validateMetadataCondition(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateDocument checks that the filter is a well-formed document filter, like
+// Validate does for metadata filters.
+func (f Filter) ValidateDocument() error {
+	for _, key := range sortedFilterKeys(f) {
+		value := f[key]
+		switch key {
+		case "$and", "$or", "$nor", "$not":
+			if err := validateLogicalOperand(key, value, Filter.ValidateDocument); err != nil {
+				return err
+			}
+		case "$exists":
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%w: $exists on document requires a boolean, got %T", ErrInvalidParameter, value)
+			}
+		default:
+			if !documentFilterOperators[key] {
+				return fmt.Errorf("%w: unknown document filter operator %s", ErrInvalidParameter, key)
+			}
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%w: %s on document requires a string, got %T", ErrInvalidParameter, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// validateFilters checks the metadata and document filters of an operation before
+// any statement is built, so that a malformed filter fails with a descriptive error.
+func validateFilters(where, whereDocument Filter) error {
+	if err := where.Validate(); err != nil {
+		return err
+	}
+	return whereDocument.ValidateDocument()
+}
+
+// validateLogicalOperand checks the operand of $and, $or, $nor or $not, validating
+// the filters it contains with validate.
+func validateLogicalOperand(op string, value interface{}, validate func(Filter) error) error {
+	if op == "$not" {
+		sub, ok := asFilter(value)
+		if !ok {
+			return fmt.Errorf("%w: $not requires a filter, got %T", ErrInvalidParameter, value)
+		}
+		return validate(sub)
+	}
+
+	subFilters, ok := asFilterList(value)
+	if !ok {
+		return fmt.Errorf("%w: %s requires a list of filters, got %T", ErrInvalidParameter, op, value)
+	}
+	for _, sub := range subFilters {
+		if err := validate(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMetadataCondition checks the condition on a single metadata key: either a
+// plain value (equality) or a map of operators.
+func validateMetadataCondition(key string, value interface{}) error {
+	ops, ok := asFilter(value)
+	if !ok {
+		if !isFilterScalar(value) {
+			return fmt.Errorf("%w: metadata key %q must be compared with a scalar value or operators, got %T", ErrInvalidParameter, key, value)
+		}
+		return nil
+	}
+	if len(ops) == 0 {
+		return fmt.Errorf("%w: metadata key %q has an empty condition", ErrInvalidParameter, key)
+	}
+
+	for _, op := range sortedFilterKeys(ops) {
+		operand := ops[op]
+		if !metadataFilterOperators[op] {
+			return fmt.Errorf("%w: unknown operator %s on metadata key %q", ErrInvalidParameter, op, key)
+		}
+		switch op {
+		case "$in", "$nin":
+			if kind := reflect.ValueOf(operand).Kind(); kind != reflect.Slice && kind != reflect.Array {
+				return fmt.Errorf("%w: %s on metadata key %q requires a list, got %T", ErrInvalidParameter, op, key, operand)
+			}
+		case "$exists", "$nexists":
+			if _, ok := operand.(bool); !ok {
+				return fmt.Errorf("%w: %s on metadata key %q requires a boolean, got %T", ErrInvalidParameter, op, key, operand)
+			}
+		case "$between":
+			if _, _, err := parseBetweenRange(key, operand); err != nil {
+				return err
+			}
+		case "$contains", "$all":
+			if _, _, err := buildMetadataArrayCondition(key, op, operand); err != nil {
+				return err
+			}
+		default:
+			if !isFilterScalar(operand) {
+				return fmt.Errorf("%w: %s on metadata key %q requires a scalar value, got %T", ErrInvalidParameter, op, key, operand)
+			}
+		}
+	}
+	return nil
+}
+
+// isFilterScalar reports whether value can be compared against a metadata value:
+// nil, a bool, a number or a string.
+func isFilterScalar(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if _, ok := betweenNumber(value); ok {
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool, reflect.String:
+		return true
+	}
+	return false
+}
+
+// sortedFilterKeys returns the keys of the filter in sorted order, so that the first
+// error reported is deterministic.
+func sortedFilterKeys(f Filter) []string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,124 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFilterValidate tests that malformed metadata filters are rejected with a descriptive error
+func TestFilterValidate(t *testing.T) {
+	valid := []Filter{
+		nil,
+		{"category": "AI", "published": true, "rating": nil},
+		{"score": Filter{"$gte": 90, "$lt": 100}},
+		{"tag": map[string]interface{}{"$in": []string{"ml", "go"}}},
+		{"score": Filter{"$between": []int{80, 90}}},
+		{"author": Filter{"$exists": true}},
+		{"tags": Filter{"$contains": "nlp"}},
+		{"tags": Filter{"$contains": []string{"nlp", "cv"}, "$all": []interface{}{"ml"}}},
+		{"$and": []interface{}{
+			map[string]interface{}{"category": "AI"},
+			map[string]interface{}{"$or": []Filter{{"year": Filter{"$gt": 2020}}, {"$not": Filter{"tag": "draft"}}}},
+		}},
+		{"$nor": []Filter{{"category": "DB"}, {"score": Filter{"$lt": 50}}}},
+	}
+	for _, filter := range valid {
+		assert.NoError(t, filter.Validate(), "%v", filter)
+	}
+
+	invalid := []struct {
+		filter  Filter
+		message string
+	}{
+		{Filter{"score": Filter{"$gt e": 90}}, `unknown operator $gt e on metadata key "score"`},
+		{Filter{"$andd": []interface{}{}}, "unknown metadata filter operator $andd"},
+		{Filter{"$contains": "text"}, "unknown metadata filter operator $contains"},
+		{Filter{"tag": Filter{"$in": "ml"}}, `$in on metadata key "tag" requires a list`},
+		{Filter{"tag": Filter{"$nin": 3}}, `$nin on metadata key "tag" requires a list`},
+		{Filter{"author": Filter{"$exists": "yes"}}, "requires a boolean"},
+		{Filter{"score": Filter{"$between": []int{90}}}, "requires a list of two bounds"},
+		{Filter{"score": Filter{"$gte": []int{1, 2}}}, `$gte on metadata key "score" requires a scalar value`},
+		{Filter{"score": []int{1, 2}}, `metadata key "score" must be compared with a scalar value`},
+		{Filter{"score": Filter{}}, "empty condition"},
+		{Filter{"tags": Filter{"$all": "ml"}}, `$all on metadata key "tags" requires a non-empty list`},
+		{Filter{"tags": Filter{"$contains": Filter{"a": 1}}}, `$contains on metadata key "tags" requires a string, number or boolean`},
+		{Filter{"$and": Filter{"a": 1}}, "$and requires a list of filters"},
+		{Filter{"$or": []interface{}{"a"}}, "$or requires a list of filters"},
+		{Filter{"$not": []interface{}{}}, "$not requires a filter"},
+		{Filter{"$nor": Filter{"a": 1}}, "$nor requires a list of filters"},
+		{Filter{"$or": []Filter{{"a": 1}, {"b": Filter{"$gtt": 1}}}}, "unknown operator $gtt"},
+	}
+	for _, tc := range invalid {
+		err := tc.filter.Validate()
+		assert.ErrorIs(t, err, ErrInvalidParameter, "%v", tc.filter)
+		assert.ErrorContains(t, err, tc.message)
+	}
+}
+
+// TestFilterValidateDocument tests that malformed document filters are rejected with a descriptive error
+func TestFilterValidateDocument(t *testing.T) {
+	valid := []Filter{
+		nil,
+		{"$contains": "machine learning"},
+		{"$regex": ".*machine.*"},
+		{"$like": "%learn%", "$exists": true},
+		{"$or": []interface{}{map[string]interface{}{"$contains": "a"}, map[string]interface{}{"$ilike": "B"}}},
+		{"$not": Filter{"$contains": "draft"}},
+		{"$nor": []Filter{{"$contains": "draft"}, {"$like": "tmp%"}}},
+	}
+	for _, filter := range valid {
+		assert.NoError(t, filter.ValidateDocument(), "%v", filter)
+	}
+
+	invalid := []struct {
+		filter  Filter
+		message string
+	}{
+		{Filter{"$contain": "text"}, "unknown document filter operator $contain"},
+		{Filter{"category": "AI"}, "unknown document filter operator category"},
+		{Filter{"$contains": 42}, "$contains on document requires a string"},
+		{Filter{"$exists": "true"}, "$exists on document requires a boolean"},
+		{Filter{"$and": "text"}, "$and requires a list of filters"},
+		{Filter{"$and": []Filter{{"$contains": "a"}, {"$regexp": "b"}}}, "unknown document filter operator $regexp"},
+	}
+	for _, tc := range invalid {
+		err := tc.filter.ValidateDocument()
+		assert.ErrorIs(t, err, ErrInvalidParameter, "%v", tc.filter)
+		assert.ErrorContains(t, err, tc.message)
+	}
+}
+
+// TestOperationsValidateFilters tests that Query, Get, Delete and Count reject
+// malformed filters before running any statement or embedding the query
+func TestOperationsValidateFilters(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{}}
+	where := Filter{"score": Filter{"$gt e": 90}}
+	whereDocument := Filter{"$contain": "text"}
+
+	embedder := &countingEmbedder{}
+	_, err := client.collectionQuery(ctx, "docs", []string{"text"}, 10, &QueryOptions{Where: where}, embedder, DistanceCosine)
+	assert.ErrorContains(t, err, "unknown operator $gt e")
+	assert.Zero(t, embedder.calls.Load())
+	err = client.collectionQueryEach(ctx, "docs", []string{"text"}, 10, &QueryOptions{WhereDocument: whereDocument}, embedder, DistanceCosine,
+		func(SearchRecord) error { return nil })
+	assert.ErrorContains(t, err, "unknown document filter operator $contain")
+	assert.Zero(t, embedder.calls.Load())
+
+	_, err = client.collectionGet(ctx, "docs", nil, &GetOptions{Where: where})
+	assert.ErrorContains(t, err, "unknown operator $gt e")
+	_, err = client.collectionGetStream(ctx, "docs", nil, &GetOptions{WhereDocument: whereDocument})
+	assert.ErrorContains(t, err, "unknown document filter operator $contain")
+
+	_, err = client.collectionDelete(ctx, "docs", nil, Filter{"$andd": []Filter{}}, nil)
+	assert.ErrorContains(t, err, "unknown metadata filter operator $andd")
+	_, err = client.collectionCount(ctx, "docs", nil, Filter{"$exists": "yes"})
+	assert.ErrorContains(t, err, "$exists on document requires a boolean")
+
+	var dryRun *DryRunError
+	require.ErrorIs(t, err, ErrInvalidParameter)
+	assert.NotErrorAs(t, err, &dryRun)
+}
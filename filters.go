@@ -0,0 +1,165 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterBuilder translates metadata and document filters into SQL conditions for
+// the WHERE clause of collection statements. The zero value is ready to use.
+type FilterBuilder struct {
+	// indexedKeys are the metadata keys with indexed generated columns, which $eq
+	// and $in conditions on string values compare against instead of JSON_EXTRACT.
+	indexedKeys map[string]bool
+}
+
+// NewFilterBuilder creates a new filter builder.
+func NewFilterBuilder() *FilterBuilder {
+	return &FilterBuilder{}
+}
+
+// newIndexedFilterBuilder creates a filter builder for a collection with the given
+// indexed metadata keys.
+func newIndexedFilterBuilder(keys []string) *FilterBuilder {
+	b := &FilterBuilder{indexedKeys: make(map[string]bool, len(keys))}
+	for _, key := range keys {
+		b.indexedKeys[key] = true
+	}
+	return b
+}
+
+// isIndexed reports whether a metadata key has an indexed generated column. A nil
+// builder has none.
+func (b *FilterBuilder) isIndexed(key string) bool {
+	return b != nil && b.indexedKeys[key]
+}
+
+// metadataComparisonOperators maps the comparison operators of metadata filters to
+// their SQL operators. $ne is built separately, as it also matches a missing key.
+var metadataComparisonOperators = map[string]string{
+	"$eq":  "=",
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+// BuildMetadataFilter builds the SQL condition and its arguments for a metadata
+// filter, e.g. {"category": "AI", "year": {"$gte": 2020}}. Keys are metadata keys
+// compared with a plain value (equality) or a map of operators; $and, $or and $nor
+// combine lists of filters and $not negates a filter, nesting to any depth. Multiple
+// keys in one filter are combined with AND. An empty filter returns an empty
+// condition.
+func (b *FilterBuilder) BuildMetadataFilter(filter Filter) (string, []interface{}, error) {
+	return buildLogicalMetadataCondition(filter, b.buildMetadataKeyCondition)
+}
+
+// buildMetadataKeyCondition builds the condition for a single metadata key: an
+// equality for a plain value, or the conditions of each operator combined with AND.
+func (b *FilterBuilder) buildMetadataKeyCondition(key string, value interface{}) (string, []interface{}, error) {
+	ops, ok := asFilter(value)
+	if !ok {
+		if b.isIndexed(key) {
+			if clause, args, ok := buildIndexedMetadataCondition(key, "$eq", value); ok {
+				return clause, args, nil
+			}
+		}
+		return fmt.Sprintf("JSON_EXTRACT(%s, ?) = ?", FieldMetadata), []interface{}{"$." + key, value}, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, op := range sortedFilterKeys(ops) {
+		clause, clauseArgs, err := b.buildMetadataOperatorCondition(key, op, ops[op])
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+			args = append(args, clauseArgs...)
+		}
+	}
+
+	return joinConditions(conditions, "AND"), args, nil
+}
+
+// buildMetadataOperatorCondition builds the condition for one operator on a metadata
+// key, e.g. "$gt" and 18.
+func (b *FilterBuilder) buildMetadataOperatorCondition(key, op string, value interface{}) (string, []interface{}, error) {
+	if b.isIndexed(key) {
+		if clause, args, ok := buildIndexedMetadataCondition(key, op, value); ok {
+			return clause, args, nil
+		}
+	}
+
+	path := "$." + key
+	if symbol, ok := metadataComparisonOperators[op]; ok {
+		return fmt.Sprintf("JSON_EXTRACT(%s, ?) %s ?", FieldMetadata, symbol), []interface{}{path, value}, nil
+	}
+
+	switch op {
+	case "$between":
+		return buildMetadataBetweenCondition(key, value)
+	case "$exists", "$nexists":
+		return buildMetadataExistsCondition(key, op, value)
+	case "$contains", "$all":
+		return buildMetadataArrayCondition(key, op, value)
+	case "$ne":
+		return buildMetadataNotEqualCondition(key, value)
+	case "$nin":
+		return buildMetadataNotInCondition(key, value)
+	case "$in":
+		values, ok := arrayOperandValues(value)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s on metadata key %q requires a list, got %T", ErrInvalidParameter, op, key, value)
+		}
+		if len(values) == 0 {
+			return "", nil, nil
+		}
+		placeholders := make([]string, len(values))
+		args := []interface{}{path}
+		for i, v := range values {
+			placeholders[i] = "?"
+			args = append(args, v)
+		}
+		return fmt.Sprintf("JSON_EXTRACT(%s, ?) IN (%s)", FieldMetadata, strings.Join(placeholders, ", ")), args, nil
+	default:
+		return "", nil, fmt.Errorf("%w: unsupported operator %s on metadata key %q", ErrInvalidParameter, op, key)
+	}
+}
+
+// BuildDocumentFilter builds the SQL condition and its arguments for a document
+// filter: {"$contains": "text"} is a full-text search of the document,
+// {"$regex": "pattern"} a regular expression match and {"$like": "pattern"} or
+// {"$ilike": "pattern"} a LIKE scan, and {"$exists": bool} tests for a non-empty
+// document; $and, $or, $nor and $not combine filters as in metadata filters. An
+// empty filter returns an empty condition.
+func (b *FilterBuilder) BuildDocumentFilter(filter Filter) (string, []interface{}, error) {
+	return buildLogicalMetadataCondition(filter, b.buildDocumentCondition)
+}
+
+// buildDocumentCondition builds the condition for one operator of a document filter.
+func (b *FilterBuilder) buildDocumentCondition(op string, value interface{}) (string, []interface{}, error) {
+	switch op {
+	case "$contains":
+		return buildDocumentTextCondition(op, value, fmt.Sprintf("MATCH(%s) AGAINST (? IN NATURAL LANGUAGE MODE)", FieldDocument))
+	case "$regex":
+		return buildDocumentTextCondition(op, value, fmt.Sprintf("%s REGEXP ?", FieldDocument))
+	case "$like", "$ilike":
+		return buildDocumentLikeCondition(op, value)
+	case "$exists":
+		return buildDocumentExistsCondition(value)
+	default:
+		return "", nil, fmt.Errorf("%w: unsupported document operator %s", ErrInvalidParameter, op)
+	}
+}
+
+// buildDocumentTextCondition returns condition with the string operand of a document
+// operator as its argument.
+func buildDocumentTextCondition(op string, value interface{}, condition string) (string, []interface{}, error) {
+	text, ok := value.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s on document requires a string, got %T", ErrInvalidParameter, op, value)
+	}
+	return condition, []interface{}{text}, nil
+}
@@ -0,0 +1,114 @@
+package goseekdb
+
+import "context"
+
+// maxGetIDsPerQuery is the number of IDs a Get looks up per IN list. Larger lookups
+// are split into several statements, which keeps each one well within the
+// server's limits on placeholders and packet size.
+const maxGetIDsPerQuery = 1000
+
+// chunkedGetIDs reports whether a Get by ids is split into several statements. Only
+// plain lookups are: offsets, cursors and metadata ordering apply to one result set
+// and cannot be combined across statements.
+func chunkedGetIDs(ids []string, opts *GetOptions) bool {
+	return len(ids) > maxGetIDsPerQuery &&
+		opts.Offset == 0 && opts.Cursor == "" && !opts.paginate && opts.OrderBy == nil
+}
+
+// collectionGetChunked gets the documents with the given ids with one statement per
+// maxGetIDsPerQuery distinct IDs, and returns them in the order of their first
+// occurrence in ids, each once. A Limit applies to the merged result.
+func (c *Client) collectionGetChunked(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*GetResult, error) {
+	ids = uniqueIDs(ids)
+
+	chunkOpts := *opts
+	chunkOpts.Limit = 0 // Each chunk returns all of its IDs
+	chunkOpts.NoLimit = false
+	chunks := make([]*GetResult, 0, (len(ids)+maxGetIDsPerQuery-1)/maxGetIDsPerQuery)
+	for start := 0; start < len(ids); start += maxGetIDsPerQuery {
+		end := start + maxGetIDsPerQuery
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk, err := c.getDocuments(ctx, collectionName, ids[start:end], &chunkOpts)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	result := mergeGetResults(ids, chunks)
+	if opts.Limit > 0 && len(result.IDs) > opts.Limit {
+		truncateGetResult(result, opts.Limit)
+	}
+	return result, nil
+}
+
+// uniqueIDs returns ids without repetitions, keeping the first occurrence of each.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// mergeGetResults merges the results of chunked lookups into one, ordered like ids.
+// IDs that were not found are skipped. Documents, metadatas, embeddings and raw
+// columns are carried along when a chunk returned them parallel to its IDs.
+func mergeGetResults(ids []string, chunks []*GetResult) *GetResult {
+	type rowRef struct {
+		chunk *GetResult
+		index int
+	}
+	rows := make(map[string]rowRef)
+	for _, chunk := range chunks {
+		for i, id := range chunk.IDs {
+			rows[id] = rowRef{chunk: chunk, index: i}
+		}
+	}
+
+	result := &GetResult{}
+	for _, id := range ids {
+		ref, ok := rows[id]
+		if !ok {
+			continue
+		}
+		chunk, i := ref.chunk, ref.index
+		result.IDs = append(result.IDs, id)
+		if len(chunk.Documents) == len(chunk.IDs) {
+			result.Documents = append(result.Documents, chunk.Documents[i])
+		}
+		if len(chunk.Metadatas) == len(chunk.IDs) {
+			result.Metadatas = append(result.Metadatas, chunk.Metadatas[i])
+		}
+		if len(chunk.Embeddings) == len(chunk.IDs) {
+			result.Embeddings = append(result.Embeddings, chunk.Embeddings[i])
+		}
+		if len(chunk.RawColumns) == len(chunk.IDs) {
+			result.RawColumns = append(result.RawColumns, chunk.RawColumns[i])
+		}
+	}
+	return result
+}
+
+// truncateGetResult keeps the first n documents of result.
+func truncateGetResult(result *GetResult, n int) {
+	result.IDs = result.IDs[:n]
+	if len(result.Documents) > n {
+		result.Documents = result.Documents[:n]
+	}
+	if len(result.Metadatas) > n {
+		result.Metadatas = result.Metadatas[:n]
+	}
+	if len(result.Embeddings) > n {
+		result.Embeddings = result.Embeddings[:n]
+	}
+	if len(result.RawColumns) > n {
+		result.RawColumns = result.RawColumns[:n]
+	}
+}
@@ -0,0 +1,78 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChunkedGetIDs tests which ID lookups are split into several statements
+func TestChunkedGetIDs(t *testing.T) {
+	many := make([]string, maxGetIDsPerQuery+1)
+	assert.False(t, chunkedGetIDs(many[:maxGetIDsPerQuery], &GetOptions{}))
+	assert.True(t, chunkedGetIDs(many, &GetOptions{}))
+	assert.True(t, chunkedGetIDs(many, &GetOptions{Limit: 10}))
+	assert.False(t, chunkedGetIDs(many, &GetOptions{Offset: 10}))
+	assert.False(t, chunkedGetIDs(many, &GetOptions{Cursor: "x"}))
+	assert.False(t, chunkedGetIDs(many, &GetOptions{paginate: true}))
+	assert.False(t, chunkedGetIDs(many, &GetOptions{OrderBy: &MetadataOrder{Key: "score"}}))
+}
+
+// TestMergeGetResults tests merging chunk results in ID order
+func TestMergeGetResults(t *testing.T) {
+	assert.Equal(t, []string{"b", "a", "c"}, uniqueIDs([]string{"b", "a", "b", "c", "a"}))
+
+	chunks := []*GetResult{
+		{IDs: []string{"a", "b"}, Documents: []string{"doc a", "doc b"}, Metadatas: []Metadata{{"n": 1}, {"n": 2}}},
+		{IDs: []string{"d"}, Documents: []string{"doc d"}, Metadatas: []Metadata{{"n": 4}}},
+	}
+	result := mergeGetResults([]string{"d", "c", "b", "a"}, chunks)
+	assert.Equal(t, []string{"d", "b", "a"}, result.IDs)
+	assert.Equal(t, []string{"doc d", "doc b", "doc a"}, result.Documents)
+	assert.Equal(t, []Metadata{{"n": 4}, {"n": 2}, {"n": 1}}, result.Metadatas)
+	assert.Nil(t, result.Embeddings)
+
+	truncateGetResult(result, 2)
+	assert.Equal(t, []string{"d", "b"}, result.IDs)
+	assert.Equal(t, []string{"doc d", "doc b"}, result.Documents)
+	assert.Len(t, result.Metadatas, 2)
+}
+
+// TestCollectionGetManyIDs tests a Get by more IDs than fit in one IN list
+func TestCollectionGetManyIDs(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_get_many_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	n := 2*maxGetIDsPerQuery + 500
+	_, err := collection.CopyFrom(ctx, recordsChannel(n))
+	require.NoError(t, err)
+
+	// Reversed, with a repeated ID and one that does not exist
+	lookup := make([]string, 0, n+2)
+	for i := n - 1; i >= 0; i-- {
+		lookup = append(lookup, fmt.Sprintf("id%04d", i))
+	}
+	lookup = append(lookup, "id0000", "missing")
+
+	result, err := collection.Get(ctx, lookup)
+	require.NoError(t, err)
+	require.Len(t, result.IDs, n)
+	assert.Equal(t, lookup[:n], result.IDs)
+	assert.Equal(t, fmt.Sprintf("document %d", n-1), result.Documents[0])
+	assert.Equal(t, "document 0", result.Documents[n-1])
+
+	limited, err := collection.Get(ctx, lookup, WithLimit(10))
+	require.NoError(t, err)
+	assert.Equal(t, lookup[:10], limited.IDs)
+}
@@ -0,0 +1,70 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// queryStringExpression returns the query_string full-text expression of search_parm
+// for query over fields, the document column if there are none, with an optional
+// analyzer.
+func queryStringExpression(query interface{}, fields []string, analyzer string) map[string]interface{} {
+	if len(fields) == 0 {
+		fields = []string{FieldDocument}
+	}
+	expr := map[string]interface{}{
+		"fields": fields,
+		"query":  query,
+	}
+	if analyzer != "" {
+		expr["analyzer"] = analyzer
+	}
+	return map[string]interface{}{"query_string": expr}
+}
+
+// validateFullText checks the full-text Fields and Analyzer of a hybrid search query.
+// Fields must be column names, optionally with a positive boost as "name^2", and
+// distinct; the analyzer must be a plain name.
+func (q *HybridSearchQuery) validateFullText() error {
+	seen := make(map[string]bool, len(q.Fields))
+	for _, field := range q.Fields {
+		name := field
+		if i := strings.IndexByte(field, '^'); i >= 0 {
+			name = field[:i]
+			boost, err := strconv.ParseFloat(field[i+1:], 64)
+			if err != nil || boost <= 0 {
+				return fmt.Errorf("%w: full-text field %q has an invalid boost", ErrInvalidParameter, field)
+			}
+		}
+		if !isPlainIdentifier(name) {
+			return fmt.Errorf("%w: full-text field %q must be a column name of letters, digits and '_'", ErrInvalidParameter, field)
+		}
+		// Column names are case-insensitive
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("%w: duplicate full-text field %q", ErrInvalidParameter, name)
+		}
+		seen[strings.ToLower(name)] = true
+	}
+
+	if q.Analyzer != "" && !isPlainIdentifier(strings.ReplaceAll(q.Analyzer, "-", "_")) {
+		return fmt.Errorf("%w: analyzer %q must contain only letters, digits, '_' and '-'", ErrInvalidParameter, q.Analyzer)
+	}
+	return nil
+}
+
+// isPlainIdentifier reports whether s is a non-empty identifier of at most
+// maxColumnNameLength letters, digits and '_' that does not start with a digit.
+func isPlainIdentifier(s string) bool {
+	if s == "" || len(s) > maxColumnNameLength {
+		return false
+	}
+	for i, r := range s {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && r != '_' && (!isDigit || i == 0) {
+			return false
+		}
+	}
+	return true
+}
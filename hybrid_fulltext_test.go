@@ -0,0 +1,88 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildDocumentQueryFields tests threading fields and analyzer into query_string
+func TestBuildDocumentQueryFields(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	expr := client.buildDocumentQuery(Filter{"$contains": "machine learning"}, nil, "")
+	assert.Equal(t, map[string]interface{}{
+		"query_string": map[string]interface{}{
+			"fields": []string{"document"},
+			"query":  "machine learning",
+		},
+	}, expr)
+
+	expr = client.buildDocumentQuery(Filter{"$or": []interface{}{
+		map[string]interface{}{"$contains": "python"},
+		map[string]interface{}{"$contains": "go"},
+	}}, []string{"document", "title^2"}, "ik")
+	assert.Equal(t, map[string]interface{}{
+		"query_string": map[string]interface{}{
+			"fields":   []string{"document", "title^2"},
+			"query":    "python OR go",
+			"analyzer": "ik",
+		},
+	}, expr)
+}
+
+// TestHybridSearchQueryValidateFullText tests rejecting invalid fields and analyzers
+func TestHybridSearchQueryValidateFullText(t *testing.T) {
+	valid := []HybridSearchQuery{
+		{},
+		{Fields: []string{"document", "title^2", "_meta_summary^0.5"}},
+		{Analyzer: "space"},
+		{Analyzer: "ngram-2"},
+	}
+	for _, query := range valid {
+		assert.NoError(t, query.validateFullText(), "%+v", query)
+	}
+
+	invalid := []HybridSearchQuery{
+		{Fields: []string{""}},
+		{Fields: []string{"metadata.title"}},
+		{Fields: []string{"title; DROP TABLE docs"}},
+		{Fields: []string{"1title"}},
+		{Fields: []string{"title^"}},
+		{Fields: []string{"title^0"}},
+		{Fields: []string{"title^x"}},
+		{Fields: []string{"title", "Title^2"}},
+		{Analyzer: "ik'"},
+		{Analyzer: "a b"},
+	}
+	for _, query := range invalid {
+		assert.ErrorIs(t, query.validateFullText(), ErrInvalidParameter, "%+v", query)
+	}
+}
+
+// TestHybridSearchFullTextFields tests that fields and analyzer reach search_parm
+func TestHybridSearchFullTextFields(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "hybrid_no_sql", []string{"query_sql"}, [][]driver.Value{{nil}})
+	conn := &hybridConnection{db: db}
+	client := &Client{conn: conn, config: &ClientConfig{}}
+
+	_, err := client.collectionHybridSearch(ctx, "docs",
+		&HybridSearchQuery{WhereDocument: Filter{"$contains": "learning"}, Fields: []string{"document", "title"}, Analyzer: "ik"},
+		nil, nil, 5, nil, DistanceL2)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, conn.tx.stmts)
+	searchParm, ok := conn.tx.stmts[0].args[0].(string)
+	require.True(t, ok)
+	assert.Contains(t, searchParm, `"fields":["document","title"]`)
+	assert.Contains(t, searchParm, `"analyzer":"ik"`)
+
+	_, err = client.collectionHybridSearch(ctx, "docs",
+		&HybridSearchQuery{WhereDocument: Filter{"$contains": "learning"}, Fields: []string{"title`"}},
+		nil, nil, 5, nil, DistanceL2)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
@@ -0,0 +1,143 @@
+package goseekdb
+
+import (
+	"cmp"
+	"reflect"
+	"sort"
+)
+
+// SortField is a metadata key to order hybrid search results by, see
+// WithSecondarySort.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// sortByMetadata stably re-orders hits with equal fused scores by the given metadata
+// fields, keeping the order between different scores. Hits missing a field, or
+// holding null, sort after all others whatever the direction. Values of different
+// types order numbers first, then strings, then booleans, then anything else.
+func (r *HybridSearchResult) sortByMetadata(fields []SortField) {
+	n := len(r.IDs)
+	if len(fields) == 0 || n < 2 || len(r.Metadatas) != n {
+		return
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	less := func(a, b int) bool {
+		for _, field := range fields {
+			if c := compareSortValues(r.Metadatas[order[a]], r.Metadatas[order[b]], field); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	}
+
+	// Without scores for every hit, all hits are tied
+	if len(r.Distances) != n {
+		sort.SliceStable(order, less)
+	} else {
+		for start := 0; start < n; {
+			end := start + 1
+			for end < n && r.Distances[end] == r.Distances[start] {
+				end++
+			}
+			run := order[start:end]
+			sort.SliceStable(run, func(a, b int) bool { return less(start+a, start+b) })
+			start = end
+		}
+	}
+
+	r.IDs = permute(r.IDs, order)
+	r.Distances = permute(r.Distances, order)
+	r.Documents = permute(r.Documents, order)
+	r.Metadatas = permute(r.Metadatas, order)
+	r.Embeddings = permute(r.Embeddings, order)
+	r.RawScores = permute(r.RawScores, order)
+}
+
+// permute returns values reordered so that element i is values[order[i]]. Slices of
+// another length than order, e.g. fields not included in the result, are returned
+// unchanged.
+func permute[T any](values []T, order []int) []T {
+	if len(values) != len(order) {
+		return values
+	}
+	permuted := make([]T, len(values))
+	for i, j := range order {
+		permuted[i] = values[j]
+	}
+	return permuted
+}
+
+// compareSortValues compares the values of field in two hits' metadata.
+func compareSortValues(a, b Metadata, field SortField) int {
+	va, okA := a[field.Field]
+	vb, okB := b[field.Field]
+	okA, okB = okA && va != nil, okB && vb != nil
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return 1
+	case !okB:
+		return -1
+	}
+
+	c := compareMetadataValues(va, vb)
+	if field.Desc {
+		return -c
+	}
+	return c
+}
+
+// compareMetadataValues orders two non-nil metadata values, first by type rank and
+// then by value within numbers, strings and booleans.
+func compareMetadataValues(a, b interface{}) int {
+	rankA, rankB := sortTypeRank(a), sortTypeRank(b)
+	if rankA != rankB {
+		return cmp.Compare(rankA, rankB)
+	}
+
+	switch rankA {
+	case 0:
+		// Compare integers exactly, e.g. int64 metadata beyond float64 precision
+		if ia, ok := a.(int64); ok {
+			if ib, ok := b.(int64); ok {
+				return cmp.Compare(ia, ib)
+			}
+		}
+		fa, _ := betweenNumber(a)
+		fb, _ := betweenNumber(b)
+		return cmp.Compare(fa, fb)
+	case 1:
+		return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+	case 2:
+		ba, bb := reflect.ValueOf(a).Bool(), reflect.ValueOf(b).Bool()
+		if ba == bb {
+			return 0
+		}
+		if !ba {
+			return -1
+		}
+		return 1
+	}
+	return 0 // Lists and objects are not ordered
+}
+
+// sortTypeRank groups values for ordering: numbers, strings, booleans, the rest.
+func sortTypeRank(value interface{}) int {
+	if _, ok := betweenNumber(value); ok {
+		return 0
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return 1
+	case reflect.Bool:
+		return 2
+	}
+	return 3
+}
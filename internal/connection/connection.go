@@ -17,6 +17,9 @@ type Connection interface {
 	// IsConnected returns true if the connection is active.
 	IsConnected() bool
 
+	// Ping verifies the server is reachable.
+	Ping(ctx context.Context) error
+
 	// Execute executes a query and returns the result.
 	Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 
@@ -36,6 +39,13 @@ type Connection interface {
 	RawConnection() interface{}
 }
 
+// Discarder is implemented by transactions that can end by closing their connection
+// instead of returning it to the pool, e.g. after session state could not be reset.
+type Discarder interface {
+	// Discard rolls back the transaction and closes its connection.
+	Discard() error
+}
+
 // Tx represents a database transaction.
 type Tx interface {
 	// Commit commits the transaction.
@@ -59,6 +59,14 @@ func (e *EmbeddedConnection) IsConnected() bool {
 	return e.connected
 }
 
+// Ping verifies the embedded database is open.
+func (e *EmbeddedConnection) Ping(ctx context.Context) error {
+	if !e.connected {
+		return fmt.Errorf("not connected")
+	}
+	return nil
+}
+
 // Execute executes a query.
 func (e *EmbeddedConnection) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	if !e.connected {
@@ -2,12 +2,28 @@ package connection
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
-	_ "github.com/go-sql-driver/mysql" // MySQL driver
+	"github.com/go-sql-driver/mysql" // MySQL driver
 )
 
+// errBadDatabase is the MySQL error number for "Unknown database".
+const errBadDatabase = 1049
+
+// tlsConfigCounter makes the names TLS configs are registered under with the
+// mysql driver unique per connection.
+var tlsConfigCounter atomic.Int64
+
 // RemoteConnection implements Connection for remote SeekDB/OceanBase servers.
 type RemoteConnection struct {
 	host     string
@@ -16,12 +32,94 @@ type RemoteConnection struct {
 	password string
 	database string
 	tenant   string
-	db       *sql.DB
+
+	// mu guards db, which is set by Connect and cleared by Close.
+	mu sync.RWMutex
+	db *sql.DB
+
+	// openDB opens a pool for a database; it is r.open except in tests.
+	openDB func(ctx context.Context, database string) (*sql.DB, error)
+
+	autoCreateDatabase bool
+	charset            string
+	collation          string
+
+	// tlsConfig is registered with the mysql driver under tlsName while connected.
+	tlsConfig     *tls.Config
+	tlsSkipVerify bool
+	tlsName       string
+
+	// stmts caches prepared statements for Query; nil disables caching.
+	stmts *stmtCache
+
+	// sessionVars are system variables set on every pooled connection.
+	sessionVars map[string]string
+}
+
+// RemoteOption is a functional option for configuring a RemoteConnection.
+type RemoteOption func(*RemoteConnection)
+
+// WithAutoCreateDatabase creates the target database on connect if it does not exist.
+func WithAutoCreateDatabase(enabled bool) RemoteOption {
+	return func(r *RemoteConnection) {
+		r.autoCreateDatabase = enabled
+	}
+}
+
+// WithCharset sets the connection character set and collation. Empty values leave
+// the server default in place.
+func WithCharset(charset, collation string) RemoteOption {
+	return func(r *RemoteConnection) {
+		r.charset = charset
+		r.collation = collation
+	}
+}
+
+// WithTLS encrypts the connection with the given TLS configuration. A config
+// without ServerName verifies the server certificate against the host name.
+func WithTLS(config *tls.Config) RemoteOption {
+	return func(r *RemoteConnection) {
+		r.tlsConfig = config
+	}
+}
+
+// WithTLSSkipVerify encrypts the connection without verifying the server
+// certificate, e.g. for self-signed certificates in development. Combined with
+// WithTLS it disables verification for that configuration.
+func WithTLSSkipVerify(skip bool) RemoteOption {
+	return func(r *RemoteConnection) {
+		r.tlsSkipVerify = skip
+	}
+}
+
+// WithStatementCacheSize caches up to size prepared statements for Query, keyed by
+// SQL text, so repeated queries skip the prepare round trip. The server holds one
+// statement per cached query and pooled connection it ran on, which counts toward
+// its max_prepared_stmt_count. A size of 0 disables the cache.
+func WithStatementCacheSize(size int) RemoteOption {
+	return func(r *RemoteConnection) {
+		r.stmts = nil
+		if size > 0 {
+			r.stmts = newStmtCache(size)
+		}
+	}
+}
+
+// WithSessionVars sets session system variables, e.g. time_zone or sql_mode, on
+// every connection the pool opens, including those replacing connections that died.
+// Values are sent as string literals unless they are numbers.
+func WithSessionVars(vars map[string]string) RemoteOption {
+	return func(r *RemoteConnection) {
+		r.sessionVars = make(map[string]string, len(vars))
+		for name, value := range vars {
+			r.sessionVars[name] = value
+		}
+	}
 }
 
 // NewRemoteConnection creates a new remote connection.
-func NewRemoteConnection(host string, port int, user, password, database, tenant string) *RemoteConnection {
-	return &RemoteConnection{
+func NewRemoteConnection(host string, port int, user, password, database, tenant string, opts ...RemoteOption) *RemoteConnection {
+	r := &RemoteConnection{
 		host:     host,
 		port:     port,
 		user:     user,
@@ -29,94 +127,306 @@ func NewRemoteConnection(host string, port int, user, password, database, tenant
 		database: database,
 		tenant:   tenant,
 	}
+	r.openDB = r.open
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.tlsConfig != nil {
+		if r.tlsSkipVerify {
+			r.tlsConfig = r.tlsConfig.Clone()
+			r.tlsConfig.InsecureSkipVerify = true
+		}
+		r.tlsName = fmt.Sprintf("seekdb-%d", tlsConfigCounter.Add(1))
+	}
+	return r
+}
+
+// dsn builds the DSN (Data Source Name) for the given default database.
+// Format: user@tenant:password@tcp(host:port)/database?params
+func (r *RemoteConnection) dsn(database string) string {
+	username := r.user
+	if r.tenant != "" && r.tenant != "test" {
+		username = fmt.Sprintf("%s@%s", r.user, r.tenant)
+	}
+
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=Local",
+		username, r.password, r.host, r.port, database)
+
+	if r.charset != "" {
+		dsn += "&charset=" + url.QueryEscape(r.charset)
+	}
+	if r.collation != "" {
+		dsn += "&collation=" + url.QueryEscape(r.collation)
+	}
+	if r.tlsName != "" {
+		dsn += "&tls=" + r.tlsName
+	} else if r.tlsSkipVerify {
+		dsn += "&tls=skip-verify"
+	}
+
+	// The driver runs SET for parameters it does not know on each new connection
+	names := make([]string, 0, len(r.sessionVars))
+	for name := range r.sessionVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dsn += "&" + name + "=" + url.QueryEscape(sessionVarLiteral(r.sessionVars[name]))
+	}
+
+	return dsn
+}
+
+// sessionVarLiteral returns value as a SQL literal for SET: numbers as is, anything
+// else as a quoted string.
+func sessionVarLiteral(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+// validateSessionVars checks that session variable names are plain identifiers and
+// that the driver passes them to the server rather than taking them as one of its
+// own DSN parameters, such as timeout.
+func (r *RemoteConnection) validateSessionVars(dsn string) error {
+	if len(r.sessionVars) == 0 {
+		return nil
+	}
+	for name := range r.sessionVars {
+		if name == "" || strings.IndexFunc(name, func(c rune) bool {
+			return !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9')
+		}) >= 0 {
+			return fmt.Errorf("invalid session variable name %q", name)
+		}
+	}
+
+	config, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return fmt.Errorf("invalid session variables: %w", err)
+	}
+	for name := range r.sessionVars {
+		if _, ok := config.Params[name]; !ok {
+			return fmt.Errorf("session variable %q is a driver parameter", name)
+		}
+	}
+	return nil
 }
 
 // Connect establishes a connection to the remote server.
 func (r *RemoteConnection) Connect(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.db != nil {
 		return nil // Already connected
 	}
 
-	// Build DSN (Data Source Name)
-	// Format: user@tenant:password@tcp(host:port)/database?params
-	username := r.user
-	if r.tenant != "" && r.tenant != "test" {
-		username = fmt.Sprintf("%s@%s", r.user, r.tenant)
+	db, err := r.openDB(ctx, r.database)
+	if err != nil && r.autoCreateDatabase && isUnknownDatabase(err) {
+		if err := r.createDatabase(ctx); err != nil {
+			return err
+		}
+		db, err = r.openDB(ctx, r.database)
+	}
+	if err != nil {
+		return err
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=Local",
-		username, r.password, r.host, r.port, r.database)
+	r.db = db
+	return nil
+}
+
+// getDB returns the current connection pool, or nil if not connected.
+func (r *RemoteConnection) getDB() *sql.DB {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.db
+}
+
+// isBadConn reports whether err means the pooled connection it ran on is dead,
+// e.g. closed by the server while idle. database/sql discards such connections.
+func isBadConn(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn)
+}
+
+// retryOnInvalidConn runs fn on the current pool and, if its connection turned out
+// to be dead, once more on another connection of the same pool. database/sql already
+// retries connections it knows to be bad before using them; this covers those found
+// dead while in use, which the mysql driver reports as ErrInvalidConn. Only use it
+// for idempotent operations: a statement may have run before its connection died.
+func (r *RemoteConnection) retryOnInvalidConn(fn func(db *sql.DB) error) error {
+	db := r.getDB()
+	if db == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	err := fn(db)
+	if !errors.Is(err, mysql.ErrInvalidConn) {
+		return err
+	}
+	return fn(db)
+}
+
+// Ping verifies the server is reachable, retrying once on another pooled connection
+// if the one used has died.
+func (r *RemoteConnection) Ping(ctx context.Context) error {
+	return r.retryOnInvalidConn(func(db *sql.DB) error {
+		return db.PingContext(ctx)
+	})
+}
+
+// open opens and pings a connection pool using the given default database.
+func (r *RemoteConnection) open(ctx context.Context, database string) (*sql.DB, error) {
+	if r.tlsName != "" {
+		// Registered on every open, as Close deregisters it
+		if err := mysql.RegisterTLSConfig(r.tlsName, r.tlsConfig); err != nil {
+			return nil, fmt.Errorf("failed to register TLS config: %w", err)
+		}
+	}
+
+	dsn := r.dsn(database)
+	if err := r.validateSessionVars(dsn); err != nil {
+		return nil, err
+	}
 
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return fmt.Errorf("failed to open connection: %w", err)
+		return nil, fmt.Errorf("failed to open connection: %w", err)
 	}
 
 	// Ping to verify connection
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		return fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	r.db = db
+	return db, nil
+}
+
+// createDatabase connects without a default schema and creates the target database.
+// The name is quoted with backticks, so names containing one are rejected.
+func (r *RemoteConnection) createDatabase(ctx context.Context) error {
+	if strings.Contains(r.database, "`") {
+		return fmt.Errorf("cannot create database %q: the name must not contain a backtick", r.database)
+	}
+
+	db, err := r.open(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	createSQL := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", r.database)
+	if _, err := db.ExecContext(ctx, createSQL); err != nil {
+		return fmt.Errorf("failed to create database %s: %w", r.database, err)
+	}
 	return nil
 }
 
+// isUnknownDatabase reports whether err is the server's "Unknown database" error.
+func isUnknownDatabase(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errBadDatabase
+}
+
 // Close closes the connection.
 func (r *RemoteConnection) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.db == nil {
 		return nil
 	}
+	if r.stmts != nil {
+		r.stmts.reset()
+	}
 	err := r.db.Close()
 	r.db = nil
+	if r.tlsName != "" {
+		mysql.DeregisterTLSConfig(r.tlsName)
+	}
 	return err
 }
 
 // IsConnected returns true if connected.
 func (r *RemoteConnection) IsConnected() bool {
-	if r.db == nil {
+	db := r.getDB()
+	if db == nil {
 		return false
 	}
-	if err := r.db.Ping(); err != nil {
+	if err := db.Ping(); err != nil {
 		return false
 	}
 	return true
 }
 
-// Execute executes a query.
+// Execute executes a query. It is never retried, as the statement may have been
+// applied before a connection failure was reported.
 func (r *RemoteConnection) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	if r.db == nil {
+	db := r.getDB()
+	if db == nil {
 		return nil, fmt.Errorf("not connected")
 	}
-	return r.db.ExecContext(ctx, query, args...)
+	return db.ExecContext(ctx, query, args...)
 }
 
 // Query executes a query and returns rows.
 func (r *RemoteConnection) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	if r.db == nil {
-		return nil, fmt.Errorf("not connected")
+	var rows *sql.Rows
+	err := r.retryOnInvalidConn(func(db *sql.DB) (err error) {
+		rows, err = r.query(ctx, db, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// query runs a query on db, through a cached prepared statement if the statement
+// cache is enabled. Statements the server refuses to prepare run unprepared.
+func (r *RemoteConnection) query(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*sql.Rows, error) {
+	if r.stmts == nil {
+		return db.QueryContext(ctx, query, args...)
+	}
+
+	entry, err := r.stmts.acquire(ctx, db, query)
+	if err != nil {
+		if isBadConn(err) {
+			return nil, err
+		}
+		return db.QueryContext(ctx, query, args...)
 	}
-	return r.db.QueryContext(ctx, query, args...)
+	// The rows keep the statement open until they are closed
+	defer r.stmts.release(entry)
+	return entry.stmt.QueryContext(ctx, args...)
 }
 
 // QueryRow executes a query that returns at most one row.
+// Its errors are deferred to Scan, so it is not retried on a dead connection.
 func (r *RemoteConnection) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	if r.db == nil {
+	db := r.getDB()
+	if db == nil {
 		return nil
 	}
-	return r.db.QueryRowContext(ctx, query, args...)
+	return db.QueryRowContext(ctx, query, args...)
 }
 
 // Begin starts a transaction.
 func (r *RemoteConnection) Begin(ctx context.Context) (Tx, error) {
-	if r.db == nil {
-		return nil, fmt.Errorf("not connected")
-	}
-	sqlTx, err := r.db.BeginTx(ctx, nil)
+	var conn *sql.Conn
+	var sqlTx *sql.Tx
+	err := r.retryOnInvalidConn(func(db *sql.DB) (err error) {
+		if conn, err = db.Conn(ctx); err != nil {
+			return err
+		}
+		if sqlTx, err = conn.BeginTx(ctx, nil); err != nil {
+			conn.Close()
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &remoteTx{tx: sqlTx}, nil
+	return &remoteTx{conn: conn, tx: sqlTx}, nil
 }
 
 // Mode returns "remote".
@@ -126,22 +436,35 @@ func (r *RemoteConnection) Mode() string {
 
 // RawConnection returns the underlying *sql.DB.
 func (r *RemoteConnection) RawConnection() interface{} {
-	return r.db
+	return r.getDB()
 }
 
-// remoteTx implements Tx for remote connections.
+// remoteTx implements Tx for remote connections. The transaction runs on a
+// dedicated connection, which returns to the pool when the transaction ends.
 type remoteTx struct {
-	tx *sql.Tx
+	conn *sql.Conn
+	tx   *sql.Tx
 }
 
 func (t *remoteTx) Commit() error {
+	defer t.conn.Close()
 	return t.tx.Commit()
 }
 
 func (t *remoteTx) Rollback() error {
+	defer t.conn.Close()
 	return t.tx.Rollback()
 }
 
+// Discard rolls back the transaction and closes its connection instead of
+// returning it to the pool.
+func (t *remoteTx) Discard() error {
+	t.tx.Rollback()
+	// Reporting the connection as bad makes database/sql close it
+	t.conn.Raw(func(driverConn interface{}) error { return driver.ErrBadConn })
+	return t.conn.Close()
+}
+
 func (t *remoteTx) Execute(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	return t.tx.ExecContext(ctx, query, args...)
 }
@@ -0,0 +1,225 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteConnectionDSN(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "secret", "test", "test")
+		assert.Equal(t, "root:secret@tcp(127.0.0.1:2881)/test?parseTime=true&loc=Local", r.dsn("test"))
+	})
+
+	t.Run("tenant", func(t *testing.T) {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "mysql")
+		assert.Equal(t, "root@mysql:@tcp(127.0.0.1:2881)/?parseTime=true&loc=Local", r.dsn(""))
+	})
+
+	t.Run("charset and collation", func(t *testing.T) {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test",
+			WithCharset("utf8mb4", "utf8mb4_general_ci"),
+		)
+		assert.Equal(t, "root:@tcp(127.0.0.1:2881)/test?parseTime=true&loc=Local&charset=utf8mb4&collation=utf8mb4_general_ci", r.dsn("test"))
+	})
+
+	t.Run("TLS skip verify", func(t *testing.T) {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test", WithTLSSkipVerify(true))
+		assert.Equal(t, "root:@tcp(127.0.0.1:2881)/test?parseTime=true&loc=Local&tls=skip-verify", r.dsn("test"))
+	})
+
+	t.Run("TLS config", func(t *testing.T) {
+		config := &tls.Config{ServerName: "db.example.com", MinVersion: tls.VersionTLS12}
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test", WithTLS(config))
+		other := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test", WithTLS(config))
+		require.NotEmpty(t, r.tlsName)
+		assert.NotEqual(t, r.tlsName, other.tlsName)
+		assert.Equal(t, "root:@tcp(127.0.0.1:2881)/test?parseTime=true&loc=Local&tls="+r.tlsName, r.dsn("test"))
+		assert.Same(t, config, r.tlsConfig)
+
+		// The registered name is understood by the driver
+		require.NoError(t, mysql.RegisterTLSConfig(r.tlsName, r.tlsConfig))
+		defer mysql.DeregisterTLSConfig(r.tlsName)
+		parsed, err := mysql.ParseDSN(r.dsn("test"))
+		require.NoError(t, err)
+		require.NotNil(t, parsed.TLS)
+		assert.Equal(t, "db.example.com", parsed.TLS.ServerName)
+	})
+
+	t.Run("session variables", func(t *testing.T) {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test",
+			WithSessionVars(map[string]string{"time_zone": "+00:00", "sql_mode": "STRICT_ALL_TABLES", "max_execution_time": "1000"}),
+		)
+		dsn := r.dsn("test")
+		assert.Equal(t, "root:@tcp(127.0.0.1:2881)/test?parseTime=true&loc=Local&max_execution_time=1000&sql_mode=%27STRICT_ALL_TABLES%27&time_zone=%27%2B00%3A00%27", dsn)
+		require.NoError(t, r.validateSessionVars(dsn))
+
+		// The driver sets them with these literals
+		parsed, err := mysql.ParseDSN(dsn)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"time_zone": "'+00:00'", "sql_mode": "'STRICT_ALL_TABLES'", "max_execution_time": "1000"}, parsed.Params)
+
+		assert.Equal(t, `'it\'s \\ quoted'`, sessionVarLiteral(`it's \ quoted`))
+	})
+
+	t.Run("invalid session variables", func(t *testing.T) {
+		for _, name := range []string{"", "time_zone = 0, sql_mode", "timeout"} {
+			r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test",
+				WithSessionVars(map[string]string{name: "1"}),
+			)
+			assert.Error(t, r.validateSessionVars(r.dsn("test")), name)
+		}
+	})
+
+	t.Run("TLS config with skip verify", func(t *testing.T) {
+		config := &tls.Config{ServerName: "db.example.com"}
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test", WithTLS(config), WithTLSSkipVerify(true))
+		assert.Contains(t, r.dsn("test"), "&tls="+r.tlsName)
+		assert.True(t, r.tlsConfig.InsecureSkipVerify)
+		assert.False(t, config.InsecureSkipVerify, "the caller's config is not modified")
+	})
+}
+
+func TestRemoteConnectionCreateDatabaseRejectsBacktick(t *testing.T) {
+	r := NewRemoteConnection("127.0.0.1", 1, "root", "", "te`st", "test")
+	err := r.createDatabase(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backtick")
+}
+
+// dyingDriver is a database/sql driver whose connections fail with
+// mysql.ErrInvalidConn while deaths remain, like connections the server closed
+// while they sat idle in the pool.
+type dyingDriver struct {
+	deaths atomic.Int32
+	calls  atomic.Int32
+}
+
+func (d *dyingDriver) Open(name string) (driver.Conn, error) { return &dyingConn{d: d}, nil }
+
+type dyingConn struct {
+	d    *dyingDriver
+	dead bool
+}
+
+// use fails the statement, and the connection for good, while deaths remain
+func (c *dyingConn) use() error {
+	c.d.calls.Add(1)
+	if c.dead || c.d.deaths.Add(-1) >= 0 {
+		c.dead = true
+		return mysql.ErrInvalidConn
+	}
+	return nil
+}
+
+func (c *dyingConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *dyingConn) Close() error                              { return nil }
+
+// IsValid makes database/sql discard dead connections, as the mysql driver does
+func (c *dyingConn) IsValid() bool { return !c.dead }
+
+func (c *dyingConn) Begin() (driver.Tx, error) {
+	if err := c.use(); err != nil {
+		return nil, err
+	}
+	return dyingTx{}, nil
+}
+
+func (c *dyingConn) Ping(ctx context.Context) error { return c.use() }
+
+func (c *dyingConn) Query(query string, args []driver.Value) (driver.Rows, error) {
+	if err := c.use(); err != nil {
+		return nil, err
+	}
+	return emptyRows{}, nil
+}
+
+func (c *dyingConn) Exec(query string, args []driver.Value) (driver.Result, error) {
+	if err := c.use(); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type dyingTx struct{}
+
+func (dyingTx) Commit() error   { return nil }
+func (dyingTx) Rollback() error { return nil }
+
+// dyingDrivers makes driver names unique across test runs
+var dyingDrivers atomic.Int32
+
+func TestRemoteConnectionRetryOnInvalidConn(t *testing.T) {
+	ctx := context.Background()
+	d := &dyingDriver{}
+	name := fmt.Sprintf("dying-%d", dyingDrivers.Add(1))
+	sql.Register(name, d)
+
+	connect := func(t *testing.T) *RemoteConnection {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test")
+		r.openDB = func(ctx context.Context, database string) (*sql.DB, error) {
+			return sql.Open(name, "")
+		}
+		require.NoError(t, r.Connect(ctx))
+		t.Cleanup(func() { r.Close() })
+		d.calls.Store(0)
+		d.deaths.Store(1)
+		return r
+	}
+
+	// Idempotent operations are retried once on another connection of the pool
+	t.Run("ping", func(t *testing.T) {
+		r := connect(t)
+		pool := r.getDB()
+		require.NoError(t, r.Ping(ctx))
+		assert.Equal(t, int32(2), d.calls.Load())
+		assert.Same(t, pool, r.getDB())
+
+		// Only once
+		d.calls.Store(0)
+		d.deaths.Store(2)
+		assert.ErrorIs(t, r.Ping(ctx), mysql.ErrInvalidConn)
+		assert.Equal(t, int32(2), d.calls.Load())
+	})
+
+	t.Run("query", func(t *testing.T) {
+		r := connect(t)
+		rows, err := r.Query(ctx, "SELECT 1")
+		require.NoError(t, err)
+		rows.Close()
+		assert.Equal(t, int32(2), d.calls.Load())
+	})
+
+	t.Run("begin", func(t *testing.T) {
+		r := connect(t)
+		tx, err := r.Begin(ctx)
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+		assert.Equal(t, int32(2), d.calls.Load())
+	})
+
+	// A write may have been applied before its connection died, so it is not retried
+	t.Run("execute", func(t *testing.T) {
+		r := connect(t)
+		_, err := r.Execute(ctx, "DELETE FROM t")
+		assert.ErrorIs(t, err, mysql.ErrInvalidConn)
+		assert.Equal(t, int32(1), d.calls.Load())
+	})
+
+	t.Run("not connected", func(t *testing.T) {
+		r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test")
+		assert.Error(t, r.Ping(ctx))
+		_, err := r.Execute(ctx, "DELETE FROM t")
+		assert.Error(t, err)
+	})
+}
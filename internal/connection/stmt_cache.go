@@ -0,0 +1,117 @@
+package connection
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// stmtCache is a least-recently-used cache of prepared statements on one pool,
+// keyed by SQL text. Statements in use when they are evicted are closed once
+// released, so eviction never fails a running query.
+type stmtCache struct {
+	size int
+
+	mu    sync.Mutex
+	db    *sql.DB                  // Pool the cached statements were prepared on
+	order *list.List               // Entries, most recently used first
+	items map[string]*list.Element // Entries by query
+}
+
+// stmtEntry is a prepared statement and the number of callers using it.
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// acquire returns the statement for query prepared on db, preparing it on a miss.
+// Statements prepared on another pool, i.e. before the connection was closed and
+// reopened, are discarded. The caller must release the entry once the statement
+// has been executed.
+func (c *stmtCache) acquire(ctx context.Context, db *sql.DB, query string) (*stmtEntry, error) {
+	c.mu.Lock()
+	if c.db != db {
+		c.resetLocked(db)
+	}
+	if elem, ok := c.items[query]; ok {
+		c.order.MoveToFront(elem)
+		entry := elem.Value.(*stmtEntry)
+		entry.refs++
+		c.mu.Unlock()
+		return entry, nil
+	}
+	c.mu.Unlock()
+
+	// Prepare without holding the lock, so a slow prepare does not block hits
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	if c.db != db {
+		// Reopened meanwhile: use the statement once and close it
+		entry.evicted = true
+		return entry, nil
+	}
+	if elem, ok := c.items[query]; ok {
+		// Prepared concurrently by another caller: use theirs
+		stmt.Close()
+		entry = elem.Value.(*stmtEntry)
+		entry.refs++
+		return entry, nil
+	}
+	c.items[query] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.evictLocked(c.order.Back())
+	}
+	return entry, nil
+}
+
+// release ends a use of an entry returned by acquire.
+func (c *stmtCache) release(entry *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// reset discards all statements, e.g. when the pool is closed.
+func (c *stmtCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resetLocked(nil)
+}
+
+// resetLocked discards all statements and starts caching statements for db.
+func (c *stmtCache) resetLocked(db *sql.DB) {
+	for c.order.Len() > 0 {
+		c.evictLocked(c.order.Back())
+	}
+	c.db = db
+}
+
+// evictLocked removes an element, closing its statement unless it is in use.
+func (c *stmtCache) evictLocked(elem *list.Element) {
+	entry := c.order.Remove(elem).(*stmtEntry)
+	delete(c.items, entry.query)
+	entry.evicted = true
+	if entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
@@ -0,0 +1,153 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingDriver is a driver whose statements return no rows, counting how many
+// statements are prepared and closed
+type countingDriver struct {
+	prepared atomic.Int32
+	closed   atomic.Int32
+}
+
+func (d *countingDriver) Open(name string) (driver.Conn, error) { return &countingConn{d}, nil }
+
+type countingConn struct{ d *countingDriver }
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	if query == "UNPREPARABLE" {
+		return nil, errors.New("This command is not supported in the prepared statement protocol yet")
+	}
+	c.d.prepared.Add(1)
+	return &countingStmt{c.d}, nil
+}
+func (c *countingConn) Close() error              { return nil }
+func (c *countingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+// Query lets unprepared queries run without going through Prepare
+func (c *countingConn) Query(query string, args []driver.Value) (driver.Rows, error) {
+	return emptyRows{}, nil
+}
+
+type countingStmt struct{ d *countingDriver }
+
+func (s *countingStmt) Close() error  { s.d.closed.Add(1); return nil }
+func (s *countingStmt) NumInput() int { return -1 }
+func (s *countingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return []string{"id"} }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// countingDrivers makes driver names unique across test runs
+var countingDrivers atomic.Int32
+
+// newCountingConnection returns a connected RemoteConnection on a countingDriver
+func newCountingConnection(t *testing.T, opts ...RemoteOption) (*RemoteConnection, *countingDriver) {
+	d := &countingDriver{}
+	name := fmt.Sprintf("counting-%d", countingDrivers.Add(1))
+	sql.Register(name, d)
+
+	r := NewRemoteConnection("127.0.0.1", 2881, "root", "", "test", "test", opts...)
+	r.openDB = func(ctx context.Context, database string) (*sql.DB, error) {
+		return sql.Open(name, "")
+	}
+	require.NoError(t, r.Connect(context.Background()))
+	return r, d
+}
+
+func TestRemoteConnectionStatementCache(t *testing.T) {
+	ctx := context.Background()
+	r, d := newCountingConnection(t, WithStatementCacheSize(2))
+
+	query := func(q string, args ...interface{}) {
+		rows, err := r.Query(ctx, q, args...)
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+	}
+
+	// Repeated shapes are prepared once
+	for i := 0; i < 3; i++ {
+		query("SELECT a WHERE x = ?", i)
+	}
+	assert.Equal(t, int32(1), d.prepared.Load())
+
+	// The least recently used statement is evicted and closed
+	query("SELECT b WHERE x = ?", 1)
+	query("SELECT a WHERE x = ?", 1)
+	query("SELECT c WHERE x = ?", 1)
+	assert.Equal(t, int32(3), d.prepared.Load())
+	assert.Equal(t, int32(1), d.closed.Load())
+	query("SELECT a WHERE x = ?", 1)
+	assert.Equal(t, int32(3), d.prepared.Load())
+
+	// Evicting a statement does not fail rows still reading from it
+	rows, err := r.Query(ctx, "SELECT a WHERE x = ?", 2)
+	require.NoError(t, err)
+	query("SELECT d WHERE x = ?", 1)
+	query("SELECT e WHERE x = ?", 1)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	assert.Equal(t, int32(3), d.closed.Load())
+
+	// Statements that cannot be prepared run unprepared
+	query("UNPREPARABLE")
+
+	require.NoError(t, r.Close())
+	assert.Equal(t, d.prepared.Load(), d.closed.Load())
+}
+
+func TestRemoteConnectionStatementCacheDisabled(t *testing.T) {
+	ctx := context.Background()
+	r, d := newCountingConnection(t)
+	assert.Nil(t, r.stmts)
+
+	for i := 0; i < 3; i++ {
+		rows, err := r.Query(ctx, "SELECT a WHERE x = ?", i)
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+	}
+	assert.Zero(t, d.prepared.Load())
+	require.NoError(t, r.Close())
+}
+
+func TestRemoteConnectionStatementCacheConcurrent(t *testing.T) {
+	ctx := context.Background()
+	r, d := newCountingConnection(t, WithStatementCacheSize(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				rows, err := r.Query(ctx, fmt.Sprintf("SELECT %d WHERE x = ?", (i+j)%5), j)
+				if assert.NoError(t, err) {
+					assert.NoError(t, rows.Close())
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, r.Close())
+	assert.Equal(t, d.prepared.Load(), d.closed.Load())
+}
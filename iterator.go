@@ -0,0 +1,105 @@
+package goseekdb
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// Hit is a single document returned by a streaming read or a single-vector query.
+type Hit struct {
+	ID        string    `json:"id"`
+	Document  string    `json:"document,omitempty"`
+	Metadata  Metadata  `json:"metadata,omitempty"`
+	Embedding []float32 `json:"embedding,omitempty"`
+	Distance  float64   `json:"distance,omitempty"` // Distance to the query, or hybrid search score; set by QueryOne and HybridSearchResult.Hits
+
+	// RawColumns holds the columns selected WithExtraColumns or WithGetExtraColumns.
+	RawColumns map[string]interface{} `json:"raw_columns,omitempty"`
+}
+
+// HitIterator iterates over results backed by live database rows instead of
+// materializing them into a result struct. It must be closed when no longer
+// needed; it also closes itself once the rows are exhausted.
+//
+//	it, err := collection.GetStream(ctx, nil)
+//	if err != nil { ... }
+//	defer it.Close()
+//	for it.Next() {
+//		hit := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil { ... }
+type HitIterator struct {
+	rows *sql.Rows
+	item Hit
+	err  error
+
+	// integerMetadata decodes integer metadata values as int64 (see WithIntegerMetadata).
+	integerMetadata bool
+
+	// metadataKeys are the metadata keys selected one by one (see WithGetMetadataKeys).
+	metadataKeys []string
+
+	// raw scans the columns selected WithGetExtraColumns.
+	raw *rawColumnScanner
+}
+
+// newHitIterator wraps rows selecting id, document, metadata and embedding.
+func newHitIterator(rows *sql.Rows, integerMetadata bool) *HitIterator {
+	return &HitIterator{rows: rows, integerMetadata: integerMetadata}
+}
+
+// Next advances to the next hit, returning false when there are no more hits or an error occurred.
+func (it *HitIterator) Next() bool {
+	if it.rows == nil {
+		return false
+	}
+
+	if !it.rows.Next() {
+		it.err = it.rows.Err()
+		it.Close()
+		return false
+	}
+
+	// NULL documents are returned as ""
+	id, document, metadataJSON, embeddingJSON, err := scanGetRow(it.rows, it.metadataKeys, it.raw)
+	if err != nil {
+		it.err = err
+		it.Close()
+		return false
+	}
+
+	it.item = Hit{ID: id, Document: document.String, RawColumns: it.raw.row()}
+	decode := it.item.Metadata.FromJSON
+	if it.integerMetadata {
+		decode = it.item.Metadata.FromJSONPreserveIntegers
+	}
+	if err := decode(metadataJSON); err != nil {
+		it.item.Metadata = nil
+	}
+	if err := json.Unmarshal([]byte(embeddingJSON), &it.item.Embedding); err != nil {
+		it.item.Embedding = nil
+	}
+
+	return true
+}
+
+// Item returns the current hit.
+func (it *HitIterator) Item() Hit {
+	return it.item
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *HitIterator) Err() error {
+	return it.err
+}
+
+// Close releases the underlying rows. It is safe to call more than once.
+func (it *HitIterator) Close() error {
+	if it.rows == nil {
+		return nil
+	}
+	err := it.rows.Close()
+	it.rows = nil
+	return err
+}
@@ -0,0 +1,26 @@
+package goseekdb
+
+// Logger receives diagnostic messages from the client. It is a minimal interface
+// so any structured or leveled logging library can be adapted to it.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// nopLogger discards all messages; it is used when no logger is configured.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
+
+// logger returns the configured logger, or one discarding all messages.
+func (c *Client) logger() Logger {
+	if c.config == nil || c.config.Logger == nil {
+		return nopLogger{}
+	}
+	return c.config.Logger
+}
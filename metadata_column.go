@@ -0,0 +1,147 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetadataColumnType is the SQL type of a collection's metadata column, set with
+// WithMetadataColumnType.
+type MetadataColumnType string
+
+const (
+	// MetadataColumnJSON stores metadata in a native JSON column, validated on write
+	// and stored in a binary form that JSON_EXTRACT reads without reparsing.
+	MetadataColumnJSON MetadataColumnType = "JSON"
+	// MetadataColumnText stores metadata as plain TEXT, e.g. for servers whose JSON
+	// type is unavailable or to match tables created by other tools. Filters still
+	// work, but every JSON_EXTRACT parses the text.
+	MetadataColumnText MetadataColumnType = "TEXT"
+
+	// DefaultMetadataColumnType is the metadata column type of new collections.
+	DefaultMetadataColumnType = MetadataColumnJSON
+)
+
+// indexedMetadataColumnPrefix prefixes the generated columns holding the values of
+// indexed metadata keys, e.g. _meta_category for the key "category".
+const indexedMetadataColumnPrefix = "_meta_"
+
+// indexedMetadataIndexPrefix prefixes the indexes on those generated columns.
+const indexedMetadataIndexPrefix = "idx_meta_"
+
+// maxIndexedMetadataKeyLength keeps the index name within MySQL's identifier limit.
+const maxIndexedMetadataKeyLength = maxColumnNameLength - len(indexedMetadataIndexPrefix)
+
+// validateMetadataColumnOptions checks the metadata column options of a new collection.
+func validateMetadataColumnOptions(opts *CreateCollectionOptions) error {
+	switch opts.MetadataColumnType {
+	case "", MetadataColumnJSON, MetadataColumnText:
+	default:
+		return fmt.Errorf("%w: unsupported metadata column type %q (use %s or %s)",
+			ErrInvalidParameter, opts.MetadataColumnType, MetadataColumnJSON, MetadataColumnText)
+	}
+	if len(opts.IndexedMetadataKeys) == 0 {
+		return nil
+	}
+	if opts.VectorsOnly {
+		return fmt.Errorf("%w: WithIndexedMetadataKeys cannot be combined with WithVectorsOnly", ErrInvalidParameter)
+	}
+	if opts.MetadataColumnType == MetadataColumnText {
+		return fmt.Errorf("%w: WithIndexedMetadataKeys requires the %s metadata column type", ErrInvalidParameter, MetadataColumnJSON)
+	}
+
+	seen := make(map[string]bool, len(opts.IndexedMetadataKeys))
+	for _, key := range opts.IndexedMetadataKeys {
+		if err := validateIndexedMetadataKey(key); err != nil {
+			return err
+		}
+		// Column names are case-insensitive
+		if seen[strings.ToLower(key)] {
+			return fmt.Errorf("%w: duplicate indexed metadata key %q", ErrInvalidParameter, key)
+		}
+		seen[strings.ToLower(key)] = true
+	}
+	return nil
+}
+
+// validateIndexedMetadataKey checks that an indexed metadata key is a plain
+// identifier, since it is part of a column and an index name.
+func validateIndexedMetadataKey(key string) error {
+	if key == "" || len(key) > maxIndexedMetadataKeyLength {
+		return fmt.Errorf("%w: indexed metadata key %q must have 1 to %d characters", ErrInvalidParameter, key, maxIndexedMetadataKeyLength)
+	}
+	for _, r := range key {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != '_' {
+			return fmt.Errorf("%w: indexed metadata key %q must contain only letters, digits and '_'", ErrInvalidParameter, key)
+		}
+	}
+	return nil
+}
+
+// metadataColumnDefinitions returns the definitions of the metadata column and of
+// the generated columns and indexes of indexed keys, for use in CREATE TABLE.
+func metadataColumnDefinitions(opts *CreateCollectionOptions) []string {
+	columnType := opts.MetadataColumnType
+	if columnType == "" {
+		columnType = DefaultMetadataColumnType
+	}
+	definitions := []string{fmt.Sprintf("%s %s", FieldMetadata, columnType)}
+	for _, key := range opts.IndexedMetadataKeys {
+		definitions = append(definitions,
+			indexedMetadataColumnDefinition(key),
+			fmt.Sprintf("KEY %s (%s)", indexedMetadataIndex(key), indexedMetadataColumn(key)))
+	}
+	return definitions
+}
+
+// indexedMetadataColumn returns the generated column holding a metadata key.
+func indexedMetadataColumn(key string) string {
+	return indexedMetadataColumnPrefix + key
+}
+
+// indexedMetadataIndex returns the index on the generated column of a metadata key.
+func indexedMetadataIndex(key string) string {
+	return indexedMetadataIndexPrefix + key
+}
+
+// indexedMetadataColumnDefinition returns the definition of the generated column
+// holding a metadata key's unquoted value. It is virtual, so only the index stores it.
+func indexedMetadataColumnDefinition(key string) string {
+	return fmt.Sprintf("%s VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(%s, '$.%s'))) VIRTUAL",
+		indexedMetadataColumn(key), FieldMetadata, key)
+}
+
+// buildIndexedMetadataCondition builds an $eq or $in condition on an indexed
+// metadata key against its generated column, so the index can serve it. Only
+// string values are rewritten: the column holds unquoted text, which compares like
+// the JSON value only for strings. ok is false if the condition must be built on
+// JSON_EXTRACT as usual.
+func buildIndexedMetadataCondition(key, op string, value interface{}) (condition string, args []interface{}, ok bool) {
+	column := indexedMetadataColumn(key)
+	switch op {
+	case "$eq":
+		s, isString := value.(string)
+		if !isString {
+			return "", nil, false
+		}
+		return fmt.Sprintf("%s = ?", column), []interface{}{s}, true
+	case "$in":
+		values, isList := arrayOperandValues(value)
+		if !isList || len(values) == 0 {
+			return "", nil, false
+		}
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			s, isString := v.(string)
+			if !isString {
+				return "", nil, false
+			}
+			placeholders[i] = "?"
+			args = append(args, s)
+		}
+		return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")), args, true
+	}
+	return "", nil, false
+}
@@ -0,0 +1,131 @@
+package goseekdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetadataColumnDefinitions tests the metadata column and indexed key definitions
+func TestMetadataColumnDefinitions(t *testing.T) {
+	assert.Equal(t, []string{"metadata JSON"}, metadataColumnDefinitions(&CreateCollectionOptions{}))
+	assert.Equal(t, []string{"metadata TEXT"}, metadataColumnDefinitions(&CreateCollectionOptions{MetadataColumnType: MetadataColumnText}))
+
+	options := &CreateCollectionOptions{}
+	WithIndexedMetadataKeys([]string{"category"})(options)
+	assert.Equal(t, []string{
+		"metadata JSON",
+		"_meta_category VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.category'))) VIRTUAL",
+		"KEY idx_meta_category (_meta_category)",
+	}, metadataColumnDefinitions(options))
+}
+
+// TestValidateMetadataColumnOptions tests rejecting invalid metadata column options
+func TestValidateMetadataColumnOptions(t *testing.T) {
+	valid := []*CreateCollectionOptions{
+		{},
+		{MetadataColumnType: MetadataColumnText},
+		{MetadataColumnType: MetadataColumnJSON, IndexedMetadataKeys: []string{"category", "year_2"}},
+	}
+	for _, options := range valid {
+		assert.NoError(t, validateMetadataColumnOptions(options))
+	}
+
+	invalid := []*CreateCollectionOptions{
+		{MetadataColumnType: "BLOB"},
+		{MetadataColumnType: MetadataColumnText, IndexedMetadataKeys: []string{"category"}},
+		{VectorsOnly: true, IndexedMetadataKeys: []string{"category"}},
+		{IndexedMetadataKeys: []string{""}},
+		{IndexedMetadataKeys: []string{"a.b"}},
+		{IndexedMetadataKeys: []string{"it's"}},
+		{IndexedMetadataKeys: []string{strings.Repeat("k", maxIndexedMetadataKeyLength+1)}},
+		{IndexedMetadataKeys: []string{"category", "Category"}},
+	}
+	for _, options := range invalid {
+		assert.ErrorIs(t, validateMetadataColumnOptions(options), ErrInvalidParameter, "%+v", options)
+	}
+}
+
+// TestBuildIndexedMetadataCondition tests filters rewritten to indexed generated columns
+func TestBuildIndexedMetadataCondition(t *testing.T) {
+	condition, args, ok := buildIndexedMetadataCondition("category", "$eq", "AI")
+	require.True(t, ok)
+	assert.Equal(t, "_meta_category = ?", condition)
+	assert.Equal(t, []interface{}{"AI"}, args)
+
+	condition, args, ok = buildIndexedMetadataCondition("category", "$in", []string{"AI", "ML"})
+	require.True(t, ok)
+	assert.Equal(t, "_meta_category IN (?, ?)", condition)
+	assert.Equal(t, []interface{}{"AI", "ML"}, args)
+
+	// Non-string values and other operators keep using JSON_EXTRACT
+	for _, tc := range []struct {
+		op    string
+		value interface{}
+	}{
+		{"$eq", 5},
+		{"$eq", true},
+		{"$in", []interface{}{"AI", 5}},
+		{"$in", []string{}},
+		{"$gt", "AI"},
+	} {
+		_, _, ok := buildIndexedMetadataCondition("category", tc.op, tc.value)
+		assert.False(t, ok, "%s %v", tc.op, tc.value)
+	}
+}
+
+// TestIndexedMetadataKeysMeta tests that the metadata column options are persisted
+func TestIndexedMetadataKeysMeta(t *testing.T) {
+	meta := newCollectionMeta(nil, nil)
+	meta.MetadataColumnType = MetadataColumnJSON
+	meta.IndexedMetadataKeys = []string{"category"}
+	option, err := meta.tableOption()
+	require.NoError(t, err)
+
+	parsed, ok, err := parseCollectionMeta(strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(option, "COMMENT = '"), "'"), "''", "'"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, MetadataColumnJSON, parsed.MetadataColumnType)
+	assert.Equal(t, []string{"category"}, parsed.IndexedMetadataKeys)
+}
+
+// TestIndexedMetadataFilters tests that filters on indexed metadata keys use their
+// generated columns once the keys of the collection are known
+func TestIndexedMetadataFilters(t *testing.T) {
+	builder := newIndexedFilterBuilder([]string{"category"})
+	clause, args, err := builder.BuildMetadataFilter(Filter{"category": "AI", "year": 2020})
+	require.NoError(t, err)
+	assert.Equal(t, "(_meta_category = ?) AND (JSON_EXTRACT(metadata, ?) = ?)", clause)
+	assert.Equal(t, []interface{}{"AI", "$.year", 2020}, args)
+
+	clause, args, err = builder.BuildMetadataFilter(Filter{"category": Filter{"$in": []string{"AI", "ML"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "_meta_category IN (?, ?)", clause)
+	assert.Equal(t, []interface{}{"AI", "ML"}, args)
+
+	// A nil builder has no indexed keys
+	var nilBuilder *FilterBuilder
+	clause, _, err = nilBuilder.BuildMetadataFilter(Filter{"category": "AI"})
+	require.NoError(t, err)
+	assert.Equal(t, "JSON_EXTRACT(metadata, ?) = ?", clause)
+
+	client := &Client{config: &ClientConfig{}}
+	countSQL, _, err := client.buildCountSQL("docs", Filter{"category": "AI"}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, countSQL, "JSON_EXTRACT(metadata, ?) = ?")
+
+	client.storeIndexedMetadataKeys("docs", []string{"category"})
+	countSQL, args, err = client.buildCountSQL("docs", Filter{"category": "AI"}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, countSQL, "_meta_category = ?")
+	assert.Equal(t, []interface{}{"AI"}, args)
+
+	client.swapIndexedMetadataKeys("docs", "shadow")
+	assert.Nil(t, client.collectionFilterBuilder("docs"))
+	assert.True(t, client.collectionFilterBuilder("shadow").isIndexed("category"))
+
+	client.storeIndexedMetadataKeys("shadow", nil)
+	assert.Nil(t, client.collectionFilterBuilder("shadow"))
+}
@@ -0,0 +1,173 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// collectionCreateMetadataIndex adds an indexed generated column for a metadata key
+// to an existing collection (see Collection.CreateMetadataIndex).
+func (c *Client) collectionCreateMetadataIndex(ctx context.Context, collectionName, key string, opts *MetadataIndexOptions) (err error) {
+	ctx, span := c.startSpan(ctx, "create_metadata_index", collectionName)
+	defer func() { span.end(0, err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if err := validateIndexedMetadataKey(key); err != nil {
+		return err
+	}
+
+	keys, err := c.collectionListMetadataIndexes(ctx, collectionName)
+	if err != nil {
+		return err
+	}
+	for _, existing := range keys {
+		if !strings.EqualFold(existing, key) {
+			continue
+		}
+		if opts.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("%w: metadata key %q of collection %s is already indexed", ErrInvalidParameter, key, collectionName)
+	}
+
+	for _, stmt := range createMetadataIndexSQL(c.GetTableName(collectionName), key) {
+		if _, err := c.conn.Execute(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create metadata index on %q: %w", key, err)
+		}
+	}
+	c.storeIndexedMetadataKeys(collectionName, append(keys, key))
+	return nil
+}
+
+// collectionDropMetadataIndex removes the index and generated column of a metadata
+// key (see Collection.DropMetadataIndex).
+func (c *Client) collectionDropMetadataIndex(ctx context.Context, collectionName, key string) (err error) {
+	ctx, span := c.startSpan(ctx, "drop_metadata_index", collectionName)
+	defer func() { span.end(0, err) }()
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	if err := validateIndexedMetadataKey(key); err != nil {
+		return err
+	}
+
+	keys, err := c.collectionListMetadataIndexes(ctx, collectionName)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, existing := range keys {
+		if strings.EqualFold(existing, key) {
+			key, found = existing, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: metadata key %q of collection %s is not indexed", ErrInvalidParameter, key, collectionName)
+	}
+
+	for _, stmt := range dropMetadataIndexSQL(c.GetTableName(collectionName), key) {
+		if _, err := c.conn.Execute(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to drop metadata index on %q: %w", key, err)
+		}
+	}
+	remaining := make([]string, 0, len(keys)-1)
+	for _, existing := range keys {
+		if existing != key {
+			remaining = append(remaining, existing)
+		}
+	}
+	c.storeIndexedMetadataKeys(collectionName, remaining)
+	return nil
+}
+
+// collectionListMetadataIndexes returns the indexed metadata keys of a collection,
+// read from its index names so that indexes created at creation time with
+// WithIndexedMetadataKeys, by CreateMetadataIndex or by other clients are all listed.
+func (c *Client) collectionListMetadataIndexes(ctx context.Context, collectionName string) ([]string, error) {
+	indexes, err := c.listIndexNames(ctx, c.GetTableName(collectionName))
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) == 0 {
+		// Every collection has a primary key, so the table is missing
+		exists, err := c.HasCollection(ctx, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, collectionName)
+		}
+	}
+	return metadataIndexKeys(indexes), nil
+}
+
+// metadataIndexKeys returns the metadata keys of the indexed metadata key indexes
+// among a table's index names, sorted.
+func metadataIndexKeys(indexes []string) []string {
+	keys := []string{}
+	for _, index := range indexes {
+		if key := strings.TrimPrefix(index, indexedMetadataIndexPrefix); key != index && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// createMetadataIndexSQL returns the statements adding the generated column of a
+// metadata key to a table and indexing it.
+func createMetadataIndexSQL(tableName, key string) []string {
+	return []string{
+		fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s", tableName, indexedMetadataColumnDefinition(key)),
+		fmt.Sprintf("CREATE INDEX %s ON `%s` (%s)", indexedMetadataIndex(key), tableName, indexedMetadataColumn(key)),
+	}
+}
+
+// dropMetadataIndexSQL returns the statements removing the index and generated
+// column of a metadata key from a table: the index first, as createMetadataIndexSQL
+// creates it last.
+func dropMetadataIndexSQL(tableName, key string) []string {
+	return []string{
+		fmt.Sprintf("DROP INDEX %s ON `%s`", indexedMetadataIndex(key), tableName),
+		fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN %s", tableName, indexedMetadataColumn(key)),
+	}
+}
+
+// storeIndexedMetadataKeys remembers the indexed metadata keys of a collection, for
+// the filters of its statements.
+func (c *Client) storeIndexedMetadataKeys(collectionName string, keys []string) {
+	if len(keys) == 0 {
+		c.indexedMetadataKeys.Delete(collectionName)
+		return
+	}
+	c.indexedMetadataKeys.Store(collectionName, newIndexedFilterBuilder(keys))
+}
+
+// swapIndexedMetadataKeys exchanges the remembered indexed metadata keys of two
+// collections after their tables were swapped.
+func (c *Client) swapIndexedMetadataKeys(a, b string) {
+	builderA, okA := c.indexedMetadataKeys.LoadAndDelete(a)
+	builderB, okB := c.indexedMetadataKeys.LoadAndDelete(b)
+	if okA {
+		c.indexedMetadataKeys.Store(b, builderA)
+	}
+	if okB {
+		c.indexedMetadataKeys.Store(a, builderB)
+	}
+}
+
+// collectionFilterBuilder returns the filter builder for the statements of a
+// collection: one comparing $eq and $in filters on its indexed metadata keys against
+// their generated columns if they are known, or else the client's.
+func (c *Client) collectionFilterBuilder(collectionName string) *FilterBuilder {
+	if builder, ok := c.indexedMetadataKeys.Load(collectionName); ok {
+		return builder.(*FilterBuilder)
+	}
+	return c.filterBuilder
+}
@@ -0,0 +1,77 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetadataIndexSQL tests the statements adding and removing a metadata key index
+func TestMetadataIndexSQL(t *testing.T) {
+	assert.Equal(t, []string{
+		"ALTER TABLE `c$v1$docs` ADD COLUMN _meta_category VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.category'))) VIRTUAL",
+		"CREATE INDEX idx_meta_category ON `c$v1$docs` (_meta_category)",
+	}, createMetadataIndexSQL("c$v1$docs", "category"))
+
+	assert.Equal(t, []string{
+		"DROP INDEX idx_meta_category ON `c$v1$docs`",
+		"ALTER TABLE `c$v1$docs` DROP COLUMN _meta_category",
+	}, dropMetadataIndexSQL("c$v1$docs", "category"))
+
+	assert.Equal(t, []string{"category", "year"},
+		metadataIndexKeys([]string{"PRIMARY", "idx_meta_year", "idx_embedding", "idx_meta_category", "idx_meta_"}))
+	assert.Empty(t, metadataIndexKeys([]string{"PRIMARY"}))
+
+	collection := &Collection{client: &fakeOperations{}, name: "vectors", dimension: 3, vectorsOnly: true}
+	assert.ErrorIs(t, collection.CreateMetadataIndex(context.Background(), "category"), ErrInvalidParameter)
+}
+
+// TestCollectionMetadataIndex tests creating, listing and dropping a metadata key index
+func TestCollectionMetadataIndex(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_metadata_index_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{{"category": "AI"}, {"category": "ML"}}),
+	)
+	require.NoError(t, err)
+
+	keys, err := collection.ListMetadataIndexes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	require.NoError(t, collection.CreateMetadataIndex(ctx, "category"))
+	assert.ErrorIs(t, collection.CreateMetadataIndex(ctx, "category"), ErrInvalidParameter)
+	require.NoError(t, collection.CreateMetadataIndex(ctx, "category", WithMetadataIndexIfNotExists(true)))
+	assert.ErrorIs(t, collection.CreateMetadataIndex(ctx, "a.b"), ErrInvalidParameter)
+
+	keys, err = collection.ListMetadataIndexes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"category"}, keys)
+
+	// Filters keep working with the generated column in place
+	results, err := collection.Get(ctx, nil, WithGetWhere(Filter{"category": Filter{"$eq": "AI"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id1"}, results.IDs)
+
+	require.NoError(t, collection.DropMetadataIndex(ctx, "category"))
+	assert.ErrorIs(t, collection.DropMetadataIndex(ctx, "category"), ErrInvalidParameter)
+	keys, err = collection.ListMetadataIndexes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	item, err := collection.GetItem(ctx, "id2")
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{"category": "ML"}, item.Metadata)
+}
@@ -0,0 +1,16 @@
+package goseekdb
+
+import "fmt"
+
+// upsertMetadataAssignment returns the ON DUPLICATE KEY UPDATE assignment of the
+// metadata column for an Upsert. By default the stored metadata is replaced; with
+// WithUpsertMergeMetadata the new metadata is merged into it with JSON_MERGE_PATCH,
+// treating missing metadata on either side as an empty object so that rows upserted
+// without metadata keep theirs.
+func upsertMetadataAssignment(merge bool) string {
+	if !merge {
+		return fmt.Sprintf("%s = VALUES(%s)", FieldMetadata, FieldMetadata)
+	}
+	return fmt.Sprintf("%s = JSON_MERGE_PATCH(COALESCE(%s, '{}'), COALESCE(VALUES(%s), '{}'))",
+		FieldMetadata, FieldMetadata, FieldMetadata)
+}
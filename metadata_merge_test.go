@@ -0,0 +1,81 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpsertMetadataAssignment tests the metadata assignment of an Upsert's update clause
+func TestUpsertMetadataAssignment(t *testing.T) {
+	assert.Equal(t, "metadata = VALUES(metadata)", upsertMetadataAssignment(false))
+	assert.Equal(t, "metadata = JSON_MERGE_PATCH(COALESCE(metadata, '{}'), COALESCE(VALUES(metadata), '{}'))", upsertMetadataAssignment(true))
+
+	collection := &Collection{client: &fakeOperations{}, name: "merge", dimension: 3, vectorsOnly: true}
+	err := collection.Upsert(context.Background(), []string{"id1"}, nil,
+		WithEmbeddings([][]float32{{1, 2, 3}}), WithUpsertMergeMetadata(true))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	assert.Equal(t, "INSERT INTO c$v1$docs (_id, document, embedding, metadata) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE "+
+		"document = VALUES(document), embedding = VALUES(embedding), "+
+		"metadata = JSON_MERGE_PATCH(COALESCE(metadata, '{}'), COALESCE(VALUES(metadata), '{}'))",
+		upsertRecordSQL("c$v1$docs", &AddOptions{MergeMetadata: true}, SchemaVersion1))
+	assert.Contains(t, upsertRecordSQL("c$v1$docs", &AddOptions{}, SchemaVersion1), "metadata = VALUES(metadata)")
+}
+
+// TestCollectionUpsertMergeMetadata tests that keys not re-supplied survive a merging Upsert
+func TestCollectionUpsertMergeMetadata(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_upsert_merge_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{
+			{"category": "AI", "author": "ann", "score": 90},
+			{"category": "ML", "author": "bob"},
+		}),
+	)
+	require.NoError(t, err)
+
+	// id1 gets partial metadata, id2 none, id3 is new
+	result, err := collection.UpsertN(ctx, []string{"id1", "id2", "id3"}, []string{"doc 1 v2", "doc 2 v2", "doc 3"},
+		WithEmbeddings([][]float32{{1, 2, 4}, {4, 5, 7}, {7, 8, 9}}),
+		WithMetadatas([]Metadata{{"category": "CV", "score": nil}, nil, {"category": "NLP"}}),
+		WithUpsertMergeMetadata(true),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, UpsertResult{Inserted: 1, Updated: 2}, result)
+
+	results, err := collection.Get(ctx, []string{"id1", "id2", "id3"})
+	require.NoError(t, err)
+	byID := make(map[string]Metadata)
+	documents := make(map[string]string)
+	for i, id := range results.IDs {
+		byID[id] = results.Metadatas[i]
+		documents[id] = results.Documents[i]
+	}
+	assert.Equal(t, Metadata{"category": "CV", "author": "ann"}, byID["id1"])
+	assert.Equal(t, Metadata{"category": "ML", "author": "bob"}, byID["id2"])
+	assert.Equal(t, Metadata{"category": "NLP"}, byID["id3"])
+	assert.Equal(t, "doc 1 v2", documents["id1"])
+
+	// Without merging the metadata is replaced
+	err = collection.Upsert(ctx, []string{"id1"}, []string{"doc 1 v3"},
+		WithEmbeddings([][]float32{{1, 2, 5}}),
+		WithMetadatas([]Metadata{{"category": "RL"}}),
+	)
+	require.NoError(t, err)
+	item, err := collection.GetItem(ctx, "id1")
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{"category": "RL"}, item.Metadata)
+}
@@ -0,0 +1,47 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetadataOrder orders results by the value of a metadata key.
+type MetadataOrder struct {
+	Key  string
+	Desc bool
+}
+
+// validate checks that the key is a plain identifier, so it can be used as a JSON
+// path without quoting. A nil order is valid.
+func (o *MetadataOrder) validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Key == "" {
+		return fmt.Errorf("%w: order by key must not be empty", ErrInvalidParameter)
+	}
+	for i, r := range o.Key {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && r != '_' && (!isDigit || i == 0) {
+			return fmt.Errorf("%w: order by key %q must start with a letter or '_' and contain only letters, digits or '_'", ErrInvalidParameter, o.Key)
+		}
+	}
+	return nil
+}
+
+// sql returns the ORDER BY terms for the order and their arguments. Numeric values
+// are compared as numbers and other values as text; rows missing the key sort like
+// NULL, first ascending and last descending.
+func (o *MetadataOrder) sql() (string, []interface{}) {
+	direction := "ASC"
+	if o.Desc {
+		direction = "DESC"
+	}
+	path := "$." + o.Key
+	terms := []string{
+		fmt.Sprintf("CASE WHEN JSON_TYPE(JSON_EXTRACT(%s, ?)) IN ('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL') THEN CAST(JSON_EXTRACT(%s, ?) AS DOUBLE) END %s", FieldMetadata, FieldMetadata, direction),
+		fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, ?)) %s", FieldMetadata, direction),
+	}
+	return strings.Join(terms, ", "), []interface{}{path, path, path}
+}
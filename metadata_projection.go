@@ -0,0 +1,82 @@
+package goseekdb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// projectedMetadataKeys returns the metadata keys a Get selects one by one, or nil if
+// it selects the whole metadata column. Keys are projected only when the full
+// column is not requested as well.
+func projectedMetadataKeys(opts *GetOptions) []string {
+	if len(opts.MetadataKeys) == 0 {
+		return nil
+	}
+	for _, include := range opts.Include {
+		if include == "metadatas" {
+			return nil
+		}
+	}
+	return opts.MetadataKeys
+}
+
+// getSelectColumns returns the select list of a Get and its arguments: the id,
+// document, metadata and embedding columns and, if metadata keys are projected, one
+// JSON_EXTRACT per key in place of the full metadata column.
+func getSelectColumns(metadataKeys []string) (string, []interface{}) {
+	if len(metadataKeys) == 0 {
+		return fmt.Sprintf("%s, %s, %s, %s", FieldID, FieldDocument, FieldMetadata, FieldEmbedding), nil
+	}
+
+	columns := fmt.Sprintf("%s, %s, '{}' AS %s, %s", FieldID, FieldDocument, FieldMetadata, FieldEmbedding)
+	args := make([]interface{}, len(metadataKeys))
+	for i, key := range metadataKeys {
+		columns += fmt.Sprintf(", JSON_EXTRACT(%s, ?)", FieldMetadata)
+		args[i] = "$." + key
+	}
+	return columns, args
+}
+
+// scanGetRow scans a row selected with getSelectColumns, followed by the extra
+// columns of raw, if any. With projected metadata keys, the returned metadata JSON
+// holds only the keys present in the row.
+func scanGetRow(rows *sql.Rows, metadataKeys []string, raw *rawColumnScanner) (id string, document sql.NullString, metadataJSON, embeddingJSON string, err error) {
+	values := make([]sql.NullString, len(metadataKeys))
+	dest := []interface{}{&id, &document, &metadataJSON, &embeddingJSON}
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+	if err := rows.Scan(raw.dest(dest)...); err != nil {
+		return "", sql.NullString{}, "", "", err
+	}
+	if len(metadataKeys) > 0 {
+		metadataJSON = projectedMetadataJSON(metadataKeys, values)
+	}
+	return id, document, metadataJSON, embeddingJSON, nil
+}
+
+// projectedMetadataJSON assembles the JSON values extracted per key into a metadata
+// object. Keys missing from the row (SQL NULL) are left out; keys holding JSON null
+// are kept.
+func projectedMetadataJSON(keys []string, values []sql.NullString) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	first := true
+	for i, key := range keys {
+		if !values[i].Valid {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		encodedKey, _ := json.Marshal(key)
+		b.Write(encodedKey)
+		b.WriteByte(':')
+		b.WriteString(values[i].String)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
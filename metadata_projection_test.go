@@ -0,0 +1,108 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildGetSQLMetadataKeys tests that projected keys replace the metadata column
+func TestBuildGetSQLMetadataKeys(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	options := &GetOptions{Limit: 10}
+	WithGetMetadataKeys([]string{"title", "year"})(options)
+	querySQL, args, err := client.buildGetSQL("docs", []string{"id1"}, options)
+	require.NoError(t, err)
+	assert.Contains(t, querySQL, "SELECT _id, document, '{}' AS metadata, embedding, JSON_EXTRACT(metadata, ?), JSON_EXTRACT(metadata, ?)")
+	assert.Equal(t, []interface{}{"$.title", "$.year", "id1", 10, 0}, args)
+
+	// The full column wins when it is included as well
+	options.Include = []string{"documents", "metadatas"}
+	querySQL, args, err = client.buildGetSQL("docs", nil, options)
+	require.NoError(t, err)
+	assert.Contains(t, querySQL, "SELECT _id, document, metadata, embedding\n")
+	assert.Equal(t, []interface{}{10, 0}, args)
+}
+
+func TestProjectedMetadataJSON(t *testing.T) {
+	values := []sql.NullString{
+		{String: `"Go"`, Valid: true},
+		{},
+		{String: "null", Valid: true},
+		{String: `{"a":[1,2]}`, Valid: true},
+	}
+	assert.JSONEq(t, `{"title":"Go","empty":null,"nested":{"a":[1,2]}}`,
+		projectedMetadataJSON([]string{"title", "missing", "empty", "nested"}, values))
+	assert.Equal(t, "{}", projectedMetadataJSON([]string{"missing"}, []sql.NullString{{}}))
+}
+
+// TestCollectionGetMetadataKeysScan tests decoding projected rows in Get and GetStream
+func TestCollectionGetMetadataKeysScan(t *testing.T) {
+	ctx := context.Background()
+	rows := [][]driver.Value{
+		{[]byte("id1"), []byte("doc 1"), []byte("{}"), []byte("[1,2,3]"), []byte(`"Go"`), []byte("2024")},
+		{[]byte("id2"), nil, []byte("{}"), []byte("[4,5,6]"), nil, []byte("2023")},
+	}
+	columns := []string{"_id", "document", "metadata", "embedding", "title", "year"}
+	db := registerStaticRows(t, "get_metadata_keys", columns, rows)
+	client := &Client{
+		conn:   &staticConnection{db: db, key: "get_metadata_keys"},
+		config: &ClientConfig{IntegerMetadata: true},
+	}
+	options := &GetOptions{MetadataKeys: []string{"title", "year"}}
+
+	result, err := client.collectionGet(ctx, "docs", nil, options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id1", "id2"}, result.IDs)
+	assert.Equal(t, []Metadata{{"title": "Go", "year": int64(2024)}, {"year": int64(2023)}}, result.Metadatas)
+
+	it, err := client.collectionGetStream(ctx, "docs", nil, options)
+	require.NoError(t, err)
+	defer it.Close()
+	require.True(t, it.Next())
+	assert.Equal(t, Hit{ID: "id1", Document: "doc 1", Metadata: Metadata{"title": "Go", "year": int64(2024)}, Embedding: []float32{1, 2, 3}}, it.Item())
+	require.True(t, it.Next())
+	assert.Equal(t, Metadata{"year": int64(2023)}, it.Item().Metadata)
+	assert.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
+
+// TestCollectionGetMetadataKeys tests projecting metadata keys against a live database
+func TestCollectionGetMetadataKeys(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_get_metadata_keys_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{
+			{"title": "Go", "year": 2024, "body": "long text"},
+			{"year": 2023, "tags": []string{"a", "b"}},
+		}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, []string{"id1", "id2"}, WithGetMetadataKeys([]string{"title", "year"}))
+	require.NoError(t, err)
+	require.Len(t, results.Metadatas, 2)
+	for i, id := range results.IDs {
+		switch id {
+		case "id1":
+			assert.Equal(t, Metadata{"title": "Go", "year": float64(2024)}, results.Metadatas[i])
+		case "id2":
+			assert.Equal(t, Metadata{"year": float64(2023)}, results.Metadatas[i])
+		}
+	}
+}
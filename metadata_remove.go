@@ -0,0 +1,38 @@
+package goseekdb
+
+import "fmt"
+
+// metadataUpdateAssignment returns the SET assignment of the metadata column for an
+// Update of one row and its arguments. metadataJSON is the row's new metadata, or
+// nil if the Update leaves it unchanged; removeKeys are then removed with
+// JSON_REMOVE, so that sets apply first. ok is false if the Update changes neither.
+func metadataUpdateAssignment(metadataJSON *string, removeKeys []string) (assignment string, args []interface{}, ok bool) {
+	if len(removeKeys) == 0 {
+		if metadataJSON == nil {
+			return "", nil, false
+		}
+		return fmt.Sprintf("%s = ?", FieldMetadata), []interface{}{*metadataJSON}, true
+	}
+
+	target := FieldMetadata
+	if metadataJSON != nil {
+		target = "?"
+		args = append(args, *metadataJSON)
+	}
+	placeholders := ""
+	for _, key := range removeKeys {
+		placeholders += ", ?"
+		args = append(args, "$."+key)
+	}
+	return fmt.Sprintf("%s = JSON_REMOVE(%s%s)", FieldMetadata, target, placeholders), args, true
+}
+
+// validateRemoveMetadataKeys checks the keys passed to WithRemoveMetadataKeys.
+func validateRemoveMetadataKeys(keys []string) error {
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("%w: metadata key to remove must not be empty", ErrInvalidParameter)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetadataUpdateAssignment tests the metadata SET clause of an Update with removed keys
+func TestMetadataUpdateAssignment(t *testing.T) {
+	_, _, ok := metadataUpdateAssignment(nil, nil)
+	assert.False(t, ok)
+
+	metadata := `{"a":1}`
+	assignment, args, ok := metadataUpdateAssignment(&metadata, nil)
+	require.True(t, ok)
+	assert.Equal(t, "metadata = ?", assignment)
+	assert.Equal(t, []interface{}{metadata}, args)
+
+	assignment, args, ok = metadataUpdateAssignment(nil, []string{"draft", "score"})
+	require.True(t, ok)
+	assert.Equal(t, "metadata = JSON_REMOVE(metadata, ?, ?)", assignment)
+	assert.Equal(t, []interface{}{"$.draft", "$.score"}, args)
+
+	assignment, args, ok = metadataUpdateAssignment(&metadata, []string{"draft"})
+	require.True(t, ok)
+	assert.Equal(t, "metadata = JSON_REMOVE(?, ?)", assignment)
+	assert.Equal(t, []interface{}{metadata, "$.draft"}, args)
+
+	collection := &Collection{client: &fakeOperations{}, name: "remove", dimension: 3}
+	err := collection.Update(context.Background(), []string{"id1"}, WithRemoveMetadataKeys([]string{""}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	// Removing keys alone is an update, applied after the new metadata otherwise
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{}}
+	var dryRun *DryRunError
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{RemoveMetadataKeys: []string{"draft"}}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "UPDATE c$v1$docs SET metadata = JSON_REMOVE(metadata, ?) WHERE _id = ?", dryRun.Statements[0].SQL)
+	assert.Equal(t, []interface{}{"$.draft", "id1"}, dryRun.Statements[0].Args)
+
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{
+		Metadatas:          []Metadata{{"a": 1}},
+		RemoveMetadataKeys: []string{"draft"},
+	}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "UPDATE c$v1$docs SET metadata = JSON_REMOVE(?, ?) WHERE _id = ?", dryRun.Statements[0].SQL)
+	assert.Equal(t, []interface{}{`{"a":1}`, "$.draft", "id1"}, dryRun.Statements[0].Args)
+}
+
+// TestCollectionUpdateRemoveMetadataKeys tests that removed keys are gone after Update
+func TestCollectionUpdateRemoveMetadataKeys(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_remove_keys_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}),
+		WithMetadatas([]Metadata{
+			{"category": "AI", "draft": true, "score": 90},
+			{"category": "ML", "draft": true},
+		}),
+	)
+	require.NoError(t, err)
+
+	// Remove only
+	err = collection.Update(ctx, []string{"id1"}, WithRemoveMetadataKeys([]string{"draft", "missing"}))
+	require.NoError(t, err)
+
+	// Set and remove in the same call
+	err = collection.Update(ctx, []string{"id2"},
+		WithUpdateMetadatas([]Metadata{{"category": "CV", "draft": false, "year": 2024}}),
+		WithRemoveMetadataKeys([]string{"draft"}),
+	)
+	require.NoError(t, err)
+
+	results, err := collection.Get(ctx, []string{"id1", "id2"})
+	require.NoError(t, err)
+	byID := make(map[string]Metadata)
+	for i, id := range results.IDs {
+		byID[id] = results.Metadatas[i]
+	}
+	assert.Equal(t, Metadata{"category": "AI", "score": float64(90)}, byID["id1"])
+	assert.Equal(t, Metadata{"category": "CV", "year": float64(2024)}, byID["id2"])
+}
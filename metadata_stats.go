@@ -0,0 +1,134 @@
+package goseekdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	// DefaultMetadataSampleSize is the number of rows DescribeMetadata samples when
+	// no sample size is given.
+	DefaultMetadataSampleSize = 1000
+
+	// MetadataDistinctLimit caps the distinct values DescribeMetadata counts per key.
+	MetadataDistinctLimit = 100
+)
+
+// MetadataFieldStats describes the values of one metadata key in a sample of rows.
+type MetadataFieldStats struct {
+	// Count is the number of sampled rows with a non-null value for the key.
+	Count int `json:"count"`
+	// NullCount is the number of sampled rows where the key is missing or null,
+	// and NullFraction its share of the sample.
+	NullCount    int     `json:"null_count"`
+	NullFraction float64 `json:"null_fraction"`
+	// Types lists the observed Go types of the decoded values, e.g. "string",
+	// "float64", "bool", "[]interface {}" or "map[string]interface {}".
+	Types []string `json:"types"`
+	// Min and Max are the smallest and largest numeric values, nil if none were seen.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// DistinctCount is the number of distinct values, counted up to
+	// MetadataDistinctLimit; DistinctCapped reports that the limit was reached.
+	DistinctCount  int  `json:"distinct_count"`
+	DistinctCapped bool `json:"distinct_capped"`
+}
+
+// DescribeMetadata infers the de-facto metadata schema of the collection from its
+// first sampleSize rows (DefaultMetadataSampleSize if sampleSize <= 0): for every key
+// seen in the sample, which value types occur, the range of numeric values, the
+// number of distinct values and how often the key is missing or null.
+func (c *Collection) DescribeMetadata(ctx context.Context, sampleSize int) (map[string]MetadataFieldStats, error) {
+	if sampleSize <= 0 {
+		sampleSize = DefaultMetadataSampleSize
+	}
+
+	sample, err := c.client.collectionGet(ctx, c.name, nil, &GetOptions{
+		Limit:       sampleSize,
+		Include:     []string{"metadatas"},
+		vectorsOnly: c.vectorsOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return describeMetadata(sample.Metadatas), nil
+}
+
+// describeMetadata aggregates per-key statistics over metadatas.
+func describeMetadata(metadatas []Metadata) map[string]MetadataFieldStats {
+	type accumulator struct {
+		stats    MetadataFieldStats
+		types    map[string]bool
+		distinct map[string]bool
+	}
+
+	accumulators := make(map[string]*accumulator)
+	for _, metadata := range metadatas {
+		for key, value := range metadata {
+			acc := accumulators[key]
+			if acc == nil {
+				acc = &accumulator{types: make(map[string]bool), distinct: make(map[string]bool)}
+				accumulators[key] = acc
+			}
+			if value == nil {
+				continue // Counted with the missing keys below
+			}
+
+			acc.stats.Count++
+			acc.types[fmt.Sprintf("%T", value)] = true
+			if n, ok := metadataNumber(value); ok {
+				if acc.stats.Min == nil || n < *acc.stats.Min {
+					acc.stats.Min = &n
+				}
+				if acc.stats.Max == nil || n > *acc.stats.Max {
+					acc.stats.Max = &n
+				}
+			}
+
+			if len(acc.distinct) < MetadataDistinctLimit {
+				// Values of different types (e.g. 1 and "1") are distinct
+				encoded, err := json.Marshal(value)
+				if err == nil {
+					acc.distinct[fmt.Sprintf("%T:%s", value, encoded)] = true
+				}
+			}
+		}
+	}
+
+	result := make(map[string]MetadataFieldStats, len(accumulators))
+	for key, acc := range accumulators {
+		stats := acc.stats
+		stats.NullCount = len(metadatas) - stats.Count
+		if len(metadatas) > 0 {
+			stats.NullFraction = float64(stats.NullCount) / float64(len(metadatas))
+		}
+		for typ := range acc.types {
+			stats.Types = append(stats.Types, typ)
+		}
+		sort.Strings(stats.Types)
+		stats.DistinctCount = len(acc.distinct)
+		stats.DistinctCapped = stats.DistinctCount >= MetadataDistinctLimit
+		result[key] = stats
+	}
+	return result
+}
+
+// metadataNumber returns value as a float64 if it is numeric.
+func metadataNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,70 @@
+package goseekdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDescribeMetadata tests inferring per-key statistics from heterogeneous metadata
+func TestDescribeMetadata(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "mixed", dimension: 3, distance: DistanceL2}
+
+	err := collection.Add(ctx, []string{"a", "b", "c", "d"}, []string{"a", "b", "c", "d"},
+		WithEmbeddings([][]float32{{1, 1, 1}, {2, 2, 2}, {3, 3, 3}, {4, 4, 4}}),
+		WithMetadatas([]Metadata{
+			{"score": 3, "tag": "x"},
+			{"score": -1.5, "tag": "y"},
+			{"score": "n/a", "tag": nil},
+			{"tags": []string{"p", "q"}},
+		}),
+	)
+	require.NoError(t, err)
+
+	stats, err := collection.DescribeMetadata(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	score := stats["score"]
+	assert.Equal(t, 3, score.Count)
+	assert.Equal(t, 1, score.NullCount)
+	assert.InDelta(t, 0.25, score.NullFraction, 1e-9)
+	assert.Equal(t, []string{"float64", "string"}, score.Types)
+	require.NotNil(t, score.Min)
+	require.NotNil(t, score.Max)
+	assert.Equal(t, -1.5, *score.Min)
+	assert.Equal(t, 3.0, *score.Max)
+	assert.Equal(t, 3, score.DistinctCount)
+
+	tag := stats["tag"]
+	assert.Equal(t, 2, tag.Count)
+	assert.Equal(t, 2, tag.NullCount)
+	assert.Equal(t, []string{"string"}, tag.Types)
+	assert.Nil(t, tag.Min)
+
+	assert.Equal(t, []string{"[]interface {}"}, stats["tags"].Types)
+	assert.Equal(t, 3, stats["tags"].NullCount)
+
+	// Sampling only looks at the first rows
+	stats, err = collection.DescribeMetadata(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats["score"].NullCount)
+	assert.NotContains(t, stats, "tags")
+}
+
+// TestDescribeMetadataDistinctLimit tests that distinct values are counted up to the cap
+func TestDescribeMetadataDistinctLimit(t *testing.T) {
+	metadatas := make([]Metadata, MetadataDistinctLimit+10)
+	for i := range metadatas {
+		metadatas[i] = Metadata{"n": float64(i), "same": true}
+	}
+	stats := describeMetadata(metadatas)
+	assert.Equal(t, MetadataDistinctLimit, stats["n"].DistinctCount)
+	assert.True(t, stats["n"].DistinctCapped)
+	assert.Equal(t, 1, stats["same"].DistinctCount)
+	assert.False(t, stats["same"].DistinctCapped)
+}
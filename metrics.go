@@ -0,0 +1,29 @@
+package goseekdb
+
+import "time"
+
+// MetricsCollector receives metrics about client operations. Operations are named
+// like the spans of a Tracer without the "seekdb." prefix, e.g. "query", "get",
+// "add", "delete" and "hybrid_search". Implementations must be safe for concurrent
+// use; with Prometheus, ObserveLatency maps to a histogram and IncError to a
+// counter, both labeled by operation.
+type MetricsCollector interface {
+	// ObserveLatency records the duration of an operation, whether it failed or not.
+	ObserveLatency(op string, d time.Duration)
+	// IncError counts an operation that returned an error.
+	IncError(op string)
+}
+
+// nopMetrics discards all metrics; it is used when no collector is configured.
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveLatency(op string, d time.Duration) {}
+func (nopMetrics) IncError(op string)                        {}
+
+// metrics returns the configured metrics collector, or one discarding all metrics.
+func (c *Client) metrics() MetricsCollector {
+	if c.config == nil || c.config.Metrics == nil {
+		return nopMetrics{}
+	}
+	return c.config.Metrics
+}
@@ -0,0 +1,101 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics records observed latencies and errors by operation.
+type recordingMetrics struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+func (m *recordingMetrics) ObserveLatency(op string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencies[op] = append(m.latencies[op], d)
+}
+
+func (m *recordingMetrics) IncError(op string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[op]++
+}
+
+func TestOperationSpanMetrics(t *testing.T) {
+	ctx := context.Background()
+	metrics := &recordingMetrics{latencies: make(map[string][]time.Duration), errors: make(map[string]int)}
+	client, err := NewClient(WithHost("localhost"), WithAutoConnect(false), WithMetricsCollector(metrics))
+	require.NoError(t, err)
+
+	// Metrics are reported without a tracer
+	spanCtx, span := client.startSpan(ctx, "query", "docs")
+	assert.Equal(t, ctx, spanCtx)
+	span.setAttribute(SpanAttrSearchParm, "{}")
+	span.end(3, nil)
+
+	_, span = client.startSpan(ctx, "query", "docs")
+	span.end(0, errors.New("boom"))
+	_, span = client.startSpan(ctx, "get", "docs")
+	span.end(1, nil)
+
+	assert.Len(t, metrics.latencies["query"], 2)
+	assert.Len(t, metrics.latencies["get"], 1)
+	assert.Equal(t, map[string]int{"query": 1}, metrics.errors)
+
+	// With a tracer as well, both receive the operation
+	tracer := &recordingTracer{}
+	client, err = NewClient(WithHost("localhost"), WithAutoConnect(false), WithMetricsCollector(metrics), WithTracer(tracer))
+	require.NoError(t, err)
+	_, span = client.startSpan(ctx, "hybrid_search", "docs")
+	span.end(2, nil)
+	require.Len(t, tracer.spans, 1)
+	assert.True(t, tracer.spans[0].ended)
+	assert.Len(t, metrics.latencies["hybrid_search"], 1)
+}
+
+// TestWriteOperationMetrics tests that add, update, upsert and delete report their
+// latency, and their errors
+func TestWriteOperationMetrics(t *testing.T) {
+	ctx := context.Background()
+	metrics := &recordingMetrics{latencies: make(map[string][]time.Duration), errors: make(map[string]int)}
+	ids := []string{"a", "b"}
+	embeddings := [][]float32{{1, 2, 3}, {2, 3, 4}}
+
+	conn := &affectedRowsConnection{affected: []int64{1, 1, 1, 1, 1, 2}}
+	client := &Client{conn: conn, config: &ClientConfig{Metrics: metrics}}
+	_, err := client.collectionAdd(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	_, err = client.collectionUpdate(ctx, "docs", ids, &UpdateOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	_, err = client.collectionUpsert(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.NoError(t, err)
+	for _, op := range []string{"add", "update", "upsert"} {
+		assert.Len(t, metrics.latencies[op], 1, op)
+	}
+	assert.Empty(t, metrics.errors)
+
+	// Failed writes count as errors
+	client = &Client{conn: newDryRunConnection(nil), config: &ClientConfig{Metrics: metrics}}
+	_, err = client.collectionAdd(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.Error(t, err)
+	_, err = client.collectionUpdate(ctx, "docs", ids, &UpdateOptions{Embeddings: embeddings}, nil)
+	require.Error(t, err)
+	_, err = client.collectionUpsert(ctx, "docs", ids, nil, &AddOptions{Embeddings: embeddings}, nil)
+	require.Error(t, err)
+	_, err = client.collectionDelete(ctx, "docs", ids, nil, nil)
+	require.Error(t, err)
+	for _, op := range []string{"add", "update", "upsert"} {
+		assert.Len(t, metrics.latencies[op], 2, op)
+	}
+	assert.Len(t, metrics.latencies["delete"], 1)
+	assert.Equal(t, map[string]int{"add": 1, "update": 1, "upsert": 1, "delete": 1}, metrics.errors)
+}
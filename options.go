@@ -1,9 +1,12 @@
 package goseekdb
 
 import (
+	"crypto/tls"
+	"sync/atomic"
 	"time"
 
 	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/ob-labs/seekdb-go/internal/connection"
 )
 
 // ClientOption is a functional option for configuring a Client.
@@ -29,6 +32,66 @@ type ClientConfig struct {
 	MaxConnections   int
 	EmbeddingFunc    embedding.EmbeddingFunc
 	AutoConnect      bool
+
+	// AutoCreateDatabase creates Database on connect if it does not exist yet.
+	AutoCreateDatabase bool
+
+	// TablePrefix overrides TableNamePrefix for collection tables.
+	TablePrefix string
+
+	// SchemaVersion is the table layout of new collections; 0 means DefaultSchemaVersion.
+	SchemaVersion int
+
+	// Tracer receives a span per collection operation; nil disables tracing.
+	Tracer Tracer
+
+	// Metrics receives the latency and errors of collection operations; nil discards them.
+	Metrics MetricsCollector
+
+	// ConnectionCharset and ConnectionCollation set the character set of the connection.
+	ConnectionCharset   string
+	ConnectionCollation string
+
+	// TLSConfig and TLSSkipVerify encrypt remote connections.
+	TLSConfig     *tls.Config
+	TLSSkipVerify bool
+
+	// DeadlockRetryAttempts is the number of attempts for write operations that
+	// fail with a deadlock; values below 2 disable retrying.
+	DeadlockRetryAttempts int
+
+	// Logger receives diagnostic messages from the client; nil discards them.
+	Logger Logger
+
+	// SlowQueryThreshold and SlowQueryLogger enable logging of slow queries.
+	SlowQueryThreshold time.Duration
+	SlowQueryLogger    Logger
+
+	// ExplainHybrid returns the generated SQL and search_parm with hybrid search results.
+	ExplainHybrid bool
+
+	// EmbeddingConcurrency is the number of goroutines generating document embeddings.
+	EmbeddingConcurrency int
+
+	// IntegerMetadata decodes integer metadata values as int64 instead of float64.
+	IntegerMetadata bool
+
+	// StatementCacheSize is the number of prepared statements cached for queries;
+	// 0 disables the cache.
+	StatementCacheSize int
+
+	// DryRun captures the SQL of operations instead of executing it.
+	DryRun bool
+
+	// DistanceFuncs overrides the SQL function computing each distance metric.
+	DistanceFuncs DistanceFuncMap
+
+	// SessionVars are session system variables set on every connection.
+	SessionVars map[string]string
+
+	// detectedDistanceFuncs holds the function names found on connect; nil for
+	// configurations not created by DefaultClientConfig, which use the defaults.
+	detectedDistanceFuncs *atomic.Pointer[DistanceFuncMap]
 }
 
 // DefaultClientConfig returns a default client configuration.
@@ -41,6 +104,11 @@ func DefaultClientConfig() *ClientConfig {
 		MaxConnections: 10,
 		AutoConnect:    true,
 		Tenant:         "test",
+
+		ConnectionCharset:   "utf8mb4",
+		ConnectionCollation: "utf8mb4_general_ci",
+
+		detectedDistanceFuncs: new(atomic.Pointer[DistanceFuncMap]),
 	}
 }
 
@@ -135,12 +203,228 @@ func WithAutoConnect(autoConnect bool) ClientOption {
 	}
 }
 
+// WithAutoCreateDatabase enables creating the target database on connect when the
+// server reports it as unknown. Intended for bootstrapping dev/test environments.
+func WithAutoCreateDatabase(autoCreate bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.AutoCreateDatabase = autoCreate
+	}
+}
+
+// WithTablePrefix sets the prefix for collection table names instead of TableNamePrefix,
+// so several applications can keep their collections apart in a shared database.
+// The prefix must be a valid SQL identifier start (letters, digits, '_' and '$').
+func WithTablePrefix(prefix string) ClientOption {
+	return func(c *ClientConfig) {
+		c.TablePrefix = prefix
+	}
+}
+
+// WithSchemaVersion sets the table layout of collections created by the client (see
+// SchemaVersion1 and SchemaVersion2). Existing collections keep their layout and are
+// read with the columns it has; use Client.MigrateCollection to upgrade them. The
+// version is ignored with WithTablePrefix, whose table names carry no version.
+func WithSchemaVersion(version int) ClientOption {
+	return func(c *ClientConfig) {
+		c.SchemaVersion = version
+	}
+}
+
+// WithLogger sets the logger receiving the client's diagnostic messages, such as
+// failures to clean up after an operation that has already succeeded. By default
+// nothing is logged. To also log ONNX model downloads, pass the logger to the
+// embedding function with embedding.WithONNXLogger.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *ClientConfig) {
+		c.Logger = logger
+	}
+}
+
+// WithExplainHybrid makes HybridSearch return the SQL generated by
+// DBMS_HYBRID_SEARCH.GET_SQL and the search_parm JSON it was generated from, in
+// HybridSearchResult.DebugSQL and DebugParm. This is for diagnosing unexpected
+// results, e.g. when tuning RRF or filters.
+func WithExplainHybrid(explain bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.ExplainHybrid = explain
+	}
+}
+
+// WithIntegerMetadata makes results decode integer metadata values, such as
+// {"year": 2023}, as int64 instead of float64, using Metadata.FromJSONPreserveIntegers.
+// Numbers with a fraction or exponent stay float64, so code reading a field that can
+// hold either must handle both types. Filters are unaffected: values of any numeric
+// Go type compare numerically on the server.
+func WithIntegerMetadata(enabled bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.IntegerMetadata = enabled
+	}
+}
+
+// WithEmbeddingConcurrency makes Add, Upsert and Update generate missing document
+// embeddings on up to n goroutines, each embedding a contiguous share of the
+// documents, which cuts latency with network-backed embedding functions. The
+// embedding function must be safe for concurrent use. If one share fails, the
+// others are cancelled and the error is returned. n <= 1 embeds all documents in
+// one call.
+func WithEmbeddingConcurrency(n int) ClientOption {
+	return func(c *ClientConfig) {
+		c.EmbeddingConcurrency = n
+	}
+}
+
+// WithTracer enables tracing of collection operations with the given tracer.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *ClientConfig) {
+		c.Tracer = tracer
+	}
+}
+
+// WithMetricsCollector reports the latency and failures of each collection
+// operation (query, get, add, delete, hybrid_search, ...) to collector, e.g. to
+// export them as Prometheus histograms and counters.
+func WithMetricsCollector(collector MetricsCollector) ClientOption {
+	return func(c *ClientConfig) {
+		c.Metrics = collector
+	}
+}
+
+// WithConnectionCharset sets the character set and collation used by the connection.
+// The default utf8mb4/utf8mb4_general_ci stores emoji and other 4-byte characters intact;
+// empty values fall back to the server default.
+func WithConnectionCharset(charset, collation string) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionCharset = charset
+		c.ConnectionCollation = collation
+	}
+}
+
+// WithTLS encrypts the connection to a remote server with the given TLS
+// configuration, e.g. with RootCAs for a private CA. If config.ServerName is
+// empty, the server certificate is verified against the host name.
+func WithTLS(config *tls.Config) ClientOption {
+	return func(c *ClientConfig) {
+		c.TLSConfig = config
+	}
+}
+
+// WithTLSSkipVerify encrypts the connection to a remote server without verifying
+// its certificate. This protects against eavesdropping but not against an
+// impersonated server, so it is meant for self-signed certificates in development.
+func WithTLSSkipVerify(skip bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.TLSSkipVerify = skip
+	}
+}
+
+// WithStatementCacheSize reuses prepared statements for up to size distinct query
+// shapes, e.g. Query calls with the same filters and different vectors, saving the
+// server a prepare round trip and a parse per call. Least recently used statements
+// are closed first. Each cached statement is prepared once per pooled connection
+// it runs on, which counts toward the server's max_prepared_stmt_count.
+func WithStatementCacheSize(size int) ClientOption {
+	return func(c *ClientConfig) {
+		c.StatementCacheSize = size
+	}
+}
+
+// WithDryRun makes operations return the SQL and arguments they generate, in a
+// *DryRunError, instead of executing them; use it to review statements before
+// running them. Catalog lookups, e.g. to open a collection, still run. An operation
+// stops at its first statement that reads rows, such as the SELECT of Query and Get
+// or the DBMS_HYBRID_SEARCH.GET_SQL call of HybridSearch. Writes such as Add and
+// Delete report all their statements.
+func WithDryRun(dryRun bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.DryRun = dryRun
+	}
+}
+
+// WithDistanceFuncs sets the SQL function names computing distance metrics, e.g.
+// DistanceFuncMap{DistanceCosine: "vec_cosine_distance"}, for server builds whose
+// names differ from the defaults. Metrics not set are detected on connect, by
+// probing the names known across server versions.
+func WithDistanceFuncs(funcs DistanceFuncMap) ClientOption {
+	return func(c *ClientConfig) {
+		if c.DistanceFuncs == nil {
+			c.DistanceFuncs = make(DistanceFuncMap)
+		}
+		for distance, name := range funcs {
+			c.DistanceFuncs[distance] = name
+		}
+	}
+}
+
+// WithSessionVars sets session system variables on every connection to a remote
+// server, e.g. {"time_zone": "+00:00", "sql_mode": "STRICT_ALL_TABLES"}, so that
+// timestamps and comparisons behave the same whatever the server defaults. They are
+// applied by the driver whenever the pool opens a connection, including those
+// replacing connections that died. Values are sent as strings unless they are
+// numbers. Unknown variables or invalid values make Connect fail.
+func WithSessionVars(vars map[string]string) ClientOption {
+	return func(c *ClientConfig) {
+		c.SessionVars = vars
+	}
+}
+
+// WithDeadlockRetry retries write operations (Add, Update, Upsert, Delete and their
+// transactions) that fail with a deadlock (error 1213), making up to maxAttempts
+// attempts with a short backoff. Other errors are returned immediately.
+func WithDeadlockRetry(maxAttempts int) ClientOption {
+	return func(c *ClientConfig) {
+		c.DeadlockRetryAttempts = maxAttempts
+	}
+}
+
+// WithSlowQueryLog logs Query, Get and HybridSearch calls whose database round trip
+// takes at least threshold, with the operation, collection, result limit, duration
+// and (truncated) SQL, via logger.Warnf. Embedding and result decoding are not timed.
+func WithSlowQueryLog(threshold time.Duration, logger Logger) ClientOption {
+	return func(c *ClientConfig) {
+		c.SlowQueryThreshold = threshold
+		c.SlowQueryLogger = logger
+	}
+}
+
+// remoteConnectionOptions returns the connection options derived from the client configuration.
+func remoteConnectionOptions(config *ClientConfig) []connection.RemoteOption {
+	return []connection.RemoteOption{
+		connection.WithAutoCreateDatabase(config.AutoCreateDatabase),
+		connection.WithCharset(config.ConnectionCharset, config.ConnectionCollation),
+		connection.WithTLS(config.TLSConfig),
+		connection.WithTLSSkipVerify(config.TLSSkipVerify),
+		connection.WithStatementCacheSize(config.StatementCacheSize),
+		connection.WithSessionVars(config.SessionVars),
+	}
+}
+
+// clientConnection returns the connection the client runs statements on: conn
+// itself, or conn wrapped to capture statements WithDryRun.
+func clientConnection(config *ClientConfig, conn connection.Connection) connection.Connection {
+	if config.DryRun {
+		return newDryRunConnection(conn)
+	}
+	return conn
+}
+
 // CreateCollectionOptions holds options for creating a collection.
 type CreateCollectionOptions struct {
 	Configuration       *HNSWConfiguration
 	EmbeddingFunc       embedding.EmbeddingFunc
 	EmbeddingFuncSet    bool // true if embedding function was explicitly set (even to nil)
 	GetOrCreate         bool
+	TreatEmptyAsNull    bool
+	ContentHash         bool
+	VectorsOnly         bool
+
+	// MetadataColumnType is the SQL type of the metadata column (DefaultMetadataColumnType if empty).
+	MetadataColumnType MetadataColumnType
+	// IndexedMetadataKeys are metadata keys given indexed generated columns.
+	IndexedMetadataKeys []string
+
+	// IgnoreConfigMismatch returns an existing collection whose dimension or distance
+	// differs from Configuration instead of failing.
+	IgnoreConfigMismatch bool
 }
 
 // CreateCollectionOption is a functional option for CreateCollection.
@@ -169,10 +453,81 @@ func WithGetOrCreate(getOrCreate bool) CreateCollectionOption {
 	}
 }
 
+// WithIgnoreConfigMismatch makes GetOrCreateCollection, and CreateCollection with
+// WithGetOrCreate, return an existing collection even if its dimension or distance
+// differs from WithConfiguration. By default that fails with ErrConfigMismatch.
+func WithIgnoreConfigMismatch(ignore bool) CreateCollectionOption {
+	return func(o *CreateCollectionOptions) {
+		o.IgnoreConfigMismatch = ignore
+	}
+}
+
+// WithTreatEmptyAsNull stores empty-string documents as SQL NULL, so "no document"
+// has a single representation in the table. Reads return both as "", and document
+// filters treat both alike: $contains never matches them and {"$exists": false}
+// matches both.
+func WithTreatEmptyAsNull(treatEmptyAsNull bool) CreateCollectionOption {
+	return func(o *CreateCollectionOptions) {
+		o.TreatEmptyAsNull = treatEmptyAsNull
+	}
+}
+
+// WithContentHash stores a SHA256 of each document in a uniquely indexed
+// _content_hash column, so identical documents are stored only once: Add and
+// CopyFrom skip rows whose document text already exists in the collection.
+// Empty documents are not hashed and never deduplicated.
+func WithContentHash(contentHash bool) CreateCollectionOption {
+	return func(o *CreateCollectionOptions) {
+		o.ContentHash = contentHash
+	}
+}
+
+// WithVectorsOnly creates a slimmer table holding only the ID and the embedding of
+// each record, for pure-vector workloads: no document or metadata columns are stored
+// or read back, and queries skip them entirely. Writes must provide embeddings and no
+// documents or metadata, and filters on either are rejected. Cannot be combined with
+// WithContentHash.
+func WithVectorsOnly(vectorsOnly bool) CreateCollectionOption {
+	return func(o *CreateCollectionOptions) {
+		o.VectorsOnly = vectorsOnly
+	}
+}
+
+// WithMetadataColumnType sets the SQL type of the metadata column: MetadataColumnJSON
+// (the default) or MetadataColumnText.
+func WithMetadataColumnType(columnType MetadataColumnType) CreateCollectionOption {
+	return func(o *CreateCollectionOptions) {
+		o.MetadataColumnType = columnType
+	}
+}
+
+// WithIndexedMetadataKeys adds an indexed generated column for each of the given
+// top-level metadata keys, so that $eq and $in filters on them with string values
+// use an index instead of scanning JSON_EXTRACT over every row. Keys must be plain
+// identifiers (letters, digits and '_'). Requires the JSON metadata column type;
+// indexes cost space and slow writes, so reserve them for frequently filtered keys.
+func WithIndexedMetadataKeys(keys []string) CreateCollectionOption {
+	return func(o *CreateCollectionOptions) {
+		o.IndexedMetadataKeys = keys
+	}
+}
+
 // AddOptions holds options for adding documents to a collection.
 type AddOptions struct {
-	Embeddings [][]float32
-	Metadatas  []Metadata
+	Embeddings      [][]float32
+	Metadatas       []Metadata
+	NamedEmbeddings map[string][][]float32
+	Timeout         time.Duration
+	// MergeMetadata merges the metadata of rows an Upsert updates into the stored
+	// metadata instead of replacing it.
+	MergeMetadata bool
+
+	// emptyDocumentsAsNull stores "" documents as NULL (set from the collection).
+	emptyDocumentsAsNull bool
+	// contentHash maintains the _content_hash column (set from the collection).
+	contentHash bool
+	// vectorsOnly omits the document and metadata columns (set from the collection).
+	vectorsOnly bool
 }
 
 // AddOption is a functional option for Add operations.
@@ -192,17 +547,168 @@ func WithMetadatas(metadatas []Metadata) AddOption {
 	}
 }
 
+// WithNamedEmbeddings provides pre-computed embeddings for named vector fields,
+// keyed by field name (see HNSWConfiguration.VectorFields).
+func WithNamedEmbeddings(embeddings map[string][][]float32) AddOption {
+	return func(o *AddOptions) {
+		o.NamedEmbeddings = embeddings
+	}
+}
+
+// WithUpsertMergeMetadata makes Upsert merge the given metadata into the stored
+// metadata of rows that already exist, with JSON_MERGE_PATCH semantics: keys not
+// given are kept, given keys are overwritten, and keys set to nil are removed. Rows
+// upserted without metadata keep theirs. New rows are inserted as usual. It has no
+// effect on Add.
+func WithUpsertMergeMetadata(merge bool) AddOption {
+	return func(o *AddOptions) {
+		o.MergeMetadata = merge
+	}
+}
+
+// WithAddTimeout bounds the Add or Upsert call, including embedding generation,
+// overriding the client's WriteTimeout.
+func WithAddTimeout(timeout time.Duration) AddOption {
+	return func(o *AddOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// CopyOptions holds options for bulk loading with CopyFrom.
+type CopyOptions struct {
+	BatchSize int                // Rows per multi-row INSERT (default DefaultCopyBatchSize)
+	Workers   int                // Concurrent INSERT workers (default DefaultCopyWorkers)
+	Progress  func(copied int64) // Called after each batch with the total rows copied so far
+
+	// emptyDocumentsAsNull stores "" documents as NULL (set from the collection).
+	emptyDocumentsAsNull bool
+	// contentHash maintains the _content_hash column (set from the collection).
+	contentHash bool
+	// vectorsOnly omits the document and metadata columns (set from the collection).
+	vectorsOnly bool
+}
+
+// CopyOption is a functional option for CopyFrom operations.
+type CopyOption func(*CopyOptions)
+
+// WithCopyBatchSize sets how many records are inserted per statement.
+func WithCopyBatchSize(batchSize int) CopyOption {
+	return func(o *CopyOptions) {
+		o.BatchSize = batchSize
+	}
+}
+
+// WithCopyWorkers sets how many batches are inserted concurrently.
+func WithCopyWorkers(workers int) CopyOption {
+	return func(o *CopyOptions) {
+		o.Workers = workers
+	}
+}
+
+// WithCopyProgress registers a callback invoked after each inserted batch with the
+// total number of rows copied so far. Calls are serialized.
+func WithCopyProgress(fn func(copied int64)) CopyOption {
+	return func(o *CopyOptions) {
+		o.Progress = fn
+	}
+}
+
+// ImportOptions holds options for loading a collection with ImportJSONL.
+type ImportOptions struct {
+	BatchSize         int                      // Records per Upsert (default DefaultImportBatchSize)
+	Distance          DistanceMetric           // Distance metric of a created collection (default DefaultDistanceMetric)
+	CollectionOptions []CreateCollectionOption // Options for getting or creating the collection
+}
+
+// ImportOption is a functional option for ImportJSONL.
+type ImportOption func(*ImportOptions)
+
+// WithImportBatchSize sets how many records are upserted per call.
+func WithImportBatchSize(batchSize int) ImportOption {
+	return func(o *ImportOptions) {
+		o.BatchSize = batchSize
+	}
+}
+
+// WithImportDistance sets the distance metric of the collection if ImportJSONL
+// creates it; an existing collection keeps its metric.
+func WithImportDistance(distance DistanceMetric) ImportOption {
+	return func(o *ImportOptions) {
+		o.Distance = distance
+	}
+}
+
+// WithImportCollectionOptions passes options to CreateCollection or GetCollection,
+// e.g. the embedding function for records without embeddings.
+func WithImportCollectionOptions(opts ...CreateCollectionOption) ImportOption {
+	return func(o *ImportOptions) {
+		o.CollectionOptions = opts
+	}
+}
+
 // QueryOptions holds options for querying a collection.
 type QueryOptions struct {
+	QueryTexts      []string
 	QueryEmbeddings [][]float32
 	Where           Filter
 	WhereDocument   Filter
 	Include         []string
+	QueryField      string
+	ScoreBoost      *ScoreBoost
+
+	// EmbeddingDecodeWorkers is the number of goroutines decoding result embeddings.
+	EmbeddingDecodeWorkers int
+
+	// FederatedParallelism is the number of collections FederatedQuery searches at once.
+	FederatedParallelism int
+
+	// EfSearch overrides the HNSW search candidate list size for this query.
+	EfSearch int
+
+	// Exact ranks by exact distances with a full scan instead of the vector index.
+	Exact bool
+
+	// AutoExactThreshold searches exactly if the collection has fewer rows than this.
+	AutoExactThreshold int
+
+	// Tiebreaker orders results with equal distances by a metadata value.
+	Tiebreaker *MetadataOrder
+
+	// MaxDistance drops results farther from the query than this distance.
+	MaxDistance *float64
+
+	// Stats collects execution statistics for each query embedding.
+	Stats bool
+
+	// ExtraColumns are additional table columns returned in QueryResult.RawColumns.
+	ExtraColumns []string
+
+	// Timeout bounds the whole operation, including embedding the query texts.
+	Timeout time.Duration
+
+	// vectorsOnly skips the document and metadata columns (set from the collection).
+	vectorsOnly bool
+}
+
+// ScoreBoost blends a numeric metadata value into the ranking of query results.
+type ScoreBoost struct {
+	MetadataKey string
+	Weight      float64
 }
 
 // QueryOption is a functional option for Query operations.
 type QueryOption func(*QueryOptions)
 
+// WithQueryTexts sets the query texts, which are embedded with the collection's
+// embedding function. It is an alternative to the queryTexts argument of Query for
+// callers that build queries from options; passing different texts both ways is an
+// error. Query embeddings from WithQueryEmbeddings take precedence over texts.
+func WithQueryTexts(texts []string) QueryOption {
+	return func(o *QueryOptions) {
+		o.QueryTexts = texts
+	}
+}
+
 // WithQueryEmbeddings provides pre-computed query embeddings.
 func WithQueryEmbeddings(embeddings [][]float32) QueryOption {
 	return func(o *QueryOptions) {
@@ -224,13 +730,144 @@ func WithWhereDocument(filter Filter) QueryOption {
 	}
 }
 
-// WithInclude specifies which fields to include in results.
+// WithInclude specifies which fields to include in results, among "documents",
+// "metadatas", "embeddings" and "distances"; IDs are always returned. Fields left out
+// are neither selected nor returned. Without WithInclude, all fields are returned.
+//
+// Results are ordered by distance whether or not "distances" is included: leaving it
+// out only drops the distance from the select list, while ORDER BY (and
+// WithMaxDistance) still compute it.
 func WithInclude(fields []string) QueryOption {
 	return func(o *QueryOptions) {
 		o.Include = fields
 	}
 }
 
+// WithQueryInclude is WithInclude with the fields as arguments, e.g.
+// WithQueryInclude("documents") for IDs and documents without distances.
+func WithQueryInclude(fields ...string) QueryOption {
+	return WithInclude(fields)
+}
+
+// WithQueryField selects the named vector field to search instead of the default embedding column.
+func WithQueryField(name string) QueryOption {
+	return func(o *QueryOptions) {
+		o.QueryField = name
+	}
+}
+
+// WithScoreBoost ranks results by distance - weight * metadata[metadataKey] instead of
+// by distance alone, so a positive weight favors documents with larger values (e.g. a
+// popularity or recency score) and a negative weight penalizes them. Documents missing
+// the key are treated as 0. For inner product collections the boost is added to the
+// similarity instead. Results are ordered like unboosted ones, and the reported
+// Distances are the unboosted values. Boosting bypasses the approximate
+// vector index, so it is best combined with a selective Where filter on large collections.
+func WithScoreBoost(metadataKey string, weight float64) QueryOption {
+	return func(o *QueryOptions) {
+		o.ScoreBoost = &ScoreBoost{MetadataKey: metadataKey, Weight: weight}
+	}
+}
+
+// WithResultEmbeddingDecodeWorkers decodes result embeddings with up to n goroutines.
+// This helps when many high-dimensional embeddings are returned; n <= 1 decodes sequentially.
+func WithResultEmbeddingDecodeWorkers(n int) QueryOption {
+	return func(o *QueryOptions) {
+		o.EmbeddingDecodeWorkers = n
+	}
+}
+
+// WithEfSearch sets the HNSW search candidate list size (ef_search) for this query,
+// overriding the index's value (DefaultHNSWEfSearch unless set at creation). Larger
+// values improve recall at the cost of latency; n <= 0 keeps the index's value.
+// The value is set on the session for the duration of the query and then restored.
+func WithEfSearch(n int) QueryOption {
+	return func(o *QueryOptions) {
+		o.EfSearch = n
+	}
+}
+
+// WithExact makes Query compute exact nearest neighbors by scanning every row
+// (matching the filters) instead of searching the approximate HNSW index. Results
+// are ground truth, e.g. for measuring the recall of approximate queries, but the
+// cost grows linearly with the collection size, so it suits small collections and
+// evaluation rather than serving traffic.
+func WithExact(exact bool) QueryOption {
+	return func(o *QueryOptions) {
+		o.Exact = exact
+	}
+}
+
+// WithAutoExactThreshold makes Query search exactly, as with WithExact, when the
+// collection holds fewer than n rows. On small collections a full scan is cheap and
+// has better recall than the HNSW index, which may also not be populated yet right
+// after the first inserts. The rows are counted, up to n, before every query; n <= 0
+// (the default) disables the check.
+func WithAutoExactThreshold(n int) QueryOption {
+	return func(o *QueryOptions) {
+		o.AutoExactThreshold = n
+	}
+}
+
+// WithQueryTiebreaker orders results with equal distances (or boosted scores) by the
+// metadata value under field, descending if desc is set, instead of leaving their
+// order to the server. field must be a plain identifier (letters, digits and '_').
+// Like WithScoreBoost, a tiebreaker bypasses the approximate vector index.
+func WithQueryTiebreaker(field string, desc bool) QueryOption {
+	return func(o *QueryOptions) {
+		o.Tiebreaker = &MetadataOrder{Key: field, Desc: desc}
+	}
+}
+
+// WithMaxDistance only returns results within threshold of the query: a distance of
+// at most threshold, or a similarity of at least threshold for metrics where higher
+// is better (inner product). At most nResults are returned, so a query may return
+// fewer hits, or none, when nothing is similar enough. With the approximate vector
+// index the threshold is applied to its top nResults candidates; with a score boost
+// it applies to the raw distance, not the boosted score.
+func WithMaxDistance(threshold float64) QueryOption {
+	return func(o *QueryOptions) {
+		o.MaxDistance = &threshold
+	}
+}
+
+// WithQueryStats makes Query return execution statistics for each query
+// embedding in QueryResult.Stats: whether the vector index was used, the
+// estimated rows scanned and the elapsed time. Each search is followed by an
+// EXPLAIN of the same statement, so this costs an extra round trip per query
+// embedding and is meant for diagnosing recall rather than for serving traffic.
+func WithQueryStats(enabled bool) QueryOption {
+	return func(o *QueryOptions) {
+		o.Stats = enabled
+	}
+}
+
+// WithExtraColumns also selects the given columns of the collection's table, for
+// tables extended with columns of their own, e.g. a tenant_id. Their values are
+// returned by name in QueryResult.RawColumns, or SearchRecord.RawColumns with
+// QueryWithCallback. Names must be plain identifiers.
+func WithExtraColumns(columns []string) QueryOption {
+	return func(o *QueryOptions) {
+		o.ExtraColumns = columns
+	}
+}
+
+// WithFederatedParallelism limits how many collections FederatedQuery searches at
+// the same time; n <= 0 uses DefaultFederatedParallelism.
+func WithFederatedParallelism(n int) QueryOption {
+	return func(o *QueryOptions) {
+		o.FederatedParallelism = n
+	}
+}
+
+// WithTimeout bounds the Query call, including embedding the query texts,
+// overriding the client's ReadTimeout.
+func WithTimeout(timeout time.Duration) QueryOption {
+	return func(o *QueryOptions) {
+		o.Timeout = timeout
+	}
+}
+
 // GetOptions holds options for getting documents from a collection.
 type GetOptions struct {
 	Where         Filter
@@ -238,6 +875,25 @@ type GetOptions struct {
 	Limit         int
 	Offset        int
 	Include       []string
+	Cursor        string
+	Timeout       time.Duration
+
+	// NoLimit returns all matching documents instead of at most 1000 by default.
+	NoLimit bool
+
+	// OrderBy orders results by a metadata value instead of table order.
+	OrderBy *MetadataOrder
+
+	// MetadataKeys selects only these metadata keys instead of the whole metadata.
+	MetadataKeys []string
+
+	// ExtraColumns are additional table columns returned in GetResult.RawColumns.
+	ExtraColumns []string
+
+	// paginate switches to keyset pagination ordered by ID (set by GetPage).
+	paginate bool
+	// vectorsOnly skips the document and metadata columns (set from the collection).
+	vectorsOnly bool
 }
 
 // GetOption is a functional option for Get operations.
@@ -257,6 +913,25 @@ func WithGetWhereDocument(filter Filter) GetOption {
 	}
 }
 
+// WithGetMetadataKeys returns only the given metadata keys of each document. Each
+// key is extracted on the server, so large metadata objects are neither transferred
+// nor parsed in full. Keys a document does not have are left out of its Metadata.
+// If "metadatas" is also in WithGetInclude, the full metadata is returned instead.
+func WithGetMetadataKeys(keys []string) GetOption {
+	return func(o *GetOptions) {
+		o.MetadataKeys = keys
+	}
+}
+
+// WithGetExtraColumns also selects the given columns of the collection's table, like
+// WithExtraColumns for queries. Their values are returned by name in
+// GetResult.RawColumns, or Hit.RawColumns with GetStream.
+func WithGetExtraColumns(columns []string) GetOption {
+	return func(o *GetOptions) {
+		o.ExtraColumns = columns
+	}
+}
+
 // WithLimit sets the maximum number of results.
 func WithLimit(limit int) GetOption {
 	return func(o *GetOptions) {
@@ -264,6 +939,15 @@ func WithLimit(limit int) GetOption {
 	}
 }
 
+// WithNoLimit returns every matching document. Without it, and without WithLimit,
+// Get returns at most 1000 documents, or as many as the IDs it is given. It cannot
+// be combined with WithLimit or GetPage.
+func WithNoLimit() GetOption {
+	return func(o *GetOptions) {
+		o.NoLimit = true
+	}
+}
+
 // WithOffset sets the offset for pagination.
 func WithOffset(offset int) GetOption {
 	return func(o *GetOptions) {
@@ -271,6 +955,26 @@ func WithOffset(offset int) GetOption {
 	}
 }
 
+// WithGetCursor resumes keyset pagination after the position encoded in cursor,
+// as returned in GetPageResult.NextCursor.
+func WithGetCursor(cursor string) GetOption {
+	return func(o *GetOptions) {
+		o.Cursor = cursor
+	}
+}
+
+// WithGetOrderBy orders results by the metadata value under field, descending if
+// desc is set, e.g. to page through documents chronologically with WithLimit and
+// WithOffset. Numeric values are compared as numbers and others as text; documents
+// without the field come first ascending and last descending, and ties are broken
+// by ID. field must be a plain identifier (letters, digits and '_'). Ordering cannot
+// be combined with WithGetCursor, whose pages are ordered by ID.
+func WithGetOrderBy(field string, desc bool) GetOption {
+	return func(o *GetOptions) {
+		o.OrderBy = &MetadataOrder{Key: field, Desc: desc}
+	}
+}
+
 // WithGetInclude specifies which fields to include in results.
 func WithGetInclude(fields []string) GetOption {
 	return func(o *GetOptions) {
@@ -278,11 +982,72 @@ func WithGetInclude(fields []string) GetOption {
 	}
 }
 
+// WithGetTimeout bounds the Get call, overriding the client's ReadTimeout.
+// It does not apply to GetStream, whose rows outlive the call.
+func WithGetTimeout(timeout time.Duration) GetOption {
+	return func(o *GetOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// HybridSearchOptions holds options for hybrid search operations.
+type HybridSearchOptions struct {
+	NormalizedScores bool
+	Timeout          time.Duration
+	SecondarySort    []SortField // Metadata fields ordering hits with equal scores
+}
+
+// HybridSearchOption is a functional option for HybridSearch operations.
+type HybridSearchOption func(*HybridSearchOptions)
+
+// WithSecondarySort orders hits with equal fused scores by a metadata field,
+// ascending or descending, after fusion. Repeat it to break remaining ties by further
+// fields. Sorting is done client side and is stable; hits missing the field sort
+// last in either direction.
+func WithSecondarySort(field string, desc bool) HybridSearchOption {
+	return func(o *HybridSearchOptions) {
+		o.SecondarySort = append(o.SecondarySort, SortField{Field: field, Desc: desc})
+	}
+}
+
+// WithNormalizedScores min-max normalizes the fused scores in HybridSearchResult.Distances
+// into 0-1 (best hit 1, worst hit 0), keeping the raw scores in RawScores.
+// Normalization is relative to the returned result set only, so normalized scores
+// are not comparable across searches.
+func WithNormalizedScores(normalize bool) HybridSearchOption {
+	return func(o *HybridSearchOptions) {
+		o.NormalizedScores = normalize
+	}
+}
+
+// WithHybridSearchTimeout bounds the HybridSearch call, overriding the client's ReadTimeout.
+func WithHybridSearchTimeout(timeout time.Duration) HybridSearchOption {
+	return func(o *HybridSearchOptions) {
+		o.Timeout = timeout
+	}
+}
+
 // UpdateOptions holds options for updating documents.
 type UpdateOptions struct {
 	Documents  []string
 	Embeddings [][]float32
 	Metadatas  []Metadata
+	Timeout    time.Duration
+
+	// RemoveMetadataKeys are removed from the metadata of every updated document,
+	// after Metadatas is applied.
+	RemoveMetadataKeys []string
+
+	// SkipReembed keeps the stored embeddings when Documents change without
+	// Embeddings, instead of regenerating them with the embedding function.
+	SkipReembed bool
+
+	// emptyDocumentsAsNull stores "" documents as NULL (set from the collection).
+	emptyDocumentsAsNull bool
+	// contentHash maintains the _content_hash column (set from the collection).
+	contentHash bool
+	// vectorsOnly omits the document and metadata columns (set from the collection).
+	vectorsOnly bool
 }
 
 // UpdateOption is a functional option for Update operations.
@@ -308,3 +1073,63 @@ func WithUpdateMetadatas(metadatas []Metadata) UpdateOption {
 		o.Metadatas = metadatas
 	}
 }
+
+// WithRemoveMetadataKeys removes the given top-level keys from the metadata of the
+// updated documents, leaving other keys as they are. Combined with
+// WithUpdateMetadatas, the keys are removed from the new metadata.
+func WithRemoveMetadataKeys(keys []string) UpdateOption {
+	return func(o *UpdateOptions) {
+		o.RemoveMetadataKeys = keys
+	}
+}
+
+// WithSkipReembed keeps the stored embeddings of documents updated with
+// WithUpdateDocuments but without WithUpdateEmbeddings. By default the collection's
+// embedding function regenerates them from the new text, so vectors do not go stale;
+// skip it when vectors are managed separately.
+func WithSkipReembed(skip bool) UpdateOption {
+	return func(o *UpdateOptions) {
+		o.SkipReembed = skip
+	}
+}
+
+// WithUpdateTimeout bounds the Update call, including embedding generation,
+// overriding the client's WriteTimeout.
+func WithUpdateTimeout(timeout time.Duration) UpdateOption {
+	return func(o *UpdateOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// MetadataIndexOptions holds options for Collection.CreateMetadataIndex.
+type MetadataIndexOptions struct {
+	// IfNotExists makes indexing an already indexed key a no-op instead of an error.
+	IfNotExists bool
+}
+
+// MetadataIndexOption is a functional option for CreateMetadataIndex.
+type MetadataIndexOption func(*MetadataIndexOptions)
+
+// WithMetadataIndexIfNotExists makes CreateMetadataIndex succeed without changes if
+// the key is already indexed, e.g. when indexes are ensured on every startup.
+func WithMetadataIndexIfNotExists(ifNotExists bool) MetadataIndexOption {
+	return func(o *MetadataIndexOptions) {
+		o.IfNotExists = ifNotExists
+	}
+}
+
+// DeleteCollectionsOptions holds options for DeleteCollections.
+type DeleteCollectionsOptions struct {
+	ConfirmDeleteAll bool // Allow a pattern that matches every collection
+}
+
+// DeleteCollectionsOption is a functional option for DeleteCollections.
+type DeleteCollectionsOption func(*DeleteCollectionsOptions)
+
+// WithConfirmDeleteAll allows DeleteCollections to be called with a pattern that
+// matches every collection, such as "" or "*", which deletes them all.
+func WithConfirmDeleteAll(confirm bool) DeleteCollectionsOption {
+	return func(o *DeleteCollectionsOptions) {
+		o.ConfirmDeleteAll = confirm
+	}
+}
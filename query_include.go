@@ -0,0 +1,104 @@
+package goseekdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryInclude is the set of fields a vector query selects and returns besides IDs.
+type queryInclude struct {
+	documents  bool
+	metadatas  bool
+	embeddings bool
+	distances  bool
+}
+
+// validateQueryInclude checks the entries of QueryOptions.Include.
+func validateQueryInclude(fields []string) error {
+	for _, field := range fields {
+		switch field {
+		case "documents", "metadatas", "embeddings", "distances":
+		default:
+			return fmt.Errorf("%w: unknown include field %q, expected documents, metadatas, embeddings or distances", ErrInvalidParameter, field)
+		}
+	}
+	return nil
+}
+
+// include returns the fields a query returns: all of them without Include, or else
+// only those listed. Vectors-only collections have no documents or metadatas.
+func (o *QueryOptions) include() queryInclude {
+	include := queryInclude{documents: true, metadatas: true, embeddings: true, distances: true}
+	if o.Include != nil {
+		include = queryInclude{}
+		for _, field := range o.Include {
+			switch field {
+			case "documents":
+				include.documents = true
+			case "metadatas":
+				include.metadatas = true
+			case "embeddings":
+				include.embeddings = true
+			case "distances":
+				include.distances = true
+			}
+		}
+	}
+	if o.vectorsOnly {
+		include.documents, include.metadatas = false, false
+	}
+	return include
+}
+
+// includeDistances returns the Include fields with "distances" added. nil includes
+// all fields already.
+func includeDistances(fields []string) []string {
+	if fields == nil {
+		return nil
+	}
+	for _, field := range fields {
+		if field == "distances" {
+			return fields
+		}
+	}
+	return append(append([]string(nil), fields...), "distances")
+}
+
+// columns returns the select list of the included columns, with vectorColumn as the
+// embedding column and distanceColumn (e.g. "l2_distance(embedding, ?) AS distance")
+// for the distance, if distances are included.
+func (i queryInclude) columns(vectorColumn, distanceColumn string) string {
+	columns := []string{FieldID}
+	if i.documents {
+		columns = append(columns, FieldDocument)
+	}
+	if i.metadatas {
+		columns = append(columns, FieldMetadata)
+	}
+	if i.embeddings {
+		columns = append(columns, vectorColumn)
+	}
+	if i.distances {
+		columns = append(columns, distanceColumn)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// dest returns the scan destinations of the included columns in buf, in the order
+// of columns, reusing the destination slice of buf.
+func (i queryInclude) dest(buf *queryScanBuffer) []interface{} {
+	buf.dest = append(buf.dest[:0], &buf.id)
+	if i.documents {
+		buf.dest = append(buf.dest, &buf.document)
+	}
+	if i.metadatas {
+		buf.dest = append(buf.dest, &buf.metadataJSON)
+	}
+	if i.embeddings {
+		buf.dest = append(buf.dest, &buf.embeddingJSON)
+	}
+	if i.distances {
+		buf.dest = append(buf.dest, &buf.distance)
+	}
+	return buf.dest
+}
@@ -0,0 +1,109 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryInclude(t *testing.T) {
+	assert.Equal(t, queryInclude{documents: true, metadatas: true, embeddings: true, distances: true}, (&QueryOptions{}).include())
+
+	options := &QueryOptions{}
+	WithQueryInclude("documents", "distances")(options)
+	assert.Equal(t, queryInclude{documents: true, distances: true}, options.include())
+	assert.Equal(t, queryInclude{}, (&QueryOptions{Include: []string{}}).include())
+
+	options.vectorsOnly = true
+	assert.Equal(t, queryInclude{distances: true}, options.include())
+
+	assert.NoError(t, validateQueryInclude([]string{"documents", "metadatas", "embeddings", "distances"}))
+	assert.ErrorIs(t, validateQueryInclude([]string{"distance"}), ErrInvalidParameter)
+
+	assert.Nil(t, includeDistances(nil))
+	assert.Equal(t, []string{"documents", "distances"}, includeDistances([]string{"documents"}))
+	assert.Equal(t, []string{"distances"}, includeDistances([]string{"distances"}))
+}
+
+// TestQueryIncludeSQL tests that excluded fields are not selected, while the
+// distance still orders the results
+func TestQueryIncludeSQL(t *testing.T) {
+	options := &QueryOptions{}
+	WithQueryInclude("documents")(options)
+	querySQL, args := buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", options)
+	assert.Contains(t, querySQL, "SELECT _id, document\n")
+	assert.Contains(t, querySQL, "ORDER BY l2_distance(embedding, ?) ASC")
+	// The vector is bound once, for ORDER BY only
+	assert.Equal(t, []interface{}{"[1,2,3]", 5}, args)
+
+	// Distances can be included without documents or metadatas
+	WithQueryInclude("distances")(options)
+	querySQL, args = buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", options)
+	assert.Contains(t, querySQL, "SELECT _id, l2_distance(embedding, ?) AS distance\n")
+	assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", 5}, args)
+
+	// The threshold query filters its candidates on the distance, but does not return it
+	WithQueryInclude("documents")(options)
+	WithMaxDistance(0.5)(options)
+	querySQL, args = buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", options)
+	assert.Contains(t, querySQL, "SELECT _id, document, l2_distance(embedding, ?) AS distance\n")
+	assert.Contains(t, querySQL, "SELECT _id, document\n\t\tFROM (")
+	assert.Equal(t, []interface{}{"[1,2,3]", "[1,2,3]", 5, 0.5}, args)
+}
+
+// TestQueryIncludeScan tests that excluded fields are returned nil
+func TestQueryIncludeScan(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "query_include_documents", []string{"_id", "document"}, [][]driver.Value{
+		{[]byte("id1"), []byte("doc 1")},
+		{[]byte("id2"), nil},
+	})
+	client := &Client{conn: &staticConnection{db: db, key: "query_include_documents"}, config: &ClientConfig{}}
+	options := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2}}}
+	WithQueryInclude("documents")(options)
+
+	result, err := client.collectionQuery(ctx, "docs", nil, 2, options, nil, DistanceL2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"id1", "id2"}}, result.IDs)
+	assert.Equal(t, [][]string{{"doc 1", ""}}, result.Documents)
+	assert.Nil(t, result.Distances)
+	assert.Nil(t, result.Metadatas)
+	assert.Nil(t, result.Embeddings)
+
+	var records []SearchRecord
+	err = client.collectionQueryEach(ctx, "docs", nil, 2, options, nil, DistanceL2, func(record SearchRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []SearchRecord{
+		{ID: "id1", Document: "doc 1"},
+		{ID: "id2", Rank: 1},
+	}, records)
+
+	WithQueryInclude("distance")(options)
+	_, err = client.collectionQuery(ctx, "docs", nil, 2, options, nil, DistanceL2)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestFederatedQueryExcludedDistances tests that collections are still merged by
+// distance when distances are not returned
+func TestFederatedQueryExcludedDistances(t *testing.T) {
+	ctx := context.Background()
+	near := &fakeOperations{rows: []fakeRow{{id: "near", embedding: []float32{1, 0}}}}
+	far := &fakeOperations{rows: []fakeRow{{id: "far", embedding: []float32{5, 0}}}}
+	collections := []*Collection{
+		{client: far, name: "far", dimension: 2, distance: DistanceL2},
+		{client: near, name: "near", dimension: 2, distance: DistanceL2},
+	}
+	options := &QueryOptions{QueryEmbeddings: [][]float32{{1, 0}}}
+	WithQueryInclude("documents")(options)
+
+	result, err := federatedQuery(ctx, collections, "", 2, options)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"near", "far"}}, result.IDs)
+	assert.Nil(t, result.Distances)
+}
@@ -0,0 +1,138 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryStats describes how the server executed the vector search of one query
+// embedding. It is diagnostics for tuning recall and latency, returned with
+// WithQueryStats.
+type QueryStats struct {
+	// IndexUsed reports whether the plan searches the HNSW vector index rather
+	// than scanning the table and computing every distance.
+	IndexUsed bool `json:"index_used"`
+	// RowsScanned is the number of rows the plan's scans read, as estimated by
+	// the optimizer.
+	RowsScanned int64 `json:"rows_scanned"`
+	// Elapsed is the client-side time of the search, including reading its rows.
+	Elapsed time.Duration `json:"elapsed"`
+	// Plan is the plan text returned by EXPLAIN.
+	Plan string `json:"plan,omitempty"`
+}
+
+// planOperator is a row of an OceanBase plan table.
+type planOperator struct {
+	operator string // e.g. "TABLE FULL SCAN", with tree drawing characters removed
+	name     string // Table, and index in parentheses, e.g. "c$v1$docs(idx_embedding)"
+	estRows  int64
+}
+
+// explainVectorQuery runs EXPLAIN for a vector search on the session that ran it and
+// fills in the plan-derived statistics.
+func (c *Client) explainVectorQuery(ctx context.Context, session rowQuerier, querySQL string, args []interface{}, vectorColumn string, stats *QueryStats) error {
+	rows, err := session.Query(ctx, "EXPLAIN "+querySQL, args...)
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %w", err)
+	}
+	var lines []string
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to read query plan: %w", err)
+		}
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = v.String
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read query plan: %w", err)
+	}
+
+	stats.Plan = strings.Join(lines, "\n")
+	stats.IndexUsed, stats.RowsScanned = summarizePlan(parsePlanTable(stats.Plan), vectorColumn)
+	return nil
+}
+
+// summarizePlan reports whether the plan uses the vector index on vectorColumn and
+// how many rows its scans are estimated to read.
+func summarizePlan(operators []planOperator, vectorColumn string) (indexUsed bool, rowsScanned int64) {
+	vectorIndex := "(idx_" + vectorColumn + ")"
+	for _, op := range operators {
+		if !strings.Contains(op.operator, "SCAN") {
+			continue
+		}
+		rowsScanned += op.estRows
+		if strings.Contains(op.operator, "VECTOR INDEX") || strings.HasSuffix(op.name, vectorIndex) {
+			indexUsed = true
+		}
+	}
+	return indexUsed, rowsScanned
+}
+
+// parsePlanTable extracts the operators from the plan table at the top of OceanBase
+// EXPLAIN output:
+//
+//	|ID|OPERATOR               |NAME                  |EST.ROWS|EST.TIME(us)|
+//	|0 |TOP-N SORT             |                      |5       |61          |
+//	|1 |└─TABLE FULL SCAN      |c$v1$docs             |100     |20          |
+//
+// Lines that are not table rows, such as the outputs and filters section, are ignored.
+func parsePlanTable(plan string) []planOperator {
+	var operators []planOperator
+	operatorCol, nameCol, rowsCol := -1, -1, -1
+	for _, line := range strings.Split(plan, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+
+		if operatorCol < 0 {
+			// Header row
+			for i, cell := range cells {
+				switch strings.ToUpper(cell) {
+				case "OPERATOR":
+					operatorCol = i
+				case "NAME":
+					nameCol = i
+				case "EST.ROWS", "EST. ROWS":
+					rowsCol = i
+				}
+			}
+			continue
+		}
+		if operatorCol >= len(cells) {
+			continue
+		}
+
+		op := planOperator{operator: strings.ToUpper(strings.TrimLeft(cells[operatorCol], "└├│─ "))}
+		if nameCol >= 0 && nameCol < len(cells) {
+			op.name = cells[nameCol]
+		}
+		if rowsCol >= 0 && rowsCol < len(cells) {
+			op.estRows, _ = strconv.ParseInt(cells[rowsCol], 10, 64)
+		}
+		operators = append(operators, op)
+	}
+	return operators
+}
@@ -0,0 +1,106 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const vectorIndexPlan = `==============================================================================
+|ID|OPERATOR                  |NAME                       |EST.ROWS|EST.TIME(us)|
+------------------------------------------------------------------------------
+|0 |TOP-N SORT                |                           |5       |112         |
+|1 |└─TABLE FULL SCAN         |c$v1$docs(idx_embedding)   |5       |98          |
+==============================================================================
+Outputs & filters:
+-------------------------------------
+  0 - output([c$v1$docs._id]), filter(nil), rowset=16`
+
+const fullScanPlan = `=====================================================================
+|ID|OPERATOR             |NAME          |EST.ROWS|EST.TIME(us)|
+---------------------------------------------------------------------
+|0 |TOP-N SORT           |              |5       |812         |
+|1 |└─TABLE FULL SCAN    |c$v1$docs     |1000    |301         |
+=====================================================================`
+
+// TestParsePlanTable tests reading operators from OceanBase EXPLAIN output
+func TestParsePlanTable(t *testing.T) {
+	operators := parsePlanTable(vectorIndexPlan)
+	assert.Equal(t, []planOperator{
+		{operator: "TOP-N SORT", estRows: 5},
+		{operator: "TABLE FULL SCAN", name: "c$v1$docs(idx_embedding)", estRows: 5},
+	}, operators)
+
+	indexUsed, rowsScanned := summarizePlan(operators, "embedding")
+	assert.True(t, indexUsed)
+	assert.Equal(t, int64(5), rowsScanned)
+
+	// A named vector field has its own index
+	indexUsed, _ = summarizePlan(operators, "vec_title")
+	assert.False(t, indexUsed)
+
+	indexUsed, rowsScanned = summarizePlan(parsePlanTable(fullScanPlan), "embedding")
+	assert.False(t, indexUsed)
+	assert.Equal(t, int64(1000), rowsScanned)
+
+	indexUsed, _ = summarizePlan([]planOperator{{operator: "VECTOR INDEX SCAN", name: "c$v1$docs"}}, "embedding")
+	assert.True(t, indexUsed)
+
+	assert.Empty(t, parsePlanTable("no plan table"))
+}
+
+// TestExplainVectorQuery tests collecting statistics from the plan rows
+func TestExplainVectorQuery(t *testing.T) {
+	var rows [][]driver.Value
+	for _, line := range strings.Split(vectorIndexPlan, "\n") {
+		rows = append(rows, []driver.Value{[]byte(line)})
+	}
+	db := registerStaticRows(t, "explain_vector_query", []string{"Query Plan"}, rows)
+	client := &Client{config: &ClientConfig{}}
+
+	var stats QueryStats
+	err := client.explainVectorQuery(context.Background(), &staticConnection{db: db, key: "explain_vector_query"}, "SELECT 1", nil, "embedding", &stats)
+	require.NoError(t, err)
+	assert.True(t, stats.IndexUsed)
+	assert.Equal(t, int64(5), stats.RowsScanned)
+	assert.Equal(t, vectorIndexPlan, stats.Plan)
+}
+
+// TestCollectionQueryStats tests that statistics are returned per query embedding
+func TestCollectionQueryStats(t *testing.T) {
+	client := createTestClient(t)
+	defer client.Close()
+
+	collectionName := "test_query_stats_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, client, collectionName, 3)
+	defer func() {
+		ctx := context.Background()
+		_ = client.DeleteCollection(ctx, collectionName)
+	}()
+
+	ctx := context.Background()
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"doc 1", "doc 2"},
+		WithEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}}),
+	)
+	require.NoError(t, err)
+
+	queryEmbeddings := WithQueryEmbeddings([][]float32{{1.0, 2.0, 3.0}, {2.0, 3.0, 4.0}})
+	results, err := collection.Query(ctx, nil, 2, queryEmbeddings, WithQueryStats(true))
+	require.NoError(t, err)
+	require.Len(t, results.Stats, 2)
+	assert.NotEmpty(t, results.Stats[0].Plan)
+	assert.Positive(t, results.Stats[0].Elapsed)
+
+	exact, err := collection.Query(ctx, nil, 2, queryEmbeddings, WithQueryStats(true), WithExact(true))
+	require.NoError(t, err)
+	assert.False(t, exact.Stats[0].IndexUsed)
+
+	results, err = collection.Query(ctx, nil, 2, queryEmbeddings)
+	require.NoError(t, err)
+	assert.Nil(t, results.Stats)
+}
@@ -0,0 +1,94 @@
+package goseekdb
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+)
+
+// Scan buffers are pooled so high-QPS query workloads do not allocate fresh scan
+// destinations for every row. Buffers hold references to row data only between
+// get and put; put clears them so nothing leaks into the next query.
+
+// hybridScanBuffer holds the destinations for scanning hybrid search rows, whose
+// columns are only known at run time, and the lowercased column index.
+type hybridScanBuffer struct {
+	values  []interface{}
+	ptrs    []interface{}
+	columns map[string]int
+}
+
+var hybridScanBufferPool = sync.Pool{
+	New: func() interface{} { return &hybridScanBuffer{columns: make(map[string]int)} },
+}
+
+// getHybridScanBuffer returns a buffer sized and indexed for cols.
+func getHybridScanBuffer(cols []string) *hybridScanBuffer {
+	buf := hybridScanBufferPool.Get().(*hybridScanBuffer)
+	if cap(buf.values) < len(cols) {
+		buf.values = make([]interface{}, len(cols))
+		buf.ptrs = make([]interface{}, len(cols))
+	}
+	buf.values = buf.values[:len(cols)]
+	buf.ptrs = buf.ptrs[:len(cols)]
+	for i := range buf.values {
+		buf.ptrs[i] = &buf.values[i]
+	}
+	for i, col := range cols {
+		buf.columns[strings.ToLower(col)] = i
+	}
+	return buf
+}
+
+// value returns the scanned value of the first present column among names.
+func (b *hybridScanBuffer) value(names ...string) (interface{}, bool) {
+	for _, name := range names {
+		if idx, ok := b.columns[name]; ok {
+			return b.values[idx], true
+		}
+	}
+	return nil, false
+}
+
+// putHybridScanBuffer clears buf and returns it to the pool.
+func putHybridScanBuffer(buf *hybridScanBuffer) {
+	for i := range buf.values {
+		buf.values[i] = nil
+	}
+	for col := range buf.columns {
+		delete(buf.columns, col)
+	}
+	hybridScanBufferPool.Put(buf)
+}
+
+// queryScanBuffer holds the destinations for scanning vector query rows and the
+// embedding JSON collected for decoding after the scan.
+type queryScanBuffer struct {
+	id             string
+	document       sql.NullString // NULL documents are returned as ""
+	metadataJSON   string
+	embeddingJSON  string
+	distance       float64
+	dest           []interface{} // Destinations of the selected columns (see queryInclude.dest)
+	embeddingJSONs []string
+}
+
+var queryScanBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &queryScanBuffer{dest: make([]interface{}, 0, 5)}
+	},
+}
+
+func getQueryScanBuffer() *queryScanBuffer {
+	return queryScanBufferPool.Get().(*queryScanBuffer)
+}
+
+// putQueryScanBuffer clears buf and returns it to the pool.
+func putQueryScanBuffer(buf *queryScanBuffer) {
+	buf.id, buf.document, buf.metadataJSON, buf.embeddingJSON, buf.distance = "", sql.NullString{}, "", "", 0
+	for i := range buf.embeddingJSONs {
+		buf.embeddingJSONs[i] = ""
+	}
+	buf.embeddingJSONs = buf.embeddingJSONs[:0]
+	queryScanBufferPool.Put(buf)
+}
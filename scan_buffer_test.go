@@ -0,0 +1,185 @@
+package goseekdb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDriver is a database/sql driver whose queries return canned result sets,
+// registered with registerStaticRows and looked up by query text.
+type staticDriver struct{}
+
+type staticResultSet struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var (
+	staticResultsMu sync.Mutex
+	staticResults   = map[string]staticResultSet{}
+	staticDriverReg sync.Once
+)
+
+// registerStaticRows makes query return the given columns and rows, and returns a
+// database handle for the static driver.
+func registerStaticRows(t testing.TB, query string, columns []string, rows [][]driver.Value) *sql.DB {
+	staticDriverReg.Do(func() { sql.Register("goseekdb_static", staticDriver{}) })
+
+	staticResultsMu.Lock()
+	staticResults[query] = staticResultSet{columns: columns, rows: rows}
+	staticResultsMu.Unlock()
+
+	db, err := sql.Open("goseekdb_static", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (staticDriver) Open(name string) (driver.Conn, error) { return staticConn{}, nil }
+
+type staticConn struct{}
+
+func (staticConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (staticConn) Close() error                              { return nil }
+func (staticConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (staticConn) Query(query string, args []driver.Value) (driver.Rows, error) {
+	staticResultsMu.Lock()
+	defer staticResultsMu.Unlock()
+
+	set, ok := staticResults[query]
+	if !ok {
+		return nil, fmt.Errorf("no static rows for %q", query)
+	}
+	return &staticRows{set: set}, nil
+}
+
+type staticRows struct {
+	set  staticResultSet
+	next int
+}
+
+func (r *staticRows) Columns() []string { return r.set.columns }
+func (r *staticRows) Close() error      { return nil }
+
+func (r *staticRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.set.rows) {
+		return io.EOF
+	}
+	copy(dest, r.set.rows[r.next])
+	r.next++
+	return nil
+}
+
+// makeHybridRows returns n hybrid search result rows
+func makeHybridRows(n int) [][]driver.Value {
+	rows := make([][]driver.Value, n)
+	for i := range rows {
+		rows[i] = []driver.Value{
+			[]byte(fmt.Sprintf("id%d", i)),
+			[]byte(fmt.Sprintf("document %d", i)),
+			[]byte(fmt.Sprintf(`{"index":%d}`, i)),
+			[]byte(fmt.Sprintf("[%d,1,2]", i)),
+			float64(n - i),
+		}
+	}
+	return rows
+}
+
+// TestTransformHybridSearchResultsReusesBuffers tests that pooled scan buffers
+// do not carry columns or values over between result sets
+func TestTransformHybridSearchResultsReusesBuffers(t *testing.T) {
+	c := &Client{}
+
+	db := registerStaticRows(t, "hybrid_full", []string{"_id", "document", "metadata", "embedding", "_score"}, makeHybridRows(3))
+	rows, err := db.Query("hybrid_full")
+	require.NoError(t, err)
+	result, err := c.transformHybridSearchResults(rows)
+	rows.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"id0", "id1", "id2"}, result.IDs)
+	assert.Equal(t, []string{"document 0", "document 1", "document 2"}, result.Documents)
+	assert.Equal(t, []float64{3, 2, 1}, result.Distances)
+	assert.Equal(t, float64(1), result.Metadatas[1]["index"])
+	assert.Equal(t, []float32{2, 1, 2}, result.Embeddings[2])
+
+	// A narrower result set scanned with a reused buffer must not see the
+	// previous columns or values
+	db = registerStaticRows(t, "hybrid_ids", []string{"_id", "_score"}, [][]driver.Value{
+		{[]byte("only"), nil},
+	})
+	rows, err = db.Query("hybrid_ids")
+	require.NoError(t, err)
+	result, err = c.transformHybridSearchResults(rows)
+	rows.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"only"}, result.IDs)
+	assert.Equal(t, []string{""}, result.Documents)
+	assert.Equal(t, []float64{0}, result.Distances)
+	assert.Equal(t, Metadata{}, result.Metadatas[0])
+	assert.Nil(t, result.Embeddings[0])
+}
+
+// TestScanQueryResults tests scanning vector query rows
+func TestScanQueryResults(t *testing.T) {
+	c := &Client{}
+
+	for _, query := range []string{"query_first", "query_second"} {
+		db := registerStaticRows(t, query, []string{"_id", "document", "metadata", "embedding", "_distance"}, makeHybridRows(2))
+		rows, err := db.Query(query)
+		require.NoError(t, err)
+		ids, distances, documents, metadatas, embeddings, _, err := c.scanQueryResults(rows, 1, (&QueryOptions{}).include(), nil)
+		rows.Close()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"id0", "id1"}, ids)
+		assert.Equal(t, []float64{2, 1}, distances)
+		assert.Equal(t, []string{"document 0", "document 1"}, documents)
+		assert.Equal(t, float64(1), metadatas[1]["index"])
+		assert.Equal(t, [][]float32{{0, 1, 2}, {1, 1, 2}}, embeddings)
+	}
+}
+
+func BenchmarkTransformHybridSearchResults(b *testing.B) {
+	c := &Client{}
+	db := registerStaticRows(b, "bench_hybrid", []string{"_id", "document", "metadata", "embedding", "_score"}, makeHybridRows(100))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query("bench_hybrid")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := c.transformHybridSearchResults(rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
+
+func BenchmarkScanQueryResults(b *testing.B) {
+	c := &Client{}
+	db := registerStaticRows(b, "bench_query", []string{"_id", "document", "metadata", "embedding", "_distance"}, makeHybridRows(100))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query("bench_query")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, _, _, _, _, _, err := c.scanQueryResults(rows, 1, (&QueryOptions{}).include(), nil); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
@@ -0,0 +1,292 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Collection table layouts. Each schema version has its own table name prefix
+// (c$v1$, c$v2$, ...), so the version of a collection can be read off its table name
+// and tables of different layouts can coexist in one database.
+const (
+	// SchemaVersion1 is the original layout: _id, document, embedding and metadata,
+	// plus _content_hash for collections created with WithContentHash.
+	SchemaVersion1 = 1
+	// SchemaVersion2 adds the uri and _content_hash columns to every collection.
+	SchemaVersion2 = 2
+
+	// DefaultSchemaVersion is the layout of new collections unless WithSchemaVersion is set.
+	DefaultSchemaVersion = SchemaVersion1
+	// LatestSchemaVersion is the newest layout this client can read and migrate to.
+	LatestSchemaVersion = SchemaVersion2
+)
+
+// FieldURI is the column of schema version 2 storing a document's source URI.
+const FieldURI = "uri"
+
+// validateSchemaVersion checks that version is a layout this client knows.
+func validateSchemaVersion(version int) error {
+	if version < SchemaVersion1 || version > LatestSchemaVersion {
+		return fmt.Errorf("%w: unsupported schema version %d (use %d to %d)",
+			ErrInvalidParameter, version, SchemaVersion1, LatestSchemaVersion)
+	}
+	return nil
+}
+
+// TableNamePrefixForVersion returns the collection table prefix of a schema version,
+// e.g. "c$v2$" for version 2. TableNamePrefix is the prefix of version 1.
+func TableNamePrefixForVersion(version int) string {
+	return fmt.Sprintf("c$v%d$", version)
+}
+
+// parseVersionedTableName splits a table name with a versioned prefix into its
+// schema version and collection name. ok is false for other tables.
+func parseVersionedTableName(tableName string) (version int, collectionName string, ok bool) {
+	if !strings.HasPrefix(tableName, "c$v") {
+		return 0, "", false
+	}
+	rest := strings.TrimPrefix(tableName, "c$v")
+	sep := strings.Index(rest, "$")
+	if sep <= 0 || sep == len(rest)-1 {
+		return 0, "", false
+	}
+	version, err := strconv.Atoi(rest[:sep])
+	// Reject non-canonical numbers such as "01" or "+1", which are different tables
+	if err != nil || version < SchemaVersion1 || strconv.Itoa(version) != rest[:sep] {
+		return 0, "", false
+	}
+	return version, rest[sep+1:], true
+}
+
+// schemaColumns returns the columns every collection table of a schema version has,
+// beyond named vector fields and options such as WithContentHash in version 1.
+func schemaColumns(version int) []string {
+	columns := []string{FieldID, FieldDocument, FieldEmbedding, FieldMetadata}
+	if version >= SchemaVersion2 {
+		columns = append(columns, FieldURI, FieldContentHash)
+	}
+	return columns
+}
+
+// schemaVersion2Definitions returns the column and index definitions schema version 2
+// adds, for use in CREATE TABLE. The content hash index is not unique; uniqueness
+// remains opt-in through WithContentHash.
+func schemaVersion2Definitions() []string {
+	return []string{
+		fmt.Sprintf("%s VARCHAR(2048) NULL", FieldURI),
+		fmt.Sprintf("%s CHAR(64) NULL", FieldContentHash),
+		fmt.Sprintf("KEY idx%s (%s)", FieldContentHash, FieldContentHash),
+	}
+}
+
+// configuredSchemaVersion returns the layout of collections created by this client.
+func (c *Client) configuredSchemaVersion() int {
+	if c.config != nil && c.config.SchemaVersion != 0 {
+		return c.config.SchemaVersion
+	}
+	return DefaultSchemaVersion
+}
+
+// collectionSchemaVersion returns the layout of a collection: the version detected by
+// resolveSchemaVersion if it has been, or else the configured version.
+func (c *Client) collectionSchemaVersion(collectionName string) int {
+	if version, ok := c.schemaVersions.Load(collectionName); ok {
+		return version.(int)
+	}
+	return c.configuredSchemaVersion()
+}
+
+// resolveSchemaVersion detects the layout of an existing collection from its table
+// name and remembers it, so later statements use that table. If the collection exists
+// in several layouts, the configured version wins, then the newest. Collections that
+// do not exist resolve to the configured version. With WithTablePrefix, table names
+// carry no version and the configured version is assumed.
+func (c *Client) resolveSchemaVersion(ctx context.Context, collectionName string) (int, error) {
+	if c.config != nil && c.config.TablePrefix != "" {
+		return c.configuredSchemaVersion(), nil
+	}
+
+	query := `
+		SELECT TABLE_NAME
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME LIKE ?
+	`
+
+	rows, err := c.conn.Query(ctx, query, "c$v%")
+	if err != nil {
+		return 0, fmt.Errorf("failed to detect schema version: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return 0, err
+		}
+		if version, name, ok := parseVersionedTableName(tableName); ok && name == collectionName {
+			versions = append(versions, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	version := pickSchemaVersion(versions, c.configuredSchemaVersion())
+	if len(versions) > 1 {
+		c.logger().Warnf("collection %s exists in schema versions %v; using version %d", collectionName, versions, version)
+	} else if version != c.configuredSchemaVersion() {
+		c.logger().Debugf("collection %s uses schema version %d", collectionName, version)
+	}
+	c.schemaVersions.Store(collectionName, version)
+	return version, nil
+}
+
+// pickSchemaVersion chooses among the layouts a collection exists in: preferred if
+// present, otherwise the newest, or preferred when there are none.
+func pickSchemaVersion(versions []int, preferred int) int {
+	if len(versions) == 0 {
+		return preferred
+	}
+	sort.Ints(versions)
+	for _, version := range versions {
+		if version == preferred {
+			return version
+		}
+	}
+	return versions[len(versions)-1]
+}
+
+// collectionNameFromTable returns the collection stored in a table, or false if the
+// table is not a collection table of this client (any schema version, or the table
+// prefix set with WithTablePrefix).
+func (c *Client) collectionNameFromTable(tableName string) (string, bool) {
+	return collectionNameFromTable(c.config, tableName)
+}
+
+// collectionNameFromTable is Client.collectionNameFromTable for a client configuration.
+func collectionNameFromTable(config *ClientConfig, tableName string) (string, bool) {
+	if config != nil && config.TablePrefix != "" {
+		name := strings.TrimPrefix(tableName, config.TablePrefix)
+		return name, name != tableName && name != ""
+	}
+	_, name, ok := parseVersionedTableName(tableName)
+	return name, ok
+}
+
+// MigrateCollection upgrades a collection's table in place to targetVersion, adding
+// the columns of each newer layout and renaming the table to the new version's prefix.
+// Existing rows are kept; columns that can be derived, such as _content_hash, are
+// backfilled. Migrating to the current version is a no-op, and downgrades are not
+// supported. The collection is unavailable to clients of the old version afterwards.
+func (c *Client) MigrateCollection(ctx context.Context, name string, targetVersion int) (err error) {
+	ctx, span := c.startSpan(ctx, "migrate", name)
+	defer func() { span.end(0, err) }()
+
+	if err := validateSchemaVersion(targetVersion); err != nil {
+		return err
+	}
+	if c.config != nil && c.config.TablePrefix != "" {
+		return fmt.Errorf("%w: collections with a custom table prefix are not versioned", ErrInvalidParameter)
+	}
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+
+	// Resolve first, so the existence check looks for the collection's current table
+	version, err := c.resolveSchemaVersion(ctx, name)
+	if err != nil {
+		return err
+	}
+	exists, err := c.HasCollection(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, name)
+	}
+	if version == targetVersion {
+		return nil
+	}
+	if version > targetVersion {
+		return fmt.Errorf("%w: cannot downgrade collection %s from schema version %d to %d",
+			ErrInvalidParameter, name, version, targetVersion)
+	}
+
+	ctx, cancel := c.writeContext(ctx)
+	defer cancel()
+
+	for ; version < targetVersion; version++ {
+		tableName := TableNamePrefixForVersion(version) + name
+		columns, err := c.listColumnNames(ctx, tableName)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range migrationSQL(tableName, version+1, columns) {
+			if _, err := c.conn.Execute(ctx, stmt); err != nil {
+				return fmt.Errorf("failed to migrate collection %s to schema version %d: %w", name, version+1, err)
+			}
+		}
+		c.schemaVersions.Store(name, version+1)
+	}
+
+	return nil
+}
+
+// migrationSQL returns the statements upgrading tableName, which has the given
+// columns, to schema version to from the version before it. The last statement
+// renames the table to the new version's prefix.
+func migrationSQL(tableName string, to int, columns []string) []string {
+	hasColumn := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		hasColumn[strings.ToLower(column)] = true
+	}
+
+	var stmts []string
+	switch to {
+	case SchemaVersion2:
+		if !hasColumn[FieldURI] {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s VARCHAR(2048) NULL", tableName, FieldURI))
+		}
+		if !hasColumn[FieldContentHash] {
+			// Hash as contentHashArg does: hex SHA256, NULL for empty documents
+			stmts = append(stmts,
+				fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s CHAR(64) NULL, ADD KEY idx%s (%s)", tableName, FieldContentHash, FieldContentHash, FieldContentHash),
+				fmt.Sprintf("UPDATE `%s` SET %s = SHA2(NULLIF(%s, ''), 256)", tableName, FieldContentHash, FieldDocument),
+			)
+		}
+	}
+
+	_, name, _ := parseVersionedTableName(tableName)
+	stmts = append(stmts, fmt.Sprintf("RENAME TABLE `%s` TO `%s`", tableName, TableNamePrefixForVersion(to)+name))
+	return stmts
+}
+
+// listColumnNames returns the column names of a table in the current database.
+func (c *Client) listColumnNames(ctx context.Context, tableName string) ([]string, error) {
+	query := `
+		SELECT COLUMN_NAME
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`
+
+	rows, err := c.conn.Query(ctx, query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
@@ -0,0 +1,247 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticConnection is a connection whose queries all return the static rows
+// registered under key
+type staticConnection struct {
+	connection.Connection
+	db  *sql.DB
+	key string
+}
+
+func (s *staticConnection) IsConnected() bool {
+	return true
+}
+
+func (s *staticConnection) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.key)
+}
+
+func (s *staticConnection) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.key)
+}
+
+// TestParseVersionedTableName tests detecting the schema version from a table name
+func TestParseVersionedTableName(t *testing.T) {
+	tests := []struct {
+		tableName string
+		version   int
+		name      string
+		ok        bool
+	}{
+		{"c$v1$docs", 1, "docs", true},
+		{"c$v2$my$docs", 2, "my$docs", true},
+		{"c$v12$docs", 12, "docs", true},
+		{"c$v01$docs", 0, "", false},
+		{"c$v0$docs", 0, "", false},
+		{"c$v$docs", 0, "", false},
+		{"c$v1$", 0, "", false},
+		{"c$vx$docs", 0, "", false},
+		{"users", 0, "", false},
+	}
+	for _, tt := range tests {
+		version, name, ok := parseVersionedTableName(tt.tableName)
+		assert.Equal(t, tt.ok, ok, tt.tableName)
+		assert.Equal(t, tt.version, version, tt.tableName)
+		assert.Equal(t, tt.name, name, tt.tableName)
+	}
+
+	assert.Equal(t, TableNamePrefix, TableNamePrefixForVersion(SchemaVersion1))
+}
+
+// TestSchemaVersionTableNames tests that table names follow the detected or configured version
+func TestSchemaVersionTableNames(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+	assert.Equal(t, "c$v1$docs", client.GetTableName("docs"))
+
+	WithSchemaVersion(SchemaVersion2)(client.config)
+	assert.Equal(t, "c$v2$docs", client.GetTableName("docs"))
+
+	// A detected version takes precedence over the configured one
+	client.schemaVersions.Store("legacy", SchemaVersion1)
+	assert.Equal(t, "c$v1$legacy", client.GetTableName("legacy"))
+
+	// A custom prefix carries no version
+	WithTablePrefix("app_")(client.config)
+	assert.Equal(t, "app_legacy", client.GetTableName("legacy"))
+
+	assert.NoError(t, validateSchemaVersion(SchemaVersion2))
+	assert.ErrorIs(t, validateSchemaVersion(0), ErrInvalidParameter)
+	assert.ErrorIs(t, validateSchemaVersion(LatestSchemaVersion+1), ErrInvalidParameter)
+}
+
+// TestResolveSchemaVersion tests detecting the layout of existing collections
+func TestResolveSchemaVersion(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "schema_tables", []string{"TABLE_NAME"}, [][]driver.Value{
+		{[]byte("c$v1$legacy")},
+		{[]byte("c$v1$both")},
+		{[]byte("c$v2$both")},
+		{[]byte("c$v1$legacy_other")},
+	})
+	client := &Client{
+		conn:   &staticConnection{db: db, key: "schema_tables"},
+		config: &ClientConfig{SchemaVersion: SchemaVersion2},
+	}
+
+	// A v1 table is read through a v2-aware client
+	version, err := client.resolveSchemaVersion(ctx, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion1, version)
+	assert.Equal(t, "c$v1$legacy", client.GetTableName("legacy"))
+
+	// The configured version wins when a collection exists in several
+	version, err = client.resolveSchemaVersion(ctx, "both")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion2, version)
+
+	// Ambiguous collections are reported to the logger
+	logger := &recordingLogger{}
+	WithLogger(logger)(client.config)
+	_, err = client.resolveSchemaVersion(ctx, "both")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"collection both exists in schema versions [1 2]; using version 2"}, logger.warnings)
+
+	// New collections use the configured version
+	version, err = client.resolveSchemaVersion(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion2, version)
+
+	assert.Equal(t, 3, pickSchemaVersion([]int{3, 1}, 2))
+}
+
+// TestSchemaVersion2SQL tests the tables created and written by a version 2 client
+func TestSchemaVersion2SQL(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{SchemaVersion: SchemaVersion2}}
+
+	createSQL, err := client.createCollectionSQL("docs", 3, DistanceL2, &CreateCollectionOptions{}, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(createSQL, "CREATE TABLE `c$v2$docs` ("), createSQL)
+	assert.Contains(t, createSQL, "uri VARCHAR(2048) NULL,")
+	assert.Contains(t, createSQL, "_content_hash CHAR(64) NULL,")
+	assert.Contains(t, createSQL, "KEY idx_content_hash (_content_hash)")
+	assert.NotContains(t, createSQL, "UNIQUE KEY")
+
+	// WithContentHash only makes the existing column unique
+	createSQL, err = client.createCollectionSQL("docs", 3, DistanceL2, &CreateCollectionOptions{ContentHash: true}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(createSQL, "_content_hash CHAR(64)"), createSQL)
+	assert.Contains(t, createSQL, "UNIQUE KEY uk_content_hash (_content_hash)")
+
+	// Writes keep the hash up to date, without deduplicating
+	var dryRun *DryRunError
+	_, err = client.collectionAdd(ctx, "docs", []string{"id1"}, []string{"hello"}, &AddOptions{Embeddings: [][]float32{{1, 2, 3}}}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "INSERT INTO c$v2$docs (_id, document, embedding, metadata, _content_hash) VALUES (?, ?, ?, ?, ?)", dryRun.Statements[0].SQL)
+	assert.Equal(t, contentHash("hello"), dryRun.Statements[0].Args[4])
+
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{Documents: []string{"hello"}}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "UPDATE c$v2$docs SET document = ?, _content_hash = ? WHERE _id = ?", dryRun.Statements[0].SQL)
+	assert.Equal(t, []interface{}{"hello", contentHash("hello"), "id1"}, dryRun.Statements[0].Args)
+}
+
+// TestMigrationSQL tests the statements upgrading a v1 table to v2
+func TestMigrationSQL(t *testing.T) {
+	stmts := migrationSQL("c$v1$docs", SchemaVersion2, schemaColumns(SchemaVersion1))
+	assert.Equal(t, []string{
+		"ALTER TABLE `c$v1$docs` ADD COLUMN uri VARCHAR(2048) NULL",
+		"ALTER TABLE `c$v1$docs` ADD COLUMN _content_hash CHAR(64) NULL, ADD KEY idx_content_hash (_content_hash)",
+		"UPDATE `c$v1$docs` SET _content_hash = SHA2(NULLIF(document, ''), 256)",
+		"RENAME TABLE `c$v1$docs` TO `c$v2$docs`",
+	}, stmts)
+
+	// Collections created WithContentHash already have the hash column
+	stmts = migrationSQL("c$v1$docs", SchemaVersion2, append(schemaColumns(SchemaVersion1), "_CONTENT_HASH"))
+	assert.Equal(t, []string{
+		"ALTER TABLE `c$v1$docs` ADD COLUMN uri VARCHAR(2048) NULL",
+		"RENAME TABLE `c$v1$docs` TO `c$v2$docs`",
+	}, stmts)
+}
+
+// TestMigrateCollectionInvalidVersion tests that unknown target versions are rejected
+func TestMigrateCollectionInvalidVersion(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+	assert.ErrorIs(t, client.MigrateCollection(context.Background(), "docs", 3), ErrInvalidParameter)
+
+	client.config.TablePrefix = "app_"
+	assert.ErrorIs(t, client.MigrateCollection(context.Background(), "docs", SchemaVersion2), ErrInvalidParameter)
+}
+
+// TestMigrateCollection tests reading a v1 collection through a v2-aware client and
+// migrating it in place
+func TestMigrateCollection(t *testing.T) {
+	v1Client := createTestClient(t)
+	defer v1Client.Close()
+
+	ctx := context.Background()
+	collectionName := "test_migrate_" + uuid.New().String()[:8]
+	collection := createTestCollection(t, v1Client, collectionName, 3)
+	defer func() {
+		_ = v1Client.DeleteCollection(ctx, collectionName)
+	}()
+
+	err := collection.Add(ctx, []string{"id1", "id2"}, []string{"first document", ""},
+		WithEmbeddings([][]float32{{1, 2, 3}, {4, 5, 6}}))
+	require.NoError(t, err)
+
+	v2Client, err := NewClient(
+		WithHost(getServerHost()),
+		WithPort(getServerPort()),
+		WithTenant("sys"),
+		WithDatabase(getServerDatabase()),
+		WithUser(getServerUser()),
+		WithPassword(getServerPassword()),
+		WithSchemaVersion(SchemaVersion2),
+	)
+	require.NoError(t, err)
+	defer v2Client.Close()
+	if err := v2Client.Connect(ctx); err != nil {
+		t.Skipf("Server connection failed: %v", err)
+	}
+
+	// The v1 table is read with the v1 column set
+	legacy, err := v2Client.GetCollection(ctx, collectionName)
+	require.NoError(t, err)
+	results, err := legacy.Get(ctx, []string{"id1", "id2"})
+	require.NoError(t, err)
+	assert.Len(t, results.IDs, 2)
+	assert.Equal(t, TableNamePrefixForVersion(SchemaVersion1)+collectionName, v2Client.GetTableName(collectionName))
+
+	require.NoError(t, v2Client.MigrateCollection(ctx, collectionName, SchemaVersion2))
+	defer func() {
+		_ = v2Client.DeleteCollection(ctx, collectionName)
+	}()
+	assert.Equal(t, TableNamePrefixForVersion(SchemaVersion2)+collectionName, v2Client.GetTableName(collectionName))
+
+	columns, err := v2Client.listColumnNames(ctx, v2Client.GetTableName(collectionName))
+	require.NoError(t, err)
+	assert.Subset(t, columns, schemaColumns(SchemaVersion2))
+
+	// Rows are kept and the content hash is backfilled
+	results, err = legacy.Get(ctx, []string{"id1", "id2"})
+	require.NoError(t, err)
+	assert.Len(t, results.IDs, 2)
+
+	id, found, err := v2Client.collectionFindByContentHash(ctx, collectionName, contentHash("first document"))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "id1", id)
+
+	// Migrating again is a no-op, and downgrading is rejected
+	require.NoError(t, v2Client.MigrateCollection(ctx, collectionName, SchemaVersion2))
+	assert.ErrorIs(t, v2Client.MigrateCollection(ctx, collectionName, SchemaVersion1), ErrInvalidParameter)
+}
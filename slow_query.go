@@ -0,0 +1,30 @@
+package goseekdb
+
+import (
+	"time"
+)
+
+// maxSlowQuerySQLLength caps the SQL included in slow query log entries.
+const maxSlowQuerySQLLength = 512
+
+// startSlowQueryTimer starts timing a database round trip. The returned function
+// must be called as soon as the round trip completes; it logs the query if it took
+// at least the configured SlowQueryThreshold. Without a slow query logger it does nothing.
+func (c *Client) startSlowQueryTimer(operation, collectionName string, nResults int, query string) func() {
+	if c.config == nil || c.config.SlowQueryLogger == nil || c.config.SlowQueryThreshold <= 0 {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		if elapsed < c.config.SlowQueryThreshold {
+			return
+		}
+		if len(query) > maxSlowQuerySQLLength {
+			query = query[:maxSlowQuerySQLLength] + "..."
+		}
+		c.config.SlowQueryLogger.Warnf("slow query: operation=%s collection=%s n_results=%d duration=%s sql=%s",
+			operation, collectionName, nResults, elapsed, query)
+	}
+}
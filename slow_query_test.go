@@ -0,0 +1,94 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ob-labs/seekdb-go/internal/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestQuery = errors.New("query failed")
+
+// slowConnection is a connection whose queries take delay and then fail
+type slowConnection struct {
+	connection.Connection
+	delay time.Duration
+}
+
+func (s *slowConnection) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	time.Sleep(s.delay)
+	return nil, errTestQuery
+}
+
+// recordingLogger collects formatted warnings
+type recordingLogger struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {}
+func (l *recordingLogger) Infof(format string, args ...interface{})  {}
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {}
+
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+// TestSlowQueryLog tests that queries over the threshold are logged
+func TestSlowQueryLog(t *testing.T) {
+	ctx := context.Background()
+	logger := &recordingLogger{}
+	client := &Client{
+		conn:   &slowConnection{delay: 20 * time.Millisecond},
+		config: &ClientConfig{SlowQueryThreshold: 10 * time.Millisecond, SlowQueryLogger: logger},
+	}
+
+	_, err := client.collectionGet(ctx, "docs", []string{"id1"}, &GetOptions{Limit: 5})
+	require.ErrorIs(t, err, errTestQuery)
+
+	_, err = client.collectionQuery(ctx, "docs", nil, 3, &QueryOptions{QueryEmbeddings: [][]float32{{1, 2, 3}}}, nil, DistanceL2)
+	require.ErrorIs(t, err, errTestQuery)
+
+	require.Len(t, logger.warnings, 2)
+	assert.Contains(t, logger.warnings[0], "operation=get collection=docs n_results=5")
+	assert.Contains(t, logger.warnings[0], "SELECT")
+	assert.Contains(t, logger.warnings[1], "operation=query collection=docs n_results=3")
+	assert.Contains(t, logger.warnings[1], "l2_distance")
+}
+
+// TestSlowQueryLogBelowThreshold tests that fast queries are not logged
+func TestSlowQueryLogBelowThreshold(t *testing.T) {
+	ctx := context.Background()
+	logger := &recordingLogger{}
+	client := &Client{
+		conn:   &slowConnection{},
+		config: &ClientConfig{SlowQueryThreshold: time.Hour, SlowQueryLogger: logger},
+	}
+
+	_, err := client.collectionGet(ctx, "docs", []string{"id1"}, &GetOptions{})
+	require.ErrorIs(t, err, errTestQuery)
+	assert.Empty(t, logger.warnings)
+}
+
+// TestSlowQueryTimerTruncatesSQL tests that long SQL is truncated in the log
+func TestSlowQueryTimerTruncatesSQL(t *testing.T) {
+	logger := &recordingLogger{}
+	client := &Client{config: &ClientConfig{SlowQueryThreshold: time.Nanosecond, SlowQueryLogger: logger}}
+
+	done := client.startSlowQueryTimer("query", "docs", 10, string(make([]byte, 2*maxSlowQuerySQLLength)))
+	time.Sleep(time.Millisecond)
+	done()
+
+	require.Len(t, logger.warnings, 1)
+	assert.Less(t, len(logger.warnings[0]), 2*maxSlowQuerySQLLength)
+	assert.Contains(t, logger.warnings[0], "...")
+}
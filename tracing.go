@@ -0,0 +1,113 @@
+package goseekdb
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+)
+
+// Tracer starts spans around client operations. It is a minimal interface so that
+// any tracing backend can be plugged in; an OpenTelemetry trace.Tracer can be
+// adapted in a few lines by wrapping its Start method and span.
+type Tracer interface {
+	// StartSpan starts a span named name as a child of any span in ctx.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttribute records a key/value attribute on the span.
+	SetAttribute(key string, value interface{})
+	// RecordError records an error that caused the operation to fail.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// Span attribute keys recorded by the client.
+const (
+	SpanAttrOperation  = "db.operation"
+	SpanAttrCollection = "seekdb.collection"
+	SpanAttrRowCount   = "seekdb.row_count"
+	SpanAttrDurationMS = "seekdb.duration_ms"
+	SpanAttrSearchParm = "seekdb.search_parm"
+)
+
+// maxSpanAttributeLength caps long attribute values such as the hybrid search search_parm JSON.
+const maxSpanAttributeLength = 1024
+
+// operationSpan wraps a Span with the bookkeeping shared by all client operations,
+// and reports the operation to the metrics collector. A nil *operationSpan is valid
+// and does nothing, so clients without tracing or metrics pay no overhead.
+type operationSpan struct {
+	span      Span // nil without a tracer
+	metrics   MetricsCollector
+	operation string
+	start     time.Time
+}
+
+// startSpan starts a span for a collection operation if a tracer or metrics
+// collector is configured.
+func (c *Client) startSpan(ctx context.Context, operation, collectionName string) (context.Context, *operationSpan) {
+	if c.config == nil || (c.config.Tracer == nil && c.config.Metrics == nil) {
+		return ctx, nil
+	}
+
+	s := &operationSpan{metrics: c.metrics(), operation: operation, start: time.Now()}
+	if c.config.Tracer != nil {
+		ctx, s.span = c.config.Tracer.StartSpan(ctx, "seekdb."+operation)
+		s.span.SetAttribute(SpanAttrOperation, operation)
+		s.span.SetAttribute(SpanAttrCollection, collectionName)
+	}
+	return ctx, s
+}
+
+// setAttribute records an attribute, truncating long string values.
+func (s *operationSpan) setAttribute(key string, value interface{}) {
+	if s == nil || s.span == nil {
+		return
+	}
+	if str, ok := value.(string); ok && len(str) > maxSpanAttributeLength {
+		// Cut at a rune boundary so the value stays valid UTF-8
+		cut := maxSpanAttributeLength
+		for cut > 0 && !utf8.RuneStart(str[cut]) {
+			cut--
+		}
+		value = str[:cut] + "..."
+	}
+	s.span.SetAttribute(key, value)
+}
+
+// end records the row count, duration and error, if any, ends the span and reports
+// the latency and error to the metrics collector.
+func (s *operationSpan) end(rowCount int, err error) {
+	if s == nil {
+		return
+	}
+	elapsed := time.Since(s.start)
+	s.metrics.ObserveLatency(s.operation, elapsed)
+	if err != nil {
+		s.metrics.IncError(s.operation)
+	}
+	if s.span == nil {
+		return
+	}
+	s.span.SetAttribute(SpanAttrRowCount, rowCount)
+	s.span.SetAttribute(SpanAttrDurationMS, float64(elapsed.Microseconds())/1000)
+	if err != nil {
+		s.span.RecordError(err)
+	}
+	s.span.End()
+}
+
+// countQueryHits returns the total number of hits across all queries of a result.
+func countQueryHits(result *QueryResult) int {
+	if result == nil {
+		return 0
+	}
+	count := 0
+	for _, ids := range result.IDs {
+		count += len(ids)
+	}
+	return count
+}
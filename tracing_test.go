@@ -0,0 +1,127 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSpan captures attributes set on a span.
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *recordingSpan) End() {
+	s.ended = true
+}
+
+// recordingTracer records every span it starts.
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name, attributes: make(map[string]interface{})}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+func TestOperationSpan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no tracer is a no-op", func(t *testing.T) {
+		client, err := NewClient(WithHost("localhost"), WithAutoConnect(false))
+		require.NoError(t, err)
+
+		spanCtx, span := client.startSpan(ctx, "query", "docs")
+		assert.Equal(t, ctx, spanCtx)
+		assert.Nil(t, span)
+		span.setAttribute(SpanAttrSearchParm, "{}")
+		span.end(3, nil)
+	})
+
+	t.Run("tracer records operation attributes", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		client, err := NewClient(WithHost("localhost"), WithAutoConnect(false), WithTracer(tracer))
+		require.NoError(t, err)
+
+		_, span := client.startSpan(ctx, "hybrid_search", "docs")
+		span.setAttribute(SpanAttrSearchParm, strings.Repeat("x", 2*maxSpanAttributeLength))
+		span.end(5, errors.New("boom"))
+
+		require.Len(t, tracer.spans, 1)
+		recorded := tracer.spans[0]
+		assert.Equal(t, "seekdb.hybrid_search", recorded.name)
+		assert.Equal(t, "hybrid_search", recorded.attributes[SpanAttrOperation])
+		assert.Equal(t, "docs", recorded.attributes[SpanAttrCollection])
+		assert.Equal(t, 5, recorded.attributes[SpanAttrRowCount])
+		assert.Contains(t, recorded.attributes, SpanAttrDurationMS)
+		assert.Len(t, recorded.attributes[SpanAttrSearchParm], maxSpanAttributeLength+len("..."))
+		assert.EqualError(t, recorded.err, "boom")
+		assert.True(t, recorded.ended)
+	})
+
+	t.Run("truncation keeps multi-byte characters whole", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		client, err := NewClient(WithHost("localhost"), WithAutoConnect(false), WithTracer(tracer))
+		require.NoError(t, err)
+
+		// "é" is two bytes, so byte maxSpanAttributeLength falls inside one
+		_, span := client.startSpan(ctx, "hybrid_search", "docs")
+		span.setAttribute(SpanAttrSearchParm, "x"+strings.Repeat("é", maxSpanAttributeLength))
+		span.end(0, nil)
+
+		value := tracer.spans[0].attributes[SpanAttrSearchParm].(string)
+		assert.True(t, utf8.ValidString(value))
+		assert.Equal(t, "x"+strings.Repeat("é", (maxSpanAttributeLength-1)/2)+"...", value)
+	})
+}
+
+// TestWriteOperationSpans tests that writes are traced like reads
+func TestWriteOperationSpans(t *testing.T) {
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{Tracer: tracer}}
+	add := &AddOptions{Embeddings: [][]float32{{1, 2, 3}}}
+
+	_, err := client.collectionAdd(ctx, "docs", []string{"id1"}, nil, add, nil)
+	require.Error(t, err)
+	_, err = client.collectionUpsert(ctx, "docs", []string{"id1"}, nil, add, nil)
+	require.Error(t, err)
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{Documents: []string{"doc"}}, nil)
+	require.Error(t, err)
+	_, err = client.collectionDelete(ctx, "docs", []string{"id1"}, nil, nil)
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 4)
+	for i, operation := range []string{"add", "upsert", "update", "delete"} {
+		span := tracer.spans[i]
+		assert.Equal(t, "seekdb."+operation, span.name)
+		assert.Equal(t, "docs", span.attributes[SpanAttrCollection])
+		assert.Equal(t, 0, span.attributes[SpanAttrRowCount])
+		var dryRun *DryRunError
+		assert.ErrorAs(t, span.err, &dryRun, operation)
+		assert.True(t, span.ended)
+	}
+}
+
+func TestCountQueryHits(t *testing.T) {
+	assert.Equal(t, 0, countQueryHits(nil))
+	assert.Equal(t, 3, countQueryHits(&QueryResult{IDs: [][]string{{"a", "b"}, {"c"}}}))
+}
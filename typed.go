@@ -0,0 +1,244 @@
+package goseekdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TypedResult is a single query hit decoded into a user-defined struct.
+type TypedResult[T any] struct {
+	Item       T
+	ID         string
+	Distance   float64
+	QueryIndex int // Index of the query text/embedding that produced this hit
+}
+
+// Struct tag values recognized by AddTyped and QueryTyped.
+//
+//	ID       string    `seekdb:"id"`
+//	Text     string    `seekdb:"document"`
+//	Vector   []float32 `seekdb:"embedding"`
+//	Extra    Metadata  `seekdb:"metadata"`
+//	Category string    `seekdb:"metadata,key=category"`
+const (
+	typedTagName      = "seekdb"
+	typedTagID        = "id"
+	typedTagDocument  = "document"
+	typedTagEmbedding = "embedding"
+	typedTagMetadata  = "metadata"
+)
+
+// typedSchema describes how the fields of a struct type map to collection columns.
+type typedSchema struct {
+	typ       reflect.Type
+	id        []int
+	document  []int
+	embedding []int
+	metadata  []int            // Field holding the whole metadata map
+	metaKeys  map[string][]int // Metadata key -> field index
+}
+
+// parseTypedSchema builds a typedSchema from the seekdb struct tags of t.
+func parseTypedSchema(t reflect.Type) (*typedSchema, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: typed collection item must be a struct, got %s", ErrInvalidParameter, t)
+	}
+
+	schema := &typedSchema{
+		typ:      t,
+		metaKeys: make(map[string][]int),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(typedTagName)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		switch parts[0] {
+		case typedTagID:
+			if field.Type.Kind() != reflect.String {
+				return nil, fmt.Errorf("%w: field %s tagged %q must be a string", ErrInvalidParameter, field.Name, tag)
+			}
+			schema.id = field.Index
+		case typedTagDocument:
+			if field.Type.Kind() != reflect.String {
+				return nil, fmt.Errorf("%w: field %s tagged %q must be a string", ErrInvalidParameter, field.Name, tag)
+			}
+			schema.document = field.Index
+		case typedTagEmbedding:
+			if field.Type != reflect.TypeOf([]float32(nil)) {
+				return nil, fmt.Errorf("%w: field %s tagged %q must be []float32", ErrInvalidParameter, field.Name, tag)
+			}
+			schema.embedding = field.Index
+		case typedTagMetadata:
+			key := ""
+			for _, opt := range parts[1:] {
+				if strings.HasPrefix(opt, "key=") {
+					key = strings.TrimPrefix(opt, "key=")
+				}
+			}
+			if key != "" {
+				schema.metaKeys[key] = field.Index
+				continue
+			}
+			if field.Type != reflect.TypeOf(Metadata(nil)) && field.Type != reflect.TypeOf(map[string]interface{}(nil)) {
+				return nil, fmt.Errorf("%w: field %s tagged %q must be Metadata", ErrInvalidParameter, field.Name, tag)
+			}
+			schema.metadata = field.Index
+		default:
+			return nil, fmt.Errorf("%w: unknown seekdb tag %q on field %s", ErrInvalidParameter, tag, field.Name)
+		}
+	}
+
+	if schema.id == nil {
+		return nil, fmt.Errorf("%w: %s has no field tagged seekdb:\"id\"", ErrInvalidParameter, t)
+	}
+
+	return schema, nil
+}
+
+// encode extracts the id, document, embedding and metadata of a single item.
+func (s *typedSchema) encode(v reflect.Value) (string, string, []float32, Metadata) {
+	id := v.FieldByIndex(s.id).String()
+
+	var document string
+	if s.document != nil {
+		document = v.FieldByIndex(s.document).String()
+	}
+
+	var embedding []float32
+	if s.embedding != nil {
+		embedding = v.FieldByIndex(s.embedding).Interface().([]float32)
+	}
+
+	metadata := Metadata{}
+	if s.metadata != nil {
+		for k, val := range v.FieldByIndex(s.metadata).Convert(reflect.TypeOf(Metadata(nil))).Interface().(Metadata) {
+			metadata[k] = val
+		}
+	}
+	for key, index := range s.metaKeys {
+		metadata[key] = v.FieldByIndex(index).Interface()
+	}
+
+	return id, document, embedding, metadata
+}
+
+// decode builds a new item from stored column values.
+func (s *typedSchema) decode(id, document string, embedding []float32, metadata Metadata) (reflect.Value, error) {
+	v := reflect.New(s.typ).Elem()
+
+	v.FieldByIndex(s.id).SetString(id)
+	if s.document != nil {
+		v.FieldByIndex(s.document).SetString(document)
+	}
+	if s.embedding != nil && embedding != nil {
+		v.FieldByIndex(s.embedding).Set(reflect.ValueOf(embedding))
+	}
+	if s.metadata != nil && metadata != nil {
+		v.FieldByIndex(s.metadata).Set(reflect.ValueOf(metadata).Convert(v.FieldByIndex(s.metadata).Type()))
+	}
+
+	for key, index := range s.metaKeys {
+		value, ok := metadata[key]
+		if !ok || value == nil {
+			continue
+		}
+		// Round-trip through JSON so stored numbers (float64) land in int fields, etc.
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to encode metadata key %q: %w", key, err)
+		}
+		if err := json.Unmarshal(raw, v.FieldByIndex(index).Addr().Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to decode metadata key %q: %w", key, err)
+		}
+	}
+
+	return v, nil
+}
+
+// AddTyped adds struct items to the collection, mapping fields to columns via seekdb struct tags.
+// Embeddings are passed through only if every item has a non-empty embedding field;
+// otherwise they are generated by the collection's embedding function.
+func AddTyped[T any](ctx context.Context, c *Collection, items []T) error {
+	schema, err := parseTypedSchema(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(items))
+	documents := make([]string, len(items))
+	embeddings := make([][]float32, len(items))
+	metadatas := make([]Metadata, len(items))
+	hasEmbeddings := schema.embedding != nil
+
+	for i := range items {
+		ids[i], documents[i], embeddings[i], metadatas[i] = schema.encode(reflect.ValueOf(items[i]))
+		if len(embeddings[i]) == 0 {
+			hasEmbeddings = false
+		}
+	}
+
+	opts := []AddOption{WithMetadatas(metadatas)}
+	if hasEmbeddings {
+		opts = append(opts, WithEmbeddings(embeddings))
+	}
+
+	return c.Add(ctx, ids, documents, opts...)
+}
+
+// QueryTyped performs a vector similarity search and decodes each hit into T.
+// Hits for all query texts/embeddings are returned in order; QueryIndex tells them apart.
+func QueryTyped[T any](ctx context.Context, c *Collection, queryTexts []string, nResults int, opts ...QueryOption) ([]TypedResult[T], error) {
+	schema, err := parseTypedSchema(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.Query(ctx, queryTexts, nResults, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var typed []TypedResult[T]
+	for q := range result.IDs {
+		for j, id := range result.IDs[q] {
+			var document string
+			if q < len(result.Documents) && j < len(result.Documents[q]) {
+				document = result.Documents[q][j]
+			}
+			var metadata Metadata
+			if q < len(result.Metadatas) && j < len(result.Metadatas[q]) {
+				metadata = result.Metadatas[q][j]
+			}
+			var embedding []float32
+			if q < len(result.Embeddings) && j < len(result.Embeddings[q]) {
+				embedding = result.Embeddings[q][j]
+			}
+			var distance float64
+			if q < len(result.Distances) && j < len(result.Distances[q]) {
+				distance = result.Distances[q][j]
+			}
+
+			v, err := schema.decode(id, document, embedding, metadata)
+			if err != nil {
+				return nil, err
+			}
+
+			typed = append(typed, TypedResult[T]{
+				Item:       v.Interface().(T),
+				ID:         id,
+				Distance:   distance,
+				QueryIndex: q,
+			})
+		}
+	}
+
+	return typed, nil
+}
@@ -0,0 +1,366 @@
+package goseekdb
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRow is a stored row in fakeOperations.
+type fakeRow struct {
+	id        string
+	document  string
+	embedding []float32
+	metadata  Metadata
+}
+
+// fakeOperations is an in-memory collectionOperations used by unit tests
+// that exercise Collection helpers without a running server.
+type fakeOperations struct {
+	rows         []fakeRow
+	hybridResult *HybridSearchResult
+	scanned      int // Rows handed to collectionQueryEach callbacks
+}
+
+func (f *fakeOperations) collectionAdd(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (int64, error) {
+	for i, id := range ids {
+		row := fakeRow{id: id}
+		if i < len(documents) {
+			row.document = documents[i]
+		}
+		if i < len(opts.Embeddings) {
+			row.embedding = opts.Embeddings[i]
+		}
+		if i < len(opts.Metadatas) {
+			// Store metadata the way the server returns it: through JSON
+			jsonStr, err := opts.Metadatas[i].ToJSON()
+			if err != nil {
+				return 0, err
+			}
+			if err := row.metadata.FromJSON(jsonStr); err != nil {
+				return 0, err
+			}
+		}
+		f.rows = append(f.rows, row)
+	}
+	return int64(len(ids)), nil
+}
+
+func (f *fakeOperations) collectionUpdate(ctx context.Context, collectionName string, ids []string, opts *UpdateOptions, embFunc embedding.EmbeddingFunc) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeOperations) collectionUpsert(ctx context.Context, collectionName string, ids []string, documents []string, opts *AddOptions, embFunc embedding.EmbeddingFunc) (UpsertResult, error) {
+	inserted, err := f.collectionAdd(ctx, collectionName, ids, documents, opts, embFunc)
+	return UpsertResult{Inserted: inserted}, err
+}
+
+func (f *fakeOperations) collectionDelete(ctx context.Context, collectionName string, ids []string, where Filter, whereDocument Filter) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeOperations) collectionQuery(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*QueryResult, error) {
+	result := &QueryResult{}
+	for _, queryEmb := range opts.QueryEmbeddings {
+		rows := append([]fakeRow(nil), f.rows...)
+		sort.SliceStable(rows, func(i, j int) bool {
+			return fakeL2(rows[i].embedding, queryEmb) < fakeL2(rows[j].embedding, queryEmb)
+		})
+		if len(rows) > nResults {
+			rows = rows[:nResults]
+		}
+
+		var ids []string
+		var distances []float64
+		var documents []string
+		var metadatas []Metadata
+		var embeddings [][]float32
+		for _, row := range rows {
+			ids = append(ids, row.id)
+			distances = append(distances, fakeL2(row.embedding, queryEmb))
+			documents = append(documents, row.document)
+			metadatas = append(metadatas, row.metadata)
+			embeddings = append(embeddings, row.embedding)
+		}
+		result.IDs = append(result.IDs, ids)
+		result.Distances = append(result.Distances, distances)
+		result.Documents = append(result.Documents, documents)
+		result.Metadatas = append(result.Metadatas, metadatas)
+		result.Embeddings = append(result.Embeddings, embeddings)
+	}
+
+	// Fields left out with WithInclude are not returned, as by the client
+	include := opts.include()
+	if !include.distances {
+		result.Distances = nil
+	}
+	if !include.documents {
+		result.Documents = nil
+	}
+	if !include.metadatas {
+		result.Metadatas = nil
+	}
+	if !include.embeddings {
+		result.Embeddings = nil
+	}
+	return result, nil
+}
+
+func (f *fakeOperations) collectionQueryEach(ctx context.Context, collectionName string, queryTexts []string, nResults int, opts *QueryOptions, embFunc embedding.EmbeddingFunc, distance DistanceMetric, fn func(SearchRecord) error) error {
+	result, err := f.collectionQuery(ctx, collectionName, queryTexts, nResults, opts, embFunc, distance)
+	if err != nil {
+		return err
+	}
+	for q := range result.IDs {
+		for rank, id := range result.IDs[q] {
+			f.scanned++
+			record := SearchRecord{
+				QueryIndex: q,
+				Rank:       rank,
+				ID:         id,
+				Document:   result.Documents[q][rank],
+				Metadata:   result.Metadatas[q][rank],
+				Embedding:  result.Embeddings[q][rank],
+				Distance:   result.Distances[q][rank],
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeOperations) collectionGet(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*GetResult, error) {
+	rows := append([]fakeRow(nil), f.rows...)
+	if len(ids) > 0 {
+		var matched []fakeRow
+		for _, row := range rows {
+			if slices.Contains(ids, row.id) {
+				matched = append(matched, row)
+			}
+		}
+		rows = matched
+	}
+	if opts.paginate || opts.Cursor != "" {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].id < rows[j].id })
+	}
+	if opts.Cursor != "" {
+		lastID, err := decodeGetCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		var after []fakeRow
+		for _, row := range rows {
+			if row.id > lastID {
+				after = append(after, row)
+			}
+		}
+		rows = after
+	}
+	if opts.Limit > 0 && len(rows) > opts.Limit {
+		rows = rows[:opts.Limit]
+	}
+
+	result := &GetResult{}
+	for _, row := range rows {
+		result.IDs = append(result.IDs, row.id)
+		result.Documents = append(result.Documents, row.document)
+		result.Metadatas = append(result.Metadatas, row.metadata)
+		result.Embeddings = append(result.Embeddings, row.embedding)
+	}
+	return result, nil
+}
+
+func (f *fakeOperations) collectionGetStream(ctx context.Context, collectionName string, ids []string, opts *GetOptions) (*HitIterator, error) {
+	return newHitIterator(nil, false), nil
+}
+
+func (f *fakeOperations) collectionCount(ctx context.Context, collectionName string, where Filter, whereDocument Filter) (int, error) {
+	return len(f.rows), nil
+}
+
+func (f *fakeOperations) collectionHybridSearch(ctx context.Context, collectionName string, query *HybridSearchQuery, knn *HybridSearchKNN, rank *HybridSearchRank, nResults int, embFunc embedding.EmbeddingFunc, distance DistanceMetric) (*HybridSearchResult, error) {
+	if f.hybridResult != nil {
+		return f.hybridResult, nil
+	}
+	return &HybridSearchResult{}, nil
+}
+
+func (f *fakeOperations) collectionSetVectorFields(ctx context.Context, collectionName string, ids []string, namedEmbeddings map[string][][]float32) error {
+	return nil
+}
+
+func (f *fakeOperations) collectionRename(ctx context.Context, oldName, newName string) error {
+	return nil
+}
+
+func (f *fakeOperations) collectionReset(ctx context.Context, collectionName string) error {
+	f.rows = nil
+	return nil
+}
+
+func (f *fakeOperations) collectionCreateMetadataIndex(ctx context.Context, collectionName, key string, opts *MetadataIndexOptions) error {
+	return nil
+}
+
+func (f *fakeOperations) collectionDropMetadataIndex(ctx context.Context, collectionName, key string) error {
+	return nil
+}
+
+func (f *fakeOperations) collectionListMetadataIndexes(ctx context.Context, collectionName string) ([]string, error) {
+	return []string{}, nil
+}
+
+func (f *fakeOperations) collectionFindByContentHash(ctx context.Context, collectionName string, hash string) (string, bool, error) {
+	for _, row := range f.rows {
+		if row.document != "" && contentHash(row.document) == hash {
+			return row.id, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (f *fakeOperations) collectionExists(ctx context.Context, collectionName string, id string) (bool, error) {
+	for _, row := range f.rows {
+		if row.id == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeOperations) collectionCopyFrom(ctx context.Context, collectionName string, records <-chan Record, opts *CopyOptions, embFunc embedding.EmbeddingFunc) (int64, error) {
+	var copied int64
+	for record := range records {
+		f.rows = append(f.rows, fakeRow{id: record.ID, document: record.Document, embedding: record.Embedding, metadata: record.Metadata})
+		copied++
+	}
+	return copied, nil
+}
+
+func fakeL2(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		if i < len(b) {
+			d := float64(a[i] - b[i])
+			sum += d * d
+		}
+	}
+	return math.Sqrt(sum)
+}
+
+type typedArticle struct {
+	ID       string    `seekdb:"id"`
+	Body     string    `seekdb:"document"`
+	Vector   []float32 `seekdb:"embedding"`
+	Category string    `seekdb:"metadata,key=category"`
+	Score    int       `seekdb:"metadata,key=score"`
+	Internal string    // untagged, not stored
+}
+
+func TestAddTypedAndQueryTyped(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "typed", dimension: 3, distance: DistanceL2}
+
+	items := []typedArticle{
+		{ID: "a1", Body: "machine learning", Vector: []float32{1, 2, 3}, Category: "AI", Score: 95, Internal: "x"},
+		{ID: "a2", Body: "python tutorial", Vector: []float32{5, 5, 5}, Category: "Programming", Score: 88},
+	}
+
+	err := AddTyped(ctx, collection, items)
+	require.NoError(t, err)
+	require.Len(t, store.rows, 2)
+	assert.Equal(t, "a1", store.rows[0].id)
+	assert.Equal(t, "machine learning", store.rows[0].document)
+	assert.Equal(t, []float32{1, 2, 3}, store.rows[0].embedding)
+	assert.Equal(t, "AI", store.rows[0].metadata["category"])
+	assert.Equal(t, float64(95), store.rows[0].metadata["score"])
+	assert.NotContains(t, store.rows[0].metadata, "Internal")
+
+	results, err := QueryTyped[typedArticle](ctx, collection, nil, 2,
+		WithQueryEmbeddings([][]float32{{1, 2, 3}}),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "a1", results[0].ID)
+	assert.Equal(t, 0, results[0].QueryIndex)
+	assert.InDelta(t, 0, results[0].Distance, 1e-9)
+	assert.Equal(t, typedArticle{
+		ID:       "a1",
+		Body:     "machine learning",
+		Vector:   []float32{1, 2, 3},
+		Category: "AI",
+		Score:    95,
+	}, results[0].Item)
+	assert.Equal(t, "Programming", results[1].Item.Category)
+	assert.Equal(t, 88, results[1].Item.Score)
+}
+
+// TestQueryTypedInclude tests decoding hits whose other fields were not included
+func TestQueryTypedInclude(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeOperations{}
+	collection := &Collection{client: store, name: "typed", dimension: 3, distance: DistanceL2}
+
+	err := AddTyped(ctx, collection, []typedArticle{
+		{ID: "a1", Body: "machine learning", Vector: []float32{1, 2, 3}, Category: "AI", Score: 95},
+	})
+	require.NoError(t, err)
+
+	results, err := QueryTyped[typedArticle](ctx, collection, nil, 1,
+		WithQueryEmbeddings([][]float32{{1, 2, 3}}),
+		WithQueryInclude("metadatas"),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, typedArticle{ID: "a1", Category: "AI", Score: 95}, results[0].Item)
+	assert.Zero(t, results[0].Distance)
+}
+
+func TestTypedWholeMetadataField(t *testing.T) {
+	type note struct {
+		Key   string   `seekdb:"id"`
+		Text  string   `seekdb:"document"`
+		Extra Metadata `seekdb:"metadata"`
+		Tag   string   `seekdb:"metadata,key=tag"`
+	}
+
+	schema, err := parseTypedSchema(reflect.TypeOf(note{}))
+	require.NoError(t, err)
+
+	_, _, embedding, metadata := schema.encode(reflect.ValueOf(note{Key: "n1", Extra: Metadata{"lang": "en"}, Tag: "t"}))
+	assert.Nil(t, embedding)
+	assert.Equal(t, Metadata{"lang": "en", "tag": "t"}, metadata)
+}
+
+func TestParseTypedSchemaErrors(t *testing.T) {
+	type noID struct {
+		Text string `seekdb:"document"`
+	}
+	_, err := parseTypedSchema(reflect.TypeOf(noID{}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	type badEmbedding struct {
+		ID     string    `seekdb:"id"`
+		Vector []float64 `seekdb:"embedding"`
+	}
+	_, err = parseTypedSchema(reflect.TypeOf(badEmbedding{}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	type unknownTag struct {
+		ID   string `seekdb:"id"`
+		Blob string `seekdb:"blob"`
+	}
+	_, err = parseTypedSchema(reflect.TypeOf(unknownTag{}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
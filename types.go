@@ -2,6 +2,9 @@ package goseekdb
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 )
 
 // DistanceMetric represents the distance metric used for vector similarity.
@@ -22,7 +25,33 @@ const DefaultVectorDimension = 384
 // DefaultDistanceMetric is the default distance metric.
 const DefaultDistanceMetric = DistanceCosine
 
+// Validate returns ErrInvalidParameter if d is not a supported distance metric.
+func (d DistanceMetric) Validate() error {
+	switch d {
+	case DistanceL2, DistanceCosine, DistanceInnerProduct:
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported distance metric %q (use %q, %q or %q)",
+			ErrInvalidParameter, string(d), DistanceL2, DistanceCosine, DistanceInnerProduct)
+	}
+}
+
+// DistanceMetricFromString parses a distance metric as reported by the server,
+// e.g. in a vector index definition. Matching ignores case and surrounding space,
+// and "ip" is accepted as an alias for inner_product.
+func DistanceMetricFromString(s string) (DistanceMetric, error) {
+	d := DistanceMetric(strings.ToLower(strings.TrimSpace(s)))
+	if d == "ip" {
+		d = DistanceInnerProduct
+	}
+	if err := d.Validate(); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
 // DistanceFuncName returns the SQL function name for the distance metric.
+// Unknown metrics map to l2_distance; use Validate to reject them up front.
 func (d DistanceMetric) DistanceFuncName() string {
 	switch d {
 	case DistanceL2:
@@ -36,10 +65,91 @@ func (d DistanceMetric) DistanceFuncName() string {
 	}
 }
 
+// HigherIsBetter reports whether larger values of the metric mean more similar
+// vectors. This holds for inner product, a similarity, whereas L2 and cosine are
+// distances where smaller is more similar.
+func (d DistanceMetric) HigherIsBetter() bool {
+	return d == DistanceInnerProduct
+}
+
 // HNSWConfiguration represents the HNSW index configuration for a collection.
 type HNSWConfiguration struct {
 	Dimension int            `json:"dimension"`
 	Distance  DistanceMetric `json:"distance"`
+
+	// VectorFields declares additional named vector columns stored alongside the
+	// default embedding column, e.g. a title embedding and a body embedding.
+	VectorFields []VectorField `json:"vector_fields,omitempty"`
+
+	// M, EfConstruction and EfSearch tune the HNSW indexes of the collection; zero
+	// leaves the server default (DefaultHNSWM, DefaultHNSWEfConstruction and
+	// DefaultHNSWEfSearch). Larger values improve recall, which matters most at high
+	// dimensions, at the cost of memory and build time (M, EfConstruction) or query
+	// latency (EfSearch). EfSearch can also be set per query with WithEfSearch.
+	M              int `json:"m,omitempty"`
+	EfConstruction int `json:"ef_construction,omitempty"`
+	EfSearch       int `json:"ef_search,omitempty"`
+}
+
+// Server defaults of the HNSW index parameters.
+const (
+	// DefaultHNSWM is the default maximum number of neighbors per graph node.
+	DefaultHNSWM = 16
+	// DefaultHNSWEfConstruction is the default candidate list size while building the index.
+	DefaultHNSWEfConstruction = 200
+	// DefaultHNSWEfSearch is the default candidate list size while searching.
+	DefaultHNSWEfSearch = 64
+)
+
+// vectorIndexOptions returns the WITH (...) options of an HNSW vector index with the
+// given distance and the tuning parameters set in config.
+func vectorIndexOptions(distance DistanceMetric, config *HNSWConfiguration) string {
+	options := fmt.Sprintf("distance=%s, type=hnsw, lib=vsag", distance)
+	if config == nil {
+		return options
+	}
+	if config.M > 0 {
+		options += fmt.Sprintf(", m=%d", config.M)
+	}
+	if config.EfConstruction > 0 {
+		options += fmt.Sprintf(", ef_construction=%d", config.EfConstruction)
+	}
+	if config.EfSearch > 0 {
+		options += fmt.Sprintf(", ef_search=%d", config.EfSearch)
+	}
+	return options
+}
+
+// validateHNSWConfiguration checks the distance metrics and index parameters of a
+// collection configuration. An empty distance means the default and is accepted.
+func validateHNSWConfiguration(config *HNSWConfiguration) error {
+	if config == nil {
+		return nil
+	}
+	if config.Distance != "" {
+		if err := config.Distance.Validate(); err != nil {
+			return err
+		}
+	}
+	if config.M < 0 || config.EfConstruction < 0 || config.EfSearch < 0 {
+		return fmt.Errorf("%w: HNSW parameters must not be negative (m=%d, ef_construction=%d, ef_search=%d)",
+			ErrInvalidParameter, config.M, config.EfConstruction, config.EfSearch)
+	}
+	for _, field := range config.VectorFields {
+		if field.Distance != "" {
+			if err := field.Distance.Validate(); err != nil {
+				return fmt.Errorf("vector field %q: %w", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// VectorField describes a named vector column with its own HNSW index.
+type VectorField struct {
+	Name      string         `json:"name"`
+	Dimension int            `json:"dimension"`
+	Distance  DistanceMetric `json:"distance,omitempty"` // Defaults to the collection distance
 }
 
 // Database represents a database in SeekDB.
@@ -60,6 +170,39 @@ type QueryResult struct {
 	Documents  [][]string    `json:"documents,omitempty"`
 	Metadatas  [][]Metadata  `json:"metadatas,omitempty"`
 	Embeddings [][][]float32 `json:"embeddings,omitempty"`
+
+	// Stats holds execution statistics per query with WithQueryStats, else nil.
+	Stats []QueryStats `json:"stats,omitempty"`
+
+	// RawColumns holds the columns selected WithExtraColumns per hit, else nil.
+	RawColumns [][]map[string]interface{} `json:"raw_columns,omitempty"`
+}
+
+// Record is a single document for bulk loading with CopyFrom.
+// If Embedding is nil it is generated from Document by the collection's embedding function.
+type Record struct {
+	ID        string
+	Document  string
+	Embedding []float32
+	Metadata  Metadata
+}
+
+// UpsertResult reports the outcome of an upsert.
+type UpsertResult struct {
+	Inserted int64 // Rows that did not exist before
+	Updated  int64 // Existing rows that were updated
+}
+
+// SearchRecord is a single vector search hit, as passed to QueryWithCallback.
+type SearchRecord struct {
+	QueryIndex int // Index of the query text/embedding that produced this hit
+	Rank       int // Position of the hit within its query's results, starting at 0
+	ID         string
+	Document   string
+	Metadata   Metadata
+	Embedding  []float32
+	Distance   float64
+	RawColumns map[string]interface{} // Columns selected WithExtraColumns
 }
 
 // GetResult contains the results of a get operation.
@@ -68,6 +211,16 @@ type GetResult struct {
 	Documents  []string    `json:"documents,omitempty"`
 	Metadatas  []Metadata  `json:"metadatas,omitempty"`
 	Embeddings [][]float32 `json:"embeddings,omitempty"`
+
+	// RawColumns holds the columns selected WithGetExtraColumns per document, else nil.
+	RawColumns []map[string]interface{} `json:"raw_columns,omitempty"`
+}
+
+// GetPageResult contains one page of a keyset-paginated get operation.
+type GetPageResult struct {
+	GetResult
+	// NextCursor resumes iteration after this page; empty when there are no more results.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // HybridSearchResult contains the results of a hybrid search.
@@ -77,6 +230,63 @@ type HybridSearchResult struct {
 	Documents  []string    `json:"documents,omitempty"`
 	Metadatas  []Metadata  `json:"metadatas,omitempty"`
 	Embeddings [][]float32 `json:"embeddings,omitempty"`
+
+	// RawScores holds the fused scores before normalization; it is only set
+	// when the search was run with WithNormalizedScores.
+	RawScores []float64 `json:"raw_scores,omitempty"`
+
+	// DebugSQL and DebugParm hold the SQL generated by DBMS_HYBRID_SEARCH.GET_SQL
+	// and the search_parm JSON it was generated from. They are only set when the
+	// client was created with WithExplainHybrid.
+	DebugSQL  string `json:"debug_sql,omitempty"`
+	DebugParm string `json:"debug_parm,omitempty"`
+}
+
+// Hits returns the results as one Hit per ID, in rank order, with the score of each
+// in Distance. Fields the search did not return are left empty; Embedding is only
+// set if embeddings were returned.
+func (r *HybridSearchResult) Hits() []Hit {
+	hits := make([]Hit, len(r.IDs))
+	for i, id := range r.IDs {
+		hits[i].ID = id
+		if i < len(r.Distances) {
+			hits[i].Distance = r.Distances[i]
+		}
+		if i < len(r.Documents) {
+			hits[i].Document = r.Documents[i]
+		}
+		if i < len(r.Metadatas) {
+			hits[i].Metadata = r.Metadatas[i]
+		}
+		if i < len(r.Embeddings) {
+			hits[i].Embedding = r.Embeddings[i]
+		}
+	}
+	return hits
+}
+
+// normalizeScores min-max scales Distances into [0, 1] within this result set,
+// keeping the original values in RawScores. Fused scores rank higher-is-better,
+// so the top hit scores 1 and the bottom hit 0; if all scores are equal, all are 1.
+func (r *HybridSearchResult) normalizeScores() {
+	r.RawScores = append([]float64(nil), r.Distances...)
+	if len(r.Distances) == 0 {
+		return
+	}
+
+	minScore, maxScore := r.Distances[0], r.Distances[0]
+	for _, score := range r.Distances[1:] {
+		minScore = math.Min(minScore, score)
+		maxScore = math.Max(maxScore, score)
+	}
+
+	for i, score := range r.Distances {
+		if maxScore == minScore {
+			r.Distances[i] = 1
+		} else {
+			r.Distances[i] = (score - minScore) / (maxScore - minScore)
+		}
+	}
 }
 
 // RRFConfig represents configuration for Reciprocal Rank Fusion.
@@ -89,6 +299,13 @@ type HybridSearchQuery struct {
 	WhereDocument Filter `json:"where_document,omitempty"`
 	Where         Filter `json:"where,omitempty"`
 	NResults      int    `json:"n_results"`
+
+	// Fields are the full-text indexed columns WhereDocument searches, each
+	// optionally boosted as "name^2"; the document column if empty.
+	Fields []string `json:"fields,omitempty"`
+	// Analyzer names the analyzer (tokenizer) applied to the query text; the
+	// analyzer of the searched fields' full-text index if empty.
+	Analyzer string `json:"analyzer,omitempty"`
 }
 
 // HybridSearchKNN represents KNN parameters for hybrid search.
@@ -129,6 +346,66 @@ func (m *Metadata) FromJSON(s string) error {
 	return json.Unmarshal([]byte(s), m)
 }
 
+// FromJSONPreserveIntegers parses JSON like FromJSON, but decodes numbers without
+// a fraction or exponent as int64 instead of float64, so {"year": 2023} yields
+// int64(2023). Other numbers, and integers outside the int64 range, are float64.
+// Nested objects and arrays are decoded the same way.
+func (m *Metadata) FromJSONPreserveIntegers(s string) error {
+	if s == "" || s == "{}" {
+		*m = Metadata{}
+		return nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var decoded map[string]interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return err
+	}
+	if decoded == nil {
+		*m = nil
+		return nil
+	}
+	for key, value := range decoded {
+		decoded[key] = convertJSONNumbers(value)
+	}
+	*m = decoded
+	return nil
+}
+
+// convertJSONNumbers replaces the json.Numbers in a value decoded with UseNumber by
+// int64 for integers and float64 otherwise.
+func convertJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			if n, err := v.Int64(); err == nil {
+				return n
+			}
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+	}
+	return value
+}
+
+// decodeMetadata parses metadata read from a collection table, preserving integers
+// if the client was configured WithIntegerMetadata.
+func (c *Client) decodeMetadata(s string, m *Metadata) error {
+	if c.config != nil && c.config.IntegerMetadata {
+		return m.FromJSONPreserveIntegers(s)
+	}
+	return m.FromJSON(s)
+}
+
 // CollectionInfo contains metadata about a collection.
 type CollectionInfo struct {
 	Name      string         `json:"name"`
@@ -144,10 +421,57 @@ const (
 	FieldMetadata  = "metadata"
 )
 
-// TableNamePrefix is the prefix for collection tables.
+// FieldContentHash is the column holding document hashes in collections
+// created with WithContentHash.
+const FieldContentHash = "_content_hash"
+
+// VectorFieldColumnPrefix is the prefix for named vector field columns.
+const VectorFieldColumnPrefix = "embedding_"
+
+// VectorFieldColumn returns the table column name for a named vector field.
+// An empty name refers to the default embedding column.
+func VectorFieldColumn(name string) string {
+	if name == "" {
+		return FieldEmbedding
+	}
+	return VectorFieldColumnPrefix + name
+}
+
+// TableNamePrefix is the prefix for collection tables of schema version 1.
 const TableNamePrefix = "c$v1$"
 
-// GetTableName returns the database table name for a collection.
+// GetTableName returns the database table name for a collection using the default prefix.
 func GetTableName(collectionName string) string {
 	return TableNamePrefix + collectionName
 }
+
+// GetTableName returns the database table name for a collection using the
+// client's configured table prefix (see WithTablePrefix), or else the prefix of the
+// collection's schema version (see WithSchemaVersion).
+func (c *Client) GetTableName(collectionName string) string {
+	if c.config != nil && c.config.TablePrefix != "" {
+		return c.config.TablePrefix + collectionName
+	}
+	return TableNamePrefixForVersion(c.collectionSchemaVersion(collectionName)) + collectionName
+}
+
+// maxTablePrefixLength leaves room for a collection name within MySQL's 64-character identifier limit.
+const maxTablePrefixLength = 32
+
+// validateTablePrefix checks that prefix can start an unquoted SQL identifier.
+func validateTablePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if len(prefix) > maxTablePrefixLength {
+		return fmt.Errorf("%w: table prefix %q exceeds %d characters", ErrInvalidParameter, prefix, maxTablePrefixLength)
+	}
+	for i, r := range prefix {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && r != '_' && r != '$' && (!isDigit || i == 0) {
+			return fmt.Errorf("%w: table prefix %q must start with a letter, '_' or '$' and contain only letters, digits, '_' or '$'", ErrInvalidParameter, prefix)
+		}
+	}
+	return nil
+}
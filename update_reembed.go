@@ -0,0 +1,25 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ob-labs/seekdb-go/embedding"
+)
+
+// reembedUpdatedDocuments fills in opts.Embeddings from opts.Documents for an Update
+// that changes document text without providing embeddings, so the stored vectors do
+// not go stale. It does nothing if embeddings are given, no embedding function is
+// configured, or the caller opted out with WithSkipReembed.
+func (c *Client) reembedUpdatedDocuments(ctx context.Context, opts *UpdateOptions, embFunc embedding.EmbeddingFunc) error {
+	if len(opts.Documents) == 0 || opts.Embeddings != nil || embFunc == nil || opts.SkipReembed {
+		return nil
+	}
+
+	embeddings, err := c.embedDocuments(ctx, embFunc, opts.Documents)
+	if err != nil {
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	opts.Embeddings = embeddings
+	return nil
+}
@@ -0,0 +1,76 @@
+package goseekdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReembedUpdatedDocuments tests that a document-only update regenerates embeddings
+func TestReembedUpdatedDocuments(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{config: &ClientConfig{}}
+	stored, err := lengthEmbedder{}.Embed([]string{"old"})
+	require.NoError(t, err)
+
+	options := &UpdateOptions{}
+	WithUpdateDocuments([]string{"new longer text"})(options)
+	require.NoError(t, client.reembedUpdatedDocuments(ctx, options, lengthEmbedder{}))
+	assert.Equal(t, [][]float32{{15, 0, 0}}, options.Embeddings)
+	assert.NotEqual(t, stored, options.Embeddings)
+
+	// Opting out keeps the stored vectors
+	options = &UpdateOptions{}
+	WithUpdateDocuments([]string{"new longer text"})(options)
+	WithSkipReembed(true)(options)
+	require.NoError(t, client.reembedUpdatedDocuments(ctx, options, lengthEmbedder{}))
+	assert.Nil(t, options.Embeddings)
+
+	// Given embeddings win, and without an embedding function nothing is generated
+	options = &UpdateOptions{Documents: []string{"text"}, Embeddings: [][]float32{{1, 2, 3}}}
+	require.NoError(t, client.reembedUpdatedDocuments(ctx, options, lengthEmbedder{}))
+	assert.Equal(t, [][]float32{{1, 2, 3}}, options.Embeddings)
+
+	options = &UpdateOptions{Documents: []string{"text"}}
+	require.NoError(t, client.reembedUpdatedDocuments(ctx, options, nil))
+	assert.Nil(t, options.Embeddings)
+
+	// Metadata-only updates do not touch embeddings
+	options = &UpdateOptions{Metadatas: []Metadata{{"k": 1}}}
+	require.NoError(t, client.reembedUpdatedDocuments(ctx, options, lengthEmbedder{}))
+	assert.Nil(t, options.Embeddings)
+}
+
+// TestUpdateReembedsDocuments tests that Update writes the regenerated embeddings
+func TestUpdateReembedsDocuments(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{}}
+
+	var dryRun *DryRunError
+	_, err := client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{Documents: []string{"new text"}}, lengthEmbedder{})
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "UPDATE c$v1$docs SET document = ?, embedding = ? WHERE _id = ?", dryRun.Statements[0].SQL)
+	assert.Equal(t, []interface{}{"new text", "[8,0,0]", "id1"}, dryRun.Statements[0].Args)
+
+	_, err = client.collectionUpdate(ctx, "docs", []string{"id1"}, &UpdateOptions{Documents: []string{"text"}}, failingEmbedder{})
+	assert.ErrorIs(t, err, errEmbedFailed)
+}
+
+func TestReembedUpdatedDocumentsError(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+	options := &UpdateOptions{Documents: []string{"text"}}
+	err := client.reembedUpdatedDocuments(context.Background(), options, failingEmbedder{})
+	assert.ErrorIs(t, err, errEmbedFailed)
+	assert.Nil(t, options.Embeddings)
+}
+
+var errEmbedFailed = errors.New("embedding failed")
+
+// failingEmbedder fails every Embed call with errEmbedFailed
+type failingEmbedder struct{}
+
+func (failingEmbedder) Embed(texts []string) ([][]float32, error) { return nil, errEmbedFailed }
+func (failingEmbedder) Dimension() int                            { return 3 }
@@ -0,0 +1,48 @@
+package goseekdb
+
+import "strconv"
+
+// VectorFormatter formats a vector as the literal sent to the server for vector
+// columns and query vectors, e.g. [0.1,0.2,0.3]. The server parses vectors only from
+// this array form, so formatters differ in how they print each component.
+type VectorFormatter func(vector []float32) string
+
+// VectorFormat is the formatter used for every vector sent to the server. It
+// defaults to FormatVectorExact; set it once at startup, e.g. to
+// FormatVectorPrecision(6), to trade precision for shorter statements.
+var VectorFormat VectorFormatter = FormatVectorExact
+
+// FormatVectorExact formats each component with the fewest digits that parse back
+// to the same float32, so vectors are stored exactly.
+func FormatVectorExact(vector []float32) string {
+	return formatVector(vector, -1)
+}
+
+// FormatVectorPrecision returns a formatter that rounds each component to the given
+// number of significant digits. Six digits keep the relative error below 1e-6,
+// which is well under the noise of typical embedding models, and shorten literals
+// of 768-dim embeddings in [-1, 1] by about 15% compared to FormatVectorExact; four
+// digits shorten them by about a third.
+func FormatVectorPrecision(digits int) VectorFormatter {
+	if digits <= 0 {
+		return FormatVectorExact
+	}
+	return func(vector []float32) string {
+		return formatVector(vector, digits)
+	}
+}
+
+// formatVector formats vector as an array literal with the given number of
+// significant digits per component, or the shortest exact form for -1.
+func formatVector(vector []float32, digits int) string {
+	buf := make([]byte, 0, 2+len(vector)*12)
+	buf = append(buf, '[')
+	for i, v := range vector {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendFloat(buf, float64(v), 'g', digits, 32)
+	}
+	buf = append(buf, ']')
+	return string(buf)
+}
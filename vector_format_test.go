@@ -0,0 +1,66 @@
+package goseekdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatVector tests that vector literals round-trip and honor the precision
+func TestFormatVector(t *testing.T) {
+	vector := []float32{0.1, -0.25, 1.0 / 3, 1e-8, 0, 12345.678}
+
+	exact := FormatVectorExact(vector)
+	assert.Equal(t, "[0.1,-0.25,0.33333334,1e-08,0,12345.678]", exact)
+	assert.Equal(t, fmt.Sprintf("%v", vector[2]), "0.33333334", "matches the former %%v formatting")
+	var decoded []float32
+	require.NoError(t, json.Unmarshal([]byte(exact), &decoded))
+	assert.Equal(t, vector, decoded)
+
+	assert.Equal(t, "[0.1,-0.25,0.333,1e-08,0,1.23e+04]", FormatVectorPrecision(3)(vector))
+	assert.Equal(t, exact, FormatVectorPrecision(0)(vector))
+	assert.Equal(t, "[]", FormatVectorExact(nil))
+}
+
+func randomVector(dim int) []float32 {
+	r := rand.New(rand.NewSource(1))
+	vector := make([]float32, dim)
+	for i := range vector {
+		vector[i] = r.Float32()*2 - 1
+	}
+	return vector
+}
+
+// BenchmarkFormatVector reports the literal size of a 768-dim vector per format
+func BenchmarkFormatVector(b *testing.B) {
+	vector := randomVector(768)
+	formats := []struct {
+		name   string
+		format VectorFormatter
+	}{
+		{"sprintf", func(v []float32) string {
+			parts := make([]string, len(v))
+			for i, x := range v {
+				parts[i] = fmt.Sprintf("%v", x)
+			}
+			return "[" + strings.Join(parts, ",") + "]"
+		}},
+		{"exact", FormatVectorExact},
+		{"precision=6", FormatVectorPrecision(6)},
+		{"precision=4", FormatVectorPrecision(4)},
+	}
+	for _, f := range formats {
+		b.Run(f.name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				size = len(f.format(vector))
+			}
+			b.ReportMetric(float64(size), "bytes/vector")
+		})
+	}
+}
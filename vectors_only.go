@@ -0,0 +1,98 @@
+package goseekdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// vectorsOnlyDefinitions returns the column and index definitions of a collection
+// created WithVectorsOnly, for use in its CREATE TABLE statement in place of the
+// usual ones: only the ID and the embedding with its vector index, without the
+// document and metadata columns or the full-text index.
+func vectorsOnlyDefinitions(dimension int, distance DistanceMetric, config *HNSWConfiguration) []string {
+	return []string{
+		fmt.Sprintf("%s VARBINARY(512) PRIMARY KEY NOT NULL", FieldID),
+		fmt.Sprintf("%s VECTOR(%d)", FieldEmbedding, dimension),
+		fmt.Sprintf("VECTOR INDEX idx_%s (%s) WITH (%s)", FieldEmbedding, FieldEmbedding, vectorIndexOptions(distance, config)),
+	}
+}
+
+// validateVectorsOnlyOptions checks the options of a collection created WithVectorsOnly,
+// which has no document to hash.
+func validateVectorsOnlyOptions(opts *CreateCollectionOptions) error {
+	if opts.VectorsOnly && opts.ContentHash {
+		return fmt.Errorf("%w: WithVectorsOnly cannot be combined with WithContentHash", ErrInvalidParameter)
+	}
+	return nil
+}
+
+// collectionVectorsOnly reports whether a collection was created WithVectorsOnly.
+// meta and hasMeta are the result of readCollectionMeta. The persisted metadata is
+// authoritative; only tables without it are inspected.
+func (c *Client) collectionVectorsOnly(ctx context.Context, collectionName string, meta collectionMeta, hasMeta bool) (bool, error) {
+	if hasMeta {
+		return meta.VectorsOnly, nil
+	}
+	return c.isVectorsOnlyTable(ctx, collectionName)
+}
+
+// isVectorsOnlyTable reports whether a collection table has neither a document nor a
+// metadata column. It detects vectors-only collections whose table comment does not
+// say so, e.g. tables created by other tools.
+func (c *Client) isVectorsOnlyTable(ctx context.Context, collectionName string) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME IN (?, ?)
+	`
+
+	var count int
+	if err := c.conn.QueryRow(ctx, query, c.GetTableName(collectionName), FieldDocument, FieldMetadata).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to read collection columns: %w", err)
+	}
+	return count == 0, nil
+}
+
+// vectorsOnlyError returns the error for a use of documents, metadata or filters on
+// them in a collection without those columns.
+func vectorsOnlyError(collectionName, what string) error {
+	return fmt.Errorf("%w: collection %q stores vectors only and has no %s", ErrInvalidParameter, collectionName, what)
+}
+
+// validateVectorsOnlyWrite checks the documents and metadatas of a write to a
+// vectors-only collection: there is nowhere to store them.
+func validateVectorsOnlyWrite(collectionName string, documents []string, metadatas []Metadata) error {
+	for _, document := range documents {
+		if document != "" {
+			return vectorsOnlyError(collectionName, "documents")
+		}
+	}
+	for _, metadata := range metadatas {
+		if len(metadata) > 0 {
+			return vectorsOnlyError(collectionName, "metadata")
+		}
+	}
+	return nil
+}
+
+// validateVectorsOnlyQuery rejects query options reading documents or metadata.
+func validateVectorsOnlyQuery(collectionName string, opts *QueryOptions) error {
+	switch {
+	case opts.Where != nil || opts.ScoreBoost != nil || opts.Tiebreaker != nil:
+		return vectorsOnlyError(collectionName, "metadata")
+	case opts.WhereDocument != nil:
+		return vectorsOnlyError(collectionName, "documents")
+	}
+	return nil
+}
+
+// validateVectorsOnlyGet rejects get options reading documents or metadata.
+func validateVectorsOnlyGet(collectionName string, opts *GetOptions) error {
+	switch {
+	case opts.Where != nil || opts.OrderBy != nil || len(opts.MetadataKeys) > 0:
+		return vectorsOnlyError(collectionName, "metadata")
+	case opts.WhereDocument != nil:
+		return vectorsOnlyError(collectionName, "documents")
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package goseekdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorsOnlyDefinitions(t *testing.T) {
+	assert.Equal(t, []string{
+		"_id VARBINARY(512) PRIMARY KEY NOT NULL",
+		"embedding VECTOR(3)",
+		"VECTOR INDEX idx_embedding (embedding) WITH (distance=cosine, type=hnsw, lib=vsag, m=24)",
+	}, vectorsOnlyDefinitions(3, DistanceCosine, &HNSWConfiguration{M: 24}))
+
+	options := &CreateCollectionOptions{}
+	WithVectorsOnly(true)(options)
+	assert.NoError(t, validateVectorsOnlyOptions(options))
+	WithContentHash(true)(options)
+	assert.ErrorIs(t, validateVectorsOnlyOptions(options), ErrInvalidParameter)
+}
+
+// TestVectorsOnlyMeta tests that the mode is persisted and read back from the table comment
+func TestVectorsOnlyMeta(t *testing.T) {
+	meta := newCollectionMeta(nil, nil)
+	option, err := meta.tableOption()
+	require.NoError(t, err)
+	assert.NotContains(t, option, "vectors_only")
+
+	meta.VectorsOnly = true
+	option, err = meta.tableOption()
+	require.NoError(t, err)
+	assert.Contains(t, option, `"vectors_only":true`)
+
+	parsed, ok, err := parseCollectionMeta(`seekdb:{"dimension":3,"distance":"l2","vectors_only":true}`)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, parsed.VectorsOnly)
+
+	client := &Client{config: &ClientConfig{}}
+	vectorsOnly, err := client.collectionVectorsOnly(context.Background(), "docs", parsed, true)
+	require.NoError(t, err)
+	assert.True(t, vectorsOnly)
+}
+
+// TestIsVectorsOnlyTable tests detecting tables without document and metadata columns
+func TestIsVectorsOnlyTable(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "vectors_only_columns", []string{"COUNT(*)"}, [][]driver.Value{{int64(0)}})
+	client := &Client{conn: &staticConnection{db: db, key: "vectors_only_columns"}, config: &ClientConfig{}}
+
+	// Without persisted metadata, the columns decide
+	vectorsOnly, err := client.collectionVectorsOnly(ctx, "docs", collectionMeta{}, false)
+	require.NoError(t, err)
+	assert.True(t, vectorsOnly)
+
+	db = registerStaticRows(t, "full_columns", []string{"COUNT(*)"}, [][]driver.Value{{int64(2)}})
+	client.conn = &staticConnection{db: db, key: "full_columns"}
+	vectorsOnly, err = client.isVectorsOnlyTable(ctx, "docs")
+	require.NoError(t, err)
+	assert.False(t, vectorsOnly)
+}
+
+func TestVectorsOnlySQL(t *testing.T) {
+	client := &Client{config: &ClientConfig{}}
+
+	queryOptions := &QueryOptions{vectorsOnly: true}
+	querySQL, _ := buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", queryOptions)
+	assert.Contains(t, querySQL, "SELECT _id, embedding, l2_distance(embedding, ?) AS distance\n")
+	assert.NotContains(t, querySQL, "document")
+	assert.NotContains(t, querySQL, "metadata")
+
+	WithMaxDistance(0.5)(queryOptions)
+	querySQL, _ = buildVectorQuerySQL("c$v1$docs", "", nil, []float32{1, 2, 3}, 5, DistanceL2, "", queryOptions)
+	assert.Contains(t, querySQL, "SELECT _id, embedding, distance\n")
+
+	getSQL, args, err := client.buildGetSQL("docs", []string{"a"}, &GetOptions{vectorsOnly: true})
+	require.NoError(t, err)
+	assert.Contains(t, getSQL, "SELECT _id, NULL AS document, '{}' AS metadata, embedding\n")
+	assert.Equal(t, []interface{}{"a", 1000, 0}, args)
+
+	assert.Equal(t, "INSERT INTO c$v1$docs (_id, embedding) VALUES (?, ?), (?, ?)",
+		copyInsertSQL("c$v1$docs", 2, &CopyOptions{vectorsOnly: true}))
+	copyArgs, err := copyRecordArgs([]Record{{ID: "a", Embedding: []float32{1, 2}}}, &CopyOptions{vectorsOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "[1,2]"}, copyArgs)
+	_, err = copyRecordArgs([]Record{{ID: "a", Document: "text"}}, &CopyOptions{vectorsOnly: true})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestVectorsOnlyCollectionSQL tests the table and the writes of a vectors-only collection
+func TestVectorsOnlyCollectionSQL(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{conn: newDryRunConnection(nil), config: &ClientConfig{}}
+
+	options := &CreateCollectionOptions{}
+	WithVectorsOnly(true)(options)
+	createSQL, err := client.createCollectionSQL("vecs", 2, DistanceL2, options, nil)
+	require.NoError(t, err)
+	assert.Contains(t, createSQL, "embedding VECTOR(2)")
+	assert.NotContains(t, createSQL, "LONGTEXT")
+	assert.NotContains(t, createSQL, "FULLTEXT")
+	assert.NotContains(t, createSQL, "metadata JSON")
+
+	_, err = client.CreateCollection(ctx, "vecs", WithVectorsOnly(true), WithContentHash(true))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	var dryRun *DryRunError
+	add := &AddOptions{Embeddings: [][]float32{{1, 2}}, vectorsOnly: true}
+	_, err = client.collectionAdd(ctx, "vecs", []string{"a"}, nil, add, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "INSERT INTO c$v1$vecs (_id, embedding) VALUES (?, ?)", dryRun.Statements[0].SQL)
+	assert.Equal(t, []interface{}{"a", "[1,2]"}, dryRun.Statements[0].Args)
+
+	_, err = client.collectionUpsert(ctx, "vecs", []string{"a"}, nil, add, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "INSERT INTO c$v1$vecs (_id, embedding) VALUES (?, ?) ON DUPLICATE KEY UPDATE embedding = VALUES(embedding)",
+		dryRun.Statements[0].SQL)
+
+	_, err = client.collectionUpdate(ctx, "vecs", []string{"a"}, &UpdateOptions{
+		Documents:   []string{""},
+		Embeddings:  [][]float32{{3, 4}},
+		vectorsOnly: true,
+	}, nil)
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, "UPDATE c$v1$vecs SET embedding = ? WHERE _id = ?", dryRun.Statements[0].SQL)
+	assert.Equal(t, []interface{}{"[3,4]", "a"}, dryRun.Statements[0].Args)
+
+	_, err = client.collectionUpdate(ctx, "vecs", []string{"a"}, &UpdateOptions{Documents: []string{""}, vectorsOnly: true}, nil)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestVectorsOnlyRejectsDocumentsAndMetadata tests that nothing is silently dropped
+func TestVectorsOnlyRejectsDocumentsAndMetadata(t *testing.T) {
+	ctx := context.Background()
+	collection := &Collection{client: &fakeOperations{}, name: "vecs", dimension: 2, vectorsOnly: true}
+	assert.True(t, collection.VectorsOnly())
+
+	_, err := collection.AddN(ctx, []string{"a"}, nil, WithEmbeddings([][]float32{{1, 2}}))
+	assert.NoError(t, err)
+	_, err = collection.AddN(ctx, []string{"b"}, []string{"text"}, WithEmbeddings([][]float32{{1, 2}}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = collection.UpsertN(ctx, []string{"b"}, nil, WithEmbeddings([][]float32{{1, 2}}), WithMetadatas([]Metadata{{"k": 1}}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, err = collection.UpdateN(ctx, []string{"a"}, WithUpdateDocuments([]string{"text"}))
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+
+	client := &Client{config: &ClientConfig{}}
+	_, err = client.collectionQuery(ctx, "vecs", nil, 1, &QueryOptions{
+		QueryEmbeddings: [][]float32{{1, 2}},
+		WhereDocument:   Filter{"$contains": "text"},
+		vectorsOnly:     true,
+	}, nil, DistanceL2)
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	_, _, err = client.buildGetSQL("vecs", nil, &GetOptions{MetadataKeys: []string{"k"}, vectorsOnly: true})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
+
+// TestVectorsOnlyScan tests that query and get results leave documents and metadatas out
+func TestVectorsOnlyScan(t *testing.T) {
+	ctx := context.Background()
+	db := registerStaticRows(t, "vectors_only_query", []string{"_id", "embedding", "distance"}, [][]driver.Value{
+		{[]byte("id1"), []byte("[1,2]"), 0.5},
+		{[]byte("id2"), []byte("[3,4]"), 1.5},
+	})
+	client := &Client{conn: &staticConnection{db: db, key: "vectors_only_query"}, config: &ClientConfig{}}
+	queryOptions := &QueryOptions{QueryEmbeddings: [][]float32{{1, 2}}, vectorsOnly: true}
+
+	result, err := client.collectionQuery(ctx, "vecs", nil, 2, queryOptions, nil, DistanceL2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"id1", "id2"}}, result.IDs)
+	assert.Equal(t, [][]float64{{0.5, 1.5}}, result.Distances)
+	assert.Equal(t, [][][]float32{{{1, 2}, {3, 4}}}, result.Embeddings)
+	assert.Nil(t, result.Documents)
+	assert.Nil(t, result.Metadatas)
+
+	var records []SearchRecord
+	err = client.collectionQueryEach(ctx, "vecs", nil, 2, queryOptions, nil, DistanceL2, func(record SearchRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "id2", records[1].ID)
+	assert.Equal(t, []float32{3, 4}, records[1].Embedding)
+	assert.Nil(t, records[1].Metadata)
+
+	db = registerStaticRows(t, "vectors_only_get", []string{"_id", "document", "metadata", "embedding"}, [][]driver.Value{
+		{[]byte("id1"), nil, []byte("{}"), []byte("[1,2]")},
+	})
+	client.conn = &staticConnection{db: db, key: "vectors_only_get"}
+	getResult, err := client.collectionGet(ctx, "vecs", nil, &GetOptions{vectorsOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id1"}, getResult.IDs)
+	assert.Equal(t, [][]float32{{1, 2}}, getResult.Embeddings)
+	assert.Nil(t, getResult.Documents)
+	assert.Nil(t, getResult.Metadatas)
+}